@@ -92,12 +92,13 @@ func (abi ABI) UnpackIntoMap(v map[string]interface{}, name string, data []byte)
 // UnmarshalJSON implements json.Unmarshaler interface
 func (abi *ABI) UnmarshalJSON(data []byte) error {
 	var fields []struct {
-		Type      string
-		Name      string
-		Constant  bool
-		Anonymous bool
-		Inputs    []Argument
-		Outputs   []Argument
+		Type            string
+		Name            string
+		Constant        bool
+		StateMutability string
+		Anonymous       bool
+		Inputs          []Argument
+		Outputs         []Argument
 	}
 	if err := json.Unmarshal(data, &fields); err != nil {
 		return err
@@ -121,7 +122,8 @@ func (abi *ABI) UnmarshalJSON(data []byte) error {
 			abi.Methods[name] = Method{
 				Name:    name,
 				RawName: field.Name,
-				Const:   field.Constant,
+				// solidity 0.6 marks the constant methods by stateMutability only
+				Const:   field.Constant || field.StateMutability == "view" || field.StateMutability == "pure",
 				Inputs:  field.Inputs,
 				Outputs: field.Outputs,
 			}
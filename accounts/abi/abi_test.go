@@ -82,6 +82,24 @@ func TestReader(t *testing.T) {
 	}
 }
 
+func TestReaderStateMutability(t *testing.T) {
+	abi, err := JSON(strings.NewReader(`[
+	{ "type" : "function", "name" : "balance", "stateMutability" : "view" },
+	{ "type" : "function", "name" : "hash", "stateMutability" : "pure" },
+	{ "type" : "function", "name" : "deposit", "stateMutability" : "payable" },
+	{ "type" : "function", "name" : "send", "stateMutability" : "nonpayable" },
+	{ "type" : "function", "name" : "total", "constant" : true }
+]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]bool{"balance": true, "hash": true, "deposit": false, "send": false, "total": true} {
+		if have := abi.Methods[name].Const; have != want {
+			t.Errorf("method %s: const mismatch, have %v, want %v", name, have, want)
+		}
+	}
+}
+
 func TestTestNumbers(t *testing.T) {
 	abi, err := JSON(strings.NewReader(jsondata2))
 	if err != nil {
@@ -402,7 +402,7 @@ func bindStructTypeGo(kind abi.Type, structs map[string]*tmplStruct) string {
 			field := bindStructTypeGo(*elem, structs)
 			fields = append(fields, &tmplField{Type: field, Name: capitalise(kind.TupleRawNames[i]), SolKind: *elem})
 		}
-		name := kind.TupleRawName
+		name := capitalise(kind.TupleRawName)
 		if name == "" {
 			name = fmt.Sprintf("Struct%d", len(structs))
 		}
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"testing"
 
+	"gbchain-org/go-gbchain/accounts/abi"
 	"gbchain-org/go-gbchain/common"
 )
 
@@ -1988,3 +1989,22 @@ public class Test {
 		}
 	}
 }
+
+// Tests that the structs named by lowercase contracts or structs are exported.
+func TestBindStructTypeGoExported(t *testing.T) {
+	kind, err := abi.NewType("tuple", "struct crossdemo.order", []abi.ArgumentMarshaling{
+		{Name: "value", Type: "uint256"},
+		{Name: "from", Type: "address"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create tuple type: %v", err)
+	}
+	structs := make(map[string]*tmplStruct)
+	if name := bindStructTypeGo(kind, structs); name != "Crossdemoorder" {
+		t.Fatalf("struct name mismatch: have %s, want %s", name, "Crossdemoorder")
+	}
+	s := structs[kind.TupleRawName+kind.String()]
+	if s == nil || len(s.Fields) != 2 || s.Fields[0].Name != "Value" || s.Fields[1].Name != "From" {
+		t.Fatalf("struct fields mismatch: have %+v", s)
+	}
+}
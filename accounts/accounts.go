@@ -25,6 +25,7 @@ const (
 	MimetypeClique            = "application/x-clique-header"
 	MimetypeDPoS              = "application/x-dpos-header"
 	MimetypeTextPlain         = "text/plain"
+	MimetypeCrossCtx          = "application/x-cross-ctx"
 )
 
 // Wallet represents a software or hardware wallet that might contain one or more
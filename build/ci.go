@@ -65,6 +65,7 @@ var (
 		executablePath("rlpdump"),
 		executablePath("wnode"),
 		executablePath("clef"),
+		executablePath("crossctl"),
 	}
 
 	// A debian package is created for all executables listed here.
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"gbchain-org/go-gbchain/accounts/abi/bind"
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/cross/contract/crossdemo"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	maxValueFlag = cli.StringFlag{
+		Name:  "maxvalue",
+		Usage: "max value of a ctx made to the remote chain in wei",
+		Value: "10000000000000000000000",
+	}
+	confirmsFlag = cli.UintFlag{
+		Name:  "confirms",
+		Usage: "signatures of anchors required by a ctx",
+		Value: 2,
+	}
+	anchorsFlag = cli.StringFlag{
+		Name:  "anchors",
+		Usage: "comma separated addresses of anchors",
+	}
+)
+
+var commandRegister = cli.Command{
+	Name:  "register",
+	Usage: "register a remote chain in the cross contract",
+	Description: `
+Register the remote chain of --chain with its anchors in the cross contract,
+the account must be the owner of contract.`,
+	Flags: append([]cli.Flag{
+		chainFlag,
+		maxValueFlag,
+		confirmsFlag,
+		anchorsFlag,
+	}, txFlags...),
+	Action: func(ctx *cli.Context) error {
+		maxValue, err := parseBig(ctx.String(maxValueFlag.Name))
+		if err != nil {
+			return fmt.Errorf("invalid max value: %v", err)
+		}
+		confirms := ctx.Uint(confirmsFlag.Name)
+		if confirms == 0 || confirms > 255 {
+			return fmt.Errorf("invalid confirms %d", confirms)
+		}
+		anchors, err := parseAddresses(ctx.String(anchorsFlag.Name))
+		if err != nil {
+			return err
+		}
+		if len(anchors) < int(confirms) {
+			return fmt.Errorf("%d anchors are less than confirms %d", len(anchors), confirms)
+		}
+		return transact(ctx, func(contract *crossdemo.CrossDemo, opts *bind.TransactOpts, remoteID *big.Int) (*types.Transaction, error) {
+			return contract.ChainRegister(opts, remoteID, maxValue, uint8(confirms), anchors)
+		})
+	},
+}
+
+var commandAnchor = cli.Command{
+	Name:  "anchor",
+	Usage: "manage the anchors of a remote chain",
+	Subcommands: []cli.Command{
+		{
+			Name:  "add",
+			Usage: "add anchors of the remote chain",
+			Flags: append([]cli.Flag{chainFlag, anchorsFlag}, txFlags...),
+			Action: func(ctx *cli.Context) error {
+				return updateAnchors(ctx, (*crossdemo.CrossDemo).AddAnchors)
+			},
+		},
+		{
+			Name:  "remove",
+			Usage: "remove anchors of the remote chain",
+			Flags: append([]cli.Flag{chainFlag, anchorsFlag}, txFlags...),
+			Action: func(ctx *cli.Context) error {
+				return updateAnchors(ctx, (*crossdemo.CrossDemo).RemoveAnchors)
+			},
+		},
+		{
+			Name:  "list",
+			Usage: "list the anchors of the remote chain",
+			Flags: []cli.Flag{chainFlag, rpcFlag, contractFlag, outputFlag},
+			Action: func(ctx *cli.Context) error {
+				client, contract, err := dialContract(ctx)
+				if err != nil {
+					return err
+				}
+				defer client.Close()
+
+				remoteID := new(big.Int).SetUint64(ctx.Uint64(chainFlag.Name))
+				anchors, confirms, err := contract.GetAnchors(nil, remoteID)
+				if err != nil {
+					return err
+				}
+				tab := &table{header: []string{"ANCHOR"}}
+				for _, anchor := range anchors {
+					tab.append(anchor.String())
+				}
+				tab.append(fmt.Sprintf("(%d anchors, %d confirms)", len(anchors), confirms))
+				return printResult(ctx, struct {
+					Anchors  []common.Address `json:"anchors"`
+					Confirms uint8            `json:"confirms"`
+				}{anchors, confirms}, tab)
+			},
+		},
+	},
+}
+
+func updateAnchors(ctx *cli.Context, update func(*crossdemo.CrossDemo, *bind.TransactOpts, *big.Int, []common.Address) (*types.Transaction, error)) error {
+	anchors, err := parseAddresses(ctx.String(anchorsFlag.Name))
+	if err != nil {
+		return err
+	}
+	if len(anchors) == 0 {
+		return errors.New("no anchors")
+	}
+	return transact(ctx, func(contract *crossdemo.CrossDemo, opts *bind.TransactOpts, remoteID *big.Int) (*types.Transaction, error) {
+		return update(contract, opts, remoteID, anchors)
+	})
+}
+
+// transact sends a transaction to the cross contract for the remote chain of --chain
+func transact(ctx *cli.Context, send func(*crossdemo.CrossDemo, *bind.TransactOpts, *big.Int) (*types.Transaction, error)) error {
+	if !ctx.IsSet(chainFlag.Name) {
+		return errors.New("remote chain is required")
+	}
+	client, contract, err := dialContract(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	opts, err := transactOpts(ctx, client)
+	if err != nil {
+		return err
+	}
+	tx, err := send(contract, opts, new(big.Int).SetUint64(ctx.Uint64(chainFlag.Name)))
+	if err != nil {
+		return err
+	}
+	return printTxs(ctx, []*txResult{newTxResult(tx, opts.From)})
+}
@@ -0,0 +1,348 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"gbchain-org/go-gbchain/accounts/abi/bind"
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/common/hexutil"
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/cross/backend"
+	"gbchain-org/go-gbchain/cross/contract/crossdemo"
+	cc "gbchain-org/go-gbchain/cross/core"
+	"gbchain-org/go-gbchain/ethclient/crossclient"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	valueFlag = cli.StringFlag{
+		Name:  "value",
+		Usage: "value paid by maker in wei",
+	}
+	destValueFlag = cli.StringFlag{
+		Name:  "destvalue",
+		Usage: "value paid by taker in the remote chain in wei",
+	}
+	toFlag = cli.StringFlag{
+		Name:  "to",
+		Usage: "the only taker could take the ctx, anyone if empty",
+	}
+	dataFlag = cli.StringFlag{
+		Name:  "data",
+		Usage: "hex input of the ctx",
+	}
+	tokenFlag = cli.StringFlag{
+		Name:  "token",
+		Usage: "ERC20 token paid by maker, native coin if empty",
+	}
+	destTokenFlag = cli.StringFlag{
+		Name:  "desttoken",
+		Usage: "ERC20 token paid by taker in the remote chain, native coin if empty",
+	}
+	countFlag = cli.IntFlag{
+		Name:  "count",
+		Usage: "number of ctxs to make or take",
+		Value: 1,
+	}
+	crossRPCFlag = cli.StringFlag{
+		Name:  "crossrpc",
+		Usage: "RPC endpoint of the anchor node serving the cross API, --rpc if empty",
+	}
+	limitFlag = cli.IntFlag{
+		Name:  "limit",
+		Usage: "number of ctxs in a page",
+		Value: 100,
+	}
+	pageFlag = cli.IntFlag{
+		Name:  "page",
+		Usage: "page of ctxs, starting from 1",
+		Value: 1,
+	}
+	ctxFlag = cli.StringFlag{
+		Name:  "ctx",
+		Usage: "id of the ctx",
+	}
+)
+
+var commandMaker = cli.Command{
+	Name:  "maker",
+	Usage: "make ctxs to the remote chain",
+	Description: `
+Make ctxs paying --value to the anchors, which are taken by the takers paying
+--destvalue in the remote chain of --chain. The ERC20 tokens must be approved
+to the cross contract before making token ctxs.`,
+	Flags: append([]cli.Flag{
+		chainFlag,
+		valueFlag,
+		destValueFlag,
+		toFlag,
+		dataFlag,
+		tokenFlag,
+		destTokenFlag,
+		countFlag,
+	}, txFlags...),
+	Action: func(ctx *cli.Context) error {
+		value, err := parseBig(ctx.String(valueFlag.Name))
+		if err != nil {
+			return fmt.Errorf("invalid value: %v", err)
+		}
+		destValue, err := parseBig(ctx.String(destValueFlag.Name))
+		if err != nil {
+			return fmt.Errorf("invalid destination value: %v", err)
+		}
+		var to, token, destToken common.Address
+		for _, f := range []struct {
+			flag cli.StringFlag
+			addr *common.Address
+		}{{toFlag, &to}, {tokenFlag, &token}, {destTokenFlag, &destToken}} {
+			if s := ctx.String(f.flag.Name); s != "" {
+				if *f.addr, err = parseAddress(s); err != nil {
+					return fmt.Errorf("invalid %s: %v", f.flag.Name, err)
+				}
+			}
+		}
+		var data []byte
+		if s := ctx.String(dataFlag.Name); s != "" {
+			if data, err = hexutil.Decode(s); err != nil {
+				return fmt.Errorf("invalid data: %v", err)
+			}
+		}
+		if !ctx.IsSet(chainFlag.Name) {
+			return errors.New("remote chain is required")
+		}
+		remoteID := new(big.Int).SetUint64(ctx.Uint64(chainFlag.Name))
+
+		client, contract, err := dialContract(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		opts, err := transactOpts(ctx, client)
+		if err != nil {
+			return err
+		}
+
+		var results []*txResult
+		for i := 0; i < ctx.Int(countFlag.Name); i++ {
+			// the tokens paid by maker are transferred by the allowance instead of the value
+			opts.Value = new(big.Int)
+			if token == (common.Address{}) {
+				opts.Value = value
+			}
+			var tx *types.Transaction
+			if token == (common.Address{}) && destToken == (common.Address{}) {
+				tx, err = contract.MakerStart(opts, remoteID, destValue, to, data)
+			} else {
+				tx, err = contract.MakerTokenStart(opts, remoteID, token, value, destToken, destValue, to, data)
+			}
+			if err != nil {
+				if len(results) > 0 {
+					printTxs(ctx, results)
+				}
+				return err
+			}
+			results = append(results, newTxResult(tx, opts.From))
+		}
+		return printTxs(ctx, results)
+	},
+}
+
+var commandTaker = cli.Command{
+	Name:  "taker",
+	Usage: "take the ctxs made in the remote chain",
+	Description: `
+Take the waiting ctxs made in the remote chain of --chain, which could be taken
+by the account. The ctxs are queried from the anchor node of --crossrpc in the
+order of prices, the cheapest ctx is taken first.`,
+	Flags: append([]cli.Flag{
+		chainFlag,
+		crossRPCFlag,
+		ctxFlag,
+		limitFlag,
+		countFlag,
+	}, txFlags...),
+	Action: func(ctx *cli.Context) error {
+		if !ctx.IsSet(chainFlag.Name) {
+			return errors.New("remote chain is required")
+		}
+		remoteID := new(big.Int).SetUint64(ctx.Uint64(chainFlag.Name))
+		ctxs, err := waitingCtxs(ctx, remoteID)
+		if err != nil {
+			return err
+		}
+
+		client, contract, err := dialContract(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		opts, err := transactOpts(ctx, client)
+		if err != nil {
+			return err
+		}
+
+		var results []*txResult
+		for _, rtx := range ctxs {
+			if len(results) >= ctx.Int(countFlag.Name) {
+				break
+			}
+			if !takeable(rtx, opts.From) {
+				continue
+			}
+			tx, err := take(contract, opts, rtx, remoteID)
+			if err != nil {
+				if len(results) > 0 {
+					printTxs(ctx, results)
+				}
+				return fmt.Errorf("take ctx %s failed: %v", rtx.CTxId.String(), err)
+			}
+			result := newTxResult(tx, opts.From)
+			result.CtxID = &rtx.CTxId
+			results = append(results, result)
+		}
+		return printTxs(ctx, results)
+	},
+}
+
+// waitingCtxs returns the ctxs of --ctx, or the cheapest remote ctxs waiting for takers
+func waitingCtxs(ctx *cli.Context, remoteID *big.Int) ([]*backend.RPCCrossTransaction, error) {
+	client, err := dialCross(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	if id := ctx.String(ctxFlag.Name); id != "" {
+		rtx, err := client.CtxGet(context.Background(), common.HexToHash(id), remoteID)
+		if err != nil {
+			return nil, fmt.Errorf("get ctx %s failed: %v", id, err)
+		}
+		return []*backend.RPCCrossTransaction{rtx}, nil
+	}
+	content, err := client.CtxContentByPage(context.Background(), 0, 1, ctx.Int(limitFlag.Name), 1, remoteID)
+	if err != nil {
+		return nil, err
+	}
+	return content["remote"].Data[remoteID.Uint64()], nil
+}
+
+// takeable reports whether the waiting ctx could be taken by the taker
+func takeable(rtx *backend.RPCCrossTransaction, taker common.Address) bool {
+	if rtx.Status != cc.CtxStatusWaiting {
+		return false
+	}
+	return rtx.From != taker && (rtx.To == common.Address{} || rtx.To == taker)
+}
+
+// take sends the taker transaction of ctx, or takerToken if the ctx swaps ERC20 tokens
+func take(contract *crossdemo.CrossDemo, opts *bind.TransactOpts, rtx *backend.RPCCrossTransaction, remoteID *big.Int) (*types.Transaction, error) {
+	var (
+		v    = make([]*big.Int, len(rtx.V))
+		r, s = make([][32]byte, len(rtx.R)), make([][32]byte, len(rtx.S))
+	)
+	for i := range rtx.V {
+		v[i], r[i], s[i] = rtx.V[i].ToInt(), common.BigToHash(rtx.R[i].ToInt()), common.BigToHash(rtx.S[i].ToInt())
+	}
+	if rtx.Token == nil {
+		opts.Value = rtx.DestinationValue.ToInt()
+		return contract.Taker(opts, crossdemo.CrossDemoOrder{
+			Value:            rtx.Value.ToInt(),
+			TxId:             rtx.CTxId,
+			TxHash:           rtx.TxHash,
+			From:             rtx.From,
+			To:               rtx.To,
+			BlockHash:        rtx.BlockHash,
+			DestinationValue: rtx.DestinationValue.ToInt(),
+			Data:             rtx.Input,
+			V:                v,
+			R:                r,
+			S:                s,
+		}, remoteID)
+	}
+	opts.Value = new(big.Int)
+	if *rtx.DestinationToken == (common.Address{}) {
+		opts.Value = rtx.DestinationValue.ToInt()
+	}
+	return contract.TakerToken(opts, crossdemo.CrossDemoTokenOrder{
+		Value:            rtx.Value.ToInt(),
+		TxId:             rtx.CTxId,
+		TxHash:           rtx.TxHash,
+		From:             rtx.From,
+		To:               rtx.To,
+		BlockHash:        rtx.BlockHash,
+		DestinationValue: rtx.DestinationValue.ToInt(),
+		Token:            *rtx.Token,
+		DestinationToken: *rtx.DestinationToken,
+		Data:             rtx.Input,
+		V:                v,
+		R:                r,
+		S:                s,
+	}, remoteID)
+}
+
+var commandQuery = cli.Command{
+	Name:  "query",
+	Usage: "query the ctxs of a chain pair",
+	Description: `
+Query the ctx of --ctx, or the waiting ctxs made in both the local chain and the
+remote chain of --chain, from the anchor node of --rpc.`,
+	Flags: []cli.Flag{
+		rpcFlag,
+		chainFlag,
+		ctxFlag,
+		limitFlag,
+		pageFlag,
+		outputFlag,
+	},
+	Action: func(ctx *cli.Context) error {
+		client, err := crossclient.Dial(ctx.String(rpcFlag.Name))
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		var remoteID *big.Int
+		if ctx.IsSet(chainFlag.Name) {
+			remoteID = new(big.Int).SetUint64(ctx.Uint64(chainFlag.Name))
+		}
+		tab := &table{header: []string{"KIND", "CTX", "STATUS", "FROM", "TO", "VALUE", "DEST CHAIN", "DEST VALUE"}}
+		appendCtx := func(kind string, rtx *backend.RPCCrossTransaction) {
+			tab.append(kind, rtx.CTxId.String(), rtx.Status.String(), rtx.From.String(), rtx.To.String(),
+				rtx.Value.ToInt().String(), rtx.DestinationId.ToInt().String(), rtx.DestinationValue.ToInt().String())
+		}
+
+		if id := ctx.String(ctxFlag.Name); id != "" {
+			rtx, err := client.CtxGet(context.Background(), common.HexToHash(id), remoteID)
+			if err != nil {
+				return fmt.Errorf("get ctx %s failed: %v", id, err)
+			}
+			appendCtx("", rtx)
+			return printResult(ctx, rtx, tab)
+		}
+
+		limit, page := ctx.Int(limitFlag.Name), ctx.Int(pageFlag.Name)
+		content, err := client.CtxContentByPage(context.Background(), limit, page, limit, page, remoteID)
+		if err != nil {
+			return err
+		}
+		for _, kind := range []string{"local", "remote"} {
+			for _, rtxs := range content[kind].Data {
+				for _, rtx := range rtxs {
+					appendCtx(kind, rtx)
+				}
+			}
+		}
+		return printResult(ctx, content, tab)
+	},
+}
+
+// dialCross connects the anchor node serving the cross API
+func dialCross(ctx *cli.Context) (*crossclient.Client, error) {
+	if endpoint := ctx.String(crossRPCFlag.Name); endpoint != "" {
+		return crossclient.Dial(endpoint)
+	}
+	return crossclient.Dial(ctx.String(rpcFlag.Name))
+}
@@ -0,0 +1,99 @@
+// crossctl is a command line tool to manage the cross contracts and the ctxs of cross chains.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"gbchain-org/go-gbchain/cmd/utils"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Git SHA1 commit hash of the release (set via linker flags)
+var gitCommit = ""
+var gitDate = ""
+
+var app *cli.App
+
+func init() {
+	app = utils.NewApp(gitCommit, gitDate, "a cross chain contract and ctx manager")
+	app.Commands = []cli.Command{
+		commandRegister,
+		commandAnchor,
+		commandMaker,
+		commandTaker,
+		commandQuery,
+		commandSign,
+	}
+	cli.CommandHelpTemplate = utils.OriginCommandHelpTemplate
+}
+
+// Commonly used command line flags.
+var (
+	rpcFlag = cli.StringFlag{
+		Name:  "rpc",
+		Usage: "RPC endpoint of the chain node",
+		Value: "http://127.0.0.1:8545",
+	}
+	contractFlag = cli.StringFlag{
+		Name:  "contract",
+		Usage: "address of the cross contract",
+	}
+	keystoreFlag = cli.StringFlag{
+		Name:  "keystore",
+		Usage: "directory of the keystore holding the account",
+	}
+	accountFlag = cli.StringFlag{
+		Name:  "account",
+		Usage: "address of the account sending transactions or signing ctxs",
+	}
+	passwordFlag = cli.StringFlag{
+		Name:  "password",
+		Usage: "the file that contains the password of the keystore account",
+	}
+	signerFlag = cli.StringFlag{
+		Name:  "signer",
+		Usage: "external signer (url or path to ipc file), like clef, instead of the keystore",
+	}
+	gasLimitFlag = cli.Uint64Flag{
+		Name:  "gaslimit",
+		Usage: "gas limit of transactions, estimated if 0",
+	}
+	gasPriceFlag = cli.StringFlag{
+		Name:  "gasprice",
+		Usage: "gas price of transactions in wei, suggested by the node if empty",
+	}
+	outputFlag = cli.StringFlag{
+		Name:  "output",
+		Usage: "output format (table, json)",
+		Value: outputTable,
+	}
+	chainFlag = cli.Uint64Flag{
+		Name:  "chain",
+		Usage: "id of the remote chain",
+	}
+)
+
+// accountFlags are the flags to sign by an account of keystore or external signer
+var accountFlags = []cli.Flag{
+	keystoreFlag,
+	accountFlag,
+	passwordFlag,
+	signerFlag,
+}
+
+// txFlags are the flags of commands sending transactions to the cross contract
+var txFlags = append([]cli.Flag{
+	rpcFlag,
+	contractFlag,
+	gasLimitFlag,
+	gasPriceFlag,
+	outputFlag,
+}, accountFlags...)
+
+func main() {
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
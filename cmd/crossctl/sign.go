@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/common/hexutil"
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/cross/contract/crossdemo"
+	cc "gbchain-org/go-gbchain/cross/core"
+	"gbchain-org/go-gbchain/ethclient"
+	"gbchain-org/go-gbchain/params"
+	"gbchain-org/go-gbchain/rlp"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	txFlag = cli.StringFlag{
+		Name:  "tx",
+		Usage: "hash of the maker transaction",
+	}
+	signaturesFlag = cli.StringFlag{
+		Name:  "signatures",
+		Usage: "comma separated signatures of other anchors, which are printed by their sign commands",
+	}
+	importFlag = cli.BoolFlag{
+		Name:  "import",
+		Usage: "import the signed ctxs into the anchor node of --crossrpc",
+	}
+)
+
+var commandSign = cli.Command{
+	Name:  "sign",
+	Usage: "sign the ctxs of a maker transaction offline",
+	Description: `
+Sign the ctxs made by the maker transaction of --tx with the anchor account, the
+signatures of other anchors are merged if given. The signed ctxs could be imported
+into an anchor node, which verifies that they are signed by enough anchors.`,
+	Flags: append([]cli.Flag{
+		rpcFlag,
+		contractFlag,
+		crossRPCFlag,
+		txFlag,
+		signaturesFlag,
+		importFlag,
+		outputFlag,
+	}, accountFlags...),
+	Action: func(ctx *cli.Context) error {
+		client, contract, err := dialContract(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		ctxs, number, err := makerCtxs(client, contract, common.HexToHash(ctx.String(txFlag.Name)))
+		if err != nil {
+			return err
+		}
+		chainID, err := client.ChainID(context.Background())
+		if err != nil {
+			return err
+		}
+		_, anchor, err := openSigner(ctx)
+		if err != nil {
+			return err
+		}
+
+		var (
+			signer = cc.MakeCtxSigner(chainID)
+			cwss   = make([]*cc.CrossTransactionWithSignatures, len(ctxs))
+		)
+		for i, tx := range ctxs {
+			signed, err := cc.SignCtxData(tx, signer, anchor.SignData)
+			if err != nil {
+				return err
+			}
+			cwss[i] = cc.NewCrossTransactionWithSignatures(signed, number)
+		}
+		if err := mergeSignatures(cwss, ctx.String(signaturesFlag.Name)); err != nil {
+			return err
+		}
+
+		results := make([]*signResult, len(cwss))
+		for i, cws := range cwss {
+			if results[i], err = newSignResult(cws); err != nil {
+				return err
+			}
+		}
+		if ctx.Bool(importFlag.Name) {
+			if err := importCtxs(ctx, cwss); err != nil {
+				return err
+			}
+		}
+		tab := &table{header: []string{"CTX", "DEST CHAIN", "SIGNATURES", "DATA"}}
+		for _, r := range results {
+			tab.append(r.CtxID.String(), r.DestinationId.ToInt().String(), fmt.Sprint(r.Signatures), r.Signed.String())
+		}
+		return printResult(ctx, results, tab)
+	},
+}
+
+// makerCtxs decodes the ctxs made by the maker transaction, and the number of block including it
+func makerCtxs(client *ethclient.Client, contract *crossdemo.CrossDemo, hash common.Hash) ([]*cc.CrossTransaction, uint64, error) {
+	receipt, err := client.TransactionReceipt(context.Background(), hash)
+	if err != nil {
+		return nil, 0, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, 0, fmt.Errorf("maker transaction %s failed", hash.String())
+	}
+	var (
+		ctxs   []*cc.CrossTransaction
+		tokens []*cc.MakerToken
+	)
+	for _, l := range receipt.Logs {
+		if len(l.Topics) == 0 {
+			continue
+		}
+		switch l.Topics[0] {
+		case params.MakerTopic:
+			ev, err := contract.ParseMakerTx(*l)
+			if err != nil {
+				return nil, 0, err
+			}
+			ctxs = append(ctxs, cc.NewCrossTransaction(ev.Value, ev.DestValue, ev.RemoteChainId,
+				ev.TxId, l.TxHash, l.BlockHash, ev.From, ev.To, ev.Data))
+		case params.MakerTokenTopic:
+			if token := cc.NewMakerToken(l); token != nil {
+				tokens = append(tokens, token)
+			}
+		}
+	}
+	if len(ctxs) == 0 {
+		return nil, 0, fmt.Errorf("no ctx is made by transaction %s", hash.String())
+	}
+	cc.WithMakerTokens(ctxs, tokens)
+	return ctxs, receipt.BlockNumber.Uint64(), nil
+}
+
+// mergeSignatures adds the signatures of other anchors to the ctxs, the duplicate signatures are skipped
+func mergeSignatures(cwss []*cc.CrossTransactionWithSignatures, signatures string) error {
+	for _, field := range strings.Split(signatures, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		enc, err := hexutil.Decode(field)
+		if err != nil {
+			return fmt.Errorf("invalid signatures: %v", err)
+		}
+		var ctxs []*cc.CrossTransaction
+		if err := rlp.DecodeBytes(enc, &ctxs); err != nil {
+			return fmt.Errorf("invalid signatures: %v", err)
+		}
+		for _, tx := range ctxs {
+			var matched bool
+			for _, cws := range cwss {
+				if cws.ID() != tx.ID() {
+					continue
+				}
+				if err := cws.AddSignature(tx); err != nil && err != cc.ErrDuplicateSign {
+					return fmt.Errorf("merge signature of ctx %s failed: %v", tx.ID().String(), err)
+				}
+				matched = true
+			}
+			if !matched {
+				return fmt.Errorf("ctx %s is not made by the transaction", tx.ID().String())
+			}
+		}
+	}
+	return nil
+}
+
+// signResult is a ctx with the signatures of anchors
+type signResult struct {
+	CtxID         common.Hash   `json:"ctxId"`
+	DestinationId *hexutil.Big  `json:"destinationId"`
+	Signatures    int           `json:"signatures"`
+	Signed        hexutil.Bytes `json:"signed"` // rlp of signatures, merged by the sign commands of other anchors
+	Ctx           hexutil.Bytes `json:"ctx"`    // rlp of ctx with signatures, imported into the anchor nodes
+}
+
+func newSignResult(cws *cc.CrossTransactionWithSignatures) (*signResult, error) {
+	signed, err := rlp.EncodeToBytes(cws.Resolution())
+	if err != nil {
+		return nil, err
+	}
+	enc, err := rlp.EncodeToBytes(cws)
+	if err != nil {
+		return nil, err
+	}
+	return &signResult{
+		CtxID:         cws.ID(),
+		DestinationId: (*hexutil.Big)(cws.DestinationId()),
+		Signatures:    cws.SignaturesLength(),
+		Signed:        signed,
+		Ctx:           enc,
+	}, nil
+}
+
+func importCtxs(ctx *cli.Context, cwss []*cc.CrossTransactionWithSignatures) error {
+	client, err := dialCross(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	for _, cws := range cwss {
+		if err := client.ImportCtx(context.Background(), cws); err != nil {
+			return fmt.Errorf("import ctx %s failed: %v", cws.ID().String(), err)
+		}
+	}
+	return nil
+}
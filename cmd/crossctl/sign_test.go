@@ -0,0 +1,76 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/common/hexutil"
+	"gbchain-org/go-gbchain/cross"
+	cc "gbchain-org/go-gbchain/cross/core"
+	"gbchain-org/go-gbchain/crypto"
+)
+
+func TestMergeSignatures(t *testing.T) {
+	var (
+		chainID = big.NewInt(18)
+		signer  = cc.MakeCtxSigner(chainID)
+		tx      = cc.NewCrossTransaction(big.NewInt(1e18), big.NewInt(2e18), big.NewInt(19),
+			common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03"),
+			common.HexToAddress("0x04"), common.Address{}, nil)
+	)
+	// each anchor signs the ctx by its own sign command
+	var (
+		cwss    []*cc.CrossTransactionWithSignatures
+		results []*signResult
+	)
+	for i := 0; i < 2; i++ {
+		key, _ := crypto.GenerateKey()
+		signed, err := cc.SignCtxData(tx, signer, cross.NewKeySigner(key).SignData)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cws := cc.NewCrossTransactionWithSignatures(signed, 1)
+		result, err := newSignResult(cws)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cwss, results = append(cwss, cws), append(results, result)
+	}
+
+	// the duplicate signature is skipped
+	signatures := results[1].Signed.String() + "," + results[0].Signed.String()
+	if err := mergeSignatures(cwss[:1], signatures); err != nil {
+		t.Fatal(err)
+	}
+	if n := cwss[0].SignaturesLength(); n != 2 {
+		t.Fatalf("signatures mismatch: have %d, want 2", n)
+	}
+	for _, ctx := range cwss[0].Resolution() {
+		if _, err := cc.CtxSender(signer, ctx); err != nil {
+			t.Errorf("invalid merged signature: %v", err)
+		}
+	}
+
+	other := cc.NewCrossTransactionWithSignatures(cc.NewCrossTransaction(big.NewInt(1), big.NewInt(1), big.NewInt(19),
+		common.HexToHash("0x05"), common.Hash{}, common.Hash{}, common.Address{}, common.Address{}, nil), 1)
+	if err := mergeSignatures([]*cc.CrossTransactionWithSignatures{other}, signatures); err == nil {
+		t.Errorf("signatures of other ctxs are merged")
+	}
+	if err := mergeSignatures(cwss, hexutil.Encode([]byte{0x01})); err == nil {
+		t.Errorf("invalid signatures are merged")
+	}
+}
+
+func TestParseAddresses(t *testing.T) {
+	addrs, err := parseAddresses("0x6051De4667626B97af2b81A392ad228e0fF58002, 0x8e422d5Aff496974f7FaE17F6848a40C59F8b2E9,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(addrs) != 2 || addrs[1] != common.HexToAddress("0x8e422d5Aff496974f7FaE17F6848a40C59F8b2E9") {
+		t.Errorf("addresses mismatch: %v", addrs)
+	}
+	if _, err := parseAddresses("0x6051De4667626B97af2b81A392ad228e0fF58002,0x01"); err == nil {
+		t.Errorf("invalid address is parsed")
+	}
+}
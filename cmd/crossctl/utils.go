@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"gbchain-org/go-gbchain/accounts"
+	"gbchain-org/go-gbchain/accounts/abi/bind"
+	"gbchain-org/go-gbchain/accounts/external"
+	"gbchain-org/go-gbchain/accounts/keystore"
+	"gbchain-org/go-gbchain/cmd/utils"
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/console"
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/cross"
+	"gbchain-org/go-gbchain/cross/contract/crossdemo"
+	"gbchain-org/go-gbchain/ethclient"
+	"gopkg.in/urfave/cli.v1"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// getPassphrase reads the passphrase from the --password file, or prompts the user for it.
+func getPassphrase(ctx *cli.Context) string {
+	if file := ctx.String(passwordFlag.Name); file != "" {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			utils.Fatalf("Failed to read password file '%s': %v", file, err)
+		}
+		return strings.TrimRight(string(content), "\r\n")
+	}
+	passphrase, err := console.Stdin.PromptPassword("Password: ")
+	if err != nil {
+		utils.Fatalf("Failed to read password: %v", err)
+	}
+	return passphrase
+}
+
+// openSigner returns the account of --account and its signer. The account is held by the
+// external signer if --signer is given, otherwise it is unlocked in the --keystore.
+func openSigner(ctx *cli.Context) (common.Address, *cross.AnchorSigner, error) {
+	account, err := parseAddress(ctx.String(accountFlag.Name))
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("invalid account: %v", err)
+	}
+	if endpoint := ctx.String(signerFlag.Name); endpoint != "" {
+		signer, err := external.NewExternalSigner(endpoint)
+		if err != nil {
+			return common.Address{}, nil, err
+		}
+		return account, cross.NewAnchorSigner(nil, account, signer), nil
+	}
+
+	dir := ctx.String(keystoreFlag.Name)
+	if dir == "" {
+		return common.Address{}, nil, errors.New("either keystore or signer is required")
+	}
+	ks := keystore.NewKeyStore(dir, keystore.StandardScryptN, keystore.StandardScryptP)
+	if _, err := ks.Find(accounts.Account{Address: account}); err != nil {
+		return common.Address{}, nil, fmt.Errorf("account %s: %v", account.String(), err)
+	}
+	if err := ks.Unlock(accounts.Account{Address: account}, getPassphrase(ctx)); err != nil {
+		return common.Address{}, nil, err
+	}
+	return account, cross.NewAnchorSigner(accounts.NewManager(&accounts.Config{}, ks), account, nil), nil
+}
+
+// dialContract connects the node of --rpc and binds the cross contract of --contract.
+func dialContract(ctx *cli.Context) (*ethclient.Client, *crossdemo.CrossDemo, error) {
+	address, err := parseAddress(ctx.String(contractFlag.Name))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid contract: %v", err)
+	}
+	client, err := ethclient.Dial(ctx.String(rpcFlag.Name))
+	if err != nil {
+		return nil, nil, err
+	}
+	contract, err := crossdemo.NewCrossDemo(address, client)
+	if err != nil {
+		client.Close()
+		return nil, nil, err
+	}
+	return client, contract, nil
+}
+
+// transactOpts creates the options of transactions signed by the account of command.
+func transactOpts(ctx *cli.Context, client *ethclient.Client) (*bind.TransactOpts, error) {
+	from, signer, err := openSigner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	chainID, err := client.ChainID(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	opts := &bind.TransactOpts{
+		From: from,
+		Signer: func(_ types.Signer, address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != from {
+				return nil, errors.New("not authorized to sign this account")
+			}
+			return signer.SignTx(tx, chainID)
+		},
+		GasLimit: ctx.Uint64(gasLimitFlag.Name),
+	}
+	if price := ctx.String(gasPriceFlag.Name); price != "" {
+		if opts.GasPrice, err = parseBig(price); err != nil {
+			return nil, fmt.Errorf("invalid gas price: %v", err)
+		}
+	}
+	return opts, nil
+}
+
+func parseAddress(s string) (common.Address, error) {
+	if !common.IsHexAddress(s) {
+		return common.Address{}, fmt.Errorf("%q is not a hex address", s)
+	}
+	return common.HexToAddress(s), nil
+}
+
+// parseAddresses parses the comma separated addresses
+func parseAddresses(s string) ([]common.Address, error) {
+	var addrs []common.Address
+	for _, field := range strings.Split(s, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		addr, err := parseAddress(field)
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
+
+// parseBig parses a decimal or 0x prefixed hex integer
+func parseBig(s string) (*big.Int, error) {
+	v, ok := new(big.Int).SetString(s, 0)
+	if !ok || v.Sign() < 0 {
+		return nil, fmt.Errorf("%q is not a non-negative integer", s)
+	}
+	return v, nil
+}
+
+// table is the human-readable form of command results
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+func (t *table) append(row ...string) {
+	t.rows = append(t.rows, row)
+}
+
+// printResult prints the result of command as --output requires, tab is the table form of result.
+func printResult(ctx *cli.Context, result interface{}, tab *table) error {
+	switch format := ctx.String(outputFlag.Name); format {
+	case outputJSON:
+		out, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+	case outputTable:
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(tab.header, "\t"))
+		for _, row := range tab.rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+	return nil
+}
+
+// txResult is a transaction sent to the cross contract
+type txResult struct {
+	Hash  common.Hash    `json:"hash"`
+	From  common.Address `json:"from"`
+	Nonce uint64         `json:"nonce"`
+	CtxID *common.Hash   `json:"ctxId,omitempty"` // the ctx taken by the transaction
+}
+
+func printTxs(ctx *cli.Context, results []*txResult) error {
+	tab := &table{header: []string{"HASH", "FROM", "NONCE", "CTX"}}
+	for _, r := range results {
+		var id string
+		if r.CtxID != nil {
+			id = r.CtxID.String()
+		}
+		tab.append(r.Hash.String(), r.From.String(), fmt.Sprint(r.Nonce), id)
+	}
+	return printResult(ctx, results, tab)
+}
+
+func newTxResult(tx *types.Transaction, from common.Address) *txResult {
+	return &txResult{Hash: tx.Hash(), From: from, Nonce: tx.Nonce()}
+}
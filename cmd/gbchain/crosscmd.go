@@ -0,0 +1,362 @@
+package main
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"gbchain-org/go-gbchain/cmd/utils"
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/core/rawdb"
+	"gbchain-org/go-gbchain/cross"
+	"gbchain-org/go-gbchain/cross/trigger/simpletrigger/retriever"
+	"gbchain-org/go-gbchain/ethdb"
+	"gbchain-org/go-gbchain/log"
+	"gbchain-org/go-gbchain/node"
+
+	cc "gbchain-org/go-gbchain/cross/core"
+	cdb "gbchain-org/go-gbchain/cross/database"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	crossChainFlag = cli.Uint64Flag{
+		Name:  "chain",
+		Usage: "Chain ID of the local chain of exported chain pair",
+	}
+	crossRemoteFlag = cli.Uint64Flag{
+		Name:  "remote",
+		Usage: "Chain ID of the remote chain of exported chain pair",
+	}
+
+	crossCommand = cli.Command{
+		Name:     "cross",
+		Usage:    "Manage cross chain databases",
+		Category: "CROSS CHAIN COMMANDS",
+		Description: `
+Manage the databases of cross chain transactions stored by anchors.`,
+		Subcommands: []cli.Command{
+			{
+				Name:   "migrate-db",
+				Usage:  "Migrate cross transactions from storm into key-value database",
+				Action: utils.MigrateFlags(migrateCrossDB),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+				},
+				Description: `
+    gbchain cross migrate-db
+
+Copies cross transactions of all chains from the storm database (<DATADIR>/crossdata)
+into the key-value database (<DATADIR>/crossindex). Existing transactions in the
+key-value database are kept. Start the node with --anchor.store=kv to use it.`,
+			},
+			{
+				Name:      "export",
+				Usage:     "Export cross transactions of a chain pair into file",
+				ArgsUsage: "<filename>",
+				Action:    utils.MigrateFlags(exportCrossDB),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.AnchorStoreFlag,
+					crossChainFlag,
+					crossRemoteFlag,
+				},
+				Description: `
+    gbchain cross export --chain <id> --remote <id> <filename>
+
+Exports the cross transactions of both directions between the chain pair, including
+the finished ones removed from the store or archived, into a checksummed RLP file.
+The file is gzipped if it ends with .gz.`,
+			},
+			{
+				Name:      "import",
+				Usage:     "Import cross transactions exported by another anchor",
+				ArgsUsage: "<filename>",
+				Action:    utils.MigrateFlags(importCrossDB),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.AnchorStoreFlag,
+					utils.ContractMainFlag,
+					utils.ContractSubFlag,
+				},
+				Description: `
+    gbchain cross import <filename>
+
+Imports the cross transactions exported by "gbchain cross export" to bootstrap a new
+anchor. The checksum of file and the signatures of all the transactions are verified
+before writing, the signers must be the anchors registered in the cross contract of
+destination chain, which is read from the local chain data of main and sub chains.
+Existing transactions are kept.`,
+			},
+		},
+	}
+)
+
+func migrateCrossDB(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	path := stack.ResolvePath(cross.DataDir)
+	if !common.FileExist(path) {
+		utils.Fatalf("Cross database missing: %s", path)
+	}
+	root, err := cdb.OpenStormDB(stack, cross.DataDir)
+	if err != nil {
+		utils.Fatalf("Failed to open storm database: %v", err)
+	}
+	defer root.Close()
+
+	kv, err := cdb.OpenEtherDB(stack, cross.IndexDir)
+	if err != nil {
+		utils.Fatalf("Failed to open key-value database: %v", err)
+	}
+	defer kv.Close()
+
+	chains, err := cdb.StormChains(root)
+	if err != nil {
+		utils.Fatalf("Failed to list chains: %v", err)
+	}
+	for _, chainID := range chains {
+		from := cdb.NewIndexDB(chainID, root, 0)
+		count, err := cdb.MigrateCtxDB(from, cdb.NewKVIndexDB(chainID, kv, 0))
+		if err != nil {
+			utils.Fatalf("Failed to migrate chain %v: %v", chainID, err)
+		}
+		log.Info("Migrated cross transactions", "chainID", chainID, "count", count, "height", from.Height())
+	}
+	return nil
+}
+
+// openCrossStore opens the cross store of node, it returns the CtxDB of chains and the database to close.
+func openCrossStore(stack *node.Node, store string) (func(chainID *big.Int) cdb.CtxDB, io.Closer) {
+	if store == cross.StoreKV {
+		kv, err := cdb.OpenEtherDB(stack, cross.IndexDir)
+		if err != nil {
+			utils.Fatalf("Failed to open key-value database: %v", err)
+		}
+		return func(chainID *big.Int) cdb.CtxDB { return cdb.NewKVIndexDB(chainID, kv, 0) }, kv
+	}
+	root, err := cdb.OpenStormDB(stack, cross.DataDir)
+	if err != nil {
+		utils.Fatalf("Failed to open storm database: %v", err)
+	}
+	return func(chainID *big.Int) cdb.CtxDB { return cdb.NewIndexDB(chainID, root, 0) }, root
+}
+
+func exportCrossDB(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	fn := ctx.Args().First()
+	chainID, remoteID := new(big.Int).SetUint64(ctx.Uint64(crossChainFlag.Name)), new(big.Int).SetUint64(ctx.Uint64(crossRemoteFlag.Name))
+	if chainID.Sign() == 0 || remoteID.Sign() == 0 || chainID.Cmp(remoteID) == 0 {
+		utils.Fatalf("Invalid chain pair %d->%d, set --%s and --%s", chainID, remoteID, crossChainFlag.Name, crossRemoteFlag.Name)
+	}
+	stack, cfg := makeConfigNode(ctx)
+	defer stack.Close()
+
+	stores, db := openCrossStore(stack, cfg.Eth.CrossConfig.Store)
+	defer db.Close()
+
+	logDB, err := cdb.OpenEtherDB(stack, cross.TxLogDir)
+	if err != nil {
+		utils.Fatalf("Failed to open transaction log: %v", err)
+	}
+	defer logDB.Close()
+	logs, err := cdb.NewTransactionLogs(logDB)
+	if err != nil {
+		utils.Fatalf("Failed to load transaction log: %v", err)
+	}
+	archive, err := cdb.OpenCtxArchive(stack, logDB, cross.ArchiveDir)
+	if err != nil {
+		utils.Fatalf("Failed to open archive: %v", err)
+	}
+	defer archive.Close()
+
+	log.Info("Exporting cross transactions", "file", fn, "chainID", chainID, "remoteID", remoteID)
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		utils.Fatalf("Failed to create file: %v", err)
+	}
+	defer fh.Close()
+
+	var writer io.Writer = fh
+	if strings.HasSuffix(fn, ".gz") {
+		writer = gzip.NewWriter(writer)
+		defer writer.(*gzip.Writer).Close()
+	}
+	exporter, err := cdb.NewCtxExporter(writer, chainID, remoteID)
+	if err != nil {
+		utils.Fatalf("Export error: %v", err)
+	}
+	for _, pair := range [][2]*big.Int{{chainID, remoteID}, {remoteID, chainID}} {
+		if err := cdb.ExportCtxs(exporter, stores(pair[0]), logs.Get(pair[0]), archive, pair[1]); err != nil {
+			utils.Fatalf("Failed to export chain %v: %v", pair[0], err)
+		}
+	}
+	if err := exporter.Close(); err != nil {
+		utils.Fatalf("Export error: %v", err)
+	}
+	log.Info("Exported cross transactions", "file", fn, "count", exporter.Count())
+	return nil
+}
+
+// openCrossExport opens the file exported by exportCrossDB
+func openCrossExport(fn string) (*cdb.CtxImporter, io.Closer) {
+	fh, err := os.Open(fn)
+	if err != nil {
+		utils.Fatalf("Failed to open file: %v", err)
+	}
+	var reader io.Reader = fh
+	if strings.HasSuffix(fn, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			fh.Close()
+			utils.Fatalf("Failed to open file: %v", err)
+		}
+	}
+	importer, err := cdb.NewCtxImporter(reader)
+	if err != nil {
+		fh.Close()
+		utils.Fatalf("Import error: %v", err)
+	}
+	return importer, fh
+}
+
+func importCrossDB(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	fn := ctx.Args().First()
+	stack, cfg := makeConfigNode(ctx)
+	defer stack.Close()
+
+	stores, db := openCrossStore(stack, cfg.Eth.CrossConfig.Store)
+	defer db.Close()
+
+	logDB, err := cdb.OpenEtherDB(stack, cross.TxLogDir)
+	if err != nil {
+		utils.Fatalf("Failed to open transaction log: %v", err)
+	}
+	defer logDB.Close()
+	logs, err := cdb.NewTransactionLogs(logDB)
+	if err != nil {
+		utils.Fatalf("Failed to load transaction log: %v", err)
+	}
+
+	// the ctxs are written only if all of them are verified and the checksum matches
+	verifier := newAnchorVerifier(stack, &cfg.Eth.CrossConfig)
+	defer verifier.Close()
+	importer, fh := openCrossExport(fn)
+	defer fh.Close()
+	header := importer.Header
+	log.Info("Importing cross transactions", "file", fn, "chainID", header.ChainID, "remoteID", header.RemoteID)
+	count, err := cdb.ImportCtxs(importer, func(tx *cc.CrossTransactionWithSignatures) error {
+		return verifier.verify(tx, header.ChainID, header.RemoteID)
+	}, stores, logs)
+	if err != nil {
+		utils.Fatalf("Import error: %v", err)
+	}
+	log.Info("Imported cross transactions", "file", fn, "count", count)
+	return nil
+}
+
+// anchorVerifier verifies the signatures of ctxs by the anchors in the cross contracts,
+// which are read from the chain data of main and sub chains of node.
+type anchorVerifier struct {
+	config  *cross.Config
+	chains  map[uint64]anchorChain   // chainID -> chain data
+	anchors map[[2]uint64]anchorList // (source, destination) -> anchors of source in destination
+}
+
+type anchorChain struct {
+	db       ethdb.Database
+	contract common.Address
+}
+
+type anchorList struct {
+	*retriever.AnchorSet
+	required int
+}
+
+func newAnchorVerifier(stack *node.Node, config *cross.Config) *anchorVerifier {
+	v := &anchorVerifier{
+		config:  config,
+		chains:  make(map[uint64]anchorChain),
+		anchors: make(map[[2]uint64]anchorList),
+	}
+	for name, contract := range map[string]common.Address{
+		common.MainchainData: config.MainContract,
+		common.SubchainData:  config.SubContract,
+	} {
+		if !common.FileExist(stack.ResolvePath(name)) {
+			continue
+		}
+		db, err := stack.OpenDatabaseWithFreezer(name, 16, 256, "", "")
+		if err != nil {
+			utils.Fatalf("Failed to open chain data %s: %v", name, err)
+		}
+		chainConfig := rawdb.ReadChainConfig(db, rawdb.ReadCanonicalHash(db, 0))
+		if chainConfig == nil || chainConfig.ChainID == nil {
+			db.Close()
+			continue
+		}
+		v.chains[chainConfig.ChainID.Uint64()] = anchorChain{db: db, contract: contract}
+	}
+	return v
+}
+
+// verify checks ctx is one of chain pair, and it is signed by enough anchors of the
+// source chain registered in the destination chain.
+func (v *anchorVerifier) verify(ctx *cc.CrossTransactionWithSignatures, chainID, remoteID *big.Int) error {
+	source, dest := ctx.ChainId(), ctx.DestinationId()
+	if !(source.Cmp(chainID) == 0 && dest.Cmp(remoteID) == 0) && !(source.Cmp(remoteID) == 0 && dest.Cmp(chainID) == 0) {
+		return fmt.Errorf("ctx %s of %d->%d is not in chain pair %d-%d", ctx.ID().String(), source, dest, chainID, remoteID)
+	}
+	key := [2]uint64{source.Uint64(), dest.Uint64()}
+	anchors, ok := v.anchors[key]
+	if !ok {
+		chain, ok := v.chains[dest.Uint64()]
+		if !ok {
+			return fmt.Errorf("no local chain data of chain %d to read anchors", dest)
+		}
+		list, required, err := retriever.ReadAnchors(chain.db, chain.contract, source.Uint64())
+		if err != nil {
+			return err
+		}
+		anchors = anchorList{AnchorSet: retriever.NewAnchorSet(list), required: required}
+		v.anchors[key] = anchors
+	}
+	if ctx.SignaturesLength() < anchors.required {
+		return fmt.Errorf("invalid signature length of ctx %s: %d, want: %d", ctx.ID().String(), ctx.SignaturesLength(), anchors.required)
+	}
+	signer, err := v.config.CtxSigner(source)
+	if err != nil {
+		return err
+	}
+	// the signatures decoded are not deduplicated, count the distinct anchors only
+	signed := make(map[common.Address]struct{})
+	for i, tx := range ctx.Resolution() {
+		addr, ok := anchors.IsAnchorSignedCtx(tx, signer)
+		if !ok {
+			return fmt.Errorf("invalid signature %d of ctx %s, signer %s is not anchor", i, ctx.ID().String(), addr.String())
+		}
+		if _, ok := signed[addr]; ok {
+			return fmt.Errorf("duplicate signature %d of ctx %s, signer %s", i, ctx.ID().String(), addr.String())
+		}
+		signed[addr] = struct{}{}
+	}
+	if len(signed) < anchors.required {
+		return fmt.Errorf("not enough anchors signed ctx %s: %d, want: %d", ctx.ID().String(), len(signed), anchors.required)
+	}
+	return nil
+}
+
+func (v *anchorVerifier) Close() {
+	for _, chain := range v.chains {
+		chain.db.Close()
+	}
+}
@@ -150,8 +150,17 @@ var (
 		utils.IstanbulBlockPeriodFlag,
 		utils.ConfirmDepthFlag,
 		utils.AnchorSignerFlag,
+		utils.AnchorSignerURLFlag,
 		utils.AnchorMaxGasPriceFlag,
+		utils.AnchorBumpIntervalFlag,
+		utils.AnchorBumpPercentFlag,
+		utils.AnchorExpireFlag,
 		utils.AnchorSyncModeFlag,
+		utils.AnchorReceiptProofFlag,
+		utils.AnchorMessageFlag,
+		utils.AnchorTakersFlag,
+		utils.AnchorStoreFlag,
+		utils.AnchorRemotesFlag,
 	}
 
 	rpcFlags = []cli.Flag{
@@ -227,6 +236,8 @@ func init() {
 		removedbCommand,
 		dumpCommand,
 		inspectCommand,
+		// See crosscmd.go:
+		crossCommand,
 		// See accountcmd.go:
 		accountCommand,
 		walletCommand,
@@ -93,8 +93,17 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.ContractSubFlag,
 			utils.ConfirmDepthFlag,
 			utils.AnchorSignerFlag,
+			utils.AnchorSignerURLFlag,
 			utils.AnchorMaxGasPriceFlag,
+			utils.AnchorBumpIntervalFlag,
+			utils.AnchorBumpPercentFlag,
+			utils.AnchorExpireFlag,
 			utils.AnchorSyncModeFlag,
+			utils.AnchorReceiptProofFlag,
+			utils.AnchorMessageFlag,
+			utils.AnchorTakersFlag,
+			utils.AnchorStoreFlag,
+			utils.AnchorRemotesFlag,
 		},
 	},
 	{
@@ -1,10 +1,15 @@
 package utils
 
 import (
+	"fmt"
+
+	"gbchain-org/go-gbchain/accounts"
+	"gbchain-org/go-gbchain/accounts/external"
 	"gbchain-org/go-gbchain/common"
 	"gbchain-org/go-gbchain/cross"
 	crossBackend "gbchain-org/go-gbchain/cross/backend"
 	crossdb "gbchain-org/go-gbchain/cross/database"
+	"gbchain-org/go-gbchain/cross/trigger/rpctrigger"
 	"gbchain-org/go-gbchain/cross/trigger/simpletrigger"
 	"gbchain-org/go-gbchain/cross/trigger/simpletrigger/executor"
 	"gbchain-org/go-gbchain/cross/trigger/simpletrigger/retriever"
@@ -14,29 +19,56 @@ import (
 	"gbchain-org/go-gbchain/sub"
 )
 
+// RegisterCrossChainService bridges the main chain to the sub chain of this node and to each
+// subchain in cfg.Remotes (set by --anchor.remotes), a handler pair is run for every chain pair.
 func RegisterCrossChainService(stack *node.Node, cfg cross.Config, mainCh chan *eth.Ethereum, subCh chan *sub.Ethereum) {
 	err := stack.Register(func(sc *node.ServiceContext) (node.Service, error) {
 		mainNode := <-mainCh
 		subNode := <-subCh
 		defer close(mainCh)
 		defer close(subCh)
-		mainCtx, err := newCreditChainContext(sc, mainNode, cfg, cfg.MainContract, "mainChain_unconfirmed.rlp", "mainChain_queue")
+		signer, err := newAnchorSigner(sc, cfg)
+		if err != nil {
+			return nil, err
+		}
+		mainCtx, err := newCreditChainContext(sc, mainNode, cfg, signer, cfg.MainContract, "mainChain_queue")
 		if err != nil {
 			return nil, err
 		}
-		subCtx, err := newCreditChainContext(sc, subNode, cfg, cfg.SubContract, "subChain_unconfirmed.rlp", "subChain_queue")
+		subCtx, err := newCreditChainContext(sc, subNode, cfg, signer, cfg.SubContract, "subChain_queue")
 		if err != nil {
 			return nil, err
 		}
-		return crossBackend.NewCrossService(sc, mainCtx, subCtx, cfg)
+		pairs := []cross.ServicePair{{Main: mainCtx, Sub: subCtx}}
+		for _, remote := range cfg.Remotes {
+			remoteCtx, err := newRPCChainContext(sc, cfg, signer, remote)
+			if err != nil {
+				return nil, err
+			}
+			pairs = append(pairs, cross.ServicePair{Main: mainCtx, Sub: remoteCtx})
+		}
+		return crossBackend.NewCrossService(sc, pairs, cfg)
 	})
 	if err != nil {
 		Fatalf("Failed to register the CrossChain service: %v", err)
 	}
 }
 
-func newCreditChainContext(node *node.ServiceContext, chain simpletrigger.GBChain, config cross.Config,
-	contract common.Address, journal string, queue string) (ctx *cross.ServiceContext, err error) {
+// newAnchorSigner returns the signer of anchor, which signs by the external signer at config.SignerURL if it is set
+func newAnchorSigner(node *node.ServiceContext, config cross.Config) (*cross.AnchorSigner, error) {
+	var wallet accounts.Wallet
+	if config.SignerURL != "" {
+		ext, err := external.NewExternalSigner(config.SignerURL)
+		if err != nil {
+			return nil, fmt.Errorf("error connecting to anchor signer: %v", err)
+		}
+		wallet = ext
+	}
+	return cross.NewAnchorSigner(node.AccountManager, config.Signer, wallet), nil
+}
+
+func newCreditChainContext(node *node.ServiceContext, chain simpletrigger.GBChain, config cross.Config, signer *cross.AnchorSigner,
+	contract common.Address, queue string) (ctx *cross.ServiceContext, err error) {
 	edb, err := crossdb.OpenEtherDB(node, queue)
 	if err != nil {
 		return nil, err
@@ -46,12 +78,45 @@ func newCreditChainContext(node *node.ServiceContext, chain simpletrigger.GBChai
 		return nil, err
 	}
 
-	ctx = &cross.ServiceContext{ProtocolChain: simpletrigger.NewSimpleProtocolChain(chain), Config: &config}
-	ctx.Executor, err = executor.NewSimpleExecutor(chain, config.Signer, contract, qdb)
+	confirmer, err := simpletrigger.NewConfirmer(config.ConfirmPolicy(chain.ChainConfig().ChainID), chain.BlockChain(), chain.ChainConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = &cross.ServiceContext{ProtocolChain: simpletrigger.NewSimpleProtocolChain(chain), Config: &config, Contract: contract}
+	ctx.Executor, err = executor.NewSimpleExecutor(chain, config.Signer, signer, contract, qdb,
+		executor.BumpConfig{Interval: config.BumpInterval, Percent: config.BumpPercent})
+	if err != nil {
+		return nil, err
+	}
+	ctx.Retriever = retriever.NewSimpleRetriever(chain.BlockChain(), chain.ProtocolManager(), contract, ctx.Config, chain.ChainConfig(), confirmer)
+	ctx.Subscriber = subscriber.NewSimpleSubscriber(contract, chain.BlockChain(), confirmer, edb)
+	return ctx, nil
+}
+
+func newRPCChainContext(node *node.ServiceContext, config cross.Config, signer *cross.AnchorSigner, remote cross.RemoteChain) (ctx *cross.ServiceContext, err error) {
+	client, err := rpctrigger.Dial(remote.URL)
+	if err != nil {
+		return nil, err
+	}
+	ctx = &cross.ServiceContext{ProtocolChain: rpctrigger.NewProtocolChain(client), Config: &config, Contract: remote.Contract}
+	depth, err := rpctrigger.ConfirmDepth(config.ConfirmPolicy(ctx.ProtocolChain.ChainID()))
+	if err != nil {
+		return nil, err
+	}
+	// the subscriber cursor and pending finish transactions of remote chain
+	edb, err := crossdb.OpenEtherDB(node, fmt.Sprintf("remoteChain%d_queue", ctx.ProtocolChain.ChainID()))
+	if err != nil {
+		return nil, err
+	}
+	ctx.Executor, err = rpctrigger.NewExecutor(client, config.Signer, remote.Contract, signer, edb)
+	if err != nil {
+		return nil, err
+	}
+	ctx.Retriever = rpctrigger.NewRetriever(client, remote.Contract, ctx.Config, depth)
+	ctx.Subscriber, err = rpctrigger.NewSubscriber(client, remote.Contract, depth, edb)
 	if err != nil {
 		return nil, err
 	}
-	ctx.Retriever = retriever.NewSimpleRetriever(chain.BlockChain(), chain.ProtocolManager(), contract, ctx.Config, chain.ChainConfig())
-	ctx.Subscriber = subscriber.NewSimpleSubscriber(contract, chain.BlockChain(), node.ResolvePath(journal))
 	return ctx, nil
 }
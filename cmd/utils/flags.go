@@ -27,6 +27,7 @@ import (
 	"gbchain-org/go-gbchain/core"
 	"gbchain-org/go-gbchain/core/vm"
 	"gbchain-org/go-gbchain/cross"
+	crossBackend "gbchain-org/go-gbchain/cross/backend"
 	"gbchain-org/go-gbchain/cross/backend/synchronise"
 	"gbchain-org/go-gbchain/crypto"
 	"gbchain-org/go-gbchain/eth"
@@ -878,11 +879,50 @@ var (
 		Name:  "anchor.signer",
 		Usage: "public address of anchor signer",
 	}
+	AnchorSignerURLFlag = cli.StringFlag{
+		Name:  "anchor.signer.url",
+		Usage: "external signer (url or path to ipc file) holding the key of anchor signer",
+	}
 	AnchorSyncModeFlag = TextMarshalerFlag{
 		Name:  "anchor.syncmode",
-		Usage: `anchor peer syncmode("all", "store", "pending" or "off")`,
+		Usage: `anchor peer syncmode("all", "store", "pending", "digest" or "off")`,
 		Value: &cross.DefaultConfig.SyncMode,
 	}
+	AnchorReceiptProofFlag = cli.BoolFlag{
+		Name:  "anchor.receiptproof",
+		Usage: "verify maker transactions by receipt proofs besides anchor signatures",
+	}
+	AnchorMessageFlag = cli.BoolFlag{
+		Name:  "anchor.message",
+		Usage: "execute the message carried by cross transactions in the destination chain",
+	}
+	AnchorTakersFlag = cli.StringFlag{
+		Name:  "anchor.takers",
+		Usage: "comma separated accounts (address or keystore index) taking remote cross chain txs by standing orders",
+	}
+	AnchorStoreFlag = cli.StringFlag{
+		Name:  "anchor.store",
+		Usage: `database of cross transactions("storm" or "kv")`,
+		Value: cross.DefaultConfig.Store,
+	}
+	AnchorRemotesFlag = cli.StringFlag{
+		Name:  "anchor.remotes",
+		Usage: "comma separated subchains bridged to main chain by rpc, each as contract@url (websocket or ipc endpoint)",
+	}
+	AnchorBumpIntervalFlag = cli.DurationFlag{
+		Name:  "anchor.bumpinterval",
+		Usage: "anchor replaces the cross chain txs not confirmed in the interval with a higher gasprice",
+		Value: 2 * time.Minute,
+	}
+	AnchorBumpPercentFlag = cli.Uint64Flag{
+		Name:  "anchor.bumppercent",
+		Usage: "percentage of gasprice increased when anchor replaces a cross chain tx",
+		Value: core.DefaultTxPoolConfig.PriceBump,
+	}
+	AnchorExpireFlag = cli.Uint64Flag{
+		Name:  "anchor.expire",
+		Usage: "blocks after which an untaken cross chain tx expires and could be cancelled by its maker (0 = never expire)",
+	}
 	ConfirmDepthFlag = cli.IntFlag{
 		Name:  "anchor.confirmdepth",
 		Usage: "anchor's confirm block depth",
@@ -1760,7 +1800,21 @@ func RegisterGraphQLService(stack *node.Node, endpoint string, cors, vhosts []st
 		// Try to construct the GraphQL service backed by a full node
 		var ethServ *eth.Ethereum
 		if err := ctx.Service(&ethServ); err == nil {
-			return graphql.New(ethServ.APIBackend, endpoint, cors, vhosts, timeouts)
+			service, err := graphql.New(ethServ.APIBackend, endpoint, cors, vhosts, timeouts)
+			if err != nil {
+				return nil, err
+			}
+			// Resolve cross transactions if the node is an anchor
+			var crossServ *crossBackend.CrossService
+			if err := ctx.Service(&crossServ); err == nil {
+				var subServ *sub.Ethereum
+				if err := ctx.Service(&subServ); err == nil {
+					service.RegisterCross(crossServ, subServ.APIBackend)
+				} else {
+					service.RegisterCross(crossServ)
+				}
+			}
+			return service, nil
 		}
 		// Try to construct the GraphQL service backed by a light node
 		var lesServ *les.LightEthereum
@@ -1991,9 +2045,12 @@ func setAnchorSign(ctx *cli.Context, ks *keystore.KeyStore, cfg *eth.Config) {
 	if ctx.GlobalIsSet(AnchorSignerFlag.Name) {
 		anchorSign = ctx.GlobalString(AnchorSignerFlag.Name)
 	}
+	if ctx.GlobalIsSet(AnchorSignerURLFlag.Name) {
+		cfg.CrossConfig.SignerURL = ctx.GlobalString(AnchorSignerURLFlag.Name)
+	}
 	// Convert the etherbase into an address and configure it
 	if anchorSign != "" {
-		if ks != nil {
+		if ks != nil || common.IsHexAddress(anchorSign) {
 			account, err := MakeAddress(ks, anchorSign)
 			if err != nil {
 				Fatalf("Invalid anchorSigner: %v", err)
@@ -2003,10 +2060,59 @@ func setAnchorSign(ctx *cli.Context, ks *keystore.KeyStore, cfg *eth.Config) {
 			Fatalf("No anchorSigner configured")
 		}
 	}
+	if ctx.GlobalIsSet(AnchorTakersFlag.Name) {
+		cfg.CrossConfig.Takers = nil
+		for _, taker := range splitAndTrim(ctx.GlobalString(AnchorTakersFlag.Name)) {
+			if ks == nil && !common.IsHexAddress(taker) {
+				Fatalf("Invalid anchor taker %q, keystore is not available", taker)
+			}
+			account, err := MakeAddress(ks, taker)
+			if err != nil {
+				Fatalf("Invalid anchor taker: %v", err)
+			}
+			cfg.CrossConfig.Takers = append(cfg.CrossConfig.Takers, account.Address)
+		}
+	}
 }
 
 func setAnchorSync(ctx *cli.Context, cfg *eth.Config) {
 	if ctx.GlobalIsSet(AnchorSyncModeFlag.Name) {
 		cfg.CrossConfig.SyncMode = *GlobalTextMarshaler(ctx, AnchorSyncModeFlag.Name).(*synchronise.SyncMode)
 	}
+	if ctx.GlobalIsSet(AnchorReceiptProofFlag.Name) {
+		cfg.CrossConfig.ReceiptProof = ctx.GlobalBool(AnchorReceiptProofFlag.Name)
+	}
+	if ctx.GlobalIsSet(AnchorMessageFlag.Name) {
+		cfg.CrossConfig.Message = ctx.GlobalBool(AnchorMessageFlag.Name)
+	}
+	if ctx.GlobalIsSet(AnchorBumpIntervalFlag.Name) {
+		cfg.CrossConfig.BumpInterval = ctx.GlobalDuration(AnchorBumpIntervalFlag.Name)
+	}
+	if ctx.GlobalIsSet(AnchorBumpPercentFlag.Name) {
+		cfg.CrossConfig.BumpPercent = ctx.GlobalUint64(AnchorBumpPercentFlag.Name)
+	}
+	if ctx.GlobalIsSet(AnchorExpireFlag.Name) {
+		cfg.CrossConfig.Expire = ctx.GlobalUint64(AnchorExpireFlag.Name)
+	}
+	if ctx.GlobalIsSet(AnchorStoreFlag.Name) {
+		switch store := ctx.GlobalString(AnchorStoreFlag.Name); store {
+		case cross.StoreStorm, cross.StoreKV:
+			cfg.CrossConfig.Store = store
+		default:
+			Fatalf("Invalid anchor store %q, want %q or %q", store, cross.StoreStorm, cross.StoreKV)
+		}
+	}
+	if ctx.GlobalIsSet(AnchorRemotesFlag.Name) {
+		cfg.CrossConfig.Remotes = nil
+		for _, remote := range splitAndTrim(ctx.GlobalString(AnchorRemotesFlag.Name)) {
+			parts := strings.SplitN(remote, "@", 2)
+			if len(parts) != 2 || !common.IsHexAddress(parts[0]) || parts[1] == "" {
+				Fatalf("Invalid anchor remote %q, want contract@url", remote)
+			}
+			cfg.CrossConfig.Remotes = append(cfg.CrossConfig.Remotes, cross.RemoteChain{
+				URL:      parts[1],
+				Contract: common.HexToAddress(parts[0]),
+			})
+		}
+	}
 }
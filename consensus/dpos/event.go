@@ -168,6 +168,18 @@ func decodeHeaderExtra(b []byte, val *HeaderExtra) error {
 	return rlp.DecodeBytes(b, val)
 }
 
+// ConfirmedNumber returns the number of the last block confirmed by signers, which is recorded in header
+func ConfirmedNumber(header *types.Header) (uint64, error) {
+	if len(header.Extra) < extraVanity+extraSeal {
+		return 0, errMissingSignature
+	}
+	var extra HeaderExtra
+	if err := decodeHeaderExtra(header.Extra[extraVanity:len(header.Extra)-extraSeal], &extra); err != nil {
+		return 0, err
+	}
+	return extra.ConfirmedBlockNumber, nil
+}
+
 // Calculate Votes from transaction in this block, write into header.Extra
 func (d *DPoS) processTxEvent(headerExtra HeaderExtra, chain consensus.ChainReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, receipts []*types.Receipt) (HeaderExtra, RefundGas, error) {
 	// if predecessor voter make transaction and vote in this block,
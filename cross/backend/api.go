@@ -1,15 +1,25 @@
 package backend
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"math/big"
+	"time"
 
+	"gbchain-org/go-gbchain/accounts/abi"
 	"gbchain-org/go-gbchain/common"
 	"gbchain-org/go-gbchain/common/hexutil"
 	"gbchain-org/go-gbchain/log"
+	"gbchain-org/go-gbchain/params"
 	"gbchain-org/go-gbchain/rlp"
+	"gbchain-org/go-gbchain/rpc"
 
+	"gbchain-org/go-gbchain/cross/backend/synchronise"
 	cc "gbchain-org/go-gbchain/cross/core"
 	cdb "gbchain-org/go-gbchain/cross/database"
+	"gbchain-org/go-gbchain/cross/trigger"
 )
 
 type PrivateCrossAdminAPI struct {
@@ -21,10 +31,11 @@ func NewPrivateCrossAdminAPI(service *CrossService) *PrivateCrossAdminAPI {
 }
 
 func (s *PrivateCrossAdminAPI) Anchors() map[uint64][]common.Address {
-	return map[uint64][]common.Address{
-		s.service.main.chainID: s.service.main.handler.config.Anchors,
-		s.service.sub.chainID:  s.service.sub.handler.config.Anchors,
+	anchors := make(map[uint64][]common.Address, len(s.service.chains))
+	for chainID, chain := range s.service.chains {
+		anchors[chainID] = chain.ctx.Config.Anchors
 	}
+	return anchors
 }
 
 func (s *PrivateCrossAdminAPI) SyncPending() (bool, error) {
@@ -35,9 +46,7 @@ func (s *PrivateCrossAdminAPI) SyncPending() (bool, error) {
 }
 
 func (s *PrivateCrossAdminAPI) SyncStore() (bool, error) {
-	main, sub := s.service.peers.BestPeer()
-	s.service.synchronise(main, sub)
-	return main != nil || sub != nil, nil
+	return s.service.synchronise(), nil
 }
 
 func (s *PrivateCrossAdminAPI) Repair() (bool, error) {
@@ -52,7 +61,7 @@ func (s *PrivateCrossAdminAPI) Repair() (bool, error) {
 	for _, store := range stores {
 		go repair(store)
 	}
-	for i := 0; i < 4; i++ {
+	for i := 0; i < len(stores); i++ {
 		err := <-errsCh
 		if err != nil {
 			errs = append(errs, err)
@@ -65,49 +74,181 @@ func (s *PrivateCrossAdminAPI) Repair() (bool, error) {
 }
 
 func (s *PrivateCrossAdminAPI) Peers() (infos []*CrossPeerInfo, err error) {
-	for _, p := range s.service.peers.peers {
+	for _, p := range s.service.peers.AllPeers() {
 		infos = append(infos, p.Info())
 	}
 	return
 }
 
+// Bans returns the anchor peers banned for misbehaving and the time their bans expire
+func (s *PrivateCrossAdminAPI) Bans() map[string]time.Time {
+	bans := make(map[string]time.Time)
+	for id, expiry := range s.service.bans.list() {
+		bans[id.String()] = expiry
+	}
+	return bans
+}
+
+// RPCEvidence is an evidence of misbehaving anchor, Input is the removeAnchors call of the cross contract
+type RPCEvidence struct {
+	ID       common.Hash    `json:"id"`
+	Kind     string         `json:"kind"`
+	ChainID  *hexutil.Big   `json:"chainId"`
+	Signer   common.Address `json:"signer"`
+	Ctx      hexutil.Bytes  `json:"ctx"`      // rlp encoded signed ctx
+	Conflict hexutil.Bytes  `json:"conflict"` // rlp encoded signed ctx conflicting with Ctx
+	Time     hexutil.Uint64 `json:"time"`
+	Input    hexutil.Bytes  `json:"input"`
+}
+
+// Evidence returns the evidences of misbehaving anchors which sign ctxs of the chain
+func (s *PrivateCrossAdminAPI) Evidence(chainID *hexutil.Big) ([]*RPCEvidence, error) {
+	if _, err := s.service.getChain(chainID.ToInt()); err != nil {
+		return nil, err
+	}
+	evidences, err := s.service.evidences.List(chainID.ToInt())
+	if err != nil {
+		return nil, err
+	}
+	data, err := hexutil.Decode(params.CrossDemoAbi)
+	if err != nil {
+		return nil, err
+	}
+	crossABI, err := abi.JSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*RPCEvidence, 0, len(evidences))
+	for _, ev := range evidences {
+		result := &RPCEvidence{
+			ID:      ev.ID(),
+			Kind:    ev.Kind.String(),
+			ChainID: chainID,
+			Signer:  ev.Signer,
+			Time:    hexutil.Uint64(ev.Time),
+		}
+		if result.Ctx, err = rlp.EncodeToBytes(ev.Ctx); err != nil {
+			return nil, err
+		}
+		if ev.Conflict != nil {
+			if result.Conflict, err = rlp.EncodeToBytes(ev.Conflict); err != nil {
+				return nil, err
+			}
+		}
+		if result.Input, err = ev.ConstructRemoveData(crossABI); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// Height returns the store height of each chain pair, keyed by "local->remote"
 func (s *PrivateCrossAdminAPI) Height() map[string]hexutil.Uint64 {
-	return map[string]hexutil.Uint64{
-		"main": hexutil.Uint64(s.service.main.handler.Height().Uint64()),
-		"sub":  hexutil.Uint64(s.service.sub.handler.Height().Uint64()),
+	heights := make(map[string]hexutil.Uint64, len(s.service.pairs))
+	for _, pair := range s.service.pairs {
+		heights[pair.String()] = hexutil.Uint64(s.service.handlers[pair].Height().Uint64())
 	}
+	return heights
 }
 
 func (s *PrivateCrossAdminAPI) Stats() map[uint64]map[cc.CtxStatus]int {
-	return s.service.main.handler.StoreStats()
+	return s.service.store.Stats()
 }
 
-func (s *PrivateCrossAdminAPI) SetStoreDelay(chainID *hexutil.Big, number hexutil.Uint64) bool {
-	handler := s.service.getCrossHandler(chainID.ToInt())
-	if handler == nil {
-		return false
+func (s *PrivateCrossAdminAPI) SetStoreDelay(chainID *hexutil.Big, number hexutil.Uint64, remoteID *hexutil.Big) (bool, error) {
+	handler, err := s.service.resolveHandler(chainID.ToInt(), remoteID)
+	if err != nil {
+		return false, err
 	}
 	handler.SetStoreDelay(uint64(number))
-	return true
+	return true, nil
 }
 
-func (s *PrivateCrossAdminAPI) Remove(chainID *hexutil.Big, number hexutil.Uint64) bool {
-	handler := s.service.getCrossHandler(chainID.ToInt())
-	if handler == nil {
-		return false
+func (s *PrivateCrossAdminAPI) Remove(chainID *hexutil.Big, number hexutil.Uint64, remoteID *hexutil.Big) (bool, error) {
+	handler, err := s.service.resolveHandler(chainID.ToInt(), remoteID)
+	if err != nil {
+		return false, err
 	}
 	if handler.RemoveCrossTransactionBefore(uint64(number)) == 0 {
-		return false
+		return false, nil
 	}
-	return true
+	return true, nil
+}
+
+// Archive moves the finished ctxs of chain pair before the block number into the archive, it
+// reports whether any ctx is archived.
+func (s *PrivateCrossAdminAPI) Archive(chainID *hexutil.Big, number hexutil.Uint64, remoteID *hexutil.Big) (bool, error) {
+	handler, err := s.service.resolveHandler(chainID.ToInt(), remoteID)
+	if err != nil {
+		return false, err
+	}
+	return handler.ArchiveCrossTransactionBefore(uint64(number)) > 0, nil
+}
+
+// RPCPruneReport is the space reclaimed from store by archiving finished ctxs
+type RPCPruneReport struct {
+	Ctxs      hexutil.Uint64 `json:"ctxs"`      // ctxs archived
+	Size      hexutil.Uint64 `json:"size"`      // bytes of the archived ctxs in store
+	Archived  hexutil.Uint64 `json:"archived"`  // bytes appended to archive
+	Reclaimed hexutil.Uint64 `json:"reclaimed"` // bytes reclaimed by compression
 }
 
-func (s *PrivateCrossAdminAPI) importCtx(local, remote *Handler, ctxWithSignsSArgs hexutil.Bytes) error {
+// PruneReport returns the space reclaimed by archive since it is created
+func (s *PrivateCrossAdminAPI) PruneReport() *RPCPruneReport {
+	stats := s.service.archive.Stats()
+	report := &RPCPruneReport{
+		Ctxs:     hexutil.Uint64(stats.Ctxs),
+		Size:     hexutil.Uint64(stats.Size),
+		Archived: hexutil.Uint64(stats.Archived),
+	}
+	if stats.Size > stats.Archived {
+		report.Reclaimed = hexutil.Uint64(stats.Size - stats.Archived)
+	}
+	return report
+}
+
+// Divergences returns the latest store ranges of each chain pair which are still different
+// from peers after synchronised by digests.
+func (s *PrivateCrossAdminAPI) Divergences() map[string][]synchronise.Divergence {
+	divergences := make(map[string][]synchronise.Divergence)
+	for _, pair := range s.service.pairs {
+		if list := s.service.handlers[pair].synchronise.Divergences(); len(list) > 0 {
+			divergences[pair.String()] = list
+		}
+	}
+	return divergences
+}
+
+// Rescan replays the block logs of chainID from fromBlock, the ctxs missed by store are ingested again
+func (s *PrivateCrossAdminAPI) Rescan(chainID *hexutil.Big, fromBlock hexutil.Uint64) (bool, error) {
+	chain, err := s.service.getChain(chainID.ToInt())
+	if err != nil {
+		return false, err
+	}
+	rescanner, ok := chain.ctx.Subscriber.(trigger.Rescanner)
+	if !ok {
+		return false, errors.New("rescan is not supported")
+	}
+	if err := rescanner.Rescan(uint64(fromBlock)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ImportCtx imports a signed ctx into the store of its chain pair
+func (s *PrivateCrossAdminAPI) ImportCtx(ctxWithSignsSArgs hexutil.Bytes) error {
 	ctx := new(cc.CrossTransactionWithSignatures)
 	if err := rlp.DecodeBytes(ctxWithSignsSArgs, ctx); err != nil {
 		return err
 	}
 
+	local := s.service.getCrossHandler(ctx.ChainId(), ctx.DestinationId())
+	remote := s.service.getCrossHandler(ctx.DestinationId(), ctx.ChainId())
+	if local == nil || remote == nil {
+		return fmt.Errorf("chain pair %d->%d is not registered", ctx.ChainId(), ctx.DestinationId())
+	}
+
 	if ctx.SignaturesLength() < local.retriever.RequireSignatures() {
 		return fmt.Errorf("invalid signture length ctx: %d,want: %d", ctx.SignaturesLength(), local.retriever.RequireSignatures())
 	}
@@ -122,6 +263,11 @@ func (s *PrivateCrossAdminAPI) importCtx(local, remote *Handler, ctxWithSignsSAr
 	if invalidSigIndex != nil {
 		return fmt.Errorf("invalid signature of ctx:%s for signature:%v\n", ctx.ID().String(), invalidSigIndex)
 	}
+	if local.prover != nil {
+		if err := local.proveCtx(ctx.CrossTransaction()); err != nil {
+			return err
+		}
+	}
 	if err := s.service.store.Add(ctx); err != nil {
 		return err
 	}
@@ -129,20 +275,145 @@ func (s *PrivateCrossAdminAPI) importCtx(local, remote *Handler, ctxWithSignsSAr
 	return nil
 }
 
+// ImportMainCtx imports a signed ctx made in the main chain of the default chain pair,
+// which is the first pair registered.
 func (s *PrivateCrossAdminAPI) ImportMainCtx(ctxWithSignsSArgs hexutil.Bytes) error {
-	return s.importCtx(s.service.main.handler, s.service.sub.handler, ctxWithSignsSArgs)
+	return s.importDefaultCtx(0, ctxWithSignsSArgs)
 }
 
+// ImportSubCtx imports a signed ctx made in the sub chain of the default chain pair.
 func (s *PrivateCrossAdminAPI) ImportSubCtx(ctxWithSignsSArgs hexutil.Bytes) error {
-	return s.importCtx(s.service.sub.handler, s.service.main.handler, ctxWithSignsSArgs)
+	return s.importDefaultCtx(1, ctxWithSignsSArgs)
 }
 
+// importDefaultCtx imports the ctx by ImportCtx if it is made in the direction of the default
+// pair at index, the directions of a pair are registered one after the other.
+func (s *PrivateCrossAdminAPI) importDefaultCtx(index int, ctxWithSignsSArgs hexutil.Bytes) error {
+	if len(s.service.pairs) <= index {
+		return errors.New("no chain pair is registered")
+	}
+	ctx := new(cc.CrossTransactionWithSignatures)
+	if err := rlp.DecodeBytes(ctxWithSignsSArgs, ctx); err != nil {
+		return err
+	}
+	if pair := s.service.pairs[index]; cc.NewChainPair(ctx.ChainId(), ctx.DestinationId()) != pair {
+		return fmt.Errorf("ctx of chain pair %d->%d is not in the default pair %s", ctx.ChainId(), ctx.DestinationId(), pair)
+	}
+	return s.ImportCtx(ctxWithSignsSArgs)
+}
+
+// TakerOrderArgs is a standing order of taker account, MaxPrice is the highest price (DestinationValue / Value)
+// accepted, which is a decimal or a fraction like "1.5" or "3/2".
+type TakerOrderArgs struct {
+	Account          common.Address `json:"account"`
+	Token            common.Address `json:"token"`            // token received in remote chain, zero for native coin
+	DestinationToken common.Address `json:"destinationToken"` // token paid in local chain, zero for native coin
+	MaxPrice         string         `json:"maxPrice"`
+	Budget           *hexutil.Big   `json:"budget"`
+}
+
+type RPCTakerOrder struct {
+	ID               hexutil.Uint64 `json:"id"`
+	Account          common.Address `json:"account"`
+	Token            common.Address `json:"token"`
+	DestinationToken common.Address `json:"destinationToken"`
+	MaxPrice         string         `json:"maxPrice"`
+	Budget           *hexutil.Big   `json:"budget"`
+	Spent            *hexutil.Big   `json:"spent"`
+}
+
+func (s *PrivateCrossAdminAPI) taker(chainID, remoteID *hexutil.Big) (*autoTaker, error) {
+	handler, err := s.service.resolveHandler(chainID.ToInt(), remoteID)
+	if err != nil {
+		return nil, err
+	}
+	if handler.taker == nil {
+		return nil, errTakerDisabled
+	}
+	return handler.taker, nil
+}
+
+// AddTakerOrder places a standing order taking the remote ctxs in chainID, the ID of order is returned
+func (s *PrivateCrossAdminAPI) AddTakerOrder(chainID *hexutil.Big, args TakerOrderArgs, remoteID *hexutil.Big) (hexutil.Uint64, error) {
+	taker, err := s.taker(chainID, remoteID)
+	if err != nil {
+		return 0, err
+	}
+	maxPrice, ok := new(big.Rat).SetString(args.MaxPrice)
+	if !ok {
+		return 0, fmt.Errorf("%w: max price %q", errInvalidOrder, args.MaxPrice)
+	}
+	id, err := taker.AddOrder(&TakerOrder{
+		Account:          args.Account,
+		Token:            args.Token,
+		DestinationToken: args.DestinationToken,
+		MaxPrice:         maxPrice,
+		Budget:           args.Budget.ToInt(),
+	})
+	return hexutil.Uint64(id), err
+}
+
+// CancelTakerOrder removes the standing order, the ctxs being taken by it are not affected
+func (s *PrivateCrossAdminAPI) CancelTakerOrder(chainID *hexutil.Big, id hexutil.Uint64, remoteID *hexutil.Big) (bool, error) {
+	taker, err := s.taker(chainID, remoteID)
+	if err != nil {
+		return false, err
+	}
+	if err := taker.CancelOrder(uint64(id)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// TakerOrders returns the standing orders in the order of placing
+func (s *PrivateCrossAdminAPI) TakerOrders(chainID *hexutil.Big, remoteID *hexutil.Big) ([]*RPCTakerOrder, error) {
+	taker, err := s.taker(chainID, remoteID)
+	if err != nil {
+		return nil, err
+	}
+	orders := taker.Orders()
+	results := make([]*RPCTakerOrder, len(orders))
+	for i, order := range orders {
+		results[i] = &RPCTakerOrder{
+			ID:               hexutil.Uint64(order.ID),
+			Account:          order.Account,
+			Token:            order.Token,
+			DestinationToken: order.DestinationToken,
+			MaxPrice:         order.MaxPrice.RatString(),
+			Budget:           (*hexutil.Big)(order.Budget),
+			Spent:            (*hexutil.Big)(order.Spent),
+		}
+	}
+	return results, nil
+}
+
+// TakerBook returns the cheapest remote waiting ctxs matched by the last round of auto taker
+func (s *PrivateCrossAdminAPI) TakerBook(chainID *hexutil.Big, limit int, remoteID *hexutil.Big) ([]*RPCCrossTransaction, error) {
+	taker, err := s.taker(chainID, remoteID)
+	if err != nil {
+		return nil, err
+	}
+	ctxs := taker.Book(limit)
+	results := make([]*RPCCrossTransaction, len(ctxs))
+	for i, ctx := range ctxs {
+		results[i] = newRPCCrossTransaction(ctx)
+	}
+	return results, nil
+}
+
+// PublicCrossChainAPI is registered on each chain, methods select the chain pair
+// by an optional remoteID which could be omitted if the chain has only one pair.
 type PublicCrossChainAPI struct {
-	handler *Handler
+	service *CrossService
+	chainID *big.Int
+}
+
+func NewPublicCrossChainAPI(service *CrossService, chainID uint64) *PublicCrossChainAPI {
+	return &PublicCrossChainAPI{service, new(big.Int).SetUint64(chainID)}
 }
 
-func NewPublicCrossChainAPI(handler *Handler) *PublicCrossChainAPI {
-	return &PublicCrossChainAPI{handler}
+func (s *PublicCrossChainAPI) handler(remoteID *hexutil.Big) (*Handler, error) {
+	return s.service.resolveHandler(s.chainID, remoteID)
 }
 
 type MonitorInfo struct {
@@ -150,13 +421,21 @@ type MonitorInfo struct {
 	Recently map[common.Address]uint32 `json:"recently"`
 }
 
-func (s *PublicCrossChainAPI) Monitor() MonitorInfo {
-	tally, recently := s.handler.monitor.GetInfo()
-	return MonitorInfo{Tally: tally, Recently: recently}
+func (s *PublicCrossChainAPI) Monitor(remoteID *hexutil.Big) (*MonitorInfo, error) {
+	h, err := s.handler(remoteID)
+	if err != nil {
+		return nil, err
+	}
+	tally, recently := h.monitor.GetInfo()
+	return &MonitorInfo{Tally: tally, Recently: recently}, nil
 }
 
-func (s *PublicCrossChainAPI) CtxContentByPage(localSize, localPage, remoteSize, remotePage int) map[string]RPCPageCrossTransactions {
-	locals, remotes, _, _ := s.handler.QueryByPage(localSize, localPage, remoteSize, remotePage)
+func (s *PublicCrossChainAPI) CtxContentByPage(localSize, localPage, remoteSize, remotePage int, remoteID *hexutil.Big) (map[string]RPCPageCrossTransactions, error) {
+	h, err := s.handler(remoteID)
+	if err != nil {
+		return nil, err
+	}
+	locals, remotes, _, _ := h.QueryByPage(localSize, localPage, remoteSize, remotePage)
 	content := map[string]RPCPageCrossTransactions{
 		"local": {
 			Data: make(map[uint64][]*RPCCrossTransaction),
@@ -177,29 +456,212 @@ func (s *PublicCrossChainAPI) CtxContentByPage(localSize, localPage, remoteSize,
 			content["remote"].Data[k] = append(content["remote"].Data[k], newRPCCrossTransaction(tx))
 		}
 	}
-	return content
+	return content, nil
 }
 
-func (s *PublicCrossChainAPI) CtxIllegalByPage(pageSize, startPage int) *RPCPageCrossTransactions {
-	txs := s.handler.QueryLocalIllegalByPage(pageSize, startPage)
+func (s *PublicCrossChainAPI) CtxIllegalByPage(pageSize, startPage int, remoteID *hexutil.Big) (*RPCPageCrossTransactions, error) {
+	h, err := s.handler(remoteID)
+	if err != nil {
+		return nil, err
+	}
+	txs := h.QueryLocalIllegalByPage(pageSize, startPage)
 	list := make([]*RPCCrossTransaction, len(txs))
 	for _, tx := range txs {
 		list = append(list, newRPCCrossTransaction(tx))
 	}
 	return &RPCPageCrossTransactions{
 		Data: map[uint64][]*RPCCrossTransaction{
-			s.handler.remoteID.Uint64(): list,
+			h.remoteID.Uint64(): list,
 		},
 		//Total: total,
+	}, nil
+}
+
+// CtxAggregatedSignature returns the aggregated BLS signature of ctx, which is submitted by takerBLS
+func (s *PublicCrossChainAPI) CtxAggregatedSignature(id common.Hash, remoteID *hexutil.Big) (*cc.AggregatedSignature, error) {
+	h, err := s.handler(remoteID)
+	if err != nil {
+		return nil, err
 	}
+	cws := h.store.Get(h.remoteID, id)
+	if cws == nil {
+		if cws = h.store.Get(h.chainID, id); cws == nil {
+			return nil, fmt.Errorf("ctx %s is not found", id.String())
+		}
+	}
+	maker := s.service.getCrossHandler(cws.ChainId(), cws.DestinationId())
+	if maker == nil {
+		return nil, fmt.Errorf("chain pair %d->%d is not registered", cws.ChainId(), cws.DestinationId())
+	}
+	return maker.pool.AggregatedSignature(cws)
 }
 
-func (s *PublicCrossChainAPI) CtxQuery(hash common.Hash) *RPCCrossTransaction {
-	return newRPCCrossTransaction(s.handler.FindByTxHash(hash))
+// ExecutorStatus returns the transactions submitted by the executor of this chain
+func (s *PublicCrossChainAPI) ExecutorStatus() (*trigger.ExecutorStatus, error) {
+	chain, err := s.service.getChain(s.chainID)
+	if err != nil {
+		return nil, err
+	}
+	reader, ok := chain.ctx.Executor.(trigger.ExecutorStatusReader)
+	if !ok {
+		return nil, errors.New("executor status is not supported")
+	}
+	return reader.Status()
 }
 
-func (s *PublicCrossChainAPI) CtxQueryDestValue(value *hexutil.Big, pageSize, startPage int) *RPCPageCrossTransactions {
-	chainID, txs, _ := s.handler.QueryRemoteByDestinationValueAndPage(value.ToInt(), pageSize, startPage)
+// RPCCancelTransaction is the takerCancel call of ctx, it is sent by the maker to the cross contract of chain
+type RPCCancelTransaction struct {
+	ChainID *hexutil.Big  `json:"chainId"`
+	Input   hexutil.Bytes `json:"input"`
+}
+
+// CtxCancel returns the takerCancel call of an expired ctx made in this chain, the maker sends it
+// to the remote chain so that the ctx could never be taken, then anchors refund the maker.
+func (s *PublicCrossChainAPI) CtxCancel(id common.Hash, remoteID *hexutil.Big) (*RPCCancelTransaction, error) {
+	h, err := s.handler(remoteID)
+	if err != nil {
+		return nil, err
+	}
+	input, err := h.CancelCtx(id)
+	if err != nil {
+		return nil, err
+	}
+	return &RPCCancelTransaction{ChainID: (*hexutil.Big)(h.remoteID), Input: input}, nil
+}
+
+// RPCMessage is the message carried by a ctx and its execution in the destination chain
+type RPCMessage struct {
+	*cc.Message
+	CTxId  common.Hash       `json:"ctxId"`
+	Status cc.CtxStatus      `json:"status"`
+	Result *cc.MessageResult `json:"result"` // nil until the execution is confirmed
+}
+
+// CtxMessage returns the message of ctx and the result of its execution
+func (s *PublicCrossChainAPI) CtxMessage(id common.Hash, remoteID *hexutil.Big) (*RPCMessage, error) {
+	h, err := s.handler(remoteID)
+	if err != nil {
+		return nil, err
+	}
+	cws, _ := h.txLog.GetFinish(id)
+	if cws == nil {
+		if cws = h.GetByCtxID(id); cws == nil {
+			return nil, fmt.Errorf("ctx %s is not found", id.String())
+		}
+	}
+	msg, err := cc.ParseMessage(cws.Data.Input)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.service.messages.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	return &RPCMessage{Message: msg, CTxId: id, Status: cws.Status, Result: result}, nil
+}
+
+// CtxStatusFilter selects the ctxs notified by CtxStatus subscription, empty fields match all ctxs
+type CtxStatusFilter struct {
+	From    []common.Address `json:"from"`    // makers of ctx
+	To      []common.Address `json:"to"`      // takers of ctx
+	ChainID *hexutil.Big     `json:"chainId"` // maker or destination chain of ctx
+	Status  []cc.CtxStatus   `json:"status"`
+}
+
+func (f *CtxStatusFilter) match(ctx *cc.CrossTransactionWithSignatures) bool {
+	if f == nil {
+		return true
+	}
+	if f.ChainID != nil && ctx.ChainId().Cmp(f.ChainID.ToInt()) != 0 && ctx.DestinationId().Cmp(f.ChainID.ToInt()) != 0 {
+		return false
+	}
+	if len(f.From) > 0 && !containsAddress(f.From, ctx.Data.From) {
+		return false
+	}
+	if len(f.To) > 0 && !containsAddress(f.To, ctx.Data.To) {
+		return false
+	}
+	if len(f.Status) > 0 {
+		for _, status := range f.Status {
+			if status == ctx.Status {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func containsAddress(addresses []common.Address, addr common.Address) bool {
+	for _, a := range addresses {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// CtxStatus creates a subscription that fires each time a ctx made in or bridged to this chain
+// is stored or its status is changed, the ctxs are filtered by the optional filter.
+func (s *PublicCrossChainAPI) CtxStatus(ctx context.Context, filter *CtxStatusFilter) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		events := make(chan cc.CtxStatusEvent, 16)
+		sub := s.service.store.SubscribeCtxStatusEvent(events)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-events:
+				for _, tx := range ev.Ctxs {
+					if tx.ChainId().Cmp(s.chainID) != 0 && tx.DestinationId().Cmp(s.chainID) != 0 {
+						continue
+					}
+					if !filter.match(tx) {
+						continue
+					}
+					if err := notifier.Notify(rpcSub.ID, newRPCCrossTransaction(tx)); err != nil {
+						log.Debug("CtxStatus notification failed", "id", rpcSub.ID, "err", err)
+						return
+					}
+				}
+			case err := <-sub.Err():
+				if err != nil {
+					log.Warn("CtxStatus subscription dropped", "id", rpcSub.ID, "err", err)
+				}
+				return
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+func (s *PublicCrossChainAPI) CtxQuery(hash common.Hash, remoteID *hexutil.Big) (*RPCCrossTransaction, error) {
+	h, err := s.handler(remoteID)
+	if err != nil {
+		return nil, err
+	}
+	return newRPCCrossTransaction(h.FindByTxHash(hash)), nil
+}
+
+// CtxQueryDestValue returns the waiting ctxs paying at least value in the destination chain,
+// the ctxs are filtered by the destination token if it is given, zero for native coin.
+func (s *PublicCrossChainAPI) CtxQueryDestValue(value *hexutil.Big, pageSize, startPage int, remoteID *hexutil.Big, token *common.Address) (*RPCPageCrossTransactions, error) {
+	h, err := s.handler(remoteID)
+	if err != nil {
+		return nil, err
+	}
+	chainID, txs, _ := h.QueryRemoteByDestinationValueAndPage(value.ToInt(), token, pageSize, startPage)
 	list := make([]*RPCCrossTransaction, len(txs))
 	for i, tx := range txs {
 		list[i] = newRPCCrossTransaction(tx)
@@ -209,11 +671,15 @@ func (s *PublicCrossChainAPI) CtxQueryDestValue(value *hexutil.Big, pageSize, st
 			chainID: list,
 		},
 		//Total: total,
-	}
+	}, nil
 }
 
-func (s *PublicCrossChainAPI) CtxOwner(from common.Address) map[string]map[uint64][]*RPCOwnerCrossTransaction {
-	locals, _ := s.handler.QueryLocalBySenderAndPage(from, 0, 0)
+func (s *PublicCrossChainAPI) CtxOwner(from common.Address, remoteID *hexutil.Big) (map[string]map[uint64][]*RPCOwnerCrossTransaction, error) {
+	h, err := s.handler(remoteID)
+	if err != nil {
+		return nil, err
+	}
+	locals, _ := h.QueryLocalBySenderAndPage(from, 0, 0)
 	content := map[string]map[uint64][]*RPCOwnerCrossTransaction{
 		"local": make(map[uint64][]*RPCOwnerCrossTransaction),
 	}
@@ -222,12 +688,16 @@ func (s *PublicCrossChainAPI) CtxOwner(from common.Address) map[string]map[uint6
 			content["local"][s] = append(content["local"][s], newOwnerRPCCrossTransaction(tx))
 		}
 	}
-	return content
+	return content, nil
 }
 
-func (s *PublicCrossChainAPI) CtxOwnerByPage(from common.Address, pageSize, startPage int) RPCPageOwnerCrossTransactions {
-	locals, _ := s.handler.QueryLocalBySenderAndPage(from, pageSize, startPage)
-	content := RPCPageOwnerCrossTransactions{
+func (s *PublicCrossChainAPI) CtxOwnerByPage(from common.Address, pageSize, startPage int, remoteID *hexutil.Big) (*RPCPageOwnerCrossTransactions, error) {
+	h, err := s.handler(remoteID)
+	if err != nil {
+		return nil, err
+	}
+	locals, _ := h.QueryLocalBySenderAndPage(from, pageSize, startPage)
+	content := &RPCPageOwnerCrossTransactions{
 		Data: make(map[uint64][]*RPCOwnerCrossTransaction, len(locals)),
 		//Total: total,
 	}
@@ -236,12 +706,16 @@ func (s *PublicCrossChainAPI) CtxOwnerByPage(from common.Address, pageSize, star
 			content.Data[chainID] = append(content.Data[chainID], newOwnerRPCCrossTransaction(tx))
 		}
 	}
-	return content
+	return content, nil
 }
 
-func (s *PublicCrossChainAPI) CtxTakerByPage(to common.Address, pageSize, startPage int) RPCPageOwnerCrossTransactions {
-	locals, _ := s.handler.QueryRemoteByTakerAndPage(to, pageSize, startPage)
-	content := RPCPageOwnerCrossTransactions{
+func (s *PublicCrossChainAPI) CtxTakerByPage(to common.Address, pageSize, startPage int, remoteID *hexutil.Big) (*RPCPageOwnerCrossTransactions, error) {
+	h, err := s.handler(remoteID)
+	if err != nil {
+		return nil, err
+	}
+	locals, _ := h.QueryRemoteByTakerAndPage(to, pageSize, startPage)
+	content := &RPCPageOwnerCrossTransactions{
 		Data: make(map[uint64][]*RPCOwnerCrossTransaction, len(locals)),
 		//Total: total,
 	}
@@ -250,46 +724,60 @@ func (s *PublicCrossChainAPI) CtxTakerByPage(to common.Address, pageSize, startP
 			content.Data[chainID] = append(content.Data[chainID], newOwnerRPCCrossTransaction(tx))
 		}
 	}
-	return content
+	return content, nil
 }
 
-func (s *PublicCrossChainAPI) CtxGet(id common.Hash) *RPCCrossTransaction {
-	ctx, _ := s.handler.txLog.GetFinish(id)
+func (s *PublicCrossChainAPI) CtxGet(id common.Hash, remoteID *hexutil.Big) (*RPCCrossTransaction, error) {
+	h, err := s.handler(remoteID)
+	if err != nil {
+		return nil, err
+	}
+	ctx, _ := h.txLog.GetFinish(id)
 	if ctx == nil {
-		ctx = s.handler.GetByCtxID(id)
+		ctx = h.GetByCtxID(id)
 	}
-	return newRPCCrossTransaction(ctx)
+	return newRPCCrossTransaction(ctx), nil
 }
 
-func (s *PublicCrossChainAPI) CtxGetByNumber(begin, end hexutil.Uint64) map[cc.CtxStatus][]common.Hash {
-	ctxList := s.handler.GetByBlockNumber(uint64(begin), uint64(end))
+func (s *PublicCrossChainAPI) CtxGetByNumber(begin, end hexutil.Uint64, remoteID *hexutil.Big) (map[cc.CtxStatus][]common.Hash, error) {
+	h, err := s.handler(remoteID)
+	if err != nil {
+		return nil, err
+	}
+	ctxList := h.GetByBlockNumber(uint64(begin), uint64(end))
 	result := make(map[cc.CtxStatus][]common.Hash)
 	for _, tx := range ctxList {
 		result[tx.Status] = append(result[tx.Status], tx.ID())
 	}
-	return result
+	return result, nil
 }
 
-func (s *PublicCrossChainAPI) PoolStats() map[string]int {
-	pending, queue := s.handler.PoolStats()
-	return map[string]int{"pending": pending, "queue": queue}
+func (s *PublicCrossChainAPI) PoolStats(remoteID *hexutil.Big) (map[string]int, error) {
+	h, err := s.handler(remoteID)
+	if err != nil {
+		return nil, err
+	}
+	pending, queue := h.PoolStats()
+	return map[string]int{"pending": pending, "queue": queue}, nil
 }
 
 type RPCCrossTransaction struct {
-	Value            *hexutil.Big   `json:"value"`
-	CTxId            common.Hash    `json:"ctxId"`
-	Status           cc.CtxStatus   `json:"status"`
-	TxHash           common.Hash    `json:"txHash"`
-	From             common.Address `json:"from"`
-	To               common.Address `json:"to"`
-	BlockHash        common.Hash    `json:"blockHash"`
-	BlockNumber      hexutil.Uint64 `json:"blockNumber"`
-	DestinationId    *hexutil.Big   `json:"destinationId"`
-	DestinationValue *hexutil.Big   `json:"destinationValue"`
-	Input            hexutil.Bytes  `json:"input"`
-	V                []*hexutil.Big `json:"v"`
-	R                []*hexutil.Big `json:"r"`
-	S                []*hexutil.Big `json:"s"`
+	Value            *hexutil.Big    `json:"value"`
+	CTxId            common.Hash     `json:"ctxId"`
+	Status           cc.CtxStatus    `json:"status"`
+	TxHash           common.Hash     `json:"txHash"`
+	From             common.Address  `json:"from"`
+	To               common.Address  `json:"to"`
+	BlockHash        common.Hash     `json:"blockHash"`
+	BlockNumber      hexutil.Uint64  `json:"blockNumber"`
+	DestinationId    *hexutil.Big    `json:"destinationId"`
+	DestinationValue *hexutil.Big    `json:"destinationValue"`
+	Token            *common.Address `json:"token,omitempty"`
+	DestinationToken *common.Address `json:"destinationToken,omitempty"`
+	Input            hexutil.Bytes   `json:"input"`
+	V                []*hexutil.Big  `json:"v"`
+	R                []*hexutil.Big  `json:"r"`
+	S                []*hexutil.Big  `json:"s"`
 }
 
 // newRPCCrossTransaction returns a transaction that will serialize to the RPC
@@ -311,6 +799,10 @@ func newRPCCrossTransaction(tx *cc.CrossTransactionWithSignatures) *RPCCrossTran
 		DestinationValue: (*hexutil.Big)(tx.Data.DestinationValue),
 		Input:            tx.Data.Input,
 	}
+	if tx.IsToken() {
+		token, destToken := tx.Token(), tx.DestinationToken()
+		result.Token, result.DestinationToken = &token, &destToken
+	}
 	for _, v := range tx.Data.V {
 		result.V = append(result.V, (*hexutil.Big)(v))
 	}
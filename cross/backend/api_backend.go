@@ -1,9 +1,15 @@
 package backend
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"math/big"
 
+	"gbchain-org/go-gbchain/accounts/abi"
 	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/common/hexutil"
+	"gbchain-org/go-gbchain/params"
 
 	cc "gbchain-org/go-gbchain/cross/core"
 	cdb "gbchain-org/go-gbchain/cross/database"
@@ -11,6 +17,8 @@ import (
 	"github.com/asdine/storm/v3/q"
 )
 
+var ErrCtxNotExpired = errors.New("ctx is not expired yet")
+
 func query(db cdb.CtxDB, pageSize, startPage int, orderBy []cdb.FieldName, reverse bool,
 	condition ...q.Matcher) []*cc.CrossTransactionWithSignatures {
 	return db.Query(pageSize, startPage, orderBy, reverse, condition...)
@@ -37,7 +45,13 @@ func (h *Handler) GetByBlockNumber(begin, end uint64) []*cc.CrossTransactionWith
 		return nil
 	}
 	store, _ := h.store.GetStore(h.chainID)
-	return store.RangeByNumber(begin, end, 0)
+	var results []*cc.CrossTransactionWithSignatures
+	for _, ctx := range store.RangeByNumber(begin, end, 0) {
+		if ctx.DestinationId().Cmp(h.remoteID) == 0 {
+			results = append(results, ctx)
+		}
+	}
+	return results
 }
 
 func (h *Handler) FindByTxHash(hash common.Hash) *cc.CrossTransactionWithSignatures {
@@ -52,17 +66,24 @@ func (h *Handler) FindByTxHash(hash common.Hash) *cc.CrossTransactionWithSignatu
 	return nil
 }
 
-func (h *Handler) QueryRemoteByDestinationValueAndPage(value *big.Int, pageSize,
+func (h *Handler) QueryRemoteByDestinationValueAndPage(value *big.Int, token *common.Address, pageSize,
 	startPage int) (remoteID uint64, txs []*cc.CrossTransactionWithSignatures, total int) {
 	if !h.retriever.CanAcceptTxs() {
 		return 0, nil, 0
 	}
 	var (
 		store, _  = h.store.GetStore(h.chainID)
-		condition = []q.Matcher{q.Eq(cdb.StatusField, cc.CtxStatusWaiting), q.Gte(cdb.DestinationValue, value)}
-		orderBy   = []cdb.FieldName{cdb.PriceIndex}
-		reverse   = false
+		condition = []q.Matcher{
+			q.Eq(cdb.StatusField, cc.CtxStatusWaiting),
+			q.Eq(cdb.DestinationId, h.remoteID),
+			q.Gte(cdb.DestinationValue, value),
+		}
+		orderBy = []cdb.FieldName{cdb.PriceIndex}
+		reverse = false
 	)
+	if token != nil {
+		condition = append(condition, q.Eq(cdb.DestinationToken, *token))
+	}
 	txs = query(store, pageSize, startPage, orderBy, reverse, condition...)
 	//total = count(store, condition...)
 	return h.RemoteID(), txs, total
@@ -76,12 +97,14 @@ func (h *Handler) QueryByPage(localSize, localPage, remoteSize, remotePage int)
 	var (
 		localStore, _  = h.store.GetStore(h.chainID)
 		remoteStore, _ = h.store.GetStore(h.remoteID)
-		condition      = []q.Matcher{q.Eq(cdb.StatusField, cc.CtxStatusWaiting)}
+		waiting        = q.Eq(cdb.StatusField, cc.CtxStatusWaiting)
 		orderBy        = []cdb.FieldName{cdb.PriceIndex}
 		reverse        = false
 	)
-	locals = map[uint64][]*cc.CrossTransactionWithSignatures{h.RemoteID(): query(localStore, localSize, localPage, orderBy, reverse, condition...)}
-	remotes = map[uint64][]*cc.CrossTransactionWithSignatures{h.RemoteID(): query(remoteStore, remoteSize, remotePage, orderBy, reverse, condition...)}
+	locals = map[uint64][]*cc.CrossTransactionWithSignatures{h.RemoteID(): query(localStore, localSize, localPage, orderBy, reverse,
+		waiting, q.Eq(cdb.DestinationId, h.remoteID))}
+	remotes = map[uint64][]*cc.CrossTransactionWithSignatures{h.RemoteID(): query(remoteStore, remoteSize, remotePage, orderBy, reverse,
+		waiting, q.Eq(cdb.DestinationId, h.chainID))}
 	//lt := count(localStore, condition...)
 	//rt := count(remoteStore, condition...)
 
@@ -95,7 +118,7 @@ func (h *Handler) QueryLocalIllegalByPage(pageSize, startPage int) []*cc.CrossTr
 
 	var (
 		store, _  = h.store.GetStore(h.chainID)
-		condition = []q.Matcher{q.Eq(cdb.StatusField, cc.CtxStatusIllegal)}
+		condition = []q.Matcher{q.Eq(cdb.StatusField, cc.CtxStatusIllegal), q.Eq(cdb.DestinationId, h.remoteID)}
 		orderBy   = []cdb.FieldName{cdb.BlockNumField}
 		reverse   = false
 	)
@@ -115,7 +138,8 @@ func (h *Handler) QueryLocalBySenderAndPage(from common.Address, pageSize, start
 				q.Eq(cdb.StatusField, cc.CtxStatusWaiting),
 				q.Eq(cdb.StatusField, cc.CtxStatusIllegal),
 			),
-			q.Eq(cdb.FromField, from)}
+			q.Eq(cdb.FromField, from),
+			q.Eq(cdb.DestinationId, h.remoteID)}
 		orderBy = []cdb.FieldName{cdb.PriceIndex}
 		reverse = false
 	)
@@ -140,10 +164,14 @@ func (h *Handler) QueryRemoteByTakerAndPage(to common.Address, pageSize, startPa
 		return nil, 0
 	}
 	var (
-		condition = []q.Matcher{q.Eq(cdb.StatusField, cc.CtxStatusWaiting), q.Eq(cdb.ToField, to)}
-		orderBy   = []cdb.FieldName{cdb.PriceIndex}
-		store, _  = h.store.GetStore(h.remoteID)
-		reverse   = false
+		condition = []q.Matcher{
+			q.Eq(cdb.StatusField, cc.CtxStatusWaiting),
+			q.Eq(cdb.ToField, to),
+			q.Eq(cdb.DestinationId, h.chainID),
+		}
+		orderBy  = []cdb.FieldName{cdb.PriceIndex}
+		store, _ = h.store.GetStore(h.remoteID)
+		reverse  = false
 	)
 
 	txs := query(store, pageSize, startPage, orderBy, reverse, condition...)
@@ -173,3 +201,32 @@ func (h *Handler) StoreStats() map[uint64]map[cc.CtxStatus]int {
 	}
 	return h.store.Stats()
 }
+
+// CancelCtx packs the takerCancel call of a local ctx, the maker sends it to the cross contract
+// of remote chain, and then anchors refund the maker after the cancel is confirmed.
+// Only expired ctxs could be cancelled, illegal ctxs are cancelled at any time since they
+// could not be taken by others any more, but anchors refund them after they expire as well.
+func (h *Handler) CancelCtx(id common.Hash) ([]byte, error) {
+	cws := h.store.Get(h.chainID, id)
+	if cws == nil || cws.DestinationId().Cmp(h.remoteID) != 0 {
+		return nil, fmt.Errorf("ctx %s is not found", id.String())
+	}
+	switch cws.Status {
+	case cc.CtxStatusIllegal:
+	case cc.CtxStatusWaiting:
+		if h.retriever.VerifyExpire(cws.CrossTransaction()) == nil {
+			return nil, ErrCtxNotExpired
+		}
+	default:
+		return nil, fmt.Errorf("ctx %s could not be cancelled in status %s", id.String(), cws.Status)
+	}
+	data, err := hexutil.Decode(params.CrossDemoAbi)
+	if err != nil {
+		return nil, err
+	}
+	crossABI, err := abi.JSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return cws.ConstructCancelData(crossABI)
+}
@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"math/big"
 	"sync"
+	"time"
 
 	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/common/hexutil"
+	"gbchain-org/go-gbchain/common/mclock"
 	"gbchain-org/go-gbchain/log"
 	"gbchain-org/go-gbchain/node"
 	"gbchain-org/go-gbchain/p2p"
@@ -19,39 +22,59 @@ import (
 	cc "gbchain-org/go-gbchain/cross/core"
 	cdb "gbchain-org/go-gbchain/cross/database"
 	cm "gbchain-org/go-gbchain/cross/metric"
+	"gbchain-org/go-gbchain/cross/trigger"
 )
 
 // CrossService implements node.Service
 type CrossService struct {
-	store  *CrossStore
-	txLogs *cdb.TransactionLogs
-
-	config cross.Config
-	peers  *anchorSet
-
-	main crossCommons
-	sub  crossCommons
+	store     *CrossStore
+	txLogs    *cdb.TransactionLogs
+	archive   *cdb.CtxArchive    // finished ctxs moved out of store, read as the ones of txLogs
+	evidences *cdb.EvidenceStore // evidences of misbehaving anchors, kept in the database of txLogs
+	messages  *cdb.MessageStore  // execution results of message ctxs, kept in the database of txLogs
+	takerDB   *cdb.TakerStore    // orders of auto takers, kept in the database of txLogs
+	takerTxs  *cdb.TakerTxStore  // transactions taking the ctxs, kept in the database of txLogs
+
+	config  cross.Config
+	peers   *anchorSet
+	fetcher *ctxFetcher // ctxs served to and fetched from cross3 peers
+	bans    *peerBans   // peers banned for misbehaving
+	changes *anchorChanges
+
+	chains   map[uint64]*crossChain    // chainID -> chain served by the anchor
+	handlers map[cc.ChainPair]*Handler // (local, remote) -> handler of the pair
+	pairs    []cc.ChainPair            // registered chain pairs in order
+
+	takers map[common.Address]trigger.Signer // signers of the taker accounts in keystore, no auto taker if empty
 
 	newPeerCh chan *anchorPeer
 	quitSync  chan struct{}
 	wg        sync.WaitGroup
 }
 
-type crossCommons struct {
-	genesis common.Hash
-	chainID uint64
-
-	handler *Handler
-	channel chan interface{}
+type crossChain struct {
+	genesis  common.Hash
+	chainID  uint64
+	contract common.Address
+	ctx      *cross.ServiceContext
 }
 
-func NewCrossService(ctx *node.ServiceContext, main, sub *cross.ServiceContext, config cross.Config) (srv *CrossService, err error) {
+func NewCrossService(ctx *node.ServiceContext, pairs []cross.ServicePair, config cross.Config) (srv *CrossService, err error) {
 	srv = &CrossService{
 		config:    config,
 		peers:     newAnchorSet(),
+		fetcher:   newCtxFetcher(),
+		bans:      newPeerBans(mclock.System{}),
+		changes:   newAnchorChanges(mclock.System{}),
+		chains:    make(map[uint64]*crossChain),
+		handlers:  make(map[cc.ChainPair]*Handler),
+		takers:    make(map[common.Address]trigger.Signer, len(config.Takers)),
 		newPeerCh: make(chan *anchorPeer),
 		quitSync:  make(chan struct{}),
 	}
+	for _, taker := range config.Takers {
+		srv.takers[taker] = cross.NewAnchorSigner(ctx.AccountManager, taker, nil)
+	}
 
 	cm.Reporter.SetRootPath(ctx.ResolvePath(cross.LogDir))
 
@@ -63,99 +86,222 @@ func NewCrossService(ctx *node.ServiceContext, main, sub *cross.ServiceContext,
 	if err != nil {
 		return nil, err
 	}
-
-	srv.store, err = NewCrossStore(ctx, cross.DataDir)
+	if srv.archive, err = cdb.OpenCtxArchive(ctx, logDB, cross.ArchiveDir); err != nil {
+		return nil, err
+	}
+	srv.txLogs.SetArchive(srv.archive)
+	srv.evidences = cdb.NewEvidenceStore(logDB)
+	srv.messages = cdb.NewMessageStore(logDB)
+	srv.takerDB = cdb.NewTakerStore(logDB)
+	srv.takerTxs = cdb.NewTakerTxStore(logDB)
+
+	if config.Store == cross.StoreKV {
+		srv.store, err = NewKVCrossStore(ctx, cross.IndexDir)
+	} else {
+		srv.store, err = NewCrossStore(ctx, cross.DataDir)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	mainCh, subCh := make(chan interface{}, defaultCrossChSize), make(chan interface{}, defaultCrossChSize)
+	for _, pair := range pairs {
+		if err := srv.registerPair(pair.Main, pair.Sub); err != nil {
+			return nil, err
+		}
+	}
 
-	mainHandler, err := NewCrossHandler(main, srv, mainCh, subCh)
-	if err != nil {
-		return nil, err
+	// register public cross apis for each chain
+	for _, chain := range srv.chains {
+		chain.ctx.ProtocolChain.RegisterAPIs([]rpc.API{
+			{
+				Namespace: "cross",
+				Version:   "1.0",
+				Service:   NewPublicCrossChainAPI(srv, chain.chainID),
+				Public:    true,
+			},
+		})
 	}
 
-	subHandler, err := NewCrossHandler(sub, srv, subCh, mainCh)
+	return srv, nil
+}
+
+// registerPair creates handlers for both directions of the chain pair
+func (srv *CrossService) registerPair(main, sub *cross.ServiceContext) error {
+	mainChain, err := srv.registerChain(main)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	subChain, err := srv.registerChain(sub)
+	if err != nil {
+		return err
+	}
+	if mainChain.chainID == subChain.chainID {
+		return fmt.Errorf("invalid chain pair, chainID %d is bridged to itself", mainChain.chainID)
 	}
 
-	mainHandler.RegisterChain(sub.ProtocolChain.ChainID())
-	subHandler.RegisterChain(main.ProtocolChain.ChainID())
+	pair := cc.ChainPair{Local: mainChain.chainID, Remote: subChain.chainID}
+	if _, ok := srv.handlers[pair]; ok {
+		return fmt.Errorf("chain pair %s is already registered", pair)
+	}
 
-	main.ProtocolChain.RegisterAPIs([]rpc.API{
-		{
-			Namespace: "cross",
-			Version:   "1.0",
-			Service:   NewPublicCrossChainAPI(mainHandler),
-			Public:    true,
-		},
-	})
-	sub.ProtocolChain.RegisterAPIs([]rpc.API{
-		{
-			Namespace: "cross",
-			Version:   "1.0",
-			Service:   NewPublicCrossChainAPI(subHandler),
-			Public:    true,
-		},
-	})
+	mainCh, subCh := make(chan interface{}, defaultCrossChSize), make(chan interface{}, defaultCrossChSize)
 
-	srv.main = crossCommons{
-		genesis: main.ProtocolChain.GenesisHash(),
-		chainID: main.ProtocolChain.ChainID().Uint64(),
-		handler: mainHandler,
-		channel: mainCh,
+	mainHandler, err := NewCrossHandler(main, srv, sub.ProtocolChain.ChainID(), mainCh, subCh)
+	if err != nil {
+		return err
+	}
+	subHandler, err := NewCrossHandler(sub, srv, main.ProtocolChain.ChainID(), subCh, mainCh)
+	if err != nil {
+		return err
 	}
 
-	srv.sub = crossCommons{
-		genesis: sub.ProtocolChain.GenesisHash(),
-		chainID: sub.ProtocolChain.ChainID().Uint64(),
-		handler: subHandler,
-		channel: subCh,
+	srv.handlers[pair] = mainHandler
+	srv.handlers[pair.Reverse()] = subHandler
+	srv.pairs = append(srv.pairs, pair, pair.Reverse())
+	return nil
+}
+
+func (srv *CrossService) registerChain(ctx *cross.ServiceContext) (*crossChain, error) {
+	chainID := ctx.ProtocolChain.ChainID().Uint64()
+	if chain, ok := srv.chains[chainID]; ok {
+		if chain.ctx != ctx {
+			return nil, fmt.Errorf("chain %d is registered with different service context", chainID)
+		}
+		return chain, nil
+	}
+	chain := &crossChain{
+		genesis:  ctx.ProtocolChain.GenesisHash(),
+		chainID:  chainID,
+		contract: ctx.Contract,
+		ctx:      ctx,
 	}
+	srv.chains[chainID] = chain
+	return chain, nil
+}
 
-	return srv, nil
+// ChainPairs returns the registered chain pairs
+func (srv *CrossService) ChainPairs() []cc.ChainPair {
+	pairs := make([]cc.ChainPair, len(srv.pairs))
+	copy(pairs, srv.pairs)
+	return pairs
 }
 
-func (srv *CrossService) getCrossHandler(chainID *big.Int) *Handler {
+func (srv *CrossService) getChain(chainID *big.Int) (*crossChain, error) {
 	if chainID == nil {
+		return nil, ErrInvalidChainStore
+	}
+	chain, ok := srv.chains[chainID.Uint64()]
+	if !ok {
+		return nil, fmt.Errorf("chain %d is not registered", chainID)
+	}
+	return chain, nil
+}
+
+// CtxStore returns the store of ctxs made in chainID
+func (srv *CrossService) CtxStore(chainID *big.Int) (cdb.CtxDB, error) {
+	if _, err := srv.getChain(chainID); err != nil {
+		return nil, err
+	}
+	return srv.store.GetStore(chainID)
+}
+
+// Contract returns the cross contract of chainID
+func (srv *CrossService) Contract(chainID *big.Int) (common.Address, error) {
+	chain, err := srv.getChain(chainID)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return chain.contract, nil
+}
+
+// Anchors returns the configured anchors of chainID
+func (srv *CrossService) Anchors(chainID *big.Int) []common.Address {
+	chain, err := srv.getChain(chainID)
+	if err != nil {
+		return nil
+	}
+	return chain.ctx.Config.Anchors
+}
+
+// TakerTx returns the transaction taking the ctx in its destination chain, false if the ctx
+// is not taken or the taker is not seen by the service
+func (srv *CrossService) TakerTx(ctxID common.Hash) (common.Hash, bool) {
+	return srv.takerTxs.Get(ctxID)
+}
+
+func (srv *CrossService) getCrossHandler(chainID, remoteID *big.Int) *Handler {
+	if chainID == nil || remoteID == nil {
 		return nil
 	}
-	if chainID.Uint64() == srv.main.chainID {
-		return srv.main.handler
+	return srv.handlers[cc.NewChainPair(chainID, remoteID)]
+}
+
+// getChainHandlers returns handlers whose local chain is chainID
+func (srv *CrossService) getChainHandlers(chainID uint64) []*Handler {
+	var handlers []*Handler
+	for _, pair := range srv.pairs {
+		if pair.Local == chainID {
+			handlers = append(handlers, srv.handlers[pair])
+		}
+	}
+	return handlers
+}
+
+// resolveHandler finds the handler of the local chain bridged to the remote one,
+// remote could be omitted if the local chain is bridged to only one chain.
+func (srv *CrossService) resolveHandler(local *big.Int, remote *hexutil.Big) (*Handler, error) {
+	if local == nil {
+		return nil, ErrInvalidChainStore
+	}
+	if remote != nil {
+		if h := srv.getCrossHandler(local, remote.ToInt()); h != nil {
+			return h, nil
+		}
+		return nil, fmt.Errorf("chain pair %d->%d is not registered", local, remote.ToInt())
 	}
-	if chainID.Uint64() == srv.sub.chainID {
-		return srv.sub.handler
+	handlers := srv.getChainHandlers(local.Uint64())
+	switch len(handlers) {
+	case 0:
+		return nil, fmt.Errorf("chain %d is not registered", local)
+	case 1:
+		return handlers[0], nil
+	default:
+		return nil, fmt.Errorf("chain %d is bridged to %d chains, remote chain is required", local, len(handlers))
 	}
-	return nil
 }
 
+// Protocols returns the anchor protocol of each supported version, the highest
+// version supported by both sides is chosen when anchors connect.
 func (srv *CrossService) Protocols() []p2p.Protocol {
-	return []p2p.Protocol{{
-		Name:    "cross",
-		Version: protocolVersion,
-		Length:  protocolMaxMsgSize,
-		Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
-			anchor := newAnchorPeer(p, rw)
-			srv.wg.Add(1)
-			defer srv.wg.Done()
-			return srv.handle(anchor)
-		},
-		NodeInfo: func() interface{} {
-			return srv.NodeInfo()
-		},
-		PeerInfo: func(id enode.ID) interface{} {
-			if p := srv.peers.Peer(fmt.Sprintf("%x", id[:8])); p != nil {
-				return p.Info()
-			}
-			return nil
-		},
-	}}
+	protocols := make([]p2p.Protocol, 0, len(protocolVersions))
+	for _, version := range protocolVersions {
+		version := version // Closure for the run
+		protocols = append(protocols, p2p.Protocol{
+			Name:    "cross",
+			Version: version,
+			Length:  protocolMaxMsgSize,
+			Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+				anchor := newAnchorPeer(int(version), p, rw)
+				srv.wg.Add(1)
+				defer srv.wg.Done()
+				return srv.handle(anchor)
+			},
+			NodeInfo: func() interface{} {
+				return srv.NodeInfo()
+			},
+			PeerInfo: func(id enode.ID) interface{} {
+				if p := srv.peers.Peer(fmt.Sprintf("%x", id[:8])); p != nil {
+					return p.Info()
+				}
+				return nil
+			},
+		})
+	}
+	return protocols
 }
 
 func (srv *CrossService) APIs() []rpc.API {
-	return []rpc.API{
+	apis := []rpc.API{
 		{
 			Namespace: "cross",
 			Version:   "1.0",
@@ -163,18 +309,39 @@ func (srv *CrossService) APIs() []rpc.API {
 			Public:    false,
 		},
 	}
+	// the chains run by separate nodes couldn't serve their apis, they are served here
+	served := make(map[uint64]bool)
+	for _, pair := range srv.pairs {
+		if served[pair.Local] {
+			continue
+		}
+		served[pair.Local] = true
+		if remote, ok := srv.chains[pair.Local].ctx.ProtocolChain.(interface{ APIs() []rpc.API }); ok {
+			apis = append(apis, remote.APIs()...)
+		}
+	}
+	return apis
 }
 
 func (srv *CrossService) Start(server *p2p.Server) error {
-	if srv.main.handler == nil {
-		return errors.New("main handler is not exist")
+	if len(srv.handlers) == 0 {
+		return errors.New("no chain pair is registered")
 	}
-	srv.main.handler.Start()
-
-	if srv.sub.handler == nil {
-		return errors.New("sub handler is not exist")
+	// executor is shared by handlers of the same local chain, start it once
+	for _, chain := range srv.chains {
+		chain.ctx.Executor.Start()
+	}
+	for _, pair := range srv.pairs {
+		srv.handlers[pair].Start()
+	}
+	// replay the blocks handled by nobody while node is stopped
+	for _, chain := range srv.chains {
+		if rescanner, ok := chain.ctx.Subscriber.(trigger.Rescanner); ok {
+			if err := rescanner.Resume(); err != nil {
+				log.Warn("Failed to resume cross subscriber", "chainID", chain.chainID, "error", err)
+			}
+		}
 	}
-	srv.sub.handler.Start()
 
 	// start sync handlers
 	go srv.sync()
@@ -183,28 +350,52 @@ func (srv *CrossService) Start(server *p2p.Server) error {
 
 func (srv *CrossService) Stop() error {
 	log.Info("Stopping CrossChain Service")
-	srv.main.handler.Stop()
-	srv.sub.handler.Stop()
+	for _, pair := range srv.pairs {
+		srv.handlers[pair].Stop()
+	}
+	//先停止executor，再停store
+	for _, chain := range srv.chains {
+		chain.ctx.Executor.Stop()
+	}
+	srv.store.Close()
 	close(srv.quitSync)
 	srv.peers.Close()
 	srv.wg.Wait()
+	if err := srv.archive.Close(); err != nil {
+		log.Warn("close archive failed", "error", err)
+	}
 	srv.txLogs.Close()
 	log.Info("CrossChain Service Stopped")
 	return nil
 }
 
+// status returns the served chains and pairs for anchor handshake
+func (srv *CrossService) status() ([]crossChainStatus, []crossPairStatus) {
+	chains := make([]crossChainStatus, 0, len(srv.chains))
+	for _, chain := range srv.chains {
+		chains = append(chains, crossChainStatus{
+			ChainID:  chain.chainID,
+			Genesis:  chain.genesis,
+			Contract: chain.contract,
+		})
+	}
+	pairs := make([]crossPairStatus, 0, len(srv.pairs))
+	for _, pair := range srv.pairs {
+		pairs = append(pairs, crossPairStatus{
+			Pair:   pair,
+			Height: srv.handlers[pair].Height(),
+		})
+	}
+	return chains, pairs
+}
+
 func (srv *CrossService) handle(p *anchorPeer) error {
-	var (
-		mainNetworkID = srv.main.chainID
-		subNetworkID  = srv.sub.chainID
-		mainGenesis   = srv.main.genesis
-		subGenesis    = srv.sub.genesis
-		mainHeight    = srv.main.handler.Height()
-		subHeight     = srv.sub.handler.Height()
-		main          = srv.config.MainContract
-		sub           = srv.config.SubContract
-	)
-	if err := p.Handshake(mainNetworkID, subNetworkID, mainGenesis, subGenesis, mainHeight, subHeight, main, sub); err != nil {
+	if srv.bans.banned(p.ID()) {
+		p.Log().Debug("Rejecting banned anchor peer")
+		return p2p.DiscUselessPeer
+	}
+	chains, pairs := srv.status()
+	if err := p.Handshake(chains, pairs); err != nil {
 		p.Log().Debug("anchor handshake failed", "err", err)
 		return err
 	}
@@ -216,11 +407,13 @@ func (srv *CrossService) handle(p *anchorPeer) error {
 	}
 	defer srv.removePeer(p.id)
 
-	if err := srv.main.handler.synchronise.RegisterPeer(p.id, p); err != nil {
-		return err
-	}
-	if err := srv.sub.handler.synchronise.RegisterPeer(p.id, p); err != nil {
-		return err
+	for _, pair := range srv.pairs {
+		if !p.SupportPair(pair) {
+			continue
+		}
+		if err := srv.handlers[pair].synchronise.RegisterPeer(p.id, p); err != nil {
+			return err
+		}
 	}
 
 	select {
@@ -234,6 +427,11 @@ func (srv *CrossService) handle(p *anchorPeer) error {
 		if err := srv.handleMsg(p); err != nil {
 			return err
 		}
+		if p.score.misbehaving() {
+			p.Log().Warn("Banning misbehaving anchor peer", "score", p.score.info())
+			srv.bans.ban(p.ID())
+			return errResp(ErrMisbehaving, "score %d", p.score.info().Score)
+		}
 	}
 }
 
@@ -254,13 +452,16 @@ func (srv *CrossService) handleMsg(p *anchorPeer) error {
 		return errResp(ErrExtraStatusMsg, "uncontrolled status message")
 
 	case msg.Code == GetCtxSyncMsg:
+		if !p.score.allowRequest() {
+			break
+		}
 		var req synchronise.SyncReq
 		if err := msg.Decode(&req); err != nil {
 			return errResp(ErrDecode, "msg %v: %v", msg, err)
 		}
-		p.Log().Info("receive ctx sync request", "chain", req.Chain, "height", req.Height)
+		p.Log().Info("receive ctx sync request", "chain", req.Chain, "remote", req.Remote, "height", req.Height)
 
-		h := srv.getCrossHandler(new(big.Int).SetUint64(req.Chain))
+		h := srv.getCrossHandler(new(big.Int).SetUint64(req.Chain), new(big.Int).SetUint64(req.Remote))
 		if h == nil {
 			break
 		}
@@ -275,16 +476,20 @@ func (srv *CrossService) handleMsg(p *anchorPeer) error {
 			data = append(data, b)
 		}
 
-		return p.SendSyncResponse(req.Chain, data)
+		return p.SendSyncResponse(req.Chain, req.Remote, data)
 
 	case msg.Code == CtxSyncMsg:
 		var resp synchronise.SyncResp
 		if err := msg.Decode(&resp); err != nil {
 			return errResp(ErrDecode, "msg %v: %v", msg, err)
 		}
-		p.Log().Debug("receive ctx sync response", "chain", resp.Chain, "len(data)", len(resp.Data))
+		p.Log().Debug("receive ctx sync response", "chain", resp.Chain, "remote", resp.Remote, "len(data)", len(resp.Data))
+		if len(resp.Data) > defaultMaxSyncSize {
+			p.score.oversizedResponse()
+			break
+		}
 
-		h := srv.getCrossHandler(new(big.Int).SetUint64(resp.Chain))
+		h := srv.getCrossHandler(new(big.Int).SetUint64(resp.Chain), new(big.Int).SetUint64(resp.Remote))
 		if h == nil /*|| atomic.LoadUint32(&h.synchronising) == 0*/ { // ignore if handler isn't synchronising
 			break
 		}
@@ -303,12 +508,15 @@ func (srv *CrossService) handleMsg(p *anchorPeer) error {
 		}
 
 	case msg.Code == GetPendingSyncMsg:
+		if !p.score.allowRequest() {
+			break
+		}
 		var req synchronise.SyncPendingReq
 		if err := msg.Decode(&req); err != nil {
 			return errResp(ErrDecode, "msg %v: %v", msg, err)
 		}
 
-		h := srv.getCrossHandler(new(big.Int).SetUint64(req.Chain))
+		h := srv.getCrossHandler(new(big.Int).SetUint64(req.Chain), new(big.Int).SetUint64(req.Remote))
 		if h == nil {
 			break
 		}
@@ -322,16 +530,20 @@ func (srv *CrossService) handleMsg(p *anchorPeer) error {
 			}
 			data = append(data, b)
 		}
-		return p.SendSyncPendingResponse(req.Chain, data)
+		return p.SendSyncPendingResponse(req.Chain, req.Remote, data)
 
 	case msg.Code == PendingSyncMsg:
 		var resp synchronise.SyncPendingResp
 		if err := msg.Decode(&resp); err != nil {
 			return errResp(ErrDecode, "msg %v: %v", msg, err)
 		}
-		p.Log().Debug("receive pending sync response", "chain", resp.Chain, "len(data)", len(resp.Data))
+		p.Log().Debug("receive pending sync response", "chain", resp.Chain, "remote", resp.Remote, "len(data)", len(resp.Data))
+		if len(resp.Data) > defaultMaxSyncSize {
+			p.score.oversizedResponse()
+			break
+		}
 
-		h := srv.getCrossHandler(new(big.Int).SetUint64(resp.Chain))
+		h := srv.getCrossHandler(new(big.Int).SetUint64(resp.Chain), new(big.Int).SetUint64(resp.Remote))
 		if h == nil {
 			break
 		}
@@ -350,22 +562,103 @@ func (srv *CrossService) handleMsg(p *anchorPeer) error {
 		}
 
 	case msg.Code == CtxSignMsg:
+		if !p.score.allowCtx() {
+			break
+		}
 		var ctx *cc.CrossTransaction
 		if err := msg.Decode(&ctx); err != nil {
 			return errResp(ErrDecode, "msg %v: %v", msg, err)
 		}
-		p.MarkCrossTransaction(ctx.SignHash())
+		srv.handleRemoteCtx(p, ctx, nil, false)
+
+	case msg.Code == CtxProofMsg:
+		if !p.score.allowCtx() {
+			break
+		}
+		var packet ctxProofPacket
+		if err := msg.Decode(&packet); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		if packet.Ctx == nil {
+			return errResp(ErrDecode, "msg %v: nil ctx", msg)
+		}
+		srv.handleRemoteCtx(p, packet.Ctx, packet.Proof, false)
+
+	case p.version >= cross3 && msg.Code == NewCtxHashesMsg:
+		if !p.score.allowCtx() {
+			break
+		}
+		var hashes []common.Hash
+		if err := msg.Decode(&hashes); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		for _, hash := range hashes {
+			p.MarkCrossTransaction(hash)
+		}
+		// request the unknown ctxs from the announcer
+		if err := requestCtxs(p, srv.fetcher.announce(p.id, hashes, time.Now())); err != nil {
+			return err
+		}
+
+	case p.version >= cross3 && msg.Code == GetCtxsMsg:
+		if !p.score.allowRequest() {
+			break
+		}
+		var hashes []common.Hash
+		if err := msg.Decode(&hashes); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		return p.SendCtxs(srv.fetcher.get(hashes))
+
+	case p.version >= cross3 && msg.Code == CtxsMsg:
+		var packets []*ctxProofPacket
+		if err := msg.Decode(&packets); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		if len(packets) > maxCtxFetch {
+			p.score.oversizedResponse()
+			break
+		}
+		for i, packet := range packets {
+			if packet == nil || packet.Ctx == nil {
+				return errResp(ErrDecode, "msg %v: nil ctx %d", msg, i)
+			}
+			requested := srv.fetcher.delivered(p.id, packet.Ctx.SignHash())
+			srv.handleRemoteCtx(p, packet.Ctx, packet.Proof, requested)
+		}
+		// request the queued ctxs since the capacity is freed
+		srv.fetchCtxs(srv.fetcher.reschedule(time.Now()))
 
-		h := srv.getCrossHandler(ctx.ChainId())
+	case p.version >= cross4 && msg.Code == GetCtxDigestsMsg:
+		if !p.score.allowRequest() {
+			break
+		}
+		var req synchronise.DigestReq
+		if err := msg.Decode(&req); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		h := srv.getCrossHandler(new(big.Int).SetUint64(req.Chain), new(big.Int).SetUint64(req.Remote))
 		if h == nil {
 			break
 		}
+		return p.SendCtxDigests(req.Chain, req.Remote, h.synchronise.Digests(req.Begin, req.End, req.Span))
 
-		err := h.AddRemoteCtx(ctx)
-		if err == cross.ErrExpiredCtx || err == cross.ErrInvalidSignCtx {
+	case p.version >= cross4 && msg.Code == CtxDigestsMsg:
+		var resp synchronise.DigestResp
+		if err := msg.Decode(&resp); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		if len(resp.Digests) > synchronise.MaxDigestRanges {
+			p.score.oversizedResponse()
 			break
 		}
-		srv.BroadcastCrossTx([]*cc.CrossTransaction{ctx}, false)
+		h := srv.getCrossHandler(new(big.Int).SetUint64(resp.Chain), new(big.Int).SetUint64(resp.Remote))
+		if h == nil {
+			break
+		}
+		if err := h.synchronise.DeliverDigests(p.id, resp.Digests); err != nil {
+			log.Debug("Failed to deliver ctx digests", "error", err)
+		}
 
 	default:
 		return errResp(ErrInvalidMsgCode, "%v", msg.Code)
@@ -374,6 +667,54 @@ func (srv *CrossService) handleMsg(p *anchorPeer) error {
 	return nil
 }
 
+// handleRemoteCtx adds the ctx signed by other anchors and relays it if it is valid, requested
+// reports whether the ctx is fetched from the peer by the announced hash.
+func (srv *CrossService) handleRemoteCtx(p *anchorPeer, ctx *cc.CrossTransaction, proof *cc.ReceiptProof, requested bool) {
+	hash := ctx.SignHash()
+	if !requested && p.HasReceivedCtx(hash) {
+		// the ctx is sent by the peer before
+		p.score.duplicateCtx()
+		return
+	}
+	known := p.HasCrossTransaction(hash)
+	p.MarkReceivedCtx(hash)
+	if !requested && known {
+		// the ctx is queued to the peer, it is sent by the peer before receiving ours
+		return
+	}
+
+	h := srv.getCrossHandler(ctx.ChainId(), ctx.DestinationId())
+	if h == nil {
+		return
+	}
+
+	switch err := h.AddRemoteCtx(ctx, proof); err {
+	case cross.ErrInvalidSignCtx:
+		// the signer may be an anchor added or removed recently
+		if !srv.changes.recent() {
+			p.score.suspectSignature()
+		}
+		return
+	case cross.ErrInvalidProofCtx:
+		p.score.invalidSignature()
+		return
+	case cross.ErrExpiredCtx:
+		return
+	case nil:
+		p.score.usefulCtx()
+	}
+	srv.BroadcastCrossTx([]*cc.CrossTransaction{ctx}, false)
+}
+
+// anchorsChanged forgives the ctxs signed by non-anchors, which may be signed by the anchors
+// changed just now.
+func (srv *CrossService) anchorsChanged() {
+	srv.changes.change()
+	for _, p := range srv.peers.AllPeers() {
+		p.score.forgiveSignatures()
+	}
+}
+
 func (srv *CrossService) removePeer(id string) {
 	// Short circuit if the peer was already removed
 	peer := srv.peers.Peer(id)
@@ -383,11 +724,15 @@ func (srv *CrossService) removePeer(id string) {
 	log.Debug("Removing cross anchor peer", "peer", id)
 
 	// Unregister the peer from the synchronise and anchor peer set
-	srv.main.handler.synchronise.UnregisterPeer(id)
-	srv.sub.handler.synchronise.UnregisterPeer(id)
+	for _, pair := range srv.pairs {
+		if peer.SupportPair(pair) {
+			srv.handlers[pair].synchronise.UnregisterPeer(id)
+		}
+	}
 	if err := srv.peers.Unregister(id); err != nil {
 		log.Error("Peer removal failed", "peer", id, "err", err)
 	}
+	srv.fetcher.dropPeer(id)
 	// Hard disconnect at the networking layer
 	peer.Disconnect(p2p.DiscUselessPeer)
 }
@@ -396,57 +741,120 @@ func (srv *CrossService) BroadcastCrossTx(ctxs []*cc.CrossTransaction, local boo
 	for _, ctx := range ctxs {
 		var txset = make(map[*anchorPeer]*cc.CrossTransaction)
 
+		// attach the receipt proof of maker log if it is verified
+		var proof *cc.ReceiptProof
+		if h := srv.getCrossHandler(ctx.ChainId(), ctx.DestinationId()); h != nil {
+			proof = h.ReceiptProof(ctx)
+		}
+
+		// keep the ctx for the peers fetching it by the announced hash
+		srv.fetcher.add(&ctxProofPacket{Ctx: ctx, Proof: proof})
+
 		// Broadcast ctx to a batch of peers not knowing about it
-		peers := srv.peers.PeersWithoutCtx(ctx.SignHash())
+		peers := srv.peers.PeersWithoutCtx(cc.NewChainPair(ctx.ChainId(), ctx.DestinationId()), ctx.SignHash())
 		for _, peer := range peers {
 			txset[peer] = ctx
 		}
 		for peer, rt := range txset {
-			peer.AsyncSendCrossTransaction(rt, local)
+			peer.AsyncSendCrossTransaction(rt, proof, local)
 			log.Debug("Broadcast CrossTransaction", "hash", ctx.SignHash(), "peer", peer.id)
 		}
 	}
 }
 
+// fetchCtxs requests the announced ctxs from the peers scheduled by fetcher
+func (srv *CrossService) fetchCtxs(requests map[string][]common.Hash) {
+	for id, hashes := range requests {
+		p := srv.peers.Peer(id)
+		if p == nil {
+			continue // retried with the others after timeout
+		}
+		go func(p *anchorPeer, hashes []common.Hash) {
+			if err := requestCtxs(p, hashes); err != nil {
+				p.Log().Debug("Failed to request ctxs", "count", len(hashes), "err", err)
+			}
+		}(p, hashes)
+	}
+}
+
+// requestCtxs requests the ctxs of hashes from peer in batches
+func requestCtxs(p *anchorPeer, hashes []common.Hash) error {
+	for len(hashes) > 0 {
+		n := len(hashes)
+		if n > maxCtxFetch {
+			n = maxCtxFetch
+		}
+		if err := p.RequestCtxs(hashes[:n]); err != nil {
+			return err
+		}
+		hashes = hashes[n:]
+	}
+	return nil
+}
+
 func (srv *CrossService) sync() {
+	fetch := time.NewTicker(ctxFetchTimeout / 2)
+	defer fetch.Stop()
+
 	for {
 		select {
 		case p := <-srv.newPeerCh:
 			if srv.peers.Len() > 0 {
-				srv.synchronise(srv.peers.BestPeer())
+				srv.synchronise()
 			}
 			srv.syncPending(p)
 
+		case <-fetch.C:
+			srv.fetchCtxs(srv.fetcher.reschedule(time.Now()))
+
 		case <-srv.quitSync:
 			return
 		}
 	}
 }
 
-func (srv *CrossService) synchronise(main, sub *anchorPeer) {
-	go srv.main.handler.synchronise.Synchronise(main.id, main.crossStatus.MainHeight)
-	go srv.sub.handler.synchronise.Synchronise(sub.id, sub.crossStatus.SubHeight)
+// synchronise syncs store of each chain pair with the best peer of the pair
+func (srv *CrossService) synchronise() (synced bool) {
+	for _, pair := range srv.pairs {
+		if best := srv.peers.BestPeer(pair); best != nil {
+			go srv.handlers[pair].synchronise.Synchronise(best.id, best.Height(pair))
+			synced = true
+		}
+	}
+	return synced
 }
 
 func (srv *CrossService) syncPending(peer *anchorPeer) {
-	go srv.main.handler.synchronise.SynchronisePending(peer.id)
-	go srv.sub.handler.synchronise.SynchronisePending(peer.id)
+	for _, pair := range srv.pairs {
+		if peer.SupportPair(pair) {
+			go srv.handlers[pair].synchronise.SynchronisePending(peer.id)
+		}
+	}
 }
 
 type CrossNodeInfo struct {
-	MainChain   uint64       `json:"mainChain"`
-	MainGenesis common.Hash  `json:"mainGenesis"`
-	SubChain    uint64       `json:"subChain"`
-	SubGenesis  common.Hash  `json:"subGenesis"`
-	Config      cross.Config `json:"config"`
+	Chains []*CrossChainInfo `json:"chains"`
+	Pairs  []cc.ChainPair    `json:"pairs"`
+	Config cross.Config      `json:"config"`
+}
+
+type CrossChainInfo struct {
+	ChainID  uint64         `json:"chainID"`
+	Genesis  common.Hash    `json:"genesis"`
+	Contract common.Address `json:"contract"`
 }
 
 func (srv *CrossService) NodeInfo() *CrossNodeInfo {
-	return &CrossNodeInfo{
-		Config:      srv.config,
-		MainChain:   srv.main.chainID,
-		MainGenesis: srv.main.genesis,
-		SubChain:    srv.sub.chainID,
-		SubGenesis:  srv.sub.genesis,
+	info := &CrossNodeInfo{
+		Config: srv.config,
+		Pairs:  srv.pairs,
+	}
+	for _, chain := range srv.chains {
+		info.Chains = append(info.Chains, &CrossChainInfo{
+			ChainID:  chain.chainID,
+			Genesis:  chain.genesis,
+			Contract: chain.contract,
+		})
 	}
+	return info
 }
@@ -0,0 +1,219 @@
+package backend
+
+import (
+	"sync"
+	"time"
+
+	"gbchain-org/go-gbchain/common"
+
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// ctxFetcher keeps the signed ctxs broadcast by the anchor to serve the requests of peers,
+// and tracks the announced ctxs being requested so that each one is fetched from only one peer.
+// The announcements beyond the fetching capacity are queued, and the timed out requests are
+// retried with the other peers announced the same ctxs.
+type ctxFetcher struct {
+	known *lru.Cache // sign hash -> *ctxProofPacket
+
+	lock      sync.Mutex
+	announced map[common.Hash][]string    // sign hash -> peers announced the ctx not fetched yet
+	counts    map[string]int              // peer -> announcements tracked of the peer
+	queue     []common.Hash               // announced hashes waiting to be requested in order
+	fetching  map[common.Hash]*ctxRequest // sign hash -> request in flight
+}
+
+// ctxRequest is the request of an announced ctx
+type ctxRequest struct {
+	peer string    // peer the request is sent to
+	time time.Time // time requested
+}
+
+func newCtxFetcher() *ctxFetcher {
+	known, _ := lru.New(maxKnownCtx)
+	return &ctxFetcher{
+		known:     known,
+		announced: make(map[common.Hash][]string),
+		counts:    make(map[string]int),
+		fetching:  make(map[common.Hash]*ctxRequest),
+	}
+}
+
+// add records the ctx which could be requested by peers
+func (f *ctxFetcher) add(packet *ctxProofPacket) {
+	hash := packet.Ctx.SignHash()
+	f.known.Add(hash, packet)
+
+	f.lock.Lock()
+	f.forget(hash)
+	f.lock.Unlock()
+}
+
+func (f *ctxFetcher) has(hash common.Hash) bool {
+	return f.known.Contains(hash)
+}
+
+// get returns the known ctxs of hashes, limited by the count and the encoded size
+func (f *ctxFetcher) get(hashes []common.Hash) []*ctxProofPacket {
+	var (
+		packets []*ctxProofPacket
+		size    common.StorageSize
+	)
+	for _, hash := range hashes {
+		if len(packets) >= maxCtxFetch || size >= softResponseLimit {
+			break
+		}
+		if v, ok := f.known.Get(hash); ok {
+			packet := v.(*ctxProofPacket)
+			packets = append(packets, packet)
+			size += packet.Ctx.Size()
+		}
+	}
+	return packets
+}
+
+// announce records the hashes announced by peer, and returns the ones should be requested from
+// it, which are unknown and not being requested from other peers. The hashes are queued if too
+// many ctxs are being requested, and at most maxCtxQueued announcements of a peer are tracked.
+func (f *ctxFetcher) announce(peer string, hashes []common.Hash, now time.Time) []common.Hash {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	var request []common.Hash
+	for _, hash := range hashes {
+		if f.known.Contains(hash) || f.counts[peer] >= maxCtxQueued {
+			continue
+		}
+		peers, ok := f.announced[hash]
+		if ok && containsPeer(peers, peer) {
+			continue
+		}
+		f.announced[hash] = append(peers, peer)
+		f.counts[peer]++
+		if ok { // being fetched or queued already, the peer is an alternate
+			continue
+		}
+		if len(f.fetching) < maxCtxFetching {
+			f.fetching[hash] = &ctxRequest{peer: peer, time: now}
+			request = append(request, hash)
+		} else {
+			f.queue = append(f.queue, hash)
+		}
+	}
+	return request
+}
+
+// delivered reports whether the ctx is requested from peer, it is not fetched any more
+// no matter it is valid or not. The ctxs requested from other peers are not accepted.
+func (f *ctxFetcher) delivered(peer string, hash common.Hash) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if req := f.fetching[hash]; req == nil || req.peer != peer {
+		return false
+	}
+	f.forget(hash)
+	return true
+}
+
+// reschedule retries the requests timed out with the alternate peers, and requests the queued
+// hashes if the capacity is freed. The hashes should be requested are returned by peers.
+func (f *ctxFetcher) reschedule(now time.Time) map[string][]common.Hash {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	var retries []common.Hash
+	for hash, req := range f.fetching {
+		if now.Sub(req.time) < ctxFetchTimeout {
+			continue
+		}
+		delete(f.fetching, hash)
+		f.removeAnnouncer(hash, req.peer)
+		if _, ok := f.announced[hash]; ok {
+			retries = append(retries, hash)
+		}
+	}
+	if len(retries) > 0 {
+		f.queue = append(retries, f.queue...)
+	}
+
+	requests := make(map[string][]common.Hash)
+	for len(f.queue) > 0 && len(f.fetching) < maxCtxFetching {
+		hash := f.queue[0]
+		f.queue = f.queue[1:]
+
+		peers, ok := f.announced[hash]
+		if !ok || f.fetching[hash] != nil { // fetched already
+			continue
+		}
+		f.fetching[hash] = &ctxRequest{peer: peers[0], time: now}
+		requests[peers[0]] = append(requests[peers[0]], hash)
+	}
+	if len(f.queue) == 0 {
+		f.queue = nil // release the backing array
+	}
+	return requests
+}
+
+// dropPeer removes the announcements of the disconnected peer, the ctxs being requested
+// from it are retried with the alternate peers at the next reschedule.
+func (f *ctxFetcher) dropPeer(peer string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.counts[peer] == 0 {
+		return
+	}
+	for hash, peers := range f.announced {
+		if !containsPeer(peers, peer) {
+			continue
+		}
+		if req := f.fetching[hash]; req != nil && req.peer == peer {
+			req.time = time.Time{} // timed out
+			continue
+		}
+		f.removeAnnouncer(hash, peer)
+	}
+}
+
+// forget stops tracking the announced hash
+func (f *ctxFetcher) forget(hash common.Hash) {
+	for _, peer := range f.announced[hash] {
+		f.uncount(peer)
+	}
+	delete(f.announced, hash)
+	delete(f.fetching, hash)
+}
+
+// removeAnnouncer removes peer from the announcers of hash, the hash is forgotten if it is
+// announced by nobody else.
+func (f *ctxFetcher) removeAnnouncer(hash common.Hash, peer string) {
+	peers := f.announced[hash]
+	for i, p := range peers {
+		if p == peer {
+			peers = append(peers[:i:i], peers[i+1:]...)
+			f.uncount(peer)
+			break
+		}
+	}
+	if len(peers) == 0 {
+		f.forget(hash)
+	} else {
+		f.announced[hash] = peers
+	}
+}
+
+func (f *ctxFetcher) uncount(peer string) {
+	if f.counts[peer]--; f.counts[peer] <= 0 {
+		delete(f.counts, peer)
+	}
+}
+
+func containsPeer(peers []string, peer string) bool {
+	for _, p := range peers {
+		if p == peer {
+			return true
+		}
+	}
+	return false
+}
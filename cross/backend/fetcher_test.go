@@ -0,0 +1,58 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"gbchain-org/go-gbchain/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCtxFetcher_Queue(t *testing.T) {
+	var (
+		f      = newCtxFetcher()
+		now    = time.Now()
+		hashes = make([]common.Hash, maxCtxFetching+2)
+	)
+	for i := range hashes {
+		hashes[i] = common.BytesToHash([]byte{byte(i >> 8), byte(i)})
+	}
+
+	// the announcements beyond the capacity are queued instead of dropped
+	assert.Equal(t, hashes[:maxCtxFetching], f.announce("a", hashes, now))
+	assert.Empty(t, f.announce("b", hashes[maxCtxFetching:], now))
+	assert.Empty(t, f.reschedule(now))
+
+	// the queued ones are requested from the announcer once the capacity is freed
+	assert.True(t, f.delivered("a", hashes[0]))
+	assert.False(t, f.delivered("b", hashes[1]), "requested from another peer")
+	assert.Equal(t, map[string][]common.Hash{"a": hashes[maxCtxFetching : maxCtxFetching+1]}, f.reschedule(now))
+}
+
+func TestCtxFetcher_Retry(t *testing.T) {
+	var (
+		f      = newCtxFetcher()
+		now    = time.Now()
+		hashes = []common.Hash{{1}, {2}, {3}}
+	)
+	assert.Equal(t, hashes, f.announce("a", hashes, now))
+	assert.Empty(t, f.announce("b", hashes[:2], now))
+	assert.Empty(t, f.announce("c", hashes[1:2], now))
+
+	// the timed out requests are retried with the alternate peers, the ones
+	// announced by nobody else are dropped
+	requests := f.reschedule(now.Add(ctxFetchTimeout))
+	assert.Len(t, requests, 1)
+	assert.ElementsMatch(t, hashes[:2], requests["b"])
+	assert.False(t, f.delivered("a", hashes[0]))
+	assert.NotContains(t, f.announced, hashes[2])
+
+	// the requests to the disconnected peer are retried at once
+	f.dropPeer("b")
+	assert.Equal(t, map[string][]common.Hash{"c": hashes[1:2]}, f.reschedule(now.Add(ctxFetchTimeout)))
+	assert.True(t, f.delivered("c", hashes[1]))
+	assert.Empty(t, f.announced)
+	assert.Empty(t, f.counts)
+	assert.Empty(t, f.fetching)
+}
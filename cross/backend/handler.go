@@ -1,6 +1,7 @@
 package backend
 
 import (
+	"fmt"
 	"math/big"
 	"sync"
 	"time"
@@ -17,15 +18,20 @@ import (
 	"gbchain-org/go-gbchain/cross/trigger"
 
 	"github.com/asdine/storm/v3/q"
+	lru "github.com/hashicorp/golang-lru"
 )
 
 const (
 	txChanSize        = 4096
 	blockChanSize     = 1
 	signedPendingSize = 256
+	signedRemoteSize  = 4096
+	evidenceChanSize  = 16
+	receiptProofSize  = 4096
 
 	defaultStoreDelay  = 120
 	intervalStoreDelay = time.Minute * 10
+	intervalRefund     = time.Minute
 )
 
 type Handler struct {
@@ -42,10 +48,16 @@ type Handler struct {
 	subscriber trigger.Subscriber
 	executor   trigger.Executor
 	retriever  trigger.ChainRetriever
+	prover     trigger.ProofRetriever // nil if ctxs are not validated by receipt proofs
+	proofs     *lru.Cache             // verified receipt proofs, ctx hash => *cc.ReceiptProof
+	taker      *autoTaker             // nil if no taker account is configured
 
 	monitor *cm.CrossMonitor
 	txLog   *cdb.TransactionLog
 
+	cancels    map[common.Hash]*cc.ReceptTransaction // confirmed cancels of local ctxs, refunded after the ctxs expire
+	cancelLock sync.Mutex
+
 	quitSync chan struct{}
 	wg       sync.WaitGroup
 
@@ -58,22 +70,27 @@ type Handler struct {
 	signedCtxCh  chan cc.SignedCtxEvent // Channel to receive signed-completely makerTx from ctxStore
 	signedCtxSub event.Subscription
 
+	evidenceCh  chan cc.EvidenceEvent // Channel to receive evidences of misbehaving anchors from pool
+	evidenceSub event.Subscription
+
 	log log.Logger
 }
 
-func NewCrossHandler(ctx *cross.ServiceContext, service *CrossService,
+func NewCrossHandler(ctx *cross.ServiceContext, service *CrossService, remoteID *big.Int,
 	crossMsgReader <-chan interface{}, crossMsgWriter chan<- interface{}) (h *Handler, err error) {
 
 	h = &Handler{
 		config:             ctx.Config,
 		chainID:            ctx.ProtocolChain.ChainID(),
+		remoteID:           remoteID,
 		service:            service,
 		store:              service.store,
 		storeDelayCleanNum: big.NewInt(defaultStoreDelay),
 		crossMsgReader:     crossMsgReader,
 		crossMsgWriter:     crossMsgWriter,
+		cancels:            make(map[common.Hash]*cc.ReceptTransaction),
 		quitSync:           make(chan struct{}),
-		log:                log.New("X-module", "handler", "chainID", ctx.ProtocolChain.ChainID()),
+		log:                log.New("X-module", "handler", "chainID", ctx.ProtocolChain.ChainID(), "remoteID", remoteID),
 	}
 
 	//initialize metric
@@ -85,9 +102,50 @@ func NewCrossHandler(ctx *cross.ServiceContext, service *CrossService,
 	h.retriever = ctx.Retriever
 	h.executor = ctx.Executor
 
+	if h.config.ReceiptProof {
+		prover, ok := h.retriever.(trigger.ProofRetriever)
+		if !ok {
+			return nil, fmt.Errorf("receipt proof is not supported by the retriever of chain %d", h.chainID)
+		}
+		h.prover = prover
+		h.proofs, _ = lru.New(receiptProofSize)
+	}
+
+	// anchors sign ctxs by the signing data so that external signers could check the content,
+	// or by BLS key instead of the key of executor if BLS is set
+	signHash, signData := h.executor.SignHash, h.executor.SignData
+	if h.config.BLS != nil {
+		key, err := h.config.BLS.SecretKey()
+		if err != nil {
+			return nil, fmt.Errorf("load bls key failed: %w", err)
+		}
+		signHash, signData = key.SignHash, nil
+	}
+
 	db := h.store.RegisterChain(h.chainID)
-	h.pool = NewCrossPool(h.chainID, h.config, h.store, h.txLog, h.retriever, h.executor.SignHash)
-	h.synchronise = synchronise.New(h.chainID, h.pool, db, h.retriever, ctx.Config.SyncMode)
+	h.store.RegisterChain(h.remoteID)
+	if h.pool, err = NewCrossPool(h.chainID, h.remoteID, h.config, h.store, h.txLog, h.retriever, signHash, signData); err != nil {
+		return nil, err
+	}
+	var (
+		syncPool  synchronise.CrossPool  = h.pool
+		syncStore synchronise.CrossStore = db
+	)
+	if h.prover != nil {
+		syncPool, syncStore = provenPool{h.pool, h}, provenStore{db, h}
+	}
+	syncStore = unfinishedStore{syncStore, h}
+	h.synchronise = synchronise.New(h.chainID, h.remoteID, syncPool, syncStore, h.retriever, ctx.Config.SyncMode)
+
+	if len(service.takers) > 0 {
+		executor, ok := h.executor.(trigger.TakerExecutor)
+		if !ok {
+			return nil, fmt.Errorf("auto taker is not supported by the executor of chain %d", h.chainID)
+		}
+		if h.taker, err = newAutoTaker(h, executor, service.takers, service.takerDB); err != nil {
+			return nil, fmt.Errorf("load taker orders of chain %d failed: %w", h.chainID, err)
+		}
+	}
 
 	return h, nil
 }
@@ -96,14 +154,19 @@ func (h *Handler) Start() {
 	h.signedCtxCh = make(chan cc.SignedCtxEvent, txChanSize)
 	h.signedCtxSub = h.pool.SubscribeSignedCtxEvent(h.signedCtxCh)
 
+	h.evidenceCh = make(chan cc.EvidenceEvent, evidenceChanSize)
+	h.evidenceSub = h.pool.SubscribeEvidenceEvent(h.evidenceCh)
+
 	h.crossBlockCh = make(chan cc.CrossBlockEvent, blockChanSize)
 	h.crossBlockSub = h.subscriber.SubscribeBlockEvent(h.crossBlockCh)
 
-	h.executor.Start()
-
+	h.loadCancels()
 	h.wg.Add(2)
 	go h.loop()
 	go h.readCrossMessage()
+	if h.taker != nil {
+		h.taker.start()
+	}
 }
 
 func (h *Handler) Stop() {
@@ -111,18 +174,22 @@ func (h *Handler) Stop() {
 	h.synchronise.Terminate()
 	h.crossBlockSub.Unsubscribe()
 	h.signedCtxSub.Unsubscribe()
+	h.evidenceSub.Unsubscribe()
 	close(h.quitSync)
 	h.wg.Wait()
-	//先停止executor，再停pool最后停store
-	h.executor.Stop()
+	if h.taker != nil {
+		h.taker.stop()
+	}
+	// executor and store are shared by handlers of the chain, stopped by service
 	h.pool.Stop()
-	h.store.Close()
 }
 
 func (h *Handler) loop() {
 	defer h.wg.Done()
 	ticker := time.NewTicker(intervalStoreDelay)
 	defer ticker.Stop()
+	refund := time.NewTicker(intervalRefund)
+	defer refund.Stop()
 
 	for {
 		select {
@@ -140,12 +207,26 @@ func (h *Handler) loop() {
 		case <-h.signedCtxSub.Err():
 			return
 
+		case ev := <-h.evidenceCh:
+			h.storeEvidences(ev.Evidences)
+		case <-h.evidenceSub.Err():
+			return
+
 		case <-ticker.C:
-			if height := h.Height(); h.storeDelayCleanNum.Cmp(common.Big0) > 0 && height != nil && height.Cmp(h.storeDelayCleanNum) > 0 {
+			switch height, age := h.Height(), h.config.ArchiveAge; {
+			case age > 0: // finished ctxs are archived instead of removed at store delay
+				if height.Uint64() > age {
+					h.log.Info("regular archive finished tx", "height", height,
+						"archived", h.ArchiveCrossTransactionBefore(height.Uint64()-age))
+				}
+			case h.storeDelayCleanNum.Cmp(common.Big0) > 0 && height.Cmp(h.storeDelayCleanNum) > 0:
 				h.log.Info("regular remove finished tx", "height", height,
 					"removed", h.RemoveCrossTransactionBefore(height.Uint64()-h.storeDelayCleanNum.Uint64()))
 			}
 
+		case <-refund.C:
+			h.refundCancels(nil)
+
 		case <-h.quitSync:
 			return
 		}
@@ -161,6 +242,9 @@ func (h *Handler) handle(current *cc.CrossBlockEvent) {
 		log.Debug("X handle crosschain block complete", "runtime", time.Since(start))
 	}(time.Now())
 
+	// the chain could be bridged to several chains, only handle logs of this pair
+	current = h.filterBlockEvent(current)
+
 	// handle anchor update
 	if updates := current.NewAnchor.ChainInfo; len(updates) > 0 {
 		h.log.Info("X handle new anchor", "number", current.Number, "newAnchor", len(current.NewAnchor.ChainInfo))
@@ -170,6 +254,7 @@ func (h *Handler) handle(current *cc.CrossBlockEvent) {
 				continue
 			}
 		}
+		h.service.anchorsChanged()
 		// fetch illegal tx after anchor updating
 		local = append(local, h.handleAnchorChange(current.Number)...)
 	}
@@ -192,6 +277,22 @@ func (h *Handler) handle(current *cc.CrossBlockEvent) {
 		return remains
 	}
 
+	handleCancels := func(cancels []*cc.ReceptTransaction, modType cc.ModType, modStatus cc.CtxStatus) (remains []*cc.ReceptTransaction) {
+		for _, tx := range cancels {
+			if err := tx.CheckCancel(h.store.Get(tx.DestinationId, tx.CTxId)); err != nil {
+				h.log.Warn("check cancel failed", "type", modType, "status", modStatus, "error", err)
+				continue
+			}
+			remote = append(remote, &cc.CrossTransactionModifier{
+				ID:     tx.CTxId,
+				Type:   modType,
+				Status: modStatus,
+			})
+			remains = append(remains, tx)
+		}
+		return remains
+	}
+
 	// ignore early block logs
 	if height := h.store.Height(h.chainID); current.Number.Uint64()+h.retriever.ConfirmedDepth() >= height {
 
@@ -199,12 +300,14 @@ func (h *Handler) handle(current *cc.CrossBlockEvent) {
 			"newAnchor", len(current.NewAnchor.ChainInfo), "confMaker", len(current.ConfirmedMaker.Txs),
 			"taker", len(current.NewTaker.Takers), "confTaker", len(current.ConfirmedTaker.Txs),
 			"finish", len(current.NewFinish.Finishes), "confFinish", len(current.ConfirmedFinish.Finishes),
-			"reTaker", len(current.ReorgTaker.Takers), "reFinish", len(current.ReorgFinish.Finishes))
+			"reTaker", len(current.ReorgTaker.Takers), "reFinish", len(current.ReorgFinish.Finishes),
+			"cancel", len(current.NewCancel.Cancels), "confCancel", len(current.ConfirmedCancel.Cancels),
+			"reCancel", len(current.ReorgCancel.Cancels), "makerCancel", len(current.MakerCancel.Cancels))
 
 		// handle reorg, rollback unconfirmed status(executing->waiting, finishing->executed)
 		// reorg taker (remote)
 		if takers := current.ReorgTaker.Takers; len(takers) > 0 {
-			handleReceptTransactions(takers, cc.Reorg, cc.CtxStatusWaiting)
+			h.storeTakerTxs(handleReceptTransactions(takers, cc.Reorg, cc.CtxStatusWaiting), true)
 		}
 
 		// reorg finish (local)
@@ -212,6 +315,11 @@ func (h *Handler) handle(current *cc.CrossBlockEvent) {
 			local = append(local, finishes...)
 		}
 
+		// reorg cancel (remote)
+		if cancels := current.ReorgCancel.Cancels; len(cancels) > 0 {
+			h.storeTakerTxs(handleCancels(cancels, cc.Reorg, cc.CtxStatusWaiting), true)
+		}
+
 		// handle confirmed maker
 		if makers := current.ConfirmedMaker.Txs; len(makers) > 0 {
 			signed, commits, errs := h.pool.AddLocals(makers...)
@@ -236,18 +344,23 @@ func (h *Handler) handle(current *cc.CrossBlockEvent) {
 			if err := h.store.Adds(h.chainID, cws, false); err != nil {
 				h.log.Warn("Store pending ctx failed", "error", err)
 			}
+			if h.prover != nil {
+				h.proveLocals(signed)
+			}
 			h.service.BroadcastCrossTx(signed, true) // broad cast self signed tx to other anchors
 		}
 
 		// handle new taker
 		if takers := current.NewTaker.Takers; len(takers) > 0 {
-			handleReceptTransactions(takers, cc.Remote, cc.CtxStatusExecuting)
+			h.storeTakerTxs(handleReceptTransactions(takers, cc.Remote, cc.CtxStatusExecuting), false)
 		}
 
 		// handle confirmed taker
 		if takers := current.ConfirmedTaker.Txs; len(takers) > 0 {
 			txs := handleReceptTransactions(takers, cc.Remote, cc.CtxStatusExecuted)
 			if len(txs) > 0 {
+				h.storeTakerTxs(txs, false)
+				h.storeMessageResults(txs)
 				h.writeCrossMessage(cc.ConfirmedTakerEvent{Txs: txs})
 			}
 		}
@@ -261,6 +374,24 @@ func (h *Handler) handle(current *cc.CrossBlockEvent) {
 		if finishes := current.ConfirmedFinish.Finishes; len(finishes) > 0 {
 			local = append(local, finishes...)
 		}
+
+		// handle new cancel, the remote ctx could never be taken
+		if cancels := current.NewCancel.Cancels; len(cancels) > 0 {
+			handleCancels(cancels, cc.Remote, cc.CtxStatusCancelling)
+		}
+
+		// handle confirmed cancel, anchors refund the maker in maker chain
+		if cancels := current.ConfirmedCancel.Cancels; len(cancels) > 0 {
+			if txs := handleCancels(cancels, cc.Remote, cc.CtxStatusCancelling); len(txs) > 0 {
+				h.storeTakerTxs(txs, false) // the cancel is restored by its transaction after restart
+				h.writeCrossMessage(cc.ConfirmedCancelEvent{Cancels: txs})
+			}
+		}
+
+		// handle confirmed refund of maker
+		if cancels := current.MakerCancel.Cancels; len(cancels) > 0 {
+			local = append(local, cancels...)
+		}
 	}
 
 	if len(local) > 0 {
@@ -283,7 +414,11 @@ func (h *Handler) handleAnchorChange(number *big.Int) []*cc.CrossTransactionModi
 		h.log.Warn("handleAnchorChange failed", "error", err)
 		return nil
 	}
-	conditions := []q.Matcher{q.Eq(cdb.StatusField, cc.CtxStatusWaiting), q.Lte(cdb.BlockNumField, number.Uint64())}
+	conditions := []q.Matcher{
+		q.Eq(cdb.StatusField, cc.CtxStatusWaiting),
+		q.Eq(cdb.DestinationId, h.remoteID),
+		q.Lte(cdb.BlockNumField, number.Uint64()),
+	}
 	txm := make([]*cc.CrossTransactionModifier, 0)
 	for _, cws := range store.Query(0, 0, []cdb.FieldName{cdb.BlockNumField}, false, conditions...) {
 		for _, ctx := range cws.Resolution() { // verify each signature
@@ -302,6 +437,54 @@ func (h *Handler) handleAnchorChange(number *big.Int) []*cc.CrossTransactionModi
 	return txm
 }
 
+// filterBlockEvent drops the logs in block event which belong to other chain pairs.
+func (h *Handler) filterBlockEvent(ev *cc.CrossBlockEvent) *cc.CrossBlockEvent {
+	filtered := &cc.CrossBlockEvent{Number: ev.Number}
+
+	for _, tx := range ev.ConfirmedMaker.Txs {
+		if tx.DestinationId().Cmp(h.remoteID) == 0 {
+			filtered.ConfirmedMaker.Txs = append(filtered.ConfirmedMaker.Txs, tx)
+		}
+	}
+	for _, info := range ev.NewAnchor.ChainInfo {
+		if info.RemoteChainId == h.remoteID.Uint64() {
+			filtered.NewAnchor.ChainInfo = append(filtered.NewAnchor.ChainInfo, info)
+		}
+	}
+
+	// takers on this chain reference the ctxs made on the remote chain
+	takers := func(txs []*cc.ReceptTransaction) (remains []*cc.ReceptTransaction) {
+		for _, tx := range txs {
+			if tx.DestinationId != nil && tx.DestinationId.Cmp(h.remoteID) == 0 {
+				remains = append(remains, tx)
+			}
+		}
+		return remains
+	}
+	filtered.NewTaker.Takers = takers(ev.NewTaker.Takers)
+	filtered.ConfirmedTaker.Txs = takers(ev.ConfirmedTaker.Txs)
+	filtered.ReorgTaker.Takers = takers(ev.ReorgTaker.Takers)
+	filtered.NewCancel.Cancels = takers(ev.NewCancel.Cancels)
+	filtered.ConfirmedCancel.Cancels = takers(ev.ConfirmedCancel.Cancels)
+	filtered.ReorgCancel.Cancels = takers(ev.ReorgCancel.Cancels)
+
+	// finish logs carry no remote chain, check the stored maker instead
+	finishes := func(txms []*cc.CrossTransactionModifier) (remains []*cc.CrossTransactionModifier) {
+		for _, txm := range txms {
+			if ctx := h.store.Get(h.chainID, txm.ID); ctx != nil && ctx.DestinationId().Cmp(h.remoteID) == 0 {
+				remains = append(remains, txm)
+			}
+		}
+		return remains
+	}
+	filtered.NewFinish.Finishes = finishes(ev.NewFinish.Finishes)
+	filtered.ConfirmedFinish.Finishes = finishes(ev.ConfirmedFinish.Finishes)
+	filtered.ReorgFinish.Finishes = finishes(ev.ReorgFinish.Finishes)
+	filtered.MakerCancel.Cancels = finishes(ev.MakerCancel.Cancels)
+
+	return filtered
+}
+
 // TxDifference returns a new set which is the difference between signed and commits.
 func txDifferent(signed []*cc.CrossTransaction, commits []*cc.CrossTransactionWithSignatures) []*cc.CrossTransaction {
 	keep := make([]*cc.CrossTransaction, 0, len(signed))
@@ -336,6 +519,7 @@ func (h *Handler) readCrossMessage() {
 			switch ev := v.(type) {
 			case cc.SignedCtxEvent: // 对面链签名完成的跨链交易消息，需要在此链验证anchor是否一致
 				var commits []cc.CommitEvent
+				var messages []*cc.CrossTransactionWithSignatures
 				for _, cws := range ev.Txs {
 					if cws.DestinationId().Cmp(h.chainID) == 0 {
 						var invalidSigIndex []int
@@ -361,16 +545,26 @@ func (h *Handler) readCrossMessage() {
 							Tx:              cws,
 							InvalidSigIndex: invalidSigIndex,
 						})
+						if invalidSigIndex == nil {
+							messages = append(messages, cws)
+						}
 					}
 				}
 
 				if ev.CallBack != nil && commits != nil {
 					ev.CallBack(commits) // call callback with signer checking results
+					if h.taker != nil {
+						h.taker.notify() // match the new remote ctxs
+					}
 				}
+				h.submitMessages(messages)
 
 			case cc.ConfirmedTakerEvent: // taker确认消息，需要anchor发起解锁交易
 				h.executor.SubmitTransaction(ev.Txs) // submit finish transaction
 
+			case cc.ConfirmedCancelEvent: // maker在目标链撤销确认，ctx过期后anchor共同签署退款
+				h.refundCancels(ev.Cancels) // submit cancel transaction of expired ctxs
+
 			default:
 				h.log.Warn("invalid cross message", "msg", ev)
 			}
@@ -382,10 +576,15 @@ func (h *Handler) readCrossMessage() {
 }
 
 // 往pool里添加从P2P网络接收的ctx与节点签名信息
-func (h *Handler) AddRemoteCtx(ctx *cc.CrossTransaction) error {
+func (h *Handler) AddRemoteCtx(ctx *cc.CrossTransaction, proof *cc.ReceiptProof) error {
 	if !h.retriever.CanAcceptTxs() { // wait until block synchronize completely
 		return nil
 	}
+	if h.prover != nil {
+		if err := h.verifyProof(ctx, proof); err != nil {
+			return err
+		}
+	}
 	signer, err := h.pool.AddRemote(ctx)
 	switch err {
 	case nil:
@@ -402,6 +601,240 @@ func (h *Handler) AddRemoteCtx(ctx *cc.CrossTransaction) error {
 	return err
 }
 
+// storeEvidences keeps the evidences of misbehaving anchors, they are exported by cross_evidence
+func (h *Handler) storeEvidences(evidences []*cc.Evidence) {
+	for _, ev := range evidences {
+		if _, err := h.service.evidences.Add(h.chainID, ev); err != nil {
+			h.log.Warn("store evidence failed", "kind", ev.Kind, "signer", ev.Signer.String(), "error", err)
+		}
+	}
+}
+
+// submitMessages executes the message ctxs signed completely if the message mode is enabled,
+// the anchor which executes a message first takes the ctx while the others fail in estimation.
+func (h *Handler) submitMessages(cwss []*cc.CrossTransactionWithSignatures) {
+	if !h.config.Message || h.config.BLS != nil || len(cwss) == 0 {
+		return // takerMessage is verified by ECDSA signatures only
+	}
+	executor, ok := h.executor.(trigger.MessageExecutor)
+	if !ok {
+		h.log.Warn("message execution is not supported by executor")
+		return
+	}
+	var messages []*cc.CrossTransactionWithSignatures
+	for _, cws := range cwss {
+		if _, err := cc.ParseMessage(cws.Data.Input); err == nil {
+			messages = append(messages, cws)
+		} else if err != cc.ErrNotMessage {
+			h.log.Debug("invalid message ctx", "ctxID", cws.ID().String(), "error", err)
+		}
+	}
+	if len(messages) > 0 {
+		executor.SubmitMessage(messages)
+	}
+}
+
+// refundCancels submits the makerCancel transactions of confirmed cancels, anchors refuse to refund
+// until the ctx expires, so that a maker can't cancel the ctx which is being taken by others.
+// The cancels of unexpired ctxs are kept and retried at intervalRefund.
+func (h *Handler) refundCancels(cancels []*cc.ReceptTransaction) {
+	h.cancelLock.Lock()
+	defer h.cancelLock.Unlock()
+	for _, rtx := range cancels {
+		h.cancels[rtx.CTxId] = rtx
+	}
+	var refunds []*cc.ReceptTransaction
+	for id, rtx := range h.cancels {
+		ctx := h.store.Get(h.chainID, id)
+		if ctx == nil || ctx.Status != cc.CtxStatusCancelling && ctx.Status != cc.CtxStatusWaiting {
+			delete(h.cancels, id) // refunded or taken after reorg
+			continue
+		}
+		if ctx.Status == cc.CtxStatusWaiting {
+			continue // the status of cancel is not updated yet
+		}
+		// ctxs never expire if the expiry is disabled, their cancels are refunded at once
+		if h.retriever.ExpireNumber() >= 0 && h.retriever.VerifyExpire(ctx.CrossTransaction()) == nil {
+			h.log.Debug("refund cancel after ctx expired", "ctxID", id.String())
+			continue
+		}
+		refunds = append(refunds, rtx)
+		delete(h.cancels, id)
+	}
+	if len(refunds) > 0 {
+		h.executor.SubmitCancel(refunds)
+	}
+}
+
+// loadCancels restores the confirmed cancels of local ctxs which are not refunded, the cancel
+// transactions are kept along with the taker transactions.
+func (h *Handler) loadCancels() {
+	store, err := h.store.GetStore(h.chainID)
+	if err != nil {
+		return
+	}
+	cancelling := store.Query(0, 0, nil, false,
+		q.Eq(cdb.StatusField, cc.CtxStatusCancelling), q.Eq(cdb.DestinationId, h.remoteID))
+	h.cancelLock.Lock()
+	defer h.cancelLock.Unlock()
+	for _, ctx := range cancelling {
+		if hash, ok := h.service.takerTxs.Get(ctx.ID()); ok {
+			h.cancels[ctx.ID()] = cc.NewReceptTransaction(ctx.ID(), hash, ctx.Data.From, ctx.Data.From, h.chainID, h.remoteID)
+		}
+	}
+	h.log.Info("load confirmed cancels", "count", len(h.cancels))
+}
+
+// storeTakerTxs keeps the transactions taking ctxs, they are exported by graphql as the takers of ctxs
+func (h *Handler) storeTakerTxs(takers []*cc.ReceptTransaction, reorg bool) {
+	for _, tx := range takers {
+		var err error
+		if reorg {
+			err = h.service.takerTxs.Delete(tx.CTxId, tx.TxHash)
+		} else {
+			err = h.service.takerTxs.Put(tx.CTxId, tx.TxHash)
+		}
+		if err != nil {
+			h.log.Warn("store taker tx failed", "ctxID", tx.CTxId.String(), "reorg", reorg, "error", err)
+		}
+	}
+}
+
+// storeMessageResults keeps the confirmed results of messages, they are exported by cross_ctxMessage
+func (h *Handler) storeMessageResults(takers []*cc.ReceptTransaction) {
+	for _, tx := range takers {
+		if tx.Result == nil {
+			continue
+		}
+		if err := h.service.messages.Put(tx.Result); err != nil {
+			h.log.Warn("store message result failed", "ctxID", tx.CTxId.String(), "error", err)
+		}
+	}
+}
+
+// proveLocals creates receipt proofs for local maker txs, proofs are broadcast along with signatures
+func (h *Handler) proveLocals(ctxs []*cc.CrossTransaction) {
+	for _, ctx := range ctxs {
+		if h.proofs.Contains(ctx.Hash()) {
+			continue
+		}
+		proof, err := h.prover.GetReceiptProof(ctx)
+		if err != nil {
+			h.log.Warn("create receipt proof failed", "ctxID", ctx.ID().String(), "error", err)
+			continue
+		}
+		h.proofs.Add(ctx.Hash(), proof)
+	}
+}
+
+// verifyProof checks the maker log of ctx is proven in a canonical block of this chain,
+// the verified proof is cached so that the other signatures of the ctx could be accepted.
+// Proofs are keyed by the hash of ctx body signed by anchors, a conflicting body of the
+// same ctx id is verified again.
+func (h *Handler) verifyProof(ctx *cc.CrossTransaction, proof *cc.ReceiptProof) error {
+	if h.proofs.Contains(ctx.Hash()) {
+		return nil
+	}
+	if proof == nil {
+		h.log.Debug("receive remote ctx without receipt proof", "ctxID", ctx.ID().String())
+		return cross.ErrInvalidProofCtx
+	}
+	if err := h.prover.VerifyReceiptProof(ctx, proof); err != nil {
+		h.log.Warn("verify receipt proof failed", "ctxID", ctx.ID().String(), "error", err)
+		return cross.ErrInvalidProofCtx
+	}
+	h.proofs.Add(ctx.Hash(), proof)
+	return nil
+}
+
+// proveCtx verifies ctx received without receipt proof, such as the synchronised and imported
+// ones, by the proof created from this chain.
+func (h *Handler) proveCtx(ctx *cc.CrossTransaction) error {
+	if h.proofs.Contains(ctx.Hash()) {
+		return nil
+	}
+	proof, err := h.prover.GetReceiptProof(ctx)
+	if err != nil {
+		h.log.Warn("prove ctx failed", "ctxID", ctx.ID().String(), "error", err)
+		return cross.ErrInvalidProofCtx
+	}
+	return h.verifyProof(ctx, proof)
+}
+
+// ReceiptProof returns the verified receipt proof of ctx, nil if it is unknown or proofs are disabled
+func (h *Handler) ReceiptProof(ctx *cc.CrossTransaction) *cc.ReceiptProof {
+	if h.proofs == nil {
+		return nil
+	}
+	if proof, ok := h.proofs.Get(ctx.Hash()); ok {
+		return proof.(*cc.ReceiptProof)
+	}
+	return nil
+}
+
+// provenPool verifies the pending ctxs synchronised from peers by receipt proofs before adding them
+type provenPool struct {
+	synchronise.CrossPool
+	h *Handler
+}
+
+func (p provenPool) AddRemotes(ctxs []*cc.CrossTransaction) ([]common.Address, []error) {
+	var (
+		proven []*cc.CrossTransaction
+		errs   []error
+	)
+	for _, ctx := range ctxs {
+		if err := p.h.proveCtx(ctx); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		proven = append(proven, ctx)
+	}
+	signers, addErrs := p.CrossPool.AddRemotes(proven)
+	return signers, append(errs, addErrs...)
+}
+
+// provenStore verifies the ctxs synchronised from peers by receipt proofs before writing them,
+// the ctxs failed are dropped.
+type provenStore struct {
+	synchronise.CrossStore
+	h *Handler
+}
+
+func (s provenStore) Writes(ctxs []*cc.CrossTransactionWithSignatures, replaceable bool) error {
+	proven := make([]*cc.CrossTransactionWithSignatures, 0, len(ctxs))
+	for _, cws := range ctxs {
+		if err := s.h.proveCtx(cws.CrossTransaction()); err != nil {
+			continue
+		}
+		proven = append(proven, cws)
+	}
+	if len(proven) == 0 {
+		return nil
+	}
+	return s.CrossStore.Writes(proven, replaceable)
+}
+
+// unfinishedStore drops the ctxs synchronised from peers which are finished and moved into
+// the txLog or archive, so that they are not written into the store again.
+type unfinishedStore struct {
+	synchronise.CrossStore
+	h *Handler
+}
+
+func (s unfinishedStore) Writes(ctxs []*cc.CrossTransactionWithSignatures, replaceable bool) error {
+	unfinished := make([]*cc.CrossTransactionWithSignatures, 0, len(ctxs))
+	for _, cws := range ctxs {
+		if !s.h.txLog.IsFinish(cws.ID()) {
+			unfinished = append(unfinished, cws)
+		}
+	}
+	if len(unfinished) == 0 {
+		return nil
+	}
+	return s.CrossStore.Writes(unfinished, replaceable)
+}
+
 // 获取未共识完成的跨链交易
 //@start 起始交易所在区块高度
 //@limit 限制一次性取的交易条数
@@ -410,11 +843,6 @@ func (h *Handler) Pending(start uint64, limit int) (ids []common.Hash) {
 	return ids
 }
 
-func (h *Handler) RegisterChain(chainID *big.Int) {
-	h.remoteID = chainID
-	h.store.RegisterChain(chainID)
-}
-
 func (h *Handler) LocalID() uint64  { return h.chainID.Uint64() }
 func (h *Handler) RemoteID() uint64 { return h.remoteID.Uint64() }
 
@@ -431,6 +859,36 @@ func (h *Handler) SetStoreDelay(number uint64) {
 
 // 在store删除number区块高度之前的finished状态的跨链交易，并持久化到txLog中
 func (h *Handler) RemoveCrossTransactionBefore(number uint64) int {
+	return h.removeFinishedBefore(number, func(ctxList []*cc.CrossTransactionWithSignatures) ([]common.Hash, error) {
+		var deletes []common.Hash
+		for _, ctx := range ctxList {
+			if err := h.txLog.AddFinish(ctx); err == nil {
+				deletes = append(deletes, ctx.ID())
+			}
+		}
+		_, err := h.txLog.Commit()
+		return deletes, err
+	})
+}
+
+// ArchiveCrossTransactionBefore moves the finished ctxs before the block number from the store
+// into the archive, the number of ctxs archived is returned.
+func (h *Handler) ArchiveCrossTransactionBefore(number uint64) int {
+	return h.removeFinishedBefore(number, func(ctxList []*cc.CrossTransactionWithSignatures) ([]common.Hash, error) {
+		if err := h.service.archive.Append(h.chainID, ctxList); err != nil {
+			return nil, err
+		}
+		deletes := make([]common.Hash, len(ctxList))
+		for i, ctx := range ctxList {
+			deletes[i] = ctx.ID()
+		}
+		return deletes, nil
+	})
+}
+
+// removeFinishedBefore deletes the finished ctxs of chain pair before the block number from
+// the store, after they are kept by keep which returns the ids of ctxs kept.
+func (h *Handler) removeFinishedBefore(number uint64, keep func([]*cc.CrossTransactionWithSignatures) ([]common.Hash, error)) int {
 	store, _ := h.store.GetStore(h.chainID)
 	var (
 		current uint64
@@ -440,17 +898,25 @@ func (h *Handler) RemoveCrossTransactionBefore(number uint64) int {
 
 	for {
 		ctxList = store.RangeByNumber(current, number, 100)
-		var deletes []common.Hash
+		var finished []*cc.CrossTransactionWithSignatures
 		for _, ctx := range ctxList {
 			current = ctx.BlockNum + 1
-			if ctx.Status == cc.CtxStatusFinished { // only finished ctx can be deleted
-				if err := h.txLog.AddFinish(ctx); err == nil {
-					deletes = append(deletes, ctx.ID())
-				}
+			if ctx.DestinationId().Cmp(h.remoteID) != 0 { // belongs to other chain pair
+				continue
+			}
+			if ctx.Status == cc.CtxStatusFinished || ctx.Status == cc.CtxStatusCancelled { // only finished ctx can be deleted
+				finished = append(finished, ctx)
 			}
 		}
-		if _, err := h.txLog.Commit(); err != nil {
-			h.log.Warn("transaction log commit failed", "error", err)
+		var deletes []common.Hash
+		if len(finished) > 0 {
+			var err error
+			if deletes, err = keep(finished); err != nil {
+				h.log.Warn("keep finished ctx failed", "number", number, "error", err)
+				if len(deletes) == 0 {
+					break
+				}
+			}
 		}
 		if err := store.Deletes(deletes); err != nil {
 			h.log.Warn("remove ctx failed", "number", number, "error", err)
@@ -479,5 +945,23 @@ func (h *Handler) GetPending(ids []common.Hash) []*cc.CrossTransaction {
 }
 
 func (h *Handler) GetCrossTransactionByHeight(height uint64, limit int) []*cc.CrossTransactionWithSignatures {
-	return h.store.stores[h.chainID.Uint64()].RangeByNumber(height, h.retriever.CurrentBlockNumber(), limit)
+	var (
+		store   = h.store.stores[h.chainID.Uint64()]
+		current = h.retriever.CurrentBlockNumber()
+		results = make([]*cc.CrossTransactionWithSignatures, 0, limit)
+	)
+	// ctxs of other chain pairs are skipped, keep ranging until limit is reached
+	for height <= current && len(results) < limit {
+		ctxList := store.RangeByNumber(height, current, limit)
+		if len(ctxList) == 0 {
+			break
+		}
+		for _, ctx := range ctxList {
+			if ctx.DestinationId().Cmp(h.remoteID) == 0 {
+				results = append(results, ctx)
+			}
+		}
+		height = ctxList[len(ctxList)-1].BlockNum + 1
+	}
+	return results
 }
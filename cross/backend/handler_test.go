@@ -6,11 +6,17 @@ import (
 	"testing"
 
 	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/crypto"
 	"gbchain-org/go-gbchain/ethdb/memorydb"
+	"gbchain-org/go-gbchain/log"
 
+	"gbchain-org/go-gbchain/cross"
 	cc "gbchain-org/go-gbchain/cross/core"
 	db "gbchain-org/go-gbchain/cross/database"
+	"gbchain-org/go-gbchain/cross/trigger"
 
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -25,12 +31,15 @@ func newHandlerTester(chainID *big.Int) (*Handler, error) {
 	}
 
 	return &Handler{
-		chainID: chainID,
-		store:   store,
-		txLog:   memLog.Get(chainID),
+		chainID:  chainID,
+		remoteID: testRemoteID,
+		store:    store,
+		txLog:    memLog.Get(chainID),
 	}, nil
 }
 
+var testRemoteID = big.NewInt(1)
+
 func generateCtx(n int, status cc.CtxStatus) []*cc.CrossTransactionWithSignatures {
 	ctxList := make([]*cc.CrossTransactionWithSignatures, n)
 	for i := 0; i < n; i++ {
@@ -42,7 +51,7 @@ func generateCtx(n int, status cc.CtxStatus) []*cc.CrossTransactionWithSignature
 				Value:            big.NewInt(rand.Int63n(1e18)),
 				From:             common.BigToAddress(bigI),
 				BlockHash:        common.Hash{},
-				DestinationId:    bigI,
+				DestinationId:    testRemoteID,
 				DestinationValue: big.NewInt(rand.Int63n(1e18)),
 				Input:            bigI.Bytes(),
 				V:                nil,
@@ -78,3 +87,236 @@ func TestHandler_RemoveCrossTransactionBefore(t *testing.T) {
 		assert.True(t, ctx.BlockNum > 60 || ctx.Status != cc.CtxStatusFinished)
 	}
 }
+
+func TestHandler_RemoveCrossTransactionBeforeOfPair(t *testing.T) {
+	handler, err := newHandlerTester(common.Big0)
+	assert.NoError(t, err)
+	defer handler.store.Close()
+
+	ctxList := generateCtx(21, cc.CtxStatusFinished)[1:] // skip ctx at block 0
+	for i := 10; i < 20; i++ {
+		ctxList[i].Data.DestinationId = big.NewInt(2) // ctx bridged to other chain
+	}
+	assert.NoError(t, handler.store.Adds(common.Big0, ctxList, false))
+	assert.Equal(t, 10, handler.RemoveCrossTransactionBefore(20))
+
+	store, _ := handler.store.GetStore(common.Big0)
+	assert.Equal(t, 10, store.Count())
+	for _, ctx := range store.Query(0, 0, nil, false) {
+		assert.Equal(t, uint64(2), ctx.DestinationId().Uint64())
+	}
+}
+
+type testProofRetriever struct {
+	valid  common.Hash   // block hash of the valid proof
+	proven []common.Hash // ctxs of which the maker logs are in this chain
+}
+
+func (r testProofRetriever) GetReceiptProof(ctx *cc.CrossTransaction) (*cc.ReceiptProof, error) {
+	for _, id := range r.proven {
+		if id == ctx.ID() {
+			return &cc.ReceiptProof{Header: &types.Header{Number: big.NewInt(1)}}, nil
+		}
+	}
+	return nil, cc.ErrProofMissingLog
+}
+
+func (r testProofRetriever) VerifyReceiptProof(ctx *cc.CrossTransaction, proof *cc.ReceiptProof) error {
+	if proof.Header.Hash() != r.valid {
+		return cc.ErrProofHeaderMismatch
+	}
+	return nil
+}
+
+func TestHandler_VerifyProof(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(1)}
+	proofs, _ := lru.New(receiptProofSize)
+	h := &Handler{
+		prover: testProofRetriever{valid: header.Hash()},
+		proofs: proofs,
+		log:    log.New(),
+	}
+	ctx := generateCtx(1, cc.CtxStatusPending)[0].CrossTransaction()
+
+	assert.Equal(t, cross.ErrInvalidProofCtx, h.verifyProof(ctx, nil))
+	assert.Equal(t, cross.ErrInvalidProofCtx, h.verifyProof(ctx, &cc.ReceiptProof{Header: &types.Header{Number: big.NewInt(2)}}))
+	assert.Nil(t, h.ReceiptProof(ctx))
+
+	proof := &cc.ReceiptProof{Header: header}
+	assert.NoError(t, h.verifyProof(ctx, proof))
+	assert.Equal(t, proof, h.ReceiptProof(ctx))
+	// other signatures of the verified ctx are accepted without proof
+	assert.NoError(t, h.verifyProof(ctx, nil))
+
+	// the conflicting body of the same ctx id is verified again
+	conflict := generateCtx(1, cc.CtxStatusPending)[0].CrossTransaction()
+	conflict.Data.Value = new(big.Int).Add(ctx.Data.Value, common.Big1)
+	assert.Equal(t, ctx.ID(), conflict.ID())
+	assert.Nil(t, h.ReceiptProof(conflict))
+	assert.Equal(t, cross.ErrInvalidProofCtx, h.verifyProof(conflict, nil))
+}
+
+func TestHandler_ProveSyncCtxs(t *testing.T) {
+	handler, err := newHandlerTester(common.Big0)
+	assert.NoError(t, err)
+	defer handler.store.Close()
+
+	ctxList := generateCtx(3, cc.CtxStatusWaiting)
+	handler.proofs, _ = lru.New(receiptProofSize)
+	handler.log = log.New()
+	// the maker logs of the first two ctxs are in this chain
+	handler.prover = testProofRetriever{
+		valid:  (&types.Header{Number: big.NewInt(1)}).Hash(),
+		proven: []common.Hash{ctxList[0].ID(), ctxList[1].ID()},
+	}
+	assert.NoError(t, handler.proveCtx(ctxList[0].CrossTransaction()))
+	assert.Equal(t, cross.ErrInvalidProofCtx, handler.proveCtx(ctxList[2].CrossTransaction()))
+
+	db, err := handler.store.GetStore(common.Big0)
+	assert.NoError(t, err)
+	store := provenStore{db, handler}
+	assert.NoError(t, store.Writes(ctxList, true))
+	assert.True(t, db.Has(ctxList[0].ID()))
+	assert.True(t, db.Has(ctxList[1].ID()))
+	assert.False(t, db.Has(ctxList[2].ID()))
+}
+
+func TestHandler_SyncUnfinished(t *testing.T) {
+	handler, err := newHandlerTester(common.Big0)
+	assert.NoError(t, err)
+	defer handler.store.Close()
+
+	// the first ctx is finished and moved into txLog
+	ctxList := generateCtx(2, cc.CtxStatusExecuted)
+	assert.NoError(t, handler.txLog.AddFinish(ctxList[0]))
+	_, err = handler.txLog.Commit()
+	assert.NoError(t, err)
+
+	db, err := handler.store.GetStore(common.Big0)
+	assert.NoError(t, err)
+	store := unfinishedStore{db, handler}
+	assert.NoError(t, store.Writes(ctxList, true))
+	assert.False(t, db.Has(ctxList[0].ID()))
+	assert.True(t, db.Has(ctxList[1].ID()))
+}
+
+func TestHandler_CancelCtx(t *testing.T) {
+	handler, err := newHandlerTester(common.Big0)
+	assert.NoError(t, err)
+	defer handler.store.Close()
+	ctxList := generateCtx(4, cc.CtxStatusIllegal)
+	ctxList[1].Status = cc.CtxStatusWaiting
+	ctxList[2].Status = cc.CtxStatusFinished
+	ctxList[3].Status = cc.CtxStatusWaiting
+	assert.NoError(t, handler.store.Adds(common.Big0, ctxList, false))
+	handler.retriever = expireRetriever{expired: map[common.Hash]bool{ctxList[3].ID(): true}}
+
+	data, err := handler.CancelCtx(ctxList[0].ID())
+	assert.NoError(t, err)
+	takerCancel := crypto.Keccak256([]byte("takerCancel((uint256,bytes32,bytes32,address,address,bytes32,uint256,bytes,uint256[],bytes32[],bytes32[]),uint256)"))
+	assert.Equal(t, takerCancel[:4], data[:4])
+
+	_, err = handler.CancelCtx(ctxList[1].ID())
+	assert.Equal(t, ErrCtxNotExpired, err)
+	_, err = handler.CancelCtx(ctxList[2].ID())
+	assert.Error(t, err)
+	_, err = handler.CancelCtx(common.HexToHash("0xff"))
+	assert.Error(t, err)
+
+	// the waiting ctx is cancelled after it is expired
+	data, err = handler.CancelCtx(ctxList[3].ID())
+	assert.NoError(t, err)
+	assert.Equal(t, takerCancel[:4], data[:4])
+
+	// cancelling and cancelled are not mixed up with the taker flow
+	err = handler.store.Updates(common.Big0, []*cc.CrossTransactionModifier{
+		{Type: cc.Normal, ID: ctxList[3].ID(), Status: cc.CtxStatusCancelling, AtBlockNumber: 2},
+		{Type: cc.Normal, ID: ctxList[2].ID(), Status: cc.CtxStatusCancelling, AtBlockNumber: 2},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, cc.CtxStatusCancelling, handler.store.Get(common.Big0, ctxList[3].ID()).Status)
+	assert.Equal(t, cc.CtxStatusFinished, handler.store.Get(common.Big0, ctxList[2].ID()).Status)
+
+	err = handler.store.Updates(common.Big0, []*cc.CrossTransactionModifier{
+		{Type: cc.Normal, ID: ctxList[3].ID(), Status: cc.CtxStatusCancelled, AtBlockNumber: 3},
+		{Type: cc.Reorg, ID: ctxList[3].ID(), Status: cc.CtxStatusExecuted},
+		{Type: cc.Normal, ID: ctxList[3].ID(), Status: cc.CtxStatusFinished, AtBlockNumber: 4},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, cc.CtxStatusCancelled, handler.store.Get(common.Big0, ctxList[3].ID()).Status)
+
+	err = handler.store.Updates(common.Big0, []*cc.CrossTransactionModifier{
+		{Type: cc.Reorg, ID: ctxList[3].ID(), Status: cc.CtxStatusCancelling},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, cc.CtxStatusCancelling, handler.store.Get(common.Big0, ctxList[3].ID()).Status)
+}
+
+func TestHandler_RefundCancels(t *testing.T) {
+	handler, err := newHandlerTester(common.Big0)
+	assert.NoError(t, err)
+	defer handler.store.Close()
+	ctxList := generateCtx(4, cc.CtxStatusCancelling)
+	ctxList[2].Status = cc.CtxStatusWaiting
+	ctxList[3].Status = cc.CtxStatusCancelled
+	assert.NoError(t, handler.store.Adds(common.Big0, ctxList, false))
+
+	var (
+		executor  = new(cancelExecutor)
+		retriever = expireRetriever{expired: map[common.Hash]bool{ctxList[0].ID(): true}}
+		cancels   = make([]*cc.ReceptTransaction, len(ctxList))
+	)
+	handler.executor, handler.retriever, handler.log = executor, retriever, log.New()
+	handler.cancels = make(map[common.Hash]*cc.ReceptTransaction)
+	for i, ctx := range ctxList {
+		cancels[i] = cc.NewReceptTransaction(ctx.ID(), common.Hash{}, ctx.Data.From, ctx.Data.From, common.Big0, testRemoteID)
+	}
+
+	// only the cancel of expired ctx is refunded, the unexpired and the not updated are kept
+	handler.refundCancels(cancels)
+	assert.Equal(t, []common.Hash{ctxList[0].ID()}, executor.refunds)
+	assert.Equal(t, 2, len(handler.cancels))
+
+	retriever.expired[ctxList[1].ID()] = true
+	handler.refundCancels(nil)
+	assert.Equal(t, []common.Hash{ctxList[0].ID(), ctxList[1].ID()}, executor.refunds)
+
+	// refunded at once if ctxs never expire
+	assert.NoError(t, handler.store.Updates(common.Big0, []*cc.CrossTransactionModifier{
+		{Type: cc.Remote, ID: ctxList[2].ID(), Status: cc.CtxStatusCancelling, AtBlockNumber: 2},
+	}))
+	handler.retriever = expireRetriever{expireNumber: -1}
+	handler.refundCancels(nil)
+	assert.Equal(t, ctxList[2].ID(), executor.refunds[2])
+	assert.Equal(t, 0, len(handler.cancels))
+}
+
+// cancelExecutor records the refunded ctxs
+type cancelExecutor struct {
+	trigger.Executor
+	refunds []common.Hash
+}
+
+func (e *cancelExecutor) SubmitCancel(rtxs []*cc.ReceptTransaction) {
+	for _, rtx := range rtxs {
+		e.refunds = append(e.refunds, rtx.CTxId)
+	}
+}
+
+// expireRetriever expires the ctxs in the expired set
+type expireRetriever struct {
+	testChainRetriever
+	expired      map[common.Hash]bool
+	expireNumber int
+}
+
+func (r expireRetriever) ExpireNumber() int {
+	return r.expireNumber
+}
+
+func (r expireRetriever) VerifyExpire(ctx *cc.CrossTransaction) error {
+	if r.expired[ctx.ID()] {
+		return cross.ErrExpiredCtx
+	}
+	return nil
+}
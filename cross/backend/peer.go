@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/common/mclock"
 	"gbchain-org/go-gbchain/p2p"
 
 	"gbchain-org/go-gbchain/cross/backend/synchronise"
@@ -15,13 +16,11 @@ import (
 	mapset "github.com/deckarep/golang-set"
 )
 
-// statusData is the network packet for the status message for eth/64 and later.
+// crossStatusData is the network packet for the status message of the anchor protocol.
 type crossStatusData struct {
-	ProtocolVersion             uint32
-	MainNetworkID, SubNetworkID uint64
-	MainGenesis, SubGenesis     common.Hash
-	MainHeight, SubHeight       *big.Int
-	MainContract, SubContract   common.Address
+	ProtocolVersion uint32
+	Chains          []crossChainStatus
+	Pairs           []crossPairStatus
 }
 
 type anchorPeer struct {
@@ -31,47 +30,46 @@ type anchorPeer struct {
 	rw          p2p.MsgReadWriter
 	term        chan struct{} // Termination channel to stop the broadcaster
 	crossStatus crossStatusData
+	pairs       map[cc.ChainPair]*big.Int // chain pairs supported by both sides -> peer height
+	score       *peerScore
 
-	knownCTxs           mapset.Set
-	queuedLocalCtxSign  chan *cc.CrossTransaction // ctx signed by local anchor
-	queuedRemoteCtxSign chan *cc.CrossTransaction // signed ctx received by others
+	knownCTxs           mapset.Set           // ctxs sent by the peer or queued to it
+	receivedCTxs        mapset.Set           // ctxs sent by the peer
+	queuedLocalCtxSign  chan *ctxProofPacket // ctx signed by local anchor
+	queuedRemoteCtxSign chan *ctxProofPacket // signed ctx received by others
 	pendingFetchRequest chan *synchronise.SyncPendingReq
 }
 
-func newAnchorPeer(p *p2p.Peer, rw p2p.MsgReadWriter) *anchorPeer {
+func newAnchorPeer(version int, p *p2p.Peer, rw p2p.MsgReadWriter) *anchorPeer {
 	return &anchorPeer{
 		Peer:                p,
-		version:             protocolVersion,
+		version:             version,
 		id:                  fmt.Sprintf("%x", p.ID().Bytes()[:8]),
 		rw:                  rw,
 		term:                make(chan struct{}),
-		queuedLocalCtxSign:  make(chan *cc.CrossTransaction, maxQueuedLocalCtx),
-		queuedRemoteCtxSign: make(chan *cc.CrossTransaction, maxQueuedRemoteCtx),
+		queuedLocalCtxSign:  make(chan *ctxProofPacket, maxQueuedLocalCtx),
+		queuedRemoteCtxSign: make(chan *ctxProofPacket, maxQueuedRemoteCtx),
 		knownCTxs:           mapset.NewSet(),
+		receivedCTxs:        mapset.NewSet(),
+		score:               newPeerScore(mclock.System{}),
 	}
 }
 
-func (p *anchorPeer) Handshake(mainNetwork, subNetwork uint64, mainGenesis, subGenesis common.Hash,
-	mainHeight, subHeight *big.Int, mainContract, subContract common.Address) error {
-
+// Handshake exchanges the served chains and chain pairs with the remote anchor,
+// and keeps the pairs supported by both sides.
+func (p *anchorPeer) Handshake(chains []crossChainStatus, pairs []crossPairStatus) error {
 	errc := make(chan error, 2)
 	go func() {
 		errc <- p2p.Send(p.rw, StatusMsg, &crossStatusData{
 			ProtocolVersion: uint32(p.version),
-			MainNetworkID:   mainNetwork,
-			SubNetworkID:    subNetwork,
-			MainGenesis:     mainGenesis,
-			SubGenesis:      subGenesis,
-			MainHeight:      mainHeight,
-			SubHeight:       subHeight,
-			MainContract:    mainContract,
-			SubContract:     subContract,
+			Chains:          chains,
+			Pairs:           pairs,
 		})
 	}()
 
 	var status crossStatusData
 	go func() {
-		errc <- p.readStatus(mainNetwork, subNetwork, mainGenesis, subGenesis, mainContract, subContract, &status)
+		errc <- p.readStatus(chains, &status)
 	}()
 
 	timeout := time.NewTimer(handshakeTimeout)
@@ -87,13 +85,26 @@ func (p *anchorPeer) Handshake(mainNetwork, subNetwork uint64, mainGenesis, subG
 			return p2p.DiscReadTimeout
 		}
 	}
+
+	local := make(map[cc.ChainPair]struct{}, len(pairs))
+	for _, pair := range pairs {
+		local[pair.Pair] = struct{}{}
+	}
+	shared := make(map[cc.ChainPair]*big.Int)
+	for _, pair := range status.Pairs {
+		if _, ok := local[pair.Pair]; ok && pair.Height != nil {
+			shared[pair.Pair] = pair.Height
+		}
+	}
+	if len(shared) == 0 {
+		return errResp(ErrChainPairMismatch, "local %d pairs, remote %d pairs", len(pairs), len(status.Pairs))
+	}
 	p.crossStatus = status
+	p.pairs = shared
 	return nil
 }
 
-func (p *anchorPeer) readStatus(mainNetwork, subNetwork uint64, mainGenesis, subGenesis common.Hash, mainContract,
-	subContract common.Address, status *crossStatusData) error {
-
+func (p *anchorPeer) readStatus(chains []crossChainStatus, status *crossStatusData) error {
 	msg, err := p.rw.ReadMsg()
 	if err != nil {
 		return err
@@ -107,42 +118,73 @@ func (p *anchorPeer) readStatus(mainNetwork, subNetwork uint64, mainGenesis, sub
 	if err := msg.Decode(&status); err != nil {
 		return errResp(ErrDecode, "msg %v: %v", msg, err)
 	}
-	if status.MainNetworkID != mainNetwork || status.SubNetworkID != subNetwork {
-		return errResp(ErrNetworkIDMismatch, "main/sub:%d/%d (!= %d/%d)", status.MainNetworkID, status.SubNetworkID, mainNetwork, subNetwork)
-	}
 	if int(status.ProtocolVersion) != p.version {
 		return errResp(ErrProtocolVersionMismatch, "%d (!= %d)", status.ProtocolVersion, p.version)
 	}
-	if status.MainGenesis != mainGenesis || status.SubGenesis != subGenesis {
-		return errResp(ErrGenesisMismatch, "main/sub:%s/%s (!= %s/%s)", status.MainGenesis.String(), status.SubGenesis.String(), mainGenesis.String(), subGenesis.String())
-	}
-	if status.MainContract != mainContract {
-		return errResp(ErrCrossMainChainMismatch, "%s (!=%s)", status.MainContract.String(), mainContract.String())
+	// chains served by both sides must be the same chain with the same cross contract
+	local := make(map[uint64]crossChainStatus, len(chains))
+	for _, chain := range chains {
+		local[chain.ChainID] = chain
 	}
-	if status.SubContract != subContract {
-		return errResp(ErrCrossSubChainMismatch, "%s (!=%s)", status.SubContract.String(), subContract.String())
+	for _, remote := range status.Chains {
+		chain, ok := local[remote.ChainID]
+		if !ok {
+			continue
+		}
+		if remote.Genesis != chain.Genesis {
+			return errResp(ErrGenesisMismatch, "chain %d: %s (!= %s)", chain.ChainID, remote.Genesis.String(), chain.Genesis.String())
+		}
+		if remote.Contract != chain.Contract {
+			return errResp(ErrCrossContractMismatch, "chain %d: %s (!= %s)", chain.ChainID, remote.Contract.String(), chain.Contract.String())
+		}
 	}
 	return nil
 }
 
-func (p *anchorPeer) RequestCtxSyncByHeight(chainID uint64, height uint64) error {
-	p.Log().Debug("Sending batch of ctx sync request", "chain", chainID, "height", height)
-	return p2p.Send(p.rw, GetCtxSyncMsg, &synchronise.SyncReq{Chain: chainID, Height: height})
+// SupportPair reports whether the chain pair is served by both anchors
+func (p *anchorPeer) SupportPair(pair cc.ChainPair) bool {
+	_, ok := p.pairs[pair]
+	return ok
 }
 
-func (p *anchorPeer) SendSyncResponse(chain uint64, data [][]byte) error {
-	p.Log().Debug("Sending batch of ctx sync response", "chain", chain, "count", len(data))
-	return p2p.Send(p.rw, CtxSyncMsg, &synchronise.SyncResp{Chain: chain, Data: data})
+// Height returns the store height of the peer for the chain pair at handshake
+func (p *anchorPeer) Height(pair cc.ChainPair) *big.Int {
+	return p.pairs[pair]
 }
 
-func (p *anchorPeer) RequestPendingSync(chain uint64, ids []common.Hash) error {
-	p.Log().Debug("Sending batch of ctx pending sync request", "chain", chain, "count", len(ids))
-	return p2p.Send(p.rw, GetPendingSyncMsg, &synchronise.SyncPendingReq{Chain: chain, Ids: ids})
+func (p *anchorPeer) RequestCtxSyncByHeight(chainID, remoteID uint64, height uint64) error {
+	p.Log().Debug("Sending batch of ctx sync request", "chain", chainID, "remote", remoteID, "height", height)
+	return p2p.Send(p.rw, GetCtxSyncMsg, &synchronise.SyncReq{Chain: chainID, Remote: remoteID, Height: height})
 }
 
-func (p *anchorPeer) SendSyncPendingResponse(chain uint64, data [][]byte) error {
-	p.Log().Debug("Sending batch of ctx pending sync response", "chain", chain, "count", len(data))
-	return p2p.Send(p.rw, PendingSyncMsg, &synchronise.SyncPendingResp{Chain: chain, Data: data})
+func (p *anchorPeer) SendSyncResponse(chain, remote uint64, data [][]byte) error {
+	p.Log().Debug("Sending batch of ctx sync response", "chain", chain, "remote", remote, "count", len(data))
+	return p2p.Send(p.rw, CtxSyncMsg, &synchronise.SyncResp{Chain: chain, Remote: remote, Data: data})
+}
+
+// RequestCtxDigests requests the digests of store ranges in blocks [begin, end], the
+// peers before cross4 don't serve digests.
+func (p *anchorPeer) RequestCtxDigests(chainID, remoteID uint64, begin, end, span uint64) error {
+	if p.version < cross4 {
+		return synchronise.ErrDigestUnsupported
+	}
+	p.Log().Debug("Sending ctx digests request", "chain", chainID, "remote", remoteID, "begin", begin, "end", end)
+	return p2p.Send(p.rw, GetCtxDigestsMsg, &synchronise.DigestReq{Chain: chainID, Remote: remoteID, Begin: begin, End: end, Span: span})
+}
+
+func (p *anchorPeer) SendCtxDigests(chain, remote uint64, digests []synchronise.RangeDigest) error {
+	p.Log().Debug("Sending ctx digests response", "chain", chain, "remote", remote, "count", len(digests))
+	return p2p.Send(p.rw, CtxDigestsMsg, &synchronise.DigestResp{Chain: chain, Remote: remote, Digests: digests})
+}
+
+func (p *anchorPeer) RequestPendingSync(chain, remote uint64, ids []common.Hash) error {
+	p.Log().Debug("Sending batch of ctx pending sync request", "chain", chain, "remote", remote, "count", len(ids))
+	return p2p.Send(p.rw, GetPendingSyncMsg, &synchronise.SyncPendingReq{Chain: chain, Remote: remote, Ids: ids})
+}
+
+func (p *anchorPeer) SendSyncPendingResponse(chain, remote uint64, data [][]byte) error {
+	p.Log().Debug("Sending batch of ctx pending sync response", "chain", chain, "remote", remote, "count", len(data))
+	return p2p.Send(p.rw, PendingSyncMsg, &synchronise.SyncPendingResp{Chain: chain, Remote: remote, Data: data})
 }
 
 func (p *anchorPeer) MarkCrossTransaction(hash common.Hash) {
@@ -157,21 +199,58 @@ func (p *anchorPeer) HasCrossTransaction(hash common.Hash) bool {
 	return p.knownCTxs.Contains(hash)
 }
 
-func (p *anchorPeer) SendCrossTransaction(ctx *cc.CrossTransaction) error {
+// MarkReceivedCtx marks the ctx sent by the peer, it is known by the peer as well
+func (p *anchorPeer) MarkReceivedCtx(hash common.Hash) {
+	for p.receivedCTxs.Cardinality() >= maxKnownCtx {
+		p.receivedCTxs.Pop()
+	}
+	p.receivedCTxs.Add(hash)
+	p.MarkCrossTransaction(hash)
+}
+
+// HasReceivedCtx reports whether the ctx is sent by the peer before
+func (p *anchorPeer) HasReceivedCtx(hash common.Hash) bool {
+	return p.receivedCTxs.Contains(hash)
+}
+
+// SendCrossTransaction sends the signed ctx, the receipt proof is attached if it is not nil
+func (p *anchorPeer) SendCrossTransaction(ctx *cc.CrossTransaction, proof *cc.ReceiptProof) error {
+	if proof != nil {
+		return p2p.Send(p.rw, CtxProofMsg, &ctxProofPacket{Ctx: ctx, Proof: proof})
+	}
 	return p2p.Send(p.rw, CtxSignMsg, ctx)
 }
 
-func (p *anchorPeer) AsyncSendCrossTransaction(ctx *cc.CrossTransaction, local bool) {
+// SendCtxHashes announces the sign hashes of ctxs, peers request the ones they lack
+func (p *anchorPeer) SendCtxHashes(hashes []common.Hash) error {
+	return p2p.Send(p.rw, NewCtxHashesMsg, hashes)
+}
+
+// RequestCtxs fetches a batch of ctxs by the announced sign hashes
+func (p *anchorPeer) RequestCtxs(hashes []common.Hash) error {
+	p.Log().Debug("Fetching batch of ctxs", "count", len(hashes))
+	return p2p.Send(p.rw, GetCtxsMsg, hashes)
+}
+
+// SendCtxs sends a batch of ctxs requested by the peer
+func (p *anchorPeer) SendCtxs(packets []*ctxProofPacket) error {
+	return p2p.Send(p.rw, CtxsMsg, packets)
+}
+
+// AsyncSendCrossTransaction queues the ctx to be sent to the peer. Since cross3, the ctxs
+// received from others are announced by hashes, while the local signed ones are still sent directly.
+func (p *anchorPeer) AsyncSendCrossTransaction(ctx *cc.CrossTransaction, proof *cc.ReceiptProof, local bool) {
+	packet := &ctxProofPacket{Ctx: ctx, Proof: proof}
 	if local {
 		// local signed ctx, wait until sent to queuedLocalCtxSign
-		p.queuedLocalCtxSign <- ctx
+		p.queuedLocalCtxSign <- packet
 		p.knownCTxs.Add(ctx.SignHash())
 		return
 	}
 
 	// received from p2p
 	select {
-	case p.queuedRemoteCtxSign <- ctx:
+	case p.queuedRemoteCtxSign <- packet:
 		p.knownCTxs.Add(ctx.SignHash())
 	default:
 		p.Log().Debug("Dropping ctx propagation", "hash", ctx.SignHash())
@@ -184,14 +263,32 @@ func (p *anchorPeer) broadcast() {
 		case <-p.term:
 			return
 
-		case ctx := <-p.queuedLocalCtxSign:
-			if err := p.SendCrossTransaction(ctx); err != nil {
+		case packet := <-p.queuedLocalCtxSign:
+			if err := p.SendCrossTransaction(packet.Ctx, packet.Proof); err != nil {
 				p.Log().Trace("SendCrossTransaction", "err", err)
 				return
 			}
-		case ctx := <-p.queuedRemoteCtxSign:
-			if err := p.SendCrossTransaction(ctx); err != nil {
-				p.Log().Trace("SendCrossTransaction", "err", err)
+		case packet := <-p.queuedRemoteCtxSign:
+			if p.version < cross3 {
+				if err := p.SendCrossTransaction(packet.Ctx, packet.Proof); err != nil {
+					p.Log().Trace("SendCrossTransaction", "err", err)
+					return
+				}
+				break
+			}
+			// announce the queued ctxs in one batch
+			hashes := []common.Hash{packet.Ctx.SignHash()}
+		batch:
+			for len(hashes) < maxCtxAnnounce {
+				select {
+				case packet := <-p.queuedRemoteCtxSign:
+					hashes = append(hashes, packet.Ctx.SignHash())
+				default:
+					break batch
+				}
+			}
+			if err := p.SendCtxHashes(hashes); err != nil {
+				p.Log().Trace("SendCtxHashes", "err", err)
 				return
 			}
 		}
@@ -199,17 +296,23 @@ func (p *anchorPeer) broadcast() {
 }
 
 type CrossPeerInfo struct {
-	Version    int      `json:"version"`
-	MainHeight *big.Int `json:"mainHeight"`
-	SubHeight  *big.Int `json:"subHeight"`
+	ID      string               `json:"id"`
+	Version int                  `json:"version"`
+	Pairs   []*CrossPeerPairInfo `json:"pairs"`
+	Score   *CrossPeerScore      `json:"score"`
+}
+
+type CrossPeerPairInfo struct {
+	Pair   cc.ChainPair `json:"pair"`
+	Height *big.Int     `json:"height"`
 }
 
 func (p *anchorPeer) Info() *CrossPeerInfo {
-	return &CrossPeerInfo{
-		Version:    p.version,
-		MainHeight: p.crossStatus.MainHeight,
-		SubHeight:  p.crossStatus.SubHeight,
+	info := &CrossPeerInfo{ID: p.ID().String(), Version: p.version, Score: p.score.info()}
+	for pair, height := range p.pairs {
+		info.Pairs = append(info.Pairs, &CrossPeerPairInfo{Pair: pair, Height: height})
 	}
+	return info
 }
 
 // close signals the broadcast goroutine to terminate.
@@ -286,35 +389,46 @@ func (ps *anchorSet) Peer(id string) *anchorPeer {
 	return ps.peers[id]
 }
 
-func (ps *anchorSet) BestPeer() (main, sub *anchorPeer) {
+// BestPeer returns the peer with the highest store height of the chain pair
+func (ps *anchorSet) BestPeer(pair cc.ChainPair) *anchorPeer {
 	ps.lock.RLock()
 	defer ps.lock.RUnlock()
 
 	var (
-		bestMainPeer, bestSubPeer     *anchorPeer
-		bestMainHeight, bestSubHeight *big.Int
+		bestPeer   *anchorPeer
+		bestHeight *big.Int
 	)
 	for _, p := range ps.peers {
-		status := p.crossStatus
-		//分别找出mainHeight和subHeight最大的peer
-		if bestMainPeer == nil || status.MainHeight.Cmp(bestMainHeight) > 0 {
-			bestMainPeer, bestMainHeight = p, status.MainHeight
+		height := p.Height(pair)
+		if height == nil {
+			continue
 		}
-
-		if bestSubPeer == nil || status.SubHeight.Cmp(bestSubHeight) > 0 {
-			bestSubPeer, bestSubHeight = p, status.SubHeight
+		if bestPeer == nil || height.Cmp(bestHeight) > 0 {
+			bestPeer, bestHeight = p, height
 		}
 	}
-	return bestMainPeer, bestSubPeer
+	return bestPeer
+}
+
+// AllPeers returns all of the registered peers
+func (ps *anchorSet) AllPeers() []*anchorPeer {
+	ps.lock.RLock()
+	defer ps.lock.RUnlock()
+
+	list := make([]*anchorPeer, 0, len(ps.peers))
+	for _, p := range ps.peers {
+		list = append(list, p)
+	}
+	return list
 }
 
-func (ps *anchorSet) PeersWithoutCtx(hash common.Hash) []*anchorPeer {
+func (ps *anchorSet) PeersWithoutCtx(pair cc.ChainPair, hash common.Hash) []*anchorPeer {
 	ps.lock.RLock()
 	defer ps.lock.RUnlock()
 
 	list := make([]*anchorPeer, 0, len(ps.peers))
 	for _, p := range ps.peers {
-		if !p.knownCTxs.Contains(hash) {
+		if p.SupportPair(pair) && !p.knownCTxs.Contains(hash) {
 			list = append(list, p)
 		}
 	}
@@ -36,20 +36,25 @@ type finishedLog interface {
 
 // CrossPool is used for collecting multisign signatures
 type CrossPool struct {
-	chainID *big.Int
-	config  *cross.Config
+	chainID  *big.Int
+	remoteID *big.Int
+	config   *cross.Config
 
 	store        store
 	retriever    trigger.ChainRetriever
 	pending      *db.CtxSortedByBlockNum //带有local签名
 	queued       *db.CtxSortedByBlockNum //网络其他节点签名
 	pendingCache *lru.Cache              // cache signed pending ctx
+	aggregated   *lru.Cache              // cache aggregated BLS signatures of committed ctx
+	signed       *lru.Cache              // remote ctxs signed by anchors, signedKey => *cc.CrossTransaction
 
-	commitFeed  event.Feed
-	commitScope event.SubscriptionScope
+	commitFeed   event.Feed
+	evidenceFeed event.Feed
+	commitScope  event.SubscriptionScope
 
 	signer   cc.CtxSigner
 	signHash cc.SignHash
+	signData cc.SignData // signs ctxs by signing data if it is set, used by external signers
 	txLog    finishedLog
 
 	mu     sync.RWMutex
@@ -59,14 +64,21 @@ type CrossPool struct {
 	logger log.Logger
 }
 
-func NewCrossPool(chainID *big.Int, config *cross.Config, store store, txLog finishedLog,
-	retriever trigger.ChainRetriever, signHash cc.SignHash) *CrossPool {
+func NewCrossPool(chainID, remoteID *big.Int, config *cross.Config, store store, txLog finishedLog,
+	retriever trigger.ChainRetriever, signHash cc.SignHash, signData cc.SignData) (*CrossPool, error) {
 
+	signer, err := config.CtxSigner(chainID)
+	if err != nil {
+		return nil, err
+	}
 	pendingCache, _ := lru.New(signedPendingSize)
-	logger := log.New("X-module", "pool")
+	aggregated, _ := lru.New(signedPendingSize)
+	signed, _ := lru.New(signedRemoteSize)
+	logger := log.New("X-module", "pool", "remoteID", remoteID)
 
 	pool := &CrossPool{
 		chainID:      chainID,
+		remoteID:     remoteID,
 		config:       config,
 		store:        store,
 		txLog:        txLog,
@@ -74,8 +86,11 @@ func NewCrossPool(chainID *big.Int, config *cross.Config, store store, txLog fin
 		pending:      db.NewCtxSortedMap(),
 		queued:       db.NewCtxSortedMap(),
 		pendingCache: pendingCache,
-		signer:       cc.MakeCtxSigner(chainID),
+		aggregated:   aggregated,
+		signed:       signed,
+		signer:       signer,
 		signHash:     signHash,
+		signData:     signData,
 		stopCh:       make(chan struct{}),
 		logger:       logger,
 	}
@@ -87,7 +102,7 @@ func NewCrossPool(chainID *big.Int, config *cross.Config, store store, txLog fin
 	pool.wg.Add(1)
 	go pool.loop()
 
-	return pool
+	return pool, nil
 }
 
 func (pool *CrossPool) load() error {
@@ -95,7 +110,8 @@ func (pool *CrossPool) load() error {
 	if err != nil {
 		return err
 	}
-	pending := store.Query(0, 0, []db.FieldName{db.BlockNumField}, false, q.Eq(db.StatusField, uint8(cc.CtxStatusPending)))
+	pending := store.Query(0, 0, []db.FieldName{db.BlockNumField}, false,
+		q.Eq(db.StatusField, uint8(cc.CtxStatusPending)), q.Eq(db.DestinationId, pool.remoteID))
 
 	pool.logger.Info("load pending tx from store", "count", len(pending))
 
@@ -197,13 +213,19 @@ func (pool *CrossPool) GetLocal(ctxID common.Hash) *cc.CrossTransaction {
 
 func (pool *CrossPool) AddRemotes(ctxList []*cc.CrossTransaction) ([]common.Address, []error) {
 	var (
-		signers []common.Address
-		errs    []error
-		legals  []*cc.CrossTransaction
+		signers   []common.Address
+		errs      []error
+		legals    []*cc.CrossTransaction
+		evidences []*cc.Evidence
 	)
 
 	for _, ctx := range ctxList {
-		signer, err := pool.addRemoteTx(ctx)
+		signer, ev, err := pool.addRemoteTx(ctx)
+		if ev != nil {
+			pool.logger.Warn("anchor misbehaving", "kind", ev.Kind, "signer", ev.Signer.String(), "ctxID", ctx.ID().String())
+			cm.Report(pool.chainID.Uint64(), "anchor misbehaving", "kind", ev.Kind.String(), "signer", ev.Signer.String(), "ctxID", ctx.ID().String())
+			evidences = append(evidences, ev)
+		}
 		signers = append(signers, signer)
 		switch err {
 		case nil: // no error, add them
@@ -219,6 +241,9 @@ func (pool *CrossPool) AddRemotes(ctxList []*cc.CrossTransaction) ([]common.Addr
 		}
 	}
 
+	if len(evidences) > 0 {
+		pool.evidenceFeed.Send(cc.EvidenceEvent{Evidences: evidences})
+	}
 	_, addErrs := pool.addTxs(legals, false)
 	errs = append(errs, addErrs...)
 	return signers, errs
@@ -233,37 +258,97 @@ func (pool *CrossPool) AddRemote(ctx *cc.CrossTransaction) (signer common.Addres
 	return signers[0], nil
 }
 
-func (pool *CrossPool) addRemoteTx(ctx *cc.CrossTransaction) (signer common.Address, err error) {
+// addRemoteTx verifies the remote ctx, the evidence is returned if its signer misbehaves
+func (pool *CrossPool) addRemoteTx(ctx *cc.CrossTransaction) (signer common.Address, ev *cc.Evidence, err error) {
 	signer, err = pool.retriever.VerifySigner(ctx, ctx.ChainId(), ctx.DestinationId())
 	if err != nil {
-		return signer, err
+		return signer, nil, err
 	}
 	// self signer ignore
 	if signer == pool.config.Signer {
-		return signer, cross.ErrLocalSignCtx
+		return signer, nil, cross.ErrLocalSignCtx
 	}
+	ev = pool.checkConflict(signer, ctx)
 	if pool.txLog.IsFinish(ctx.ID()) {
 		// already exist in finished log, ignore ctx
-		return signer, cross.ErrFinishedCtx
+		return signer, ev, cross.ErrFinishedCtx
 	}
 	// already exist in store and not at pending status
 	if old := pool.store.Get(pool.chainID, ctx.ID()); old != nil && old.Status != cc.CtxStatusPending {
 		pool.logger.Debug("ctx is already signed", "ctxID", ctx.ID().String())
-		return signer, cross.ErrAlreadyExistCtx
+		return signer, ev, cross.ErrAlreadyExistCtx
 	}
 	// check transaction is expired
 	if err := pool.retriever.VerifyExpire(ctx); err != nil {
-		return signer, err
+		return signer, ev, err
 	}
 	// check contract include this maker transaction
 	if err := pool.retriever.VerifyContract(ctx); err != nil {
-		return signer, err
+		if ev == nil && err == cross.ErrRepetitionCtx && pool.isMissingMaker(ctx) {
+			ev = pool.newEvidence(cc.NewNoMakerEvidence(pool.signer, ctx, uint64(time.Now().Unix())))
+		}
+		return signer, ev, err
+	}
+	return signer, ev, nil
+}
+
+// signedKey identifies the ctxs signed by an anchor
+type signedKey struct {
+	signer common.Address
+	id     common.Hash
+}
+
+// checkConflict returns the evidence if the signer has signed another ctx with the same CTxId,
+// the ctxs known by pool and the stored signatures are checked.
+func (pool *CrossPool) checkConflict(signer common.Address, ctx *cc.CrossTransaction) *cc.Evidence {
+	key := signedKey{signer: signer, id: ctx.ID()}
+	var prev *cc.CrossTransaction
+	if signed, ok := pool.signed.Get(key); ok {
+		prev = signed.(*cc.CrossTransaction)
+	} else {
+		pool.signed.Add(key, ctx)
+		if old := pool.store.Get(pool.chainID, ctx.ID()); old != nil {
+			for _, stored := range old.Resolution() {
+				if from, err := cc.CtxSender(pool.signer, stored); err == nil && from == signer {
+					prev = stored
+					break
+				}
+			}
+		}
+	}
+	if prev == nil || prev.Hash() == ctx.Hash() {
+		return nil
+	}
+	return pool.newEvidence(cc.NewConflictEvidence(pool.signer, prev, ctx, uint64(time.Now().Unix())))
+}
+
+// isMissingMaker reports whether the maker of local ctx does not exist, while the block of the ctx is confirmed
+// and the ctx is never seen by the local anchor. The ctxs of remote chains are validated by their own anchors.
+func (pool *CrossPool) isMissingMaker(ctx *cc.CrossTransaction) bool {
+	if ctx.ChainId().Cmp(pool.chainID) != 0 {
+		return false
 	}
-	return signer, nil
+	if pool.retriever.GetTransactionNumberOnChain(ctx)+pool.retriever.ConfirmedDepth() > pool.retriever.CurrentBlockNumber() {
+		return false // the block is unconfirmed or unknown, the local chain could be falling behind
+	}
+	return pool.pending.Get(ctx.ID()) == nil && pool.store.Get(pool.chainID, ctx.ID()) == nil
+}
+
+func (pool *CrossPool) newEvidence(ev *cc.Evidence, err error) *cc.Evidence {
+	if err != nil {
+		pool.logger.Debug("invalid evidence", "error", err)
+		return nil
+	}
+	return ev
 }
 
 func (pool *CrossPool) signTx(ctx *cc.CrossTransaction) (*cc.CrossTransaction, error) {
-	ctx, err := cc.SignCtx(ctx, pool.signer, pool.signHash)
+	var err error
+	if pool.signData != nil {
+		ctx, err = cc.SignCtxData(ctx, pool.signer, pool.signData)
+	} else {
+		ctx, err = cc.SignCtx(ctx, pool.signer, pool.signHash)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -296,6 +381,15 @@ func (pool *CrossPool) addTx(ctx *cc.CrossTransaction, local bool) (*cc.CrossTra
 	// check transaction's signatures is enough
 	checkAndCommit := func(id common.Hash) (*cc.CrossTransactionWithSignatures, error) {
 		if cws := pool.pending.Get(id); cws != nil && cws.SignaturesLength() >= pool.retriever.RequireSignatures() {
+			// aggregate BLS signatures, ctx is not committed until all the signatures are aggregated
+			if signer, ok := pool.signer.(cc.BLSCtxSigner); ok {
+				agg, err := signer.Aggregate(cws)
+				if err != nil {
+					pool.logger.Warn("aggregate signatures failed", "ctxID", id.String(), "error", err)
+					return nil, err
+				}
+				pool.aggregated.Add(id, agg)
+			}
 			return cws, nil
 		}
 		return nil, nil
@@ -378,6 +472,23 @@ func (pool *CrossPool) Commit(txs []*cc.CrossTransactionWithSignatures) {
 	}()
 }
 
+// AggregatedSignature returns the aggregated BLS signature of ctx
+func (pool *CrossPool) AggregatedSignature(cws *cc.CrossTransactionWithSignatures) (*cc.AggregatedSignature, error) {
+	signer, ok := pool.signer.(cc.BLSCtxSigner)
+	if !ok {
+		return nil, cc.ErrNotBLSSigner
+	}
+	if agg, ok := pool.aggregated.Get(cws.ID()); ok {
+		return agg.(*cc.AggregatedSignature), nil
+	}
+	agg, err := signer.Aggregate(cws)
+	if err != nil {
+		return nil, err
+	}
+	pool.aggregated.Add(cws.ID(), agg)
+	return agg, nil
+}
+
 // Store ctx into CrossStore
 func (pool *CrossPool) Store(cwsList []*cc.CrossTransactionWithSignatures) {
 	// if pending exist, update to waiting
@@ -393,6 +504,7 @@ func (pool *CrossPool) Rollback(cws *cc.CrossTransactionWithSignatures, invalidS
 	for _, invalid := range invalidSigIndex {
 		cws.RemoveSignature(invalid)
 	}
+	pool.aggregated.Remove(cws.ID())
 	pool.pending.Put(cws)
 	cm.Report(pool.chainID.Uint64(), "pending rollback for invalid signature", "ctxID", cws.ID(), "invalidSigIndex", invalidSigIndex)
 }
@@ -428,6 +540,13 @@ func (pool *CrossPool) Pending(startNumber uint64, limit int) (ids []common.Hash
 	return ids, pending
 }
 
+// SubscribeEvidenceEvent registers a subscription of the evidences of misbehaving anchors
+func (pool *CrossPool) SubscribeEvidenceEvent(ch chan<- cc.EvidenceEvent) event.Subscription {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	return pool.commitScope.Track(pool.evidenceFeed.Subscribe(ch))
+}
+
 func (pool *CrossPool) SubscribeSignedCtxEvent(ch chan<- cc.SignedCtxEvent) event.Subscription {
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
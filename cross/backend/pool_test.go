@@ -9,7 +9,9 @@ import (
 	"time"
 
 	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/common/hexutil"
 	"gbchain-org/go-gbchain/crypto"
+	"gbchain-org/go-gbchain/crypto/bls"
 	"gbchain-org/go-gbchain/params"
 
 	"gbchain-org/go-gbchain/cross"
@@ -35,7 +37,7 @@ func newPoolTester(store store) *poolTester {
 	fromSigner := func(hash []byte) ([]byte, error) { return crypto.Sign(hash, localKey) }
 
 	return &poolTester{
-		CrossPool: *NewCrossPool(params.TestChainConfig.ChainID, &cross.Config{}, store, testFinishLog{}, testChainRetriever{}, fromSigner),
+		CrossPool: *mustCrossPool(NewCrossPool(params.TestChainConfig.ChainID, common.Big0, &cross.Config{}, store, testFinishLog{}, testChainRetriever{}, fromSigner, nil)),
 		store:     store,
 		chainID:   chainID,
 		localKey:  localKey,
@@ -43,6 +45,13 @@ func newPoolTester(store store) *poolTester {
 	}
 }
 
+func mustCrossPool(pool *CrossPool, err error) *CrossPool {
+	if err != nil {
+		panic(err)
+	}
+	return pool
+}
+
 func TestCrossPool_Add(t *testing.T) {
 	p := newPoolTester(newTestMemoryStore())
 	signedCh := make(chan cc.SignedCtxEvent, 1) // receive signed ctx
@@ -145,6 +154,55 @@ func TestCrossPool_Commit(t *testing.T) {
 
 }
 
+func TestCrossPool_AddBLS(t *testing.T) {
+	chainID := params.TestChainConfig.ChainID
+	localKey, _ := bls.GenerateKey(nil)
+	remoteKey, _ := bls.GenerateKey(nil)
+	localAddr, remoteAddr := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	config := &cross.Config{
+		Signer: localAddr,
+		BLS: &cross.BLSConfig{Anchors: map[common.Address]hexutil.Bytes{
+			localAddr:  localKey.PublicKey().Marshal(),
+			remoteAddr: remoteKey.PublicKey().Marshal(),
+		}},
+	}
+	p, err := NewCrossPool(chainID, common.Big0, config, newTestMemoryStore(), testFinishLog{}, testChainRetriever{}, localKey.SignHash, nil)
+	assert.NoError(t, err)
+	signedCh := make(chan cc.SignedCtxEvent, 1)
+	p.SubscribeSignedCtxEvent(signedCh)
+
+	ctx := generateCtx(1, cc.CtxStatusPending)[0].CrossTransaction()
+	signer, err := config.CtxSigner(chainID)
+	assert.NoError(t, err)
+	remoteTx, err := cc.SignCtx(ctx, signer, remoteKey.SignHash)
+	assert.NoError(t, err)
+	_, err = p.addTx(remoteTx, false)
+	assert.NoError(t, err)
+	_, _, errs := p.AddLocals(ctx)
+	assert.Nil(t, errs)
+
+	select {
+	case <-time.After(time.Second):
+		t.Fatal("add timeout")
+	case ev := <-signedCh:
+		cws := ev.Txs[0]
+		assert.Equal(t, 2, cws.SignaturesLength())
+		for _, tx := range cws.Resolution() {
+			_, err := cc.CtxSender(signer, tx)
+			assert.NoError(t, err)
+		}
+		agg, err := p.AggregatedSignature(cws)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []common.Address{localAddr, remoteAddr}, agg.Signers)
+		assert.NoError(t, signer.(cc.BLSCtxSigner).VerifyAggregated(ctx, agg))
+	}
+
+	// the pool is not created by invalid public keys instead of signing by ECDSA
+	config.BLS = &cross.BLSConfig{Anchors: map[common.Address]hexutil.Bytes{remoteAddr: {0x01}}}
+	_, err = NewCrossPool(chainID, common.Big0, config, newTestMemoryStore(), testFinishLog{}, testChainRetriever{}, localKey.SignHash, nil)
+	assert.Error(t, err)
+}
+
 func (p *poolTester) addLocal(t *testing.T) {
 	fromAddr := crypto.PubkeyToAddress(p.localKey.PublicKey)
 	toAddr := crypto.PubkeyToAddress(p.remoteKey.PublicKey)
@@ -259,3 +317,95 @@ func (r testChainRetriever) VerifySigner(ctx *cc.CrossTransaction, signChain, st
 func (r testChainRetriever) UpdateAnchors(info *cc.RemoteChainInfo) error { return nil }
 func (r testChainRetriever) RequireSignatures() int                       { return 2 }
 func (r testChainRetriever) ExpireNumber() int                            { return -1 }
+
+// evidenceRetriever recovers the signers of ctxs, the maker logs are missing if noMaker is set
+type evidenceRetriever struct {
+	testChainRetriever
+	noMaker bool
+}
+
+func (r evidenceRetriever) VerifySigner(ctx *cc.CrossTransaction, signChain, storeChainID *big.Int) (common.Address, error) {
+	return cc.CtxSender(cc.MakeCtxSigner(signChain), ctx)
+}
+
+func (r evidenceRetriever) VerifyContract(cws trigger.Transaction) error {
+	if r.noMaker {
+		return cross.ErrRepetitionCtx
+	}
+	return nil
+}
+
+func TestCrossPool_Evidence(t *testing.T) {
+	var (
+		chainID      = params.TestChainConfig.ChainID
+		localKey, _  = crypto.GenerateKey()
+		remoteKey, _ = crypto.GenerateKey()
+		remoteAddr   = crypto.PubkeyToAddress(remoteKey.PublicKey)
+		signer       = cc.MakeCtxSigner(chainID)
+		config       = &cross.Config{Signer: crypto.PubkeyToAddress(localKey.PublicKey)}
+	)
+	sign := func(ctx *cc.CrossTransaction) *cc.CrossTransaction {
+		signed, err := cc.SignCtx(ctx, signer, func(hash []byte) ([]byte, error) { return crypto.Sign(hash, remoteKey) })
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	newCtx := func(value int64, blockHash common.Hash) *cc.CrossTransaction {
+		return cc.NewCrossTransaction(big.NewInt(value), big.NewInt(2e18), big.NewInt(19),
+			common.HexToHash("0x01"), common.HexToHash("0x02"), blockHash,
+			common.HexToAddress("0x03"), common.Address{}, nil)
+	}
+	expectEvidence := func(ch chan cc.EvidenceEvent) *cc.Evidence {
+		select {
+		case ev := <-ch:
+			assert.Equal(t, 1, len(ev.Evidences))
+			return ev.Evidences[0]
+		default:
+			return nil
+		}
+	}
+
+	p, err := NewCrossPool(chainID, big.NewInt(19), config, newTestMemoryStore(), testFinishLog{}, evidenceRetriever{}, nil, nil)
+	assert.NoError(t, err)
+	defer p.Stop()
+	evidenceCh := make(chan cc.EvidenceEvent, 1)
+	p.SubscribeEvidenceEvent(evidenceCh)
+
+	signed := sign(newCtx(1e18, common.HexToHash("0x04")))
+	_, err = p.AddRemote(signed)
+	assert.NoError(t, err)
+	assert.Nil(t, expectEvidence(evidenceCh))
+
+	// the ctx re-signed after reorg is not a conflict
+	_, err = p.AddRemote(sign(newCtx(1e18, common.HexToHash("0x05"))))
+	assert.Equal(t, cc.ErrInvalidSign, err)
+	assert.Nil(t, expectEvidence(evidenceCh))
+
+	// the anchor signs another value for the same ctx
+	conflict := sign(newCtx(2e18, common.HexToHash("0x04")))
+	p.AddRemote(conflict)
+	ev := expectEvidence(evidenceCh)
+	if assert.NotNil(t, ev) {
+		assert.Equal(t, cc.EvidenceConflict, ev.Kind)
+		assert.Equal(t, remoteAddr, ev.Signer)
+		assert.Equal(t, signed.Hash(), ev.Ctx.Hash())
+		assert.Equal(t, conflict.Hash(), ev.Conflict.Hash())
+		assert.NoError(t, ev.Verify(signer))
+	}
+
+	// the anchor signs a ctx of which the maker log does not exist
+	p, err = NewCrossPool(chainID, big.NewInt(19), config, newTestMemoryStore(), testFinishLog{}, evidenceRetriever{noMaker: true}, nil, nil)
+	assert.NoError(t, err)
+	defer p.Stop()
+	p.SubscribeEvidenceEvent(evidenceCh)
+	_, err = p.AddRemote(signed)
+	assert.Equal(t, cross.ErrRepetitionCtx, err)
+	ev = expectEvidence(evidenceCh)
+	if assert.NotNil(t, ev) {
+		assert.Equal(t, cc.EvidenceNoMaker, ev.Kind)
+		assert.Equal(t, remoteAddr, ev.Signer)
+		assert.Nil(t, ev.Conflict)
+		assert.NoError(t, ev.Verify(signer))
+	}
+}
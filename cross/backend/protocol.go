@@ -3,13 +3,25 @@ package backend
 import (
 	"errors"
 	"fmt"
+	"math/big"
 	"time"
 
 	"gbchain-org/go-gbchain/common"
+
+	cc "gbchain-org/go-gbchain/cross/core"
+)
+
+// Constants to match up protocol versions and messages
+const (
+	cross2 = 2
+	cross3 = 3 // relayed ctxs are announced by hashes and fetched in batches
+	cross4 = 4 // stores are compared by range digests before synchronised
 )
 
+// protocolVersions are the supported versions of the anchor protocol (first is primary).
+var protocolVersions = []uint{cross4, cross3, cross2}
+
 const (
-	protocolVersion    = 1
 	protocolMaxMsgSize = 10 * 1024 * 1024
 	handshakeTimeout   = 5 * time.Second
 	//rttMaxEstimate     = 20 * time.Second // Maximum round-trip time to target for download requests
@@ -19,6 +31,13 @@ const (
 	maxKnownCtx        = 32768 // Maximum cross transactions hashes to keep in the known list (prevent DOS)
 	maxQueuedLocalCtx  = 4096
 	maxQueuedRemoteCtx = 128
+
+	maxCtxAnnounce    = 256                // Maximum ctx hashes in an announcement
+	maxCtxFetch       = 64                 // Maximum ctxs requested in a batch
+	softResponseLimit = 2 * 1024 * 1024    // Target maximum size of returned ctxs
+	ctxFetchTimeout   = 5 * time.Second    // Time allowance before an announced ctx is requested from another peer
+	maxCtxFetching    = 4 * maxCtxAnnounce // Maximum announced ctxs being requested at the same time
+	maxCtxQueued      = 4096               // Maximum announcements of a peer tracked before they are fetched
 )
 
 const (
@@ -28,6 +47,16 @@ const (
 	CtxSyncMsg        = 0x33
 	GetPendingSyncMsg = 0x34
 	PendingSyncMsg    = 0x35
+	CtxProofMsg       = 0x36 // signed ctx along with the receipt proof of its maker log
+
+	// Protocol messages belonging to cross3
+	NewCtxHashesMsg = 0x37 // sign hashes of the relayed ctxs
+	GetCtxsMsg      = 0x38 // request the ctxs by sign hashes
+	CtxsMsg         = 0x39 // ctxs along with the receipt proofs
+
+	// Protocol messages belonging to cross4
+	GetCtxDigestsMsg = 0x3a // request the digests of store ranges
+	CtxDigestsMsg    = 0x3b // digests of store ranges
 )
 
 var (
@@ -47,8 +76,9 @@ const (
 	ErrGenesisMismatch
 	ErrNoStatusMsg
 	ErrExtraStatusMsg
-	ErrCrossMainChainMismatch
-	ErrCrossSubChainMismatch
+	ErrCrossContractMismatch
+	ErrChainPairMismatch
+	ErrMisbehaving
 )
 
 func errResp(code errCode, format string, v ...interface{}) error {
@@ -69,13 +99,26 @@ var errorToString = map[int]string{
 	ErrGenesisMismatch:         "Genesis mismatch",
 	ErrNoStatusMsg:             "No status message",
 	ErrExtraStatusMsg:          "Extra status message",
-	ErrCrossMainChainMismatch:  "main chain contract mismatch",
-	ErrCrossSubChainMismatch:   "sub chain contract mismatch",
+	ErrCrossContractMismatch:   "cross contract mismatch",
+	ErrChainPairMismatch:       "no common chain pair",
+	ErrMisbehaving:             "peer misbehaving",
+}
+
+// crossChainStatus describes a chain served by the anchor in the handshake
+type crossChainStatus struct {
+	ChainID  uint64
+	Genesis  common.Hash
+	Contract common.Address
+}
+
+// crossPairStatus describes a chain pair served by the anchor in the handshake
+type crossPairStatus struct {
+	Pair   cc.ChainPair
+	Height *big.Int
 }
 
-type NodeInfo struct {
-	MainChain    uint64
-	MainContract common.Address
-	SubChain     uint64
-	SubContract  common.Address
+// ctxProofPacket is the network packet of CtxProofMsg and the item of CtxsMsg
+type ctxProofPacket struct {
+	Ctx   *cc.CrossTransaction
+	Proof *cc.ReceiptProof `rlp:"nil"` // nil in CtxsMsg if receipt proof is disabled
 }
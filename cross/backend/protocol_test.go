@@ -0,0 +1,226 @@
+package backend
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/common/mclock"
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/p2p"
+	"gbchain-org/go-gbchain/p2p/enode"
+
+	"gbchain-org/go-gbchain/cross/backend/synchronise"
+	cc "gbchain-org/go-gbchain/cross/core"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testPair = cc.NewChainPair(common.Big0, testRemoteID)
+
+// newTestPeer creates an anchor peer of the version which supports testPair, the other end of pipe is returned
+func newTestPeer(id byte, version int) (*anchorPeer, *p2p.MsgPipeRW) {
+	app, net := p2p.MsgPipe()
+	p := newAnchorPeer(version, p2p.NewPeer(enode.ID{id}, "test", nil), app)
+	p.pairs = map[cc.ChainPair]*big.Int{testPair: common.Big0}
+	return p, net
+}
+
+func newTestService() *CrossService {
+	return &CrossService{
+		peers:    newAnchorSet(),
+		fetcher:  newCtxFetcher(),
+		bans:     newPeerBans(mclock.System{}),
+		changes:  newAnchorChanges(mclock.System{}),
+		handlers: make(map[cc.ChainPair]*Handler),
+		quitSync: make(chan struct{}),
+	}
+}
+
+func newTestCtxs(n int) []*cc.CrossTransaction {
+	ctxs := make([]*cc.CrossTransaction, n)
+	for i, cws := range generateCtx(n, cc.CtxStatusPending) {
+		ctx := cws.CrossTransaction()
+		ctx.Data.V, ctx.Data.R, ctx.Data.S = big.NewInt(1), big.NewInt(int64(i+1)), big.NewInt(1)
+		ctxs[i] = ctx
+	}
+	return ctxs
+}
+
+func TestAnchorPeer_Handshake(t *testing.T) {
+	chains := []crossChainStatus{{ChainID: 0}, {ChainID: testRemoteID.Uint64()}}
+	pairs := []crossPairStatus{{Pair: testPair, Height: big.NewInt(10)}}
+
+	for _, tt := range []struct {
+		local, remote int
+		fail          bool
+	}{
+		{cross4, cross4, false},
+		{cross3, cross3, false},
+		{cross2, cross2, false},
+		{cross3, cross2, true}, // never happens since the protocol version is negotiated by p2p
+	} {
+		app, net := p2p.MsgPipe()
+		local := newAnchorPeer(tt.local, p2p.NewPeer(enode.ID{1}, "local", nil), app)
+		remote := newAnchorPeer(tt.remote, p2p.NewPeer(enode.ID{2}, "remote", nil), net)
+
+		errc := make(chan error, 2)
+		go func() { errc <- local.Handshake(chains, pairs) }()
+		go func() { errc <- remote.Handshake(chains, pairs) }()
+		err1, err2 := <-errc, <-errc
+		if tt.fail {
+			assert.True(t, err1 != nil || err2 != nil, "versions %d, %d", tt.local, tt.remote)
+		} else {
+			assert.NoError(t, err1)
+			assert.NoError(t, err2)
+			assert.Equal(t, big.NewInt(10), local.Height(testPair))
+			assert.Equal(t, tt.local, local.Info().Version)
+		}
+		app.Close()
+	}
+}
+
+func TestAnchorPeer_Broadcast(t *testing.T) {
+	ctxs := newTestCtxs(3)
+
+	// cross2 peers receive the full ctxs
+	p, net := newTestPeer(1, cross2)
+	go p.broadcast()
+	p.AsyncSendCrossTransaction(ctxs[0], nil, false)
+	assert.NoError(t, p2p.ExpectMsg(net, CtxSignMsg, ctxs[0]))
+	p.close()
+
+	// cross3 peers receive the hashes of remote ctxs and the full local ctxs
+	p, net = newTestPeer(2, cross3)
+	p.AsyncSendCrossTransaction(ctxs[0], nil, false)
+	p.AsyncSendCrossTransaction(ctxs[1], nil, false)
+	go p.broadcast()
+	assert.NoError(t, p2p.ExpectMsg(net, NewCtxHashesMsg, []common.Hash{ctxs[0].SignHash(), ctxs[1].SignHash()}))
+	p.AsyncSendCrossTransaction(ctxs[2], nil, true)
+	assert.NoError(t, p2p.ExpectMsg(net, CtxSignMsg, ctxs[2]))
+	assert.True(t, p.HasCrossTransaction(ctxs[2].SignHash()))
+	p.close()
+}
+
+func TestCrossService_HandleAnnounce(t *testing.T) {
+	var (
+		srv    = newTestService()
+		ctxs   = newTestCtxs(3)
+		p1, n1 = newTestPeer(1, cross3)
+		p2, n2 = newTestPeer(2, cross3)
+	)
+	srv.fetcher.add(&ctxProofPacket{Ctx: ctxs[0]})
+
+	// the unknown ctxs are requested from the first announcer
+	errc := make(chan error, 1)
+	go func() { errc <- srv.handleMsg(p1) }()
+	hashes := []common.Hash{ctxs[0].SignHash(), ctxs[1].SignHash(), ctxs[2].SignHash()}
+	assert.NoError(t, p2p.Send(n1, NewCtxHashesMsg, hashes))
+	assert.NoError(t, p2p.ExpectMsg(n1, GetCtxsMsg, hashes[1:]))
+	assert.NoError(t, <-errc)
+	for _, hash := range hashes {
+		assert.True(t, p1.HasCrossTransaction(hash))
+	}
+
+	// the ctxs being fetched are not requested again
+	go func() { errc <- srv.handleMsg(p2) }()
+	assert.NoError(t, p2p.Send(n2, NewCtxHashesMsg, hashes))
+	assert.NoError(t, <-errc)
+
+	// but requested from the other announcers after timeout
+	requests := srv.fetcher.reschedule(time.Now().Add(ctxFetchTimeout))
+	assert.Len(t, requests, 1)
+	assert.ElementsMatch(t, hashes[1:], requests[p2.id])
+
+	// the ctxs requested from others are not accepted as requested
+	assert.False(t, srv.fetcher.delivered(p1.id, hashes[1]))
+
+	// the delivered ctxs are relayed without handler, they are not known by the fetcher
+	go func() { errc <- srv.handleMsg(p2) }()
+	assert.NoError(t, p2p.Send(n2, CtxsMsg, []*ctxProofPacket{{Ctx: ctxs[1]}}))
+	assert.NoError(t, <-errc)
+	assert.Equal(t, hashes[1:2], srv.fetcher.announce(p1.id, hashes, time.Now()))
+}
+
+func TestCrossService_HandleGetCtxs(t *testing.T) {
+	var (
+		srv    = newTestService()
+		ctxs   = newTestCtxs(2)
+		p, net = newTestPeer(1, cross3)
+		proof  = &cc.ReceiptProof{Header: &types.Header{Number: big.NewInt(1)}, Proof: [][]byte{{1}}}
+	)
+	srv.BroadcastCrossTx(ctxs[:1], false)
+	srv.fetcher.add(&ctxProofPacket{Ctx: ctxs[1], Proof: proof})
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.handleMsg(p) }()
+	assert.NoError(t, p2p.Send(net, GetCtxsMsg, []common.Hash{ctxs[0].SignHash(), {0xff}, ctxs[1].SignHash()}))
+
+	msg, err := net.ReadMsg()
+	assert.NoError(t, err)
+	assert.EqualValues(t, CtxsMsg, msg.Code)
+	var packets []*ctxProofPacket
+	assert.NoError(t, msg.Decode(&packets))
+	assert.NoError(t, <-errc)
+
+	assert.Equal(t, 2, len(packets))
+	assert.Equal(t, ctxs[0].SignHash(), packets[0].Ctx.SignHash())
+	assert.Nil(t, packets[0].Proof)
+	assert.Equal(t, ctxs[1].SignHash(), packets[1].Ctx.SignHash())
+	assert.Equal(t, proof.Header.Hash(), packets[1].Proof.Header.Hash())
+}
+
+func TestCrossService_HandleGetCtxDigests(t *testing.T) {
+	handler, err := newHandlerTester(common.Big0)
+	assert.NoError(t, err)
+	defer handler.store.Close()
+	ctxs := generateCtx(10, cc.CtxStatusWaiting)
+	assert.NoError(t, handler.store.Adds(common.Big0, ctxs, false))
+	db, err := handler.store.GetStore(common.Big0)
+	assert.NoError(t, err)
+	handler.synchronise = synchronise.New(common.Big0, testRemoteID, nil, db, nil, synchronise.DIGEST)
+	defer handler.synchronise.Terminate()
+
+	var (
+		srv    = newTestService()
+		p, net = newTestPeer(1, cross4)
+	)
+	srv.handlers[testPair] = handler
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.handleMsg(p) }()
+	assert.NoError(t, p2p.Send(net, GetCtxDigestsMsg, &synchronise.DigestReq{Remote: testRemoteID.Uint64(), Begin: 1, End: 9, Span: 5}))
+
+	msg, err := net.ReadMsg()
+	assert.NoError(t, err)
+	assert.EqualValues(t, CtxDigestsMsg, msg.Code)
+	var resp synchronise.DigestResp
+	assert.NoError(t, msg.Decode(&resp))
+	assert.NoError(t, <-errc)
+
+	assert.Equal(t, 2, len(resp.Digests))
+	assert.Equal(t, synchronise.RangeDigest{
+		Begin:      1,
+		End:        5,
+		Count:      5,
+		Root:       synchronise.DigestRoot(ctxs[1:6]),
+		StatusRoot: synchronise.StatusRoot(ctxs[1:6]),
+	}, resp.Digests[0])
+	assert.Equal(t, synchronise.DigestRoot(ctxs[6:]), resp.Digests[1].Root)
+
+	// peers before cross4 don't serve digests
+	legacy, _ := newTestPeer(2, cross3)
+	assert.Equal(t, synchronise.ErrDigestUnsupported, legacy.RequestCtxDigests(0, testRemoteID.Uint64(), 0, 9, 5))
+}
+
+func TestCrossService_HandleLegacyPeer(t *testing.T) {
+	var (
+		srv    = newTestService()
+		p, net = newTestPeer(1, cross2)
+	)
+	errc := make(chan error, 1)
+	go func() { errc <- srv.handleMsg(p) }()
+	assert.NoError(t, p2p.Send(net, NewCtxHashesMsg, []common.Hash{{1}}))
+	assert.Error(t, <-errc)
+}
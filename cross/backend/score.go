@@ -0,0 +1,263 @@
+package backend
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"gbchain-org/go-gbchain/common/mclock"
+	"gbchain-org/go-gbchain/p2p/enode"
+)
+
+// Score changes of anchor peer behaviours, the peer is disconnected and banned
+// when its score drops to banScore.
+const (
+	scoreUsefulCtx         = 1   // a new valid ctx is received
+	scoreDuplicateCtx      = -1  // the ctx is sent by the peer already
+	scoreRateExceeded      = -5  // a message exceeds the rate limit, it is dropped
+	scoreOversizedResponse = -20 // a sync response contains more items than requested
+	scoreInvalidSignature  = -20 // a ctx is not signed by an anchor or its receipt proof is invalid
+
+	maxScore    = 100
+	banScore    = -100
+	banDuration = 30 * time.Minute
+
+	scoreHalfLife     = 10 * time.Minute // scores decay towards zero, so misbehaviours long ago are forgiven
+	anchorChangeGrace = 2 * time.Minute  // signatures of non-anchors are penalized if the anchor set is not changed in the grace
+
+	ctxMsgRate      = 500  // Allowed ctx messages per second
+	ctxMsgBurst     = 4096 // Allowed ctx messages in a burst, the local signed ctxs are queued up to maxQueuedLocalCtx
+	requestMsgRate  = 50   // Allowed sync and fetch requests per second
+	requestMsgBurst = 200
+)
+
+// rateLimiter is a token bucket allowing rate events per second on average with bursts of burst events
+type rateLimiter struct {
+	rate, burst float64
+	tokens      float64
+	last        mclock.AbsTime
+}
+
+func newRateLimiter(rate, burst int, now mclock.AbsTime) *rateLimiter {
+	return &rateLimiter{rate: float64(rate), burst: float64(burst), tokens: float64(burst), last: now}
+}
+
+func (l *rateLimiter) allow(now mclock.AbsTime) bool {
+	l.tokens += time.Duration(now-l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// CrossPeerScore is the accounting of an anchor peer
+type CrossPeerScore struct {
+	Score              int    `json:"score"`
+	UsefulCtxs         uint64 `json:"usefulCtxs"`
+	DuplicateCtxs      uint64 `json:"duplicateCtxs"`
+	InvalidSignatures  uint64 `json:"invalidSignatures"`
+	OversizedResponses uint64 `json:"oversizedResponses"`
+	RateLimited        uint64 `json:"rateLimited"`
+}
+
+// peerScore accounts the behaviours of an anchor peer
+type peerScore struct {
+	lock     sync.Mutex
+	clock    mclock.Clock
+	score    CrossPeerScore
+	value    float64        // score decayed until updated
+	updated  mclock.AbsTime // time of the last decay
+	suspects []mclock.AbsTime
+	ctxs     *rateLimiter
+	requests *rateLimiter
+}
+
+func newPeerScore(clock mclock.Clock) *peerScore {
+	now := clock.Now()
+	return &peerScore{
+		clock:    clock,
+		updated:  now,
+		ctxs:     newRateLimiter(ctxMsgRate, ctxMsgBurst, now),
+		requests: newRateLimiter(requestMsgRate, requestMsgBurst, now),
+	}
+}
+
+func (s *peerScore) add(delta int, counter *uint64) {
+	s.decay()
+	s.value += float64(delta)
+	if s.value > maxScore {
+		s.value = maxScore
+	}
+	s.score.Score = int(math.Round(s.value))
+	*counter++
+}
+
+// decay halves the score every scoreHalfLife, and penalizes the suspected signatures
+// which are not forgiven by an anchor set change in anchorChangeGrace.
+func (s *peerScore) decay() {
+	now := s.clock.Now()
+	if elapsed := now - s.updated; elapsed > 0 {
+		s.value *= math.Exp2(-float64(elapsed) / float64(scoreHalfLife))
+		s.score.Score = int(math.Round(s.value))
+		s.updated = now
+	}
+	for len(s.suspects) > 0 && now-s.suspects[0] >= mclock.AbsTime(anchorChangeGrace) {
+		s.suspects = s.suspects[1:]
+		s.value += scoreInvalidSignature
+		s.score.Score = int(math.Round(s.value))
+		s.score.InvalidSignatures++
+	}
+}
+
+// allowCtx reports whether the ctx message is within the rate limit, the peer is penalized if not
+func (s *peerScore) allowCtx() bool {
+	return s.allow(s.ctxs)
+}
+
+// allowRequest reports whether the request is within the rate limit, the peer is penalized if not
+func (s *peerScore) allowRequest() bool {
+	return s.allow(s.requests)
+}
+
+func (s *peerScore) allow(limiter *rateLimiter) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if limiter.allow(s.clock.Now()) {
+		return true
+	}
+	s.add(scoreRateExceeded, &s.score.RateLimited)
+	return false
+}
+
+func (s *peerScore) usefulCtx() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.add(scoreUsefulCtx, &s.score.UsefulCtxs)
+}
+
+func (s *peerScore) duplicateCtx() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.add(scoreDuplicateCtx, &s.score.DuplicateCtxs)
+}
+
+func (s *peerScore) invalidSignature() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.add(scoreInvalidSignature, &s.score.InvalidSignatures)
+}
+
+// suspectSignature records a ctx signed by a non-anchor, the signer may be an anchor added or
+// removed recently, which is known by only one side. The peer is penalized after anchorChangeGrace
+// unless the anchor set is changed.
+func (s *peerScore) suspectSignature() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.suspects = append(s.suspects, s.clock.Now())
+}
+
+// forgiveSignatures drops the suspected signatures when the anchor set is changed
+func (s *peerScore) forgiveSignatures() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.suspects = nil
+}
+
+func (s *peerScore) oversizedResponse() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.add(scoreOversizedResponse, &s.score.OversizedResponses)
+}
+
+// misbehaving reports whether the peer should be disconnected and banned
+func (s *peerScore) misbehaving() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.decay()
+	return s.value <= banScore
+}
+
+func (s *peerScore) info() *CrossPeerScore {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.decay()
+	score := s.score
+	return &score
+}
+
+// anchorChanges records when the anchor sets are changed, the ctxs signed by non-anchors are
+// not penalized in anchorChangeGrace after a change since the peer may not see it yet
+type anchorChanges struct {
+	lock    sync.Mutex
+	clock   mclock.Clock
+	last    mclock.AbsTime
+	changed bool
+}
+
+func newAnchorChanges(clock mclock.Clock) *anchorChanges {
+	return &anchorChanges{clock: clock}
+}
+
+func (c *anchorChanges) change() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.last, c.changed = c.clock.Now(), true
+}
+
+// recent reports whether an anchor set is changed in anchorChangeGrace
+func (c *anchorChanges) recent() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.changed && c.clock.Now()-c.last < mclock.AbsTime(anchorChangeGrace)
+}
+
+// peerBans is the list of anchor peers temporarily banned for misbehaving
+type peerBans struct {
+	lock  sync.Mutex
+	clock mclock.Clock
+	bans  map[enode.ID]mclock.AbsTime // node -> ban expiry
+}
+
+func newPeerBans(clock mclock.Clock) *peerBans {
+	return &peerBans{clock: clock, bans: make(map[enode.ID]mclock.AbsTime)}
+}
+
+func (b *peerBans) ban(id enode.ID) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.bans[id] = b.clock.Now().Add(banDuration)
+}
+
+func (b *peerBans) banned(id enode.ID) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	expiry, ok := b.bans[id]
+	if ok && b.clock.Now() >= expiry {
+		delete(b.bans, id)
+		return false
+	}
+	return ok
+}
+
+// list returns the banned nodes and the time their bans expire
+func (b *peerBans) list() map[enode.ID]time.Time {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	var (
+		now  = b.clock.Now()
+		list = make(map[enode.ID]time.Time, len(b.bans))
+	)
+	for id, expiry := range b.bans {
+		if now >= expiry {
+			delete(b.bans, id)
+			continue
+		}
+		list[id] = time.Now().Add(time.Duration(expiry - now))
+	}
+	return list
+}
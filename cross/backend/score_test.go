@@ -0,0 +1,161 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/common/mclock"
+	"gbchain-org/go-gbchain/p2p"
+	"gbchain-org/go-gbchain/p2p/enode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	var (
+		clock   = new(mclock.Simulated)
+		limiter = newRateLimiter(10, 20, clock.Now())
+	)
+	for i := 0; i < 20; i++ {
+		assert.True(t, limiter.allow(clock.Now()))
+	}
+	assert.False(t, limiter.allow(clock.Now()))
+
+	clock.Run(100 * time.Millisecond)
+	assert.True(t, limiter.allow(clock.Now()))
+	assert.False(t, limiter.allow(clock.Now()))
+
+	// tokens are refilled up to the burst
+	clock.Run(time.Hour)
+	for i := 0; i < 20; i++ {
+		assert.True(t, limiter.allow(clock.Now()))
+	}
+	assert.False(t, limiter.allow(clock.Now()))
+}
+
+func TestPeerScore(t *testing.T) {
+	score := newPeerScore(new(mclock.Simulated))
+	for i := 0; i < maxScore+10; i++ {
+		score.usefulCtx()
+	}
+	assert.Equal(t, maxScore, score.info().Score)
+
+	for i := 0; i < requestMsgBurst; i++ {
+		assert.True(t, score.allowRequest())
+	}
+	assert.False(t, score.allowRequest())
+	assert.Equal(t, maxScore+scoreRateExceeded, score.info().Score)
+
+	for !score.misbehaving() {
+		score.invalidSignature()
+	}
+	info := score.info()
+	assert.True(t, info.Score <= banScore)
+	assert.EqualValues(t, maxScore+10, info.UsefulCtxs)
+	assert.EqualValues(t, 1, info.RateLimited)
+	assert.EqualValues(t, 10, info.InvalidSignatures) // 95 -> -105
+}
+
+func TestPeerScore_Decay(t *testing.T) {
+	var (
+		clock = new(mclock.Simulated)
+		score = newPeerScore(clock)
+	)
+	for i := 0; i < 4; i++ {
+		score.invalidSignature()
+	}
+	assert.Equal(t, 4*scoreInvalidSignature, score.info().Score)
+	clock.Run(scoreHalfLife)
+	assert.Equal(t, 2*scoreInvalidSignature, score.info().Score)
+	clock.Run(10 * scoreHalfLife)
+	assert.Equal(t, 0, score.info().Score)
+	assert.EqualValues(t, 4, score.info().InvalidSignatures)
+}
+
+func TestPeerScore_SuspectSignature(t *testing.T) {
+	var (
+		clock = new(mclock.Simulated)
+		score = newPeerScore(clock)
+	)
+	// forgiven by an anchor set change in the grace
+	score.suspectSignature()
+	clock.Run(anchorChangeGrace / 2)
+	score.forgiveSignatures()
+	clock.Run(anchorChangeGrace)
+	assert.Equal(t, 0, score.info().Score)
+
+	// penalized after the grace
+	score.suspectSignature()
+	score.suspectSignature()
+	assert.Equal(t, 0, score.info().Score)
+	clock.Run(anchorChangeGrace)
+	assert.Equal(t, 2*scoreInvalidSignature, score.info().Score)
+	assert.EqualValues(t, 2, score.info().InvalidSignatures)
+
+	changes := newAnchorChanges(clock)
+	assert.False(t, changes.recent())
+	changes.change()
+	assert.True(t, changes.recent())
+	clock.Run(anchorChangeGrace)
+	assert.False(t, changes.recent())
+}
+
+func TestPeerBans(t *testing.T) {
+	var (
+		clock = new(mclock.Simulated)
+		bans  = newPeerBans(clock)
+		id    = enode.ID{1}
+	)
+	assert.False(t, bans.banned(id))
+	bans.ban(id)
+	assert.True(t, bans.banned(id))
+	assert.Equal(t, 1, len(bans.list()))
+
+	clock.Run(banDuration)
+	assert.False(t, bans.banned(id))
+	assert.Empty(t, bans.list())
+}
+
+func TestCrossService_ScorePeer(t *testing.T) {
+	var (
+		srv    = newTestService()
+		ctxs   = newTestCtxs(2)
+		p, net = newTestPeer(1, cross3)
+		errc   = make(chan error, 1)
+	)
+	send := func(code uint64, data interface{}) {
+		go func() { errc <- srv.handleMsg(p) }()
+		assert.NoError(t, p2p.Send(net, code, data))
+		assert.NoError(t, <-errc)
+	}
+
+	// the ctx sent twice is a useless duplicate
+	send(CtxSignMsg, ctxs[0])
+	send(CtxSignMsg, ctxs[0])
+	assert.EqualValues(t, 1, p.score.info().DuplicateCtxs)
+
+	// the ctx queued to the peer is not a duplicate when the peer sends it as well
+	p.MarkCrossTransaction(ctxs[1].SignHash())
+	send(CtxSignMsg, ctxs[1])
+	assert.EqualValues(t, 1, p.score.info().DuplicateCtxs)
+
+	// more ctxs than requested
+	packets := make([]*ctxProofPacket, maxCtxFetch+1)
+	for i := range packets {
+		packets[i] = &ctxProofPacket{Ctx: ctxs[0]}
+	}
+	send(CtxsMsg, packets)
+	assert.EqualValues(t, 1, p.score.info().OversizedResponses)
+	assert.Equal(t, scoreDuplicateCtx+scoreOversizedResponse, p.score.info().Score)
+}
+
+func TestCrossService_RejectBanned(t *testing.T) {
+	var (
+		srv  = newTestService()
+		p, _ = newTestPeer(1, cross3)
+	)
+	srv.bans.ban(p.ID())
+	assert.Equal(t, p2p.DiscUselessPeer, srv.handle(p))
+	assert.Equal(t, common.Big0, p.Height(testPair))
+}
@@ -2,29 +2,48 @@ package backend
 
 import (
 	"errors"
+	"io"
 	"math/big"
 	"sync"
 
 	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/event"
 	"gbchain-org/go-gbchain/log"
 
 	cc "gbchain-org/go-gbchain/cross/core"
 	cdb "gbchain-org/go-gbchain/cross/database"
 
-	"github.com/asdine/storm/v3"
 	"github.com/asdine/storm/v3/q"
 )
 
-const defaultCacheSize = 4096
+const (
+	defaultCacheSize = 4096
+	statusChanSize   = 256 // buffered status events of a subscriber before it is dropped
+)
 
-var ErrInvalidChainStore = errors.New("invalid chain store, chainID can not be nil")
+var (
+	ErrInvalidChainStore = errors.New("invalid chain store, chainID can not be nil")
+	ErrSlowSubscriber    = errors.New("status subscriber is dropped since it falls behind")
+)
 
 // CrossStore store cross transactions into CtxDBs
 type CrossStore struct {
 	stores map[uint64]cdb.CtxDB // chainID -> CtxDB
-	db     *storm.DB            // database to store cws
+	db     io.Closer            // database to store cws
+	newDB  func(chainID *big.Int) cdb.CtxDB
 	mu     sync.Mutex
 	logger log.Logger
+
+	statusSubs  map[*statusSubscriber]struct{}
+	statusLock  sync.Mutex
+	statusScope event.SubscriptionScope
+}
+
+// statusSubscriber buffers the status events of a subscription, it is dropped once the
+// buffer is full so that writing the store never waits for a slow subscriber.
+type statusSubscriber struct {
+	events  chan cc.CtxStatusEvent
+	dropped chan struct{}
 }
 
 func NewCrossStore(ctx cdb.ServiceContext, makerDb string) (*CrossStore, error) {
@@ -37,11 +56,33 @@ func NewCrossStore(ctx cdb.ServiceContext, makerDb string) (*CrossStore, error)
 		return nil, err
 	}
 	store.db = db
+	store.newDB = func(chainID *big.Int) cdb.CtxDB {
+		return cdb.NewIndexDB(chainID, db, defaultCacheSize)
+	}
+	store.stores = make(map[uint64]cdb.CtxDB)
+	return store, nil
+}
+
+// NewKVCrossStore stores cross transactions into a key-value database instead of storm
+func NewKVCrossStore(ctx cdb.ServiceContext, name string) (*CrossStore, error) {
+	store := &CrossStore{
+		logger: log.New("X-module", "store"),
+	}
+
+	db, err := cdb.OpenEtherDB(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	store.db = db
+	store.newDB = func(chainID *big.Int) cdb.CtxDB {
+		return cdb.NewKVIndexDB(chainID, db, defaultCacheSize)
+	}
 	store.stores = make(map[uint64]cdb.CtxDB)
 	return store, nil
 }
 
 func (s *CrossStore) Close() {
+	s.statusScope.Close()
 	if err := s.db.Close(); err != nil {
 		s.logger.Warn("close store failed", "error", err)
 	}
@@ -51,7 +92,7 @@ func (s *CrossStore) RegisterChain(chainID *big.Int) cdb.CtxDB {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if s.stores[chainID.Uint64()] == nil {
-		s.stores[chainID.Uint64()] = cdb.NewIndexDB(chainID, s.db, defaultCacheSize)
+		s.stores[chainID.Uint64()] = s.newDB(chainID)
 		s.logger.New("remote", chainID)
 		s.logger.Info("Register chain successfully")
 	}
@@ -63,7 +104,11 @@ func (s *CrossStore) Add(ctx *cc.CrossTransactionWithSignatures) error {
 	if err != nil {
 		return err
 	}
-	return store.Write(ctx)
+	if err := store.Write(ctx); err != nil {
+		return err
+	}
+	s.sendStatus(cc.CtxStatusEvent{Ctxs: []*cc.CrossTransactionWithSignatures{ctx}})
+	return nil
 }
 
 func (s *CrossStore) Adds(chainID *big.Int, ctxList []*cc.CrossTransactionWithSignatures, replaceable bool) error {
@@ -71,7 +116,13 @@ func (s *CrossStore) Adds(chainID *big.Int, ctxList []*cc.CrossTransactionWithSi
 	if err != nil {
 		return err
 	}
-	return store.Writes(ctxList, replaceable)
+	if err := store.Writes(ctxList, replaceable); err != nil {
+		return err
+	}
+	if len(ctxList) > 0 {
+		s.sendStatus(cc.CtxStatusEvent{Ctxs: ctxList})
+	}
+	return nil
 }
 
 func (s *CrossStore) Get(chainID *big.Int, ctxID common.Hash) *cc.CrossTransactionWithSignatures {
@@ -103,26 +154,101 @@ func (s *CrossStore) Updates(chainID *big.Int, txmList []*cc.CrossTransactionMod
 	var (
 		ids      []cc.CtxID
 		updaters []func(ctx *cdb.CrossTransactionIndexed)
+		changed  []cc.CtxID
 	)
 	for _, txm := range txmList {
-		upType, upStatus, upNumber := txm.Type, uint8(txm.Status), txm.AtBlockNumber //必须复制变量，迭代器引用会产生的问题
+		upType, upStatus, upNumber := txm.Type, txm.Status, txm.AtBlockNumber //必须复制变量，迭代器引用会产生的问题
 		ids = append(ids, txm.ID)
 		updaters = append(updaters, func(ctx *cdb.CrossTransactionIndexed) {
+			old, status := ctx.Status, cc.CtxStatus(ctx.Status)
 			switch {
 			// force update if tx status is changed by block reorg
-			case upType == cc.Reorg && upStatus < ctx.Status:
-				ctx.Status = upStatus
+			case upType == cc.Reorg && upStatus.Before(status):
+				ctx.Status = uint8(upStatus)
 			// update from remote
-			case upType == cc.Remote && upStatus > ctx.Status:
-				ctx.Status = upStatus
+			case upType == cc.Remote && status.Before(upStatus):
+				ctx.Status = uint8(upStatus)
 			// update from local
-			case upType == cc.Normal && upStatus > ctx.Status: // 正常情况下，status更大则状态变更的高度更高，但是回滚时就不一定，所以不限制高度大小
-				ctx.Status = upStatus
+			case upType == cc.Normal && status.Before(upStatus): // 正常情况下，status更大则状态变更的高度更高，但是回滚时就不一定，所以不限制高度大小
+				ctx.Status = uint8(upStatus)
 				ctx.BlockNum = upNumber
 			}
+			if ctx.Status != old {
+				changed = append(changed, ctx.CtxId)
+			}
 		})
 	}
-	return store.Updates(ids, updaters)
+	if err := store.Updates(ids, updaters); err != nil {
+		return err
+	}
+	if len(changed) > 0 {
+		ctxList := make([]*cc.CrossTransactionWithSignatures, 0, len(changed))
+		for _, id := range changed {
+			if ctx, err := store.Read(id); err == nil {
+				ctxList = append(ctxList, ctx)
+			}
+		}
+		s.sendStatus(cc.CtxStatusEvent{Ctxs: ctxList})
+	}
+	return nil
+}
+
+// SubscribeCtxStatusEvent registers a subscription of CtxStatusEvent, which is posted
+// when ctxs are added into the store or their status is updated. The subscription is
+// ended with ErrSlowSubscriber if more than statusChanSize events are not received.
+func (s *CrossStore) SubscribeCtxStatusEvent(ch chan<- cc.CtxStatusEvent) event.Subscription {
+	sub := &statusSubscriber{
+		events:  make(chan cc.CtxStatusEvent, statusChanSize),
+		dropped: make(chan struct{}),
+	}
+	s.statusLock.Lock()
+	if s.statusSubs == nil {
+		s.statusSubs = make(map[*statusSubscriber]struct{})
+	}
+	s.statusSubs[sub] = struct{}{}
+	s.statusLock.Unlock()
+
+	return s.statusScope.Track(event.NewSubscription(func(quit <-chan struct{}) error {
+		defer s.removeStatusSubscriber(sub)
+		for {
+			select {
+			case ev := <-sub.events:
+				select {
+				case ch <- ev:
+				case <-sub.dropped:
+					return ErrSlowSubscriber
+				case <-quit:
+					return nil
+				}
+			case <-sub.dropped:
+				return ErrSlowSubscriber
+			case <-quit:
+				return nil
+			}
+		}
+	}))
+}
+
+// sendStatus delivers the status event to subscribers without blocking, the ones of
+// which the buffer is full are dropped.
+func (s *CrossStore) sendStatus(ev cc.CtxStatusEvent) {
+	s.statusLock.Lock()
+	defer s.statusLock.Unlock()
+	for sub := range s.statusSubs {
+		select {
+		case sub.events <- ev:
+		default:
+			s.logger.Warn("Drop slow status subscriber", "pending", len(sub.events))
+			delete(s.statusSubs, sub)
+			close(sub.dropped)
+		}
+	}
+}
+
+func (s *CrossStore) removeStatusSubscriber(sub *statusSubscriber) {
+	s.statusLock.Lock()
+	defer s.statusLock.Unlock()
+	delete(s.statusSubs, sub)
 }
 
 func (s *CrossStore) Height(chainID *big.Int) uint64 {
@@ -140,19 +266,23 @@ func (s *CrossStore) Stats() map[uint64]map[cc.CtxStatus]int {
 	executed := q.Eq(cdb.StatusField, cc.CtxStatusExecuted)
 	finishing := q.Eq(cdb.StatusField, cc.CtxStatusFinishing)
 	finished := q.Eq(cdb.StatusField, cc.CtxStatusFinished)
+	cancelling := q.Eq(cdb.StatusField, cc.CtxStatusCancelling)
+	cancelled := q.Eq(cdb.StatusField, cc.CtxStatusCancelled)
 	pending := q.Eq(cdb.StatusField, cc.CtxStatusPending)
 
 	results := make(map[uint64]map[cc.CtxStatus]int, len(s.stores))
 
 	stats := func(db cdb.CtxDB) map[cc.CtxStatus]int {
 		return map[cc.CtxStatus]int{
-			cc.CtxStatusWaiting:   db.Count(waiting),
-			cc.CtxStatusIllegal:   db.Count(illegal),
-			cc.CtxStatusExecuting: db.Count(executing),
-			cc.CtxStatusExecuted:  db.Count(executed),
-			cc.CtxStatusFinishing: db.Count(finishing),
-			cc.CtxStatusFinished:  db.Count(finished),
-			cc.CtxStatusPending:   db.Count(pending),
+			cc.CtxStatusWaiting:    db.Count(waiting),
+			cc.CtxStatusIllegal:    db.Count(illegal),
+			cc.CtxStatusExecuting:  db.Count(executing),
+			cc.CtxStatusExecuted:   db.Count(executed),
+			cc.CtxStatusFinishing:  db.Count(finishing),
+			cc.CtxStatusFinished:   db.Count(finished),
+			cc.CtxStatusCancelling: db.Count(cancelling),
+			cc.CtxStatusCancelled:  db.Count(cancelled),
+			cc.CtxStatusPending:    db.Count(pending),
 		}
 	}
 	for chain, store := range s.stores {
@@ -3,7 +3,10 @@ package backend
 import (
 	"math/big"
 	"testing"
+	"time"
 
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/common/hexutil"
 	"gbchain-org/go-gbchain/params"
 
 	cc "gbchain-org/go-gbchain/cross/core"
@@ -171,6 +174,72 @@ func TestCrossStore_UpdatesNormal(t *testing.T) {
 	}
 }
 
+func TestCrossStore_SubscribeCtxStatus(t *testing.T) {
+	chainID := big.NewInt(10)
+	s, err := newStoreTester(chainID)
+	assert.NoError(t, err)
+	defer s.Close()
+
+	events := make(chan cc.CtxStatusEvent, 2)
+	sub := s.SubscribeCtxStatusEvent(events)
+	defer sub.Unsubscribe()
+
+	ctxList := generateCtx(2, cc.CtxStatusWaiting)
+	assert.NoError(t, s.Adds(chainID, ctxList, false))
+	ev := <-events
+	assert.Equal(t, 2, len(ev.Ctxs))
+
+	// only the changed ctx is notified
+	assert.NoError(t, s.Updates(chainID, []*cc.CrossTransactionModifier{
+		{ID: ctxList[0].ID(), Type: cc.Remote, Status: cc.CtxStatusExecuting},
+		{ID: ctxList[1].ID(), Type: cc.Reorg, Status: cc.CtxStatusExecuting},
+	}))
+	ev = <-events
+	assert.Equal(t, 1, len(ev.Ctxs))
+	assert.Equal(t, ctxList[0].ID(), ev.Ctxs[0].ID())
+	assert.Equal(t, cc.CtxStatusExecuting, ev.Ctxs[0].Status)
+
+	filter := &CtxStatusFilter{Status: []cc.CtxStatus{cc.CtxStatusExecuting}}
+	assert.True(t, filter.match(ev.Ctxs[0]))
+	assert.False(t, filter.match(ctxList[1]))
+	filter = &CtxStatusFilter{From: []common.Address{ctxList[1].Data.From}, ChainID: (*hexutil.Big)(testRemoteID)}
+	assert.False(t, filter.match(ev.Ctxs[0]))
+	assert.True(t, filter.match(ctxList[1]))
+}
+
+func TestCrossStore_SubscribeCtxStatusSlow(t *testing.T) {
+	chainID := big.NewInt(10)
+	s, err := newStoreTester(chainID)
+	assert.NoError(t, err)
+	defer s.Close()
+
+	// the subscriber never receives, the store must not be blocked
+	events := make(chan cc.CtxStatusEvent)
+	sub := s.SubscribeCtxStatusEvent(events)
+	defer sub.Unsubscribe()
+
+	ctxList := generateCtx(1, cc.CtxStatusWaiting)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < statusChanSize+2; i++ {
+			s.sendStatus(cc.CtxStatusEvent{Ctxs: ctxList})
+		}
+		assert.NoError(t, s.Adds(chainID, ctxList, false))
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("store is blocked by slow subscriber")
+	}
+	select {
+	case err := <-sub.Err():
+		assert.Equal(t, ErrSlowSubscriber, err)
+	case <-time.After(time.Second):
+		t.Fatal("slow subscriber is not dropped")
+	}
+}
+
 func newStoreTester(chainID *big.Int) (*CrossStore, error) {
 	store, err := NewCrossStore(nil, "testing-cross-store")
 	if err != nil {
@@ -0,0 +1,218 @@
+package synchronise
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"sort"
+	"time"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/crypto"
+
+	cc "gbchain-org/go-gbchain/cross/core"
+)
+
+const (
+	DefaultDigestSpan = 1024 // blocks of a range digest
+	MaxDigestSpan     = 4096 // maximum blocks of a range digest served
+	MaxDigestRanges   = 256  // maximum range digests in a response
+	maxDigestCtxs     = 4096 // maximum ctxs digested in a range, except for the ones in its first block
+	maxDivergences    = 64   // maximum divergences kept for report
+)
+
+// ErrDigestUnsupported is returned by peers not serving range digests
+var ErrDigestUnsupported = errors.New("range digest is not supported by peer")
+
+// RangeDigest is the digest of the ctxs stored in blocks [Begin, End], the pending ctxs
+// are not included since they are not synchronised, neither are the finished and cancelled
+// ones since they are removed from stores or archived by peers at their own pace.
+type RangeDigest struct {
+	Begin      uint64
+	End        uint64
+	Count      uint64
+	Root       common.Hash // merkle root of the ids and signed hashes of ctxs
+	StatusRoot common.Hash // merkle root of the ids and statuses of ctxs
+}
+
+// Divergence is the range of which the ctxs stored are different from the peer after
+// it is synchronised.
+type Divergence struct {
+	Peer   string      `json:"peer"`
+	Begin  uint64      `json:"begin"`
+	End    uint64      `json:"end"`
+	Local  common.Hash `json:"local"`
+	Remote common.Hash `json:"remote"`
+	Time   time.Time   `json:"time"`
+}
+
+// DigestRoot returns the merkle root over the ids and signed hashes of ctxs sorted by ids,
+// the statuses are excluded since they progress differently among peers.
+func DigestRoot(ctxs []*cc.CrossTransactionWithSignatures) common.Hash {
+	return merkleRoot(ctxs, func(ctx *cc.CrossTransactionWithSignatures) []byte {
+		hash := ctx.Hash()
+		return hash[:]
+	})
+}
+
+// StatusRoot returns the merkle root over the ids and statuses of ctxs sorted by ids
+func StatusRoot(ctxs []*cc.CrossTransactionWithSignatures) common.Hash {
+	return merkleRoot(ctxs, func(ctx *cc.CrossTransactionWithSignatures) []byte {
+		return []byte{byte(ctx.Status)}
+	})
+}
+
+// merkleRoot returns the merkle root over the ids and fields of ctxs sorted by ids,
+// the last node of an odd level is promoted to the upper level.
+func merkleRoot(ctxs []*cc.CrossTransactionWithSignatures, field func(*cc.CrossTransactionWithSignatures) []byte) common.Hash {
+	if len(ctxs) == 0 {
+		return common.Hash{}
+	}
+	sorted := make([]*cc.CrossTransactionWithSignatures, len(ctxs))
+	copy(sorted, ctxs)
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i].ID(), sorted[j].ID()
+		return bytes.Compare(a[:], b[:]) < 0
+	})
+	nodes := make([]common.Hash, len(sorted))
+	for i, ctx := range sorted {
+		id := ctx.ID()
+		nodes[i] = crypto.Keccak256Hash(id[:], field(ctx))
+	}
+	for len(nodes) > 1 {
+		n := 0
+		for i := 0; i < len(nodes); i += 2 {
+			if i+1 < len(nodes) {
+				nodes[n] = crypto.Keccak256Hash(nodes[i][:], nodes[i+1][:])
+			} else {
+				nodes[n] = nodes[i]
+			}
+			n++
+		}
+		nodes = nodes[:n]
+	}
+	return nodes[0]
+}
+
+// rangeCtxs returns the ctxs to remoteID stored in blocks [begin, end], the ones not digested are skipped.
+// If limit is positive and more ctxs are stored, the range is shortened to the blocks before the one
+// exceeding limit, but at least the first block is kept. The end of the range returned is reported.
+func rangeCtxs(store CrossStore, remoteID *big.Int, begin, end uint64, limit int) ([]*cc.CrossTransactionWithSignatures, uint64) {
+	var results []*cc.CrossTransactionWithSignatures
+	for from := begin; from <= end; {
+		ctxList := store.RangeByNumber(from, end, defaultMaxSyncSize)
+		if len(ctxList) == 0 {
+			break
+		}
+		for _, ctx := range ctxList {
+			if ctx.DestinationId().Cmp(remoteID) == 0 && digested(ctx.Status) {
+				results = append(results, ctx)
+			}
+		}
+		if limit > 0 && len(results) > limit {
+			if over := results[limit].BlockNum; over > begin {
+				end = over - 1
+			} else {
+				end = begin
+			}
+			n := 0
+			for _, ctx := range results {
+				if ctx.BlockNum <= end {
+					results[n] = ctx
+					n++
+				}
+			}
+			return results[:n], end
+		}
+		last := ctxList[len(ctxList)-1].BlockNum
+		if last >= end {
+			break
+		}
+		from = last + 1
+	}
+	return results, end
+}
+
+// digested reports whether the ctxs of status are included in range digests
+func digested(status cc.CtxStatus) bool {
+	return status != cc.CtxStatusPending && status != cc.CtxStatusFinished && status != cc.CtxStatusCancelled
+}
+
+// rangeDigest returns the digest of ctxs to remoteID stored in blocks [begin, end]
+func rangeDigest(store CrossStore, remoteID *big.Int, begin, end uint64) RangeDigest {
+	return limitedRangeDigest(store, remoteID, begin, end, 0)
+}
+
+// limitedRangeDigest returns the digest of at most limit ctxs to remoteID stored from block begin,
+// the range may end before block end.
+func limitedRangeDigest(store CrossStore, remoteID *big.Int, begin, end uint64, limit int) RangeDigest {
+	ctxs, end := rangeCtxs(store, remoteID, begin, end, limit)
+	return RangeDigest{
+		Begin:      begin,
+		End:        end,
+		Count:      uint64(len(ctxs)),
+		Root:       DigestRoot(ctxs),
+		StatusRoot: StatusRoot(ctxs),
+	}
+}
+
+// Digests returns the digests of ctxs to remoteID stored in blocks [begin, end], every
+// range contains at most span blocks and maxDigestCtxs ctxs. The span is capped to MaxDigestSpan,
+// and at most MaxDigestRanges digests are returned.
+func Digests(store CrossStore, remoteID *big.Int, begin, end, span uint64) []RangeDigest {
+	if span == 0 || begin > end {
+		return nil
+	}
+	if span > MaxDigestSpan {
+		span = MaxDigestSpan
+	}
+	if last := begin + span*MaxDigestRanges - 1; last >= begin && last < end {
+		end = last
+	}
+	var digests []RangeDigest
+	for len(digests) < MaxDigestRanges {
+		last := begin + span - 1
+		if last > end || last < begin { // overflowed
+			last = end
+		}
+		digest := limitedRangeDigest(store, remoteID, begin, last, maxDigestCtxs)
+		digests = append(digests, digest)
+		if digest.End == end {
+			break
+		}
+		begin = digest.End + 1
+	}
+	return digests
+}
+
+// Digests returns the digests of ranges in blocks [begin, end] of the store synchronised
+func (s *Sync) Digests(begin, end, span uint64) []RangeDigest {
+	return Digests(s.store, s.remoteID, begin, end, span)
+}
+
+// reportDivergence records the range of which the ctxs are different from peer
+func (s *Sync) reportDivergence(id string, local, remote RangeDigest) {
+	s.log.Warn("Cross store diverged from peer", "peer", id, "begin", local.Begin, "end", local.End,
+		"local", local.Count, "remote", remote.Count, "localRoot", local.Root, "remoteRoot", remote.Root)
+
+	s.divergenceLock.Lock()
+	defer s.divergenceLock.Unlock()
+	s.divergences = append(s.divergences, Divergence{
+		Peer:   id,
+		Begin:  local.Begin,
+		End:    local.End,
+		Local:  local.Root,
+		Remote: remote.Root,
+		Time:   time.Now(),
+	})
+	if len(s.divergences) > maxDivergences {
+		s.divergences = s.divergences[len(s.divergences)-maxDivergences:]
+	}
+}
+
+// Divergences returns the latest ranges of which the ctxs stored are different from peers
+func (s *Sync) Divergences() []Divergence {
+	s.divergenceLock.RLock()
+	defer s.divergenceLock.RUnlock()
+	return append([]Divergence(nil), s.divergences...)
+}
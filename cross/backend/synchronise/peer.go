@@ -20,8 +20,9 @@ type peerConnection struct {
 }
 
 type Peer interface {
-	RequestCtxSyncByHeight(chainID uint64, height uint64) error
-	RequestPendingSync(chain uint64, ids []common.Hash) error
+	RequestCtxSyncByHeight(chainID, remoteID uint64, height uint64) error
+	RequestCtxDigests(chainID, remoteID uint64, begin, end, span uint64) error
+	RequestPendingSync(chain, remote uint64, ids []common.Hash) error
 	HasCrossTransaction(hash common.Hash) bool
 }
 
@@ -36,15 +36,20 @@ var (
 type Sync struct {
 	synchronising  uint32
 	synchronizeCh  chan []*cc.CrossTransactionWithSignatures
+	digestCh       chan []RangeDigest
 	pendingSyncing syncmap.Map // map[string]chan []*cc.CrossTransaction
 
+	divergences    []Divergence // latest ranges diverged from peers after synchronised
+	divergenceLock sync.RWMutex
+
 	peers *peerSet
 	mode  SyncMode
 
-	chainID *big.Int
-	pool    CrossPool
-	store   CrossStore
-	chain   CrossChain
+	chainID  *big.Int
+	remoteID *big.Int
+	pool     CrossPool
+	store    CrossStore
+	chain    CrossChain
 
 	wg       sync.WaitGroup
 	quitSync chan struct{}
@@ -59,26 +64,31 @@ type CrossPool interface {
 type CrossStore interface {
 	Height() uint64
 	Writes([]*cc.CrossTransactionWithSignatures, bool) error
+	RangeByNumber(begin, end uint64, limit int) []*cc.CrossTransactionWithSignatures
 }
 
 type CrossChain interface {
 	CanAcceptTxs() bool
 	RequireSignatures() int
 	GetConfirmedTransactionNumberOnChain(trigger.Transaction) uint64
+	ConfirmedDepth() uint64
+	CurrentBlockNumber() uint64
 }
 
-func New(chainID *big.Int, pool CrossPool, store CrossStore, chain CrossChain, mode SyncMode) *Sync {
-	logger := log.New("X-module", "sync", "chainID", chainID)
+func New(chainID, remoteID *big.Int, pool CrossPool, store CrossStore, chain CrossChain, mode SyncMode) *Sync {
+	logger := log.New("X-module", "sync", "chainID", chainID, "remoteID", remoteID)
 	logger.Info("Initialising cross synchronisation", "mode", mode.String())
 
 	s := &Sync{
 		chainID:       chainID,
+		remoteID:      remoteID,
 		peers:         newPeerSet(),
 		mode:          mode,
 		pool:          pool,
 		store:         store,
 		chain:         chain,
 		synchronizeCh: make(chan []*cc.CrossTransactionWithSignatures, syncChannelSize),
+		digestCh:      make(chan []RangeDigest, syncChannelSize),
 		quitSync:      make(chan struct{}),
 		log:           logger,
 	}
@@ -152,8 +162,14 @@ func (s *Sync) Synchronise(id string, height *big.Int) error {
 	if s.mode == OFF || s.mode == PENDING {
 		return nil
 	}
-	s.log.Info("start sync cross transactions", "peer", id, "height", height)
-	err := s.syncWithPeer(id, height)
+	s.log.Info("start sync cross transactions", "peer", id, "height", height, "mode", s.mode)
+	var err error
+	if s.mode == DIGEST {
+		err = s.syncDigestWithPeer(id, height)
+	}
+	if s.mode != DIGEST || err == ErrDigestUnsupported {
+		err = s.syncWithPeer(id, height)
+	}
 	switch err {
 	case nil:
 	case errBusy, errCanceled:
@@ -248,7 +264,7 @@ func (s *Sync) syncWithPeer(id string, peerHeight *big.Int) error {
 	// 通过区块log标识的跨链交易高度同步store。
 	// TODO:如果在store同步过程中，所在高度H的跨链交易状态被其他链修改(executing,executed)，那么此次状态更新讲无法被同步
 	if height := s.store.Height(); height <= peerHeight.Uint64() {
-		go p.peer.RequestCtxSyncByHeight(s.chainID.Uint64(), height)
+		go p.peer.RequestCtxSyncByHeight(s.chainID.Uint64(), s.remoteID.Uint64(), height)
 	}
 
 	timeout := time.NewTimer(rttMaxEstimate)
@@ -261,10 +277,10 @@ func (s *Sync) syncWithPeer(id string, peerHeight *big.Int) error {
 		case txs := <-s.synchronizeCh:
 			var selfTxs []*cc.CrossTransactionWithSignatures
 			for _, tx := range txs {
-				if tx.ChainId().Cmp(s.chainID) == 0 {
+				if tx.ChainId().Cmp(s.chainID) == 0 && tx.DestinationId().Cmp(s.remoteID) == 0 {
 					selfTxs = append(selfTxs, tx)
 				}
-				//ignore other tx: 子链的tx需要被负责它的handler同步
+				//ignore other tx: 其他链对的tx需要被负责它的handler同步
 			}
 			if selfTxs == nil {
 				s.log.Debug("sync ctx request completed")
@@ -278,7 +294,7 @@ func (s *Sync) syncWithPeer(id string, peerHeight *big.Int) error {
 			sort.Sort(sortedTxs)
 			self = s.syncCrossTransaction(sortedTxs)
 			lastHeight = sortedTxs.LastNumber()
-			go p.peer.RequestCtxSyncByHeight(s.chainID.Uint64(), lastHeight+1)
+			go p.peer.RequestCtxSyncByHeight(s.chainID.Uint64(), s.remoteID.Uint64(), lastHeight+1)
 
 			log.Info("Import cross transactions", "chainID", s.chainID.Uint64(), "total", len(txs),
 				"self", self, "lastHeight", lastHeight)
@@ -292,6 +308,125 @@ func (s *Sync) syncWithPeer(id string, peerHeight *big.Int) error {
 	}
 }
 
+// syncDigestWithPeer compares the range digests of store with the peer, and only the
+// ranges differing are synchronised. The confirmed ranges of which the ctxs still differ
+// after synchronised are reported as divergences, the statuses are not compared then
+// since a peer may lag behind.
+func (s *Sync) syncDigestWithPeer(id string, peerHeight *big.Int) error {
+	if !atomic.CompareAndSwapUint32(&s.synchronising, 0, 1) {
+		s.log.Debug("sync busy")
+		return errBusy
+	}
+	defer atomic.StoreUint32(&s.synchronising, 0)
+
+	p := s.peers.Peer(id)
+	if p == nil {
+		return errUnknownPeer
+	}
+
+	// ignore prev sync
+	for empty := false; !empty; {
+		select {
+		case <-s.synchronizeCh:
+		case <-s.digestCh:
+		default:
+			empty = true
+		}
+	}
+
+	var (
+		end              = peerHeight.Uint64()
+		synced, diverged int
+	)
+	for begin := uint64(0); begin <= end; {
+		last := begin + DefaultDigestSpan*MaxDigestRanges - 1
+		if last > end || last < begin { // overflowed
+			last = end
+		}
+		if err := p.peer.RequestCtxDigests(s.chainID.Uint64(), s.remoteID.Uint64(), begin, last, DefaultDigestSpan); err != nil {
+			return err
+		}
+		var digests []RangeDigest
+		select {
+		case <-s.quitSync:
+			return errCanceled
+		case digests = <-s.digestCh:
+		case <-time.After(rttMaxEstimate):
+			s.log.Debug("sync digest request timed out")
+			return errTimeout
+		}
+		if len(digests) == 0 {
+			break
+		}
+		for i, remote := range digests {
+			// the ranges must be continuous from begin
+			if remote.Begin != begin || remote.End < remote.Begin || remote.End > last {
+				p.log.Debug("invalid range digest", "index", i, "begin", remote.Begin, "end", remote.End, "want", begin)
+				return errBadPeer
+			}
+			begin = remote.End + 1
+			if local := rangeDigest(s.store, s.remoteID, remote.Begin, remote.End); local == remote {
+				continue
+			}
+			if err := s.syncRange(p, remote.Begin, remote.End); err != nil {
+				return err
+			}
+			synced++
+			if !s.confirmed(remote.End) {
+				continue
+			}
+			if local := rangeDigest(s.store, s.remoteID, remote.Begin, remote.End); local.Root != remote.Root {
+				s.reportDivergence(id, local, remote)
+				diverged++
+			}
+		}
+		if begin == 0 { // overflowed
+			break
+		}
+	}
+	s.log.Info("Synchronised cross store by digests", "peer", id, "height", end, "synced", synced, "diverged", diverged)
+	return nil
+}
+
+// confirmed reports whether the block number is confirmed on chain
+func (s *Sync) confirmed(number uint64) bool {
+	current, depth := s.chain.CurrentBlockNumber(), s.chain.ConfirmedDepth()
+	return current >= depth && number <= current-depth
+}
+
+// syncRange synchronises the ctxs stored in blocks [begin, end] from the peer
+func (s *Sync) syncRange(p *peerConnection, begin, end uint64) error {
+	for height := begin; height <= end; {
+		go p.peer.RequestCtxSyncByHeight(s.chainID.Uint64(), s.remoteID.Uint64(), height)
+
+		var txs []*cc.CrossTransactionWithSignatures
+		select {
+		case <-s.quitSync:
+			return errCanceled
+		case txs = <-s.synchronizeCh:
+		case <-time.After(rttMaxEstimate):
+			s.log.Debug("sync ctx request timed out")
+			return errTimeout
+		}
+		var selfTxs SortedTxByBlockNum
+		for _, tx := range txs {
+			if tx.ChainId().Cmp(s.chainID) == 0 && tx.DestinationId().Cmp(s.remoteID) == 0 &&
+				tx.BlockNum >= height && tx.BlockNum <= end {
+				selfTxs = append(selfTxs, tx)
+			}
+		}
+		if len(selfTxs) == 0 {
+			return nil
+		}
+		sort.Sort(selfTxs)
+		s.syncCrossTransaction(selfTxs)
+		if height = selfTxs.LastNumber() + 1; height == 0 { // overflowed
+			return nil
+		}
+	}
+	return nil
+}
+
 func (s *Sync) syncPendingWithPeer(id string, request []common.Hash) error {
 	p := s.peers.Peer(id)
 	if p == nil {
@@ -309,7 +444,7 @@ func (s *Sync) syncPendingWithPeer(id string, request []common.Hash) error {
 
 	s.log.Debug("sync pending from peer", "id", id, "requests", len(request))
 
-	go p.peer.RequestPendingSync(s.chainID.Uint64(), request) //TODO-U:判断是否需要向此节点请求签名(高度？or 历史签名？)
+	go p.peer.RequestPendingSync(s.chainID.Uint64(), s.remoteID.Uint64(), request) //TODO-U:判断是否需要向此节点请求签名(高度？or 历史签名？)
 
 	ch, _ := s.pendingSyncing.Load(p.id)              // must exist
 	pendingSyncCh := ch.(chan []*cc.CrossTransaction) // must success
@@ -336,7 +471,7 @@ func (s *Sync) syncPendingWithPeer(id string, request []common.Hash) error {
 				return nil
 			}
 			// send next sync pending request
-			go p.peer.RequestPendingSync(s.chainID.Uint64(), request)
+			go p.peer.RequestPendingSync(s.chainID.Uint64(), s.remoteID.Uint64(), request)
 			log.Info("Import pending", "chainID", s.chainID.Uint64(), "total", len(pending), "syncedHeight", synced)
 
 			timeout.Reset(rttMaxEstimate)
@@ -363,6 +498,25 @@ func (s *Sync) DeliverCrossTransactions(pid string, ctxList []*cc.CrossTransacti
 	return nil
 }
 
+// DeliverDigests delivers the range digests responded by peer to the digest synchronisation
+func (s *Sync) DeliverDigests(pid string, digests []RangeDigest) error {
+	peer := s.peers.Peer(pid)
+	if peer == nil {
+		return errUnknownPeer
+	}
+
+	// the digests are only requested one at a time, drop the unsolicited ones
+	select {
+	case s.digestCh <- digests:
+		peer.log.Debug("syncing range digests", "peer", peer.id, "count", len(digests))
+	case <-s.quitSync:
+		return errCanceled
+	default:
+		return errSyncNotStart
+	}
+	return nil
+}
+
 func (s *Sync) DeliverPending(pid string, pending []*cc.CrossTransaction) error {
 	peer := s.peers.Peer(pid)
 	if peer == nil {
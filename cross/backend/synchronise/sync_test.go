@@ -2,6 +2,7 @@ package synchronise
 
 import (
 	"encoding/binary"
+	"math"
 	"math/big"
 	"math/rand"
 	"sync"
@@ -21,6 +22,7 @@ import (
 
 type syncTester struct {
 	synchronize *Sync
+	chain       *chainTester
 	pending     *db.CtxSortedByBlockNum
 	queue       *db.CtxSortedByBlockNum
 	store       *storeTester
@@ -32,6 +34,10 @@ type syncTester struct {
 var bigZero = new(big.Int)
 
 func newTester() *syncTester {
+	return newTesterWithMode(ALL)
+}
+
+func newTesterWithMode(mode SyncMode) *syncTester {
 	chainID := bigZero
 	tester := &syncTester{
 		pending: db.NewCtxSortedMap(),
@@ -40,7 +46,8 @@ func newTester() *syncTester {
 		peers:   make(map[string]*syncTesterPeer),
 	}
 	tester.store = newStoreTester()
-	tester.synchronize = New(chainID, tester, tester, &chainTester{}, 0)
+	tester.chain = &chainTester{current: 1 << 20, depth: 12}
+	tester.synchronize = New(chainID, bigZero, tester, tester, tester.chain, mode)
 	return tester
 }
 
@@ -91,10 +98,15 @@ func (sc *syncTester) Writes(ctxList []*cc.CrossTransactionWithSignatures, repla
 	return sc.store.Writes(ctxList, replaceable)
 }
 
+func (sc *syncTester) RangeByNumber(begin, end uint64, limit int) []*cc.CrossTransactionWithSignatures {
+	return sc.store.RangeByNumber(begin, end, limit)
+}
+
 type syncTesterPeer struct {
-	id    string
-	sc    *syncTester
-	store *storeTester
+	id        string
+	sc        *syncTester
+	store     *storeTester
+	requested []uint64 // heights of ctx sync requests
 }
 
 func (sc *syncTester) newPeer(id string, store *storeTester) error {
@@ -107,12 +119,20 @@ func (sc *syncTester) newPeer(id string, store *storeTester) error {
 	return sc.synchronize.RegisterPeer(id, peer)
 }
 
-func (p *syncTesterPeer) RequestCtxSyncByHeight(chainID uint64, height uint64) error {
-	ctxList := p.store.rangeByNumber(height, p.store.Height(), 10)
+func (p *syncTesterPeer) RequestCtxSyncByHeight(chainID, remoteID uint64, height uint64) error {
+	p.sc.lock.Lock()
+	p.requested = append(p.requested, height)
+	p.sc.lock.Unlock()
+	ctxList := p.store.RangeByNumber(height, p.store.Height(), 10)
 	return p.sc.synchronize.DeliverCrossTransactions(p.id, ctxList)
 }
 
-func (p *syncTesterPeer) RequestPendingSync(chain uint64, ids []common.Hash) error {
+func (p *syncTesterPeer) RequestCtxDigests(chainID, remoteID uint64, begin, end, span uint64) error {
+	go p.sc.synchronize.DeliverDigests(p.id, Digests(p.store, bigZero, begin, end, span))
+	return nil
+}
+
+func (p *syncTesterPeer) RequestPendingSync(chain, remote uint64, ids []common.Hash) error {
 	var ctxList []*cc.CrossTransaction
 	for _, id := range ids {
 		if ctx := p.store.get(id); ctx != nil {
@@ -178,15 +198,24 @@ func (st *storeTester) Writes(ctxList []*cc.CrossTransactionWithSignatures, repl
 	st.lock.Lock()
 	defer st.lock.Unlock()
 	for _, ctx := range ctxList {
-		if st.transactionm[ctx.ID()] != nil && !replaceable {
+		old := st.transactionm[ctx.ID()]
+		if old != nil && !replaceable {
 			continue
 		}
+		if old != nil { // drop the replaced one from the number index
+			for it := st.numberTree.LowerBound(old.BlockNum); it != st.numberTree.UpperBound(old.BlockNum); it.Next() {
+				if it.Value().(*cc.CrossTransactionWithSignatures).ID() == ctx.ID() {
+					st.numberTree.RemoveOne(it)
+					break
+				}
+			}
+		}
 		st.numberTree.Put(ctx.BlockNum, ctx)
 		st.transactionm[ctx.ID()] = ctx
 	}
 	return nil
 }
-func (st *storeTester) rangeByNumber(begin, end uint64, limit int) []*cc.CrossTransactionWithSignatures {
+func (st *storeTester) RangeByNumber(begin, end uint64, limit int) []*cc.CrossTransactionWithSignatures {
 	st.lock.RLock()
 	defer st.lock.RUnlock()
 	var res []*cc.CrossTransactionWithSignatures
@@ -208,18 +237,25 @@ func (st *storeTester) get(id common.Hash) *cc.CrossTransactionWithSignatures {
 }
 
 func (st *storeTester) generate(number uint64, n int) []*cc.CrossTransactionWithSignatures {
+	ctxs := generateCtxs(number, n)
+	st.Writes(ctxs, false)
+	return ctxs
+}
+
+func generateCtxs(number uint64, n int) []*cc.CrossTransactionWithSignatures {
 	var ctxs []*cc.CrossTransactionWithSignatures
 	for i := 0; i < n; i++ {
 		ctxs = append(ctxs, &cc.CrossTransactionWithSignatures{
 			BlockNum: number,
 			Status:   cc.CtxStatusWaiting,
 			Data: cc.CtxDatas{
-				CTxId: encodeBlockNumber(number, uint32(i)),
+				CTxId:            encodeBlockNumber(number, uint32(i)),
+				Value:            new(big.Int),
+				DestinationId:    bigZero,
+				DestinationValue: new(big.Int),
 			},
 		})
 	}
-
-	st.Writes(ctxs, false)
 	return ctxs
 }
 
@@ -241,7 +277,10 @@ func TestEncodeDecode(t *testing.T) {
 	assert.Equal(t, number, dcNumber)
 }
 
-type chainTester struct{}
+type chainTester struct {
+	current uint64
+	depth   uint64
+}
 
 func (*chainTester) GetConfirmedTransactionNumberOnChain(tx trigger.Transaction) uint64 {
 	return decodeBlockNumber(tx.ID())
@@ -255,6 +294,14 @@ func (*chainTester) RequireSignatures() int {
 	return 3
 }
 
+func (c *chainTester) ConfirmedDepth() uint64 {
+	return c.depth
+}
+
+func (c *chainTester) CurrentBlockNumber() uint64 {
+	return c.current
+}
+
 func TestSync_Synchronise(t *testing.T) {
 	sc := newTester()
 	defer sc.synchronize.Terminate()
@@ -357,3 +404,154 @@ func TestSync_SynchronisePendingMultiPeers(t *testing.T) {
 		assert.Equal(t, 3, sc.queue.Get(ctxs5[i].ID()).SignaturesLength())
 	}
 }
+
+func TestSync_SynchroniseDigest(t *testing.T) {
+	sc := newTesterWithMode(DIGEST)
+	defer sc.synchronize.Terminate()
+	store := newStoreTester()
+	assert.NoError(t, sc.newPeer("pa", store))
+
+	// the ranges [0, 1023] and [1024, 2047] are synchronised before
+	sc.store.Writes(store.generate(1, 100), false)
+	sc.store.Writes(store.generate(1500, 100), false)
+	store.generate(5000, 100)
+	store.generate(5001, 100)
+
+	// the status of a ctx in [3072, 4095] is changed by peer
+	ctxs := store.generate(4000, 10)
+	sc.store.Writes(generateCtxs(4000, 10), false)
+	ctxs[3].Status = cc.CtxStatusExecuted
+
+	// the ctxs in [2048, 3071] are not stored by peer
+	sc.store.Writes(generateCtxs(3000, 5), false)
+
+	assert.NoError(t, sc.sync("pa", nil))
+	assert.Equal(t, store.Height(), sc.store.Height())
+	assert.Equal(t, cc.CtxStatusExecuted, sc.store.get(ctxs[3].ID()).Status)
+	assert.Equal(t, len(store.transactionm)+5, len(sc.store.transactionm))
+
+	// only the ranges differing are requested
+	assert.Equal(t, []uint64{2048, 3072, 4001, 4096, 5001}, sc.peers["pa"].requested)
+
+	divergences := sc.synchronize.Divergences()
+	if assert.Len(t, divergences, 1) {
+		assert.Equal(t, "pa", divergences[0].Peer)
+		assert.EqualValues(t, 2048, divergences[0].Begin)
+		assert.EqualValues(t, 3071, divergences[0].End)
+		assert.Equal(t, common.Hash{}, divergences[0].Remote)
+	}
+
+	// nothing is requested once synchronised
+	sc.peers["pa"].requested = nil
+	assert.NoError(t, sc.sync("pa", nil))
+	assert.Equal(t, []uint64{2048}, sc.peers["pa"].requested)
+}
+
+func TestSync_SynchroniseDigestFinished(t *testing.T) {
+	sc := newTesterWithMode(DIGEST)
+	defer sc.synchronize.Terminate()
+	store := newStoreTester()
+	assert.NoError(t, sc.newPeer("pa", store))
+
+	// the finished ctxs removed by peer
+	finished := generateCtxs(1, 10)
+	for _, ctx := range finished {
+		ctx.Status = cc.CtxStatusFinished
+	}
+	sc.store.Writes(finished, false)
+
+	// the cancelled ctxs archived locally
+	for _, ctx := range store.generate(3000, 10) {
+		ctx.Status = cc.CtxStatusCancelled
+	}
+	store.generate(5000, 5)
+	sc.store.Writes(generateCtxs(5000, 5), false)
+
+	assert.NoError(t, sc.sync("pa", nil))
+	assert.Empty(t, sc.peers["pa"].requested)
+	assert.Empty(t, sc.synchronize.Divergences())
+}
+
+func TestDigestRoot(t *testing.T) {
+	ctxs := generateCtxs(1, 5)
+	root := DigestRoot(ctxs)
+	assert.NotEqual(t, common.Hash{}, root)
+	assert.Equal(t, common.Hash{}, DigestRoot(nil))
+
+	// the order of ctxs is ignored
+	reversed := []*cc.CrossTransactionWithSignatures{ctxs[4], ctxs[3], ctxs[2], ctxs[1], ctxs[0]}
+	assert.Equal(t, root, DigestRoot(reversed))
+
+	// the ids and signed fields are digested, but the statuses are not
+	status := StatusRoot(ctxs)
+	ctxs[2].Status = cc.CtxStatusFinished
+	assert.Equal(t, root, DigestRoot(ctxs))
+	assert.NotEqual(t, status, StatusRoot(ctxs))
+	assert.NotEqual(t, root, DigestRoot(ctxs[:4]))
+
+	changed := generateCtxs(1, 5)
+	changed[2].Data.Value = big.NewInt(1)
+	assert.NotEqual(t, root, DigestRoot(changed))
+	assert.Equal(t, StatusRoot(generateCtxs(1, 5)), StatusRoot(changed))
+}
+
+func TestSync_SynchroniseDigestLagging(t *testing.T) {
+	sc := newTesterWithMode(DIGEST)
+	defer sc.synchronize.Terminate()
+	store := newStoreTester()
+	assert.NoError(t, sc.newPeer("pa", store))
+
+	// the peer has not updated the statuses of ctxs executed
+	store.generate(1, 10)
+	executed := generateCtxs(1, 10)
+	for _, ctx := range executed {
+		ctx.Status = cc.CtxStatusExecuted
+	}
+	sc.store.Writes(executed, false)
+
+	// the peer has not stored the latest ctxs which are not confirmed yet
+	store.generate(5000, 5)
+	sc.store.Writes(generateCtxs(5000, 5), false)
+	sc.store.Writes(generateCtxs(4990, 3), false)
+	sc.chain.current = 5010
+
+	assert.NoError(t, sc.sync("pa", nil))
+	assert.Empty(t, sc.synchronize.Divergences())
+
+	// the range is reported once confirmed
+	sc.chain.current = 6000
+	assert.NoError(t, sc.sync("pa", nil))
+	divergences := sc.synchronize.Divergences()
+	if assert.Len(t, divergences, 1) {
+		assert.EqualValues(t, 4096, divergences[0].Begin)
+		assert.EqualValues(t, 5000, divergences[0].End)
+	}
+}
+
+func TestDigests_Capped(t *testing.T) {
+	store := newStoreTester()
+	store.generate(1, maxDigestCtxs)
+	store.generate(2, 10)
+	store.generate(5, maxDigestCtxs+10)
+
+	// the span and the total range are capped
+	digests := Digests(store, bigZero, 100, math.MaxUint64, math.MaxUint64)
+	if assert.Len(t, digests, MaxDigestRanges) {
+		assert.EqualValues(t, 100+MaxDigestSpan-1, digests[0].End)
+		assert.EqualValues(t, 100+MaxDigestSpan*MaxDigestRanges-1, digests[MaxDigestRanges-1].End)
+	}
+
+	// the ctxs of a digest are capped, but its first block is kept
+	digests = Digests(store, bigZero, 1, 10, 10)
+	if assert.Len(t, digests, 4) {
+		assert.Equal(t, RangeDigest{Begin: 1, End: 1, Count: maxDigestCtxs}, RangeDigest{Begin: digests[0].Begin, End: digests[0].End, Count: digests[0].Count})
+		assert.EqualValues(t, 2, digests[1].Begin)
+		assert.EqualValues(t, 4, digests[1].End)
+		assert.EqualValues(t, 10, digests[1].Count)
+		assert.EqualValues(t, 5, digests[2].Begin)
+		assert.EqualValues(t, 5, digests[2].End)
+		assert.EqualValues(t, maxDigestCtxs+10, digests[2].Count)
+		assert.EqualValues(t, 6, digests[3].Begin)
+		assert.EqualValues(t, 10, digests[3].End)
+	}
+}
@@ -14,6 +14,7 @@ const (
 	STORE
 	PENDING
 	OFF
+	DIGEST // like ALL, but the store is synchronised by the ranges differing in digests
 )
 
 func (mode SyncMode) String() string {
@@ -26,6 +27,8 @@ func (mode SyncMode) String() string {
 		return "pending"
 	case OFF:
 		return "off"
+	case DIGEST:
+		return "digest"
 	default:
 		return "unknown"
 	}
@@ -41,6 +44,8 @@ func (mode SyncMode) MarshalText() ([]byte, error) {
 		return []byte("pending"), nil
 	case OFF:
 		return []byte("off"), nil
+	case DIGEST:
+		return []byte("digest"), nil
 	default:
 		return nil, fmt.Errorf("unknown sync mode %d", mode)
 	}
@@ -56,30 +61,50 @@ func (mode *SyncMode) UnmarshalText(text []byte) error {
 		*mode = PENDING
 	case "off":
 		*mode = OFF
+	case "digest":
+		*mode = DIGEST
 	default:
-		return fmt.Errorf(`unknown sync mode %q, want "all", "store" or "pending" or "off"`, text)
+		return fmt.Errorf(`unknown sync mode %q, want "all", "store", "pending", "digest" or "off"`, text)
 	}
 	return nil
 }
 
 type SyncReq struct {
 	Chain  uint64
+	Remote uint64
 	Height uint64
 }
 
 type SyncResp struct {
-	Chain uint64
-	Data  [][]byte
+	Chain  uint64
+	Remote uint64
+	Data   [][]byte
 }
 
 type SyncPendingReq struct {
-	Chain uint64
-	Ids   []common.Hash
+	Chain  uint64
+	Remote uint64
+	Ids    []common.Hash
 }
 
 type SyncPendingResp struct {
-	Chain uint64
-	Data  [][]byte
+	Chain  uint64
+	Remote uint64
+	Data   [][]byte
+}
+
+type DigestReq struct {
+	Chain  uint64
+	Remote uint64
+	Begin  uint64
+	End    uint64
+	Span   uint64 // blocks of a range
+}
+
+type DigestResp struct {
+	Chain   uint64
+	Remote  uint64
+	Digests []RangeDigest
 }
 
 type SortedTxByBlockNum []*core.CrossTransactionWithSignatures
@@ -0,0 +1,452 @@
+package backend
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/log"
+
+	cc "gbchain-org/go-gbchain/cross/core"
+	cdb "gbchain-org/go-gbchain/cross/database"
+	"gbchain-org/go-gbchain/cross/trigger"
+
+	"github.com/asdine/storm/v3/q"
+)
+
+const (
+	takerBookSize = 1024             // the cheapest remote waiting ctxs matched in a round
+	takerInterval = 15 * time.Second // interval of matching rounds besides the arrival of remote ctxs
+	takerTimeout  = 5 * time.Minute  // ctxs still waiting after the timeout are matched again
+)
+
+var (
+	errTakerDisabled = errors.New("auto taker is disabled")
+	errUnknownTaker  = errors.New("account is not a configured taker")
+	errUnknownOrder  = errors.New("unknown taker order")
+	errInvalidOrder  = errors.New("invalid taker order")
+)
+
+// TakerOrder is a standing order of a taker account, it takes the remote ctxs whose prices
+// (DestinationValue / Value) are not higher than MaxPrice until the budget is spent.
+type TakerOrder struct {
+	ID               uint64
+	Account          common.Address
+	Token            common.Address // token received in remote chain, zero for native coin
+	DestinationToken common.Address // token paid in local chain, zero for native coin
+	MaxPrice         *big.Rat
+	Budget           *big.Int // destination values paid at most
+	Spent            *big.Int // destination values of the ctxs being taken or taken
+
+	canceled bool // the ctxs being taken by the order are not refunded into the store
+}
+
+func (o *TakerOrder) remains() *big.Int {
+	return new(big.Int).Sub(o.Budget, o.Spent)
+}
+
+// accepts reports whether the order could take cws with its remaining budget
+func (o *TakerOrder) accepts(cws *cc.CrossTransactionWithSignatures) bool {
+	if cws.Token() != o.Token || cws.DestinationToken() != o.DestinationToken {
+		return false
+	}
+	if cws.Data.From == o.Account || (cws.Data.To != common.Address{} && cws.Data.To != o.Account) {
+		return false
+	}
+	// the message ctxs are only taken by takerMessage of anchors, the contract rejects the
+	// inputs leading with the message magic even if they are malformed
+	if _, err := cc.ParseMessage(cws.Data.Input); err != cc.ErrNotMessage {
+		return false
+	}
+	return cws.Price().Cmp(o.MaxPrice) <= 0 && o.remains().Cmp(cws.Data.DestinationValue) >= 0
+}
+
+// orderBook keeps the remote waiting ctxs sorted by price, the cheapest ctx comes first
+type orderBook struct {
+	ctxs []*cc.CrossTransactionWithSignatures
+}
+
+func (b *orderBook) reset(ctxs []*cc.CrossTransactionWithSignatures) {
+	b.ctxs = append(b.ctxs[:0], ctxs...)
+	sort.SliceStable(b.ctxs, func(i, j int) bool {
+		return b.ctxs[i].Price().Cmp(b.ctxs[j].Price()) < 0
+	})
+}
+
+// top returns the n cheapest ctxs, all the ctxs if n is not positive
+func (b *orderBook) top(n int) []*cc.CrossTransactionWithSignatures {
+	if n <= 0 || n > len(b.ctxs) {
+		n = len(b.ctxs)
+	}
+	return append([]*cc.CrossTransactionWithSignatures{}, b.ctxs[:n]...)
+}
+
+type takerMatch struct {
+	order *TakerOrder
+	ctx   *cc.CrossTransactionWithSignatures
+}
+
+// match fills the orders by the ctxs in the order of prices, the order placed earlier is filled
+// first if several orders accept a ctx. The ctxs being taken are skipped, and the budgets of
+// matched orders are spent.
+func (b *orderBook) match(orders []*TakerOrder, taking map[common.Hash]*takerSubmission) []takerMatch {
+	var matches []takerMatch
+	for _, ctx := range b.ctxs {
+		if _, ok := taking[ctx.ID()]; ok {
+			continue
+		}
+		for _, order := range orders {
+			if order.accepts(ctx) {
+				order.Spent.Add(order.Spent, ctx.Data.DestinationValue)
+				matches = append(matches, takerMatch{order: order, ctx: ctx})
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// takerSubmission is a ctx being taken by an order
+type takerSubmission struct {
+	order *TakerOrder
+	value *big.Int
+	hash  common.Hash // the taker transaction, zero until it is sent
+	time  time.Time
+}
+
+// autoTaker takes the remote ctxs of the chain pair by the standing orders of taker accounts,
+// the orders and the ctxs being taken are persisted in db to survive the restart of node.
+type autoTaker struct {
+	chainID   *big.Int
+	remoteID  *big.Int
+	store     *CrossStore
+	db        *cdb.TakerStore // orders are kept in memory only if nil
+	retriever trigger.ChainRetriever
+	executor  trigger.TakerExecutor
+	signers   map[common.Address]trigger.Signer
+
+	mu     sync.Mutex // protects book, orders and taking
+	book   *orderBook
+	orders []*TakerOrder // in the order of placing
+	nextID uint64
+	taking map[common.Hash]*takerSubmission
+
+	notifyCh chan struct{}
+	quit     chan struct{}
+	wg       sync.WaitGroup
+	log      log.Logger
+}
+
+func newAutoTaker(h *Handler, executor trigger.TakerExecutor, signers map[common.Address]trigger.Signer, db *cdb.TakerStore) (*autoTaker, error) {
+	t := &autoTaker{
+		chainID:   h.chainID,
+		remoteID:  h.remoteID,
+		store:     h.store,
+		db:        db,
+		retriever: h.retriever,
+		executor:  executor,
+		signers:   signers,
+		book:      new(orderBook),
+		nextID:    1,
+		taking:    make(map[common.Hash]*takerSubmission),
+		notifyCh:  make(chan struct{}, 1),
+		quit:      make(chan struct{}),
+		log:       h.log.New("module", "taker"),
+	}
+	if err := t.load(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// load restores the orders and the ctxs being taken persisted before restart
+func (t *autoTaker) load() error {
+	if t.db == nil {
+		return nil
+	}
+	records, err := t.db.Orders(t.chainID, t.remoteID)
+	if err != nil {
+		return err
+	}
+	orders := make(map[uint64]*TakerOrder, len(records))
+	for _, r := range records {
+		order := &TakerOrder{
+			ID:               r.ID,
+			Account:          r.Account,
+			Token:            r.Token,
+			DestinationToken: r.DestinationToken,
+			MaxPrice:         new(big.Rat).SetFrac(r.PriceNum, r.PriceDenom),
+			Budget:           r.Budget,
+			Spent:            r.Spent,
+		}
+		t.orders = append(t.orders, order)
+		orders[order.ID] = order
+		if order.ID >= t.nextID {
+			t.nextID = order.ID + 1
+		}
+	}
+	if next := t.db.NextID(t.chainID, t.remoteID); next > t.nextID {
+		t.nextID = next
+	}
+
+	subs, err := t.db.Submissions(t.chainID, t.remoteID)
+	if err != nil {
+		return err
+	}
+	for _, sub := range subs {
+		order := orders[sub.Order]
+		if order == nil { // the ctx is skipped by other orders until the submission expires
+			order = &TakerOrder{ID: sub.Order, Budget: new(big.Int), Spent: new(big.Int).Set(sub.Value), canceled: true}
+		}
+		t.taking[sub.CtxID] = &takerSubmission{order: order, value: sub.Value, hash: sub.Hash, time: time.Unix(int64(sub.Time), 0)}
+	}
+	if len(t.orders) > 0 || len(t.taking) > 0 {
+		t.log.Info("Loaded taker orders", "orders", len(t.orders), "taking", len(t.taking))
+	}
+	return nil
+}
+
+// saveOrder persists the order unless it is canceled
+func (t *autoTaker) saveOrder(order *TakerOrder) {
+	if t.db == nil || order.canceled {
+		return
+	}
+	if err := t.db.PutOrder(t.chainID, t.remoteID, &cdb.TakerOrder{
+		ID:               order.ID,
+		Account:          order.Account,
+		Token:            order.Token,
+		DestinationToken: order.DestinationToken,
+		PriceNum:         order.MaxPrice.Num(),
+		PriceDenom:       order.MaxPrice.Denom(),
+		Budget:           order.Budget,
+		Spent:            order.Spent,
+	}); err != nil {
+		t.log.Warn("save taker order failed", "id", order.ID, "error", err)
+	}
+}
+
+// saveSubmission persists the ctx being taken, which is sent already
+func (t *autoTaker) saveSubmission(id common.Hash, s *takerSubmission) {
+	if t.db == nil {
+		return
+	}
+	if err := t.db.PutSubmission(t.chainID, t.remoteID, &cdb.TakerSubmission{
+		CtxID: id,
+		Order: s.order.ID,
+		Value: s.value,
+		Hash:  s.hash,
+		Time:  uint64(s.time.Unix()),
+	}); err != nil {
+		t.log.Warn("save taker submission failed", "id", id, "error", err)
+	}
+}
+
+// dropSubmission removes the submission persisted
+func (t *autoTaker) dropSubmission(id common.Hash) {
+	delete(t.taking, id)
+	if t.db == nil {
+		return
+	}
+	if err := t.db.DeleteSubmission(t.chainID, t.remoteID, id); err != nil {
+		t.log.Warn("delete taker submission failed", "id", id, "error", err)
+	}
+}
+
+func (t *autoTaker) start() {
+	t.wg.Add(1)
+	go t.loop()
+}
+
+func (t *autoTaker) stop() {
+	close(t.quit)
+	t.wg.Wait()
+}
+
+// notify starts a matching round for the new remote ctxs
+func (t *autoTaker) notify() {
+	select {
+	case t.notifyCh <- struct{}{}:
+	default:
+	}
+}
+
+func (t *autoTaker) loop() {
+	defer t.wg.Done()
+	ticker := time.NewTicker(takerInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.notifyCh:
+			t.round(time.Now())
+		case <-ticker.C:
+			t.round(time.Now())
+		case <-t.quit:
+			return
+		}
+	}
+}
+
+// waiting returns the cheapest remote ctxs could be taken in local chain
+func (t *autoTaker) waiting() []*cc.CrossTransactionWithSignatures {
+	if !t.retriever.CanAcceptTxs() {
+		return nil
+	}
+	store, err := t.store.GetStore(t.remoteID)
+	if err != nil {
+		t.log.Warn("get remote store failed", "error", err)
+		return nil
+	}
+	return store.Query(takerBookSize, 1, []cdb.FieldName{cdb.PriceIndex}, false,
+		q.Eq(cdb.StatusField, cc.CtxStatusWaiting), q.Eq(cdb.DestinationId, t.chainID))
+}
+
+// round matches the orders with the book and sends the taker transactions of matched ctxs
+func (t *autoTaker) round(now time.Time) {
+	ctxs := t.waiting()
+
+	t.mu.Lock()
+	t.book.reset(ctxs)
+	t.expire(now)
+	matches := t.book.match(t.orders, t.taking)
+	for _, m := range matches {
+		t.taking[m.ctx.ID()] = &takerSubmission{order: m.order, value: m.ctx.Data.DestinationValue, time: now}
+	}
+	t.mu.Unlock()
+	if len(matches) == 0 {
+		return
+	}
+
+	// send the transactions of each account in the order of prices
+	var (
+		accounts []common.Address
+		grouped  = make(map[common.Address][]*cc.CrossTransactionWithSignatures)
+	)
+	for _, m := range matches {
+		if _, ok := grouped[m.order.Account]; !ok {
+			accounts = append(accounts, m.order.Account)
+		}
+		grouped[m.order.Account] = append(grouped[m.order.Account], m.ctx)
+	}
+	for _, account := range accounts {
+		cwss := grouped[account]
+		hashes, err := t.executor.SubmitTaker(account, t.signers[account], cwss)
+		if err != nil {
+			t.log.Warn("submit taker transactions failed", "taker", account, "count", len(cwss), "error", err)
+		}
+		t.mu.Lock()
+		for i, cws := range cwss {
+			if err != nil || hashes[i] == (common.Hash{}) {
+				t.refund(cws.ID())
+				continue
+			}
+			s := t.taking[cws.ID()]
+			s.hash = hashes[i]
+			t.saveSubmission(cws.ID(), s)
+		}
+		t.mu.Unlock()
+		t.log.Info("Take remote ctxs", "taker", account, "matched", len(cwss))
+	}
+
+	// persist the budgets spent by the ctxs sent
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	saved := make(map[*TakerOrder]bool)
+	for _, m := range matches {
+		if !saved[m.order] {
+			t.saveOrder(m.order)
+			saved[m.order] = true
+		}
+	}
+}
+
+// expire drops the submissions timed out, the budgets are charged only if our taker transactions
+// are executed successfully, otherwise they are refunded no matter the ctxs are taken by others or not.
+func (t *autoTaker) expire(now time.Time) {
+	for id, s := range t.taking {
+		if now.Sub(s.time) < takerTimeout {
+			continue
+		}
+		receipt, err := t.executor.TakerReceipt(s.hash)
+		switch {
+		case err != nil:
+			t.log.Debug("get taker receipt failed", "id", id, "tx", s.hash, "error", err)
+		case receipt != nil && receipt.Status == types.ReceiptStatusSuccessful:
+			t.dropSubmission(id)
+		default:
+			t.refund(id)
+			t.saveOrder(s.order)
+		}
+	}
+}
+
+func (t *autoTaker) refund(id common.Hash) {
+	if s, ok := t.taking[id]; ok {
+		s.order.Spent.Sub(s.order.Spent, s.value)
+		t.dropSubmission(id)
+	}
+}
+
+// AddOrder places a standing order, the ID of order is returned
+func (t *autoTaker) AddOrder(order *TakerOrder) (uint64, error) {
+	if _, ok := t.signers[order.Account]; !ok {
+		return 0, errUnknownTaker
+	}
+	if order.MaxPrice == nil || order.MaxPrice.Sign() <= 0 || order.Budget == nil || order.Budget.Sign() <= 0 {
+		return 0, errInvalidOrder
+	}
+	t.mu.Lock()
+	order.ID, order.Spent = t.nextID, new(big.Int)
+	t.nextID++
+	t.orders = append(t.orders, order)
+	t.saveOrder(order)
+	if t.db != nil {
+		if err := t.db.SetNextID(t.chainID, t.remoteID, t.nextID); err != nil {
+			t.log.Warn("save next taker order id failed", "error", err)
+		}
+	}
+	t.mu.Unlock()
+
+	t.notify()
+	return order.ID, nil
+}
+
+// CancelOrder removes the order, the ctxs being taken by it are not affected
+func (t *autoTaker) CancelOrder(id uint64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, order := range t.orders {
+		if order.ID == id {
+			t.orders = append(t.orders[:i], t.orders[i+1:]...)
+			order.canceled = true
+			if t.db != nil {
+				return t.db.DeleteOrder(t.chainID, t.remoteID, id)
+			}
+			return nil
+		}
+	}
+	return errUnknownOrder
+}
+
+// Orders returns the copies of standing orders
+func (t *autoTaker) Orders() []*TakerOrder {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	orders := make([]*TakerOrder, len(t.orders))
+	for i, order := range t.orders {
+		cpy := *order
+		cpy.Spent = new(big.Int).Set(order.Spent)
+		orders[i] = &cpy
+	}
+	return orders
+}
+
+// Book returns the n cheapest ctxs in the book of the last round
+func (t *autoTaker) Book(n int) []*cc.CrossTransactionWithSignatures {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.book.top(n)
+}
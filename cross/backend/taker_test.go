@@ -0,0 +1,192 @@
+package backend
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/ethdb/memorydb"
+	"gbchain-org/go-gbchain/log"
+
+	cc "gbchain-org/go-gbchain/cross/core"
+	cdb "gbchain-org/go-gbchain/cross/database"
+	"gbchain-org/go-gbchain/cross/trigger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testTaker = common.HexToAddress("0x7a")
+	testOther = common.HexToAddress("0x7b")
+)
+
+// newTakerCtxs creates the remote waiting ctxs paying value 10 each, the prices are dests / 10
+func newTakerCtxs(dests ...int64) []*cc.CrossTransactionWithSignatures {
+	ctxs := generateCtx(len(dests), cc.CtxStatusWaiting)
+	for i, ctx := range ctxs {
+		ctx.Data.Value = big.NewInt(10)
+		ctx.Data.DestinationValue = big.NewInt(dests[i])
+		ctx.Data.DestinationId = common.Big0
+	}
+	return ctxs
+}
+
+func newTakerOrder(account common.Address, maxPrice string, budget int64) *TakerOrder {
+	price, _ := new(big.Rat).SetString(maxPrice)
+	return &TakerOrder{Account: account, MaxPrice: price, Budget: big.NewInt(budget), Spent: new(big.Int)}
+}
+
+func TestOrderBook_Match(t *testing.T) {
+	ctxs := newTakerCtxs(30, 5, 15, 10, 12)
+	ctxs[4].Data.To = testOther // only testOther could take it
+
+	book := new(orderBook)
+	book.reset(ctxs)
+	assert.Equal(t, []*cc.CrossTransactionWithSignatures{ctxs[1], ctxs[3], ctxs[4], ctxs[2], ctxs[0]}, book.top(0))
+	assert.Equal(t, 2, len(book.top(2)))
+
+	var (
+		cheap = newTakerOrder(testTaker, "1", 16)    // takes 5 and 10, then 15 is out of budget
+		other = newTakerOrder(testOther, "3/2", 100) // takes the rest under 1.5
+	)
+	matches := book.match([]*TakerOrder{cheap, other}, map[common.Hash]*takerSubmission{ctxs[2].ID(): {}})
+	assert.Equal(t, []takerMatch{{cheap, ctxs[1]}, {cheap, ctxs[3]}, {other, ctxs[4]}}, matches)
+	assert.EqualValues(t, 15, cheap.Spent.Int64())
+	assert.EqualValues(t, 12, other.Spent.Int64())
+
+	// makers never take their own ctxs, and tokens must be the same
+	ctxs[1].Data.Tokens = []common.Address{{}, common.HexToAddress("0x70")}
+	assert.False(t, newTakerOrder(testTaker, "1", 100).accepts(ctxs[1]))
+	assert.False(t, newTakerOrder(ctxs[3].Data.From, "1", 100).accepts(ctxs[3]))
+	assert.True(t, newTakerOrder(testTaker, "1", 100).accepts(ctxs[3]))
+
+	// the message ctxs are left to anchors
+	input, err := (&cc.Message{Target: testOther, GasLimit: 21000}).Input()
+	assert.NoError(t, err)
+	ctxs[3].Data.Input = input
+	assert.False(t, newTakerOrder(testTaker, "1", 100).accepts(ctxs[3]))
+}
+
+// testTakerExecutor sends the taker transactions except the ctxs in fails, the hash of
+// transaction is the ID of ctx
+type testTakerExecutor struct {
+	fails    map[common.Hash]bool
+	sent     map[common.Address][]common.Hash
+	receipts map[common.Hash]*types.Receipt
+}
+
+func (e *testTakerExecutor) TakerReceipt(hash common.Hash) (*types.Receipt, error) {
+	return e.receipts[hash], nil
+}
+
+func (e *testTakerExecutor) SubmitTaker(taker common.Address, signer trigger.Signer, cwss []*cc.CrossTransactionWithSignatures) ([]common.Hash, error) {
+	hashes := make([]common.Hash, len(cwss))
+	for i, cws := range cwss {
+		if !e.fails[cws.ID()] {
+			hashes[i] = cws.ID()
+			e.sent[taker] = append(e.sent[taker], cws.ID())
+		}
+	}
+	return hashes, nil
+}
+
+func TestAutoTaker_Round(t *testing.T) {
+	handler, err := newHandlerTester(common.Big0)
+	assert.NoError(t, err)
+	defer handler.store.Close()
+	handler.store.RegisterChain(testRemoteID).Clean() // the statuses may be changed by the other tests
+	handler.retriever, handler.log = testChainRetriever{}, log.New()
+
+	ctxs := newTakerCtxs(5, 10, 20)
+	ctxs[1].Data.DestinationId = big.NewInt(7) // belongs to other chain pair
+	assert.NoError(t, handler.store.Adds(testRemoteID, ctxs, false))
+
+	executor := &testTakerExecutor{fails: map[common.Hash]bool{ctxs[2].ID(): true}, sent: make(map[common.Address][]common.Hash)}
+	taker, err := newAutoTaker(handler, executor, map[common.Address]trigger.Signer{testTaker: nil}, nil)
+	assert.NoError(t, err)
+
+	_, err = taker.AddOrder(newTakerOrder(testOther, "1", 100))
+	assert.Equal(t, errUnknownTaker, err)
+	_, err = taker.AddOrder(newTakerOrder(testTaker, "0", 100))
+	assert.Equal(t, errInvalidOrder, err)
+	id, err := taker.AddOrder(newTakerOrder(testTaker, "2", 100))
+	assert.NoError(t, err)
+
+	// the failed ctx is refunded and matched again in the next round
+	now := time.Now()
+	taker.round(now)
+	assert.Equal(t, []common.Hash{ctxs[0].ID()}, executor.sent[testTaker])
+	assert.EqualValues(t, 5, taker.Orders()[0].Spent.Int64())
+	assert.Equal(t, 2, len(taker.Book(0)))
+
+	delete(executor.fails, ctxs[2].ID())
+	taker.round(now)
+	assert.Equal(t, []common.Hash{ctxs[0].ID(), ctxs[2].ID()}, executor.sent[testTaker])
+	assert.EqualValues(t, 25, taker.Orders()[0].Spent.Int64())
+
+	// the ctxs still waiting after timeout are taken again
+	taker.round(now.Add(takerTimeout))
+	assert.Equal(t, 4, len(executor.sent[testTaker]))
+	assert.EqualValues(t, 25, taker.Orders()[0].Spent.Int64())
+
+	assert.NoError(t, taker.CancelOrder(id))
+	assert.Equal(t, errUnknownOrder, taker.CancelOrder(id))
+	assert.Equal(t, 0, len(taker.Orders()))
+}
+
+func TestAutoTaker_Expire(t *testing.T) {
+	handler, err := newHandlerTester(common.Big0)
+	assert.NoError(t, err)
+	defer handler.store.Close()
+	handler.store.RegisterChain(testRemoteID).Clean() // the statuses are changed by the test
+	handler.retriever, handler.log = testChainRetriever{}, log.New()
+
+	ctxs := newTakerCtxs(5, 10, 20)
+	assert.NoError(t, handler.store.Adds(testRemoteID, ctxs, false))
+
+	var (
+		db       = cdb.NewTakerStore(memorydb.New())
+		executor = &testTakerExecutor{sent: make(map[common.Address][]common.Hash), receipts: make(map[common.Hash]*types.Receipt)}
+		signers  = map[common.Address]trigger.Signer{testTaker: nil}
+	)
+	taker, err := newAutoTaker(handler, executor, signers, db)
+	assert.NoError(t, err)
+	_, err = taker.AddOrder(newTakerOrder(testTaker, "2", 100))
+	assert.NoError(t, err)
+	canceled, err := taker.AddOrder(newTakerOrder(testTaker, "1", 1))
+	assert.NoError(t, err)
+	assert.NoError(t, taker.CancelOrder(canceled))
+	now := time.Now()
+	taker.round(now)
+	assert.EqualValues(t, 35, taker.Orders()[0].Spent.Int64())
+
+	// the orders and the ctxs being taken are restored after restart
+	taker, err = newAutoTaker(handler, executor, signers, db)
+	assert.NoError(t, err)
+	if orders := taker.Orders(); assert.Len(t, orders, 1) {
+		assert.EqualValues(t, 35, orders[0].Spent.Int64())
+		assert.Equal(t, big.NewRat(2, 1), orders[0].MaxPrice)
+	}
+	assert.Len(t, taker.taking, 3)
+	assert.EqualValues(t, canceled+1, taker.nextID)
+
+	// all ctxs are not waiting any more, but only the one taken by our transaction is charged,
+	// the failed one and the one taken by others are refunded
+	assert.NoError(t, handler.store.Updates(testRemoteID, []*cc.CrossTransactionModifier{
+		{ID: ctxs[0].ID(), Type: cc.Remote, Status: cc.CtxStatusExecuting},
+		{ID: ctxs[1].ID(), Type: cc.Remote, Status: cc.CtxStatusExecuting},
+		{ID: ctxs[2].ID(), Type: cc.Remote, Status: cc.CtxStatusExecuting},
+	}))
+	executor.receipts[ctxs[0].ID()] = &types.Receipt{Status: types.ReceiptStatusSuccessful}
+	executor.receipts[ctxs[1].ID()] = &types.Receipt{Status: types.ReceiptStatusFailed}
+	taker.round(now.Add(takerTimeout))
+	assert.EqualValues(t, 5, taker.Orders()[0].Spent.Int64())
+	assert.Empty(t, taker.taking)
+
+	taker, err = newAutoTaker(handler, executor, signers, db)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 5, taker.Orders()[0].Spent.Int64())
+	assert.Empty(t, taker.taking)
+}
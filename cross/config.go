@@ -1,26 +1,89 @@
 package cross
 
 import (
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
 	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/common/hexutil"
 	"gbchain-org/go-gbchain/cross/backend/synchronise"
+	cc "gbchain-org/go-gbchain/cross/core"
+	"gbchain-org/go-gbchain/crypto/bls"
 )
 
 const (
-	LogDir   = "crosslog"
-	TxLogDir = "crosstxlog"
-	DataDir  = "crossdata"
+	LogDir     = "crosslog"
+	TxLogDir   = "crosstxlog"
+	DataDir    = "crossdata"
+	IndexDir   = "crossindex"
+	ArchiveDir = "crossarchive"
+)
+
+// the databases of cross store
+const (
+	StoreStorm = "storm" // storm (boltdb) file at DataDir
+	StoreKV    = "kv"    // key-value database at IndexDir
 )
 
 type Config struct {
 	MainContract common.Address       `json:"mainContract"`
 	SubContract  common.Address       `json:"subContract"`
 	Signer       common.Address       `json:"signer"`
+	SignerURL    string               `json:"signerURL"` // clef-compatible endpoint holding the key of Signer, the keystore of node is used if empty
 	Anchors      []common.Address     `json:"anchors"`
 	SyncMode     synchronise.SyncMode `json:"syncMode"`
+	Remotes      []RemoteChain        `json:"remotes"`      // chains bridged to main chain by rpc
+	ReceiptProof bool                 `json:"receiptProof"` // verify maker logs by receipt proofs besides anchor signatures
+	Message      bool                 `json:"message"`      // execute the message ctxs signed completely by takerMessage
+	Takers       []common.Address     `json:"takers"`       // keystore accounts taking remote ctxs by standing orders, no auto taker if empty
+	BLS          *BLSConfig           `json:"bls"`          // anchors sign ctxs by aggregatable BLS signatures if it is set
+	Store        string               `json:"store"`        // database of cross store, "storm" or "kv"
+	BumpInterval time.Duration        `json:"bumpInterval"` // executor replaces the transactions not confirmed in the interval
+	BumpPercent  uint64               `json:"bumpPercent"`  // percentage of gas price increased on replacement
+	Expire       uint64               `json:"expire"`       // blocks after which waiting ctxs expire and could be cancelled by makers, 0 if never expired
+
+	Confirmations []ConfirmPolicy `json:"confirmations"` // confirmation policies of chains, DefaultConfirmDepth blocks if not set
+	ArchiveAge    uint64          `json:"archiveAge"`    // blocks after which finished ctxs are archived instead of removed, 0 disables archive
+}
+
+// the types of confirmation policy
+const (
+	ConfirmDepth    = "depth"    // events are confirmed by the blocks on top of their block
+	ConfirmFinality = "finality" // events are confirmed when their block is final by consensus engine
+	ConfirmTime     = "time"     // events are confirmed when their block is old enough
+)
+
+// ConfirmPolicy decides when the maker, taker and finish events of a chain are confirmed
+type ConfirmPolicy struct {
+	ChainID uint64        `json:"chainID"`
+	Type    string        `json:"type"`   // "depth", "finality" or "time"
+	Depth   uint64        `json:"depth"`  // blocks on top of event block for "depth", DefaultConfirmDepth if zero
+	Period  time.Duration `json:"period"` // time since event block for "time"
+}
+
+// BLSConfig is the BLS keys of anchors, all anchors of the chain pairs must use BLS signatures
+type BLSConfig struct {
+	KeyFile string                           `json:"keyFile"` // file of hex encoded BLS secret key of local anchor
+	Anchors map[common.Address]hexutil.Bytes `json:"anchors"` // BLS public keys (G1 || G2) of anchors
+
+	once sync.Once
+	keys map[common.Address]*bls.PublicKey
+	err  error
+}
+
+// RemoteChain is a chain run by a separate node, the anchor reaches it by websocket or ipc rpc
+type RemoteChain struct {
+	URL      string         `json:"url"`
+	Contract common.Address `json:"contract"`
 }
 
 var DefaultConfig = Config{
 	SyncMode: synchronise.ALL,
+	Store:    StoreStorm,
 }
 
 func (config *Config) Sanitize() Config {
@@ -28,6 +91,19 @@ func (config *Config) Sanitize() Config {
 		MainContract: config.MainContract,
 		SubContract:  config.SubContract,
 		Signer:       config.Signer,
+		SignerURL:    config.SignerURL,
+		Remotes:      config.Remotes,
+		ReceiptProof: config.ReceiptProof,
+		Message:      config.Message,
+		Takers:       config.Takers,
+		BLS:          config.BLS,
+		Store:        config.Store,
+		BumpInterval: config.BumpInterval,
+		BumpPercent:  config.BumpPercent,
+		Expire:       config.Expire,
+
+		Confirmations: config.Confirmations,
+		ArchiveAge:    config.ArchiveAge,
 	}
 	set := make(map[common.Address]struct{})
 	for _, anchor := range config.Anchors {
@@ -38,3 +114,62 @@ func (config *Config) Sanitize() Config {
 	}
 	return cfg
 }
+
+// ConfirmPolicy returns the confirmation policy of chain, nil if it is not configured
+func (config *Config) ConfirmPolicy(chainID *big.Int) *ConfirmPolicy {
+	for i := range config.Confirmations {
+		if policy := &config.Confirmations[i]; chainID != nil && policy.ChainID == chainID.Uint64() {
+			return policy
+		}
+	}
+	return nil
+}
+
+// ExpireNumber returns the blocks after which waiting ctxs expire, -1 if they never expire
+func (config *Config) ExpireNumber() int {
+	if config.Expire == 0 {
+		return -1
+	}
+	return int(config.Expire)
+}
+
+// CtxSigner returns the signer of ctxs signed by anchors, it fails if BLS is set with invalid public keys
+func (config *Config) CtxSigner(chainID *big.Int) (cc.CtxSigner, error) {
+	if config.BLS != nil {
+		keys, err := config.BLS.PublicKeys()
+		if err != nil {
+			return nil, fmt.Errorf("invalid bls public keys: %w", err)
+		}
+		return cc.NewBLSCtxSigner(chainID, keys), nil
+	}
+	return cc.MakeCtxSigner(chainID), nil
+}
+
+// PublicKeys decodes the public keys of anchors, keys are decoded only once
+func (c *BLSConfig) PublicKeys() (map[common.Address]*bls.PublicKey, error) {
+	c.once.Do(func() {
+		c.keys = make(map[common.Address]*bls.PublicKey, len(c.Anchors))
+		for anchor, b := range c.Anchors {
+			pk, err := bls.PublicKeyFromBytes(b)
+			if err != nil {
+				c.err = fmt.Errorf("anchor %s: %w", anchor.String(), err)
+				return
+			}
+			c.keys[anchor] = pk
+		}
+	})
+	return c.keys, c.err
+}
+
+// SecretKey loads the secret key of local anchor from KeyFile
+func (c *BLSConfig) SecretKey() (*bls.SecretKey, error) {
+	data, err := ioutil.ReadFile(c.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	b, err := hexutil.Decode(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, err
+	}
+	return bls.SecretKeyFromBytes(b)
+}
@@ -1,5 +1,9 @@
-pragma solidity ^0.6.0;
-pragma experimental ABIEncoderV2;
+pragma solidity ^0.8.0;
+interface IERC20 {
+    function transfer(address to, uint value) external returns (bool);
+    function transferFrom(address from, address to, uint value) external returns (bool);
+}
+
 contract crossDemo{
     //合约管理员
     address public owner;
@@ -7,6 +11,12 @@ contract crossDemo{
     //其他链的信息
     mapping (uint => Chain) public crossChains;
 
+    //消息调用的代理合约，不持有任何资产，目标合约看到的msg.sender是代理合约
+    crossMessageProxy public messageProxy;
+
+    //托管在本合约的ERC20 token，不能作为消息的目标
+    mapping (address => bool) public escrowTokens;
+
     //仅做信息登记，关联chainId
     struct Chain{
         uint remoteChainId;
@@ -40,6 +50,7 @@ contract crossDemo{
         address payable from;
         address payable to;
         bytes32 takerHash;
+        address token; //ERC20 token of value, 0 for native coin
     }
 
     struct TakerInfo {
@@ -53,6 +64,14 @@ contract crossDemo{
     event MakerFinish(bytes32 indexed txId, address indexed to);
     //达成交易 taker
     event TakerTx(bytes32 indexed txId, address indexed to, uint remoteChainId, address from,uint value, uint destValue);
+    //撤销交易 maker在目标链撤销挂单
+    event TakerCancel(bytes32 indexed txId, address indexed from, uint remoteChainId);
+    //撤销完成 锚定节点退款给maker
+    event MakerCancel(bytes32 indexed txId, address indexed from);
+    //ERC20跨链交易 紧随MakerTx记录两条链的token，0为原生币
+    event MakerToken(bytes32 indexed txId, address indexed token, address destToken);
+    //消息执行 锚定节点在目标链调用目标合约
+    event MessageExecuted(bytes32 indexed txId, address indexed target, bool success, bytes result);
 
     event AddAnchors(uint remoteChainId);
 
@@ -73,8 +92,9 @@ contract crossDemo{
         _;
     }
 
-    constructor() public {
+    constructor() {
         owner = msg.sender;
+        messageProxy = new crossMessageProxy();
     }
 
     //更改跨链交易奖励 管理员操作
@@ -99,23 +119,13 @@ contract crossDemo{
     function chainRegister(uint remoteChainId,uint maxValue, uint8 signConfirmCount, address[] memory _anchors) public onlyOwner returns(bool) {
         require (crossChains[remoteChainId].remoteChainId == 0,"remoteChainId err");
         require (_anchors.length <= 64,"_anchors err");
-        uint64 temp = 0;
-        address[] memory newAnchors;
-        address[] memory delAnchors;
-
-        //初始化信息
-        crossChains[remoteChainId] = Chain({
-            remoteChainId: remoteChainId,
-            maxValue: maxValue,
-            signConfirmCount: signConfirmCount,
-            anchorsPositionBit: (temp - 1) >> (64 - _anchors.length),
-            anchorAddress:newAnchors,
-            reward:0,
-            totalReward:0,
-            delsPositionBit: (temp - 1) >> 64,
-            delsAddress:delAnchors,
-            delId:0
-            });
+
+        //初始化信息，Chain包含mapping不能整体赋值
+        Chain storage chain = crossChains[remoteChainId];
+        chain.remoteChainId = remoteChainId;
+        chain.maxValue = maxValue;
+        chain.signConfirmCount = signConfirmCount;
+        chain.anchorsPositionBit = type(uint64).max >> (64 - _anchors.length);
 
         //加入锚定矿工
         for (uint8 i=0; i<_anchors.length; i++) {
@@ -134,8 +144,7 @@ contract crossDemo{
         require (crossChains[remoteChainId].remoteChainId > 0,"remoteChainId err");
         require (_anchors.length > 0 && _anchors.length < 64,"need _anchors");
         require ((crossChains[remoteChainId].anchorAddress.length + _anchors.length) <= 64,"_anchors err");
-        uint64 temp = 0;
-        crossChains[remoteChainId].anchorsPositionBit = (temp - 1) >> (64 - crossChains[remoteChainId].anchorAddress.length - _anchors.length);
+        crossChains[remoteChainId].anchorsPositionBit = type(uint64).max >> (64 - crossChains[remoteChainId].anchorAddress.length - _anchors.length);
         //加入锚定矿工
         for (uint8 i=0; i<_anchors.length; i++) {
             if (crossChains[remoteChainId].anchors[_anchors[i]].remoteChainId != 0) {
@@ -157,8 +166,7 @@ contract crossDemo{
         require (crossChains[remoteChainId].remoteChainId > 0,"remoteChainId err");
         require (_anchors.length > 0,"need _anchors");
         require((crossChains[remoteChainId].anchorAddress.length - crossChains[remoteChainId].signConfirmCount) >= _anchors.length,"_anchors err");
-        uint64 temp = 0;
-        crossChains[remoteChainId].anchorsPositionBit = (temp - 1) >> (64 - crossChains[remoteChainId].anchorAddress.length + _anchors.length);
+        crossChains[remoteChainId].anchorsPositionBit = type(uint64).max >> (64 - crossChains[remoteChainId].anchorAddress.length + _anchors.length);
         for (uint8 i=0; i<_anchors.length; i++) {
             if (crossChains[remoteChainId].anchors[_anchors[i]].remoteChainId == 0) {
                 revert();
@@ -185,8 +193,7 @@ contract crossDemo{
             revert();
         }
         if(crossChains[remoteChainId].delsAddress.length < 64){
-            uint64 temp = 0;
-            crossChains[remoteChainId].delsPositionBit = (temp - 1) >> (64 - crossChains[remoteChainId].delsAddress.length - 1);
+            crossChains[remoteChainId].delsPositionBit = type(uint64).max >> (64 - crossChains[remoteChainId].delsAddress.length - 1);
             crossChains[remoteChainId].delAnchors[del] = Anchor({remoteChainId:remoteChainId, position:uint8(crossChains[remoteChainId].delsAddress.length),status:false,signCount:0,finishCount:0});
             crossChains[remoteChainId].delsAddress.push(del);
 
@@ -275,19 +282,54 @@ contract crossDemo{
         require(crossChains[remoteChainId].remoteChainId > 0,"chainId err"); //是否支持的跨链
         bytes32 txId = keccak256(abi.encodePacked(msg.sender, list(), remoteChainId));
         assert(crossChains[remoteChainId].makerTxs[txId].value == 0);
-        crossChains[remoteChainId].makerTxs[txId] = MakerInfo({
-            value:(msg.value - crossChains[remoteChainId].reward),
-            signatureCount:0,
-            to:focus,
-            from:msg.sender,
-            takerHash:bytes32(0x0)
-            });
+        newMaker(remoteChainId, txId, msg.value - crossChains[remoteChainId].reward, focus, address(0x0));
         uint total = crossChains[remoteChainId].totalReward + crossChains[remoteChainId].reward;
         assert(total >= crossChains[remoteChainId].totalReward);
         crossChains[remoteChainId].totalReward = total;
         emit MakerTx(txId, msg.sender, focus, remoteChainId, msg.value, destValue, data);
     }
 
+    //增加ERC20跨链交易，token为0时以原生币挂单，手续费reward以原生币支付
+    function makerTokenStart(uint remoteChainId, address token, uint value, address destToken, uint destValue, address payable focus, bytes memory data) public payable {
+        require(crossChains[remoteChainId].remoteChainId > 0,"chainId err"); //是否支持的跨链
+        require(token != address(0x0) || destToken != address(0x0),"token err");
+        require(value > 0,"value err");
+        if (token == address(0x0)) {
+            require(msg.value == value + crossChains[remoteChainId].reward && msg.value < crossChains[remoteChainId].maxValue,"value err");
+        } else {
+            require(msg.value == crossChains[remoteChainId].reward,"reward err");
+            escrowTokens[token] = true;
+            require(IERC20(token).transferFrom(msg.sender, address(this), value),"token transfer err");
+        }
+        bytes32 txId = keccak256(abi.encodePacked(msg.sender, list(), remoteChainId));
+        assert(crossChains[remoteChainId].makerTxs[txId].value == 0);
+        newMaker(remoteChainId, txId, value, focus, token);
+        uint total = crossChains[remoteChainId].totalReward + crossChains[remoteChainId].reward;
+        assert(total >= crossChains[remoteChainId].totalReward);
+        crossChains[remoteChainId].totalReward = total;
+        emit MakerTx(txId, msg.sender, focus, remoteChainId, token == address(0x0) ? msg.value : value, destValue, data);
+        emit MakerToken(txId, token, destToken);
+    }
+
+    //MakerInfo包含mapping不能整体赋值，逐个字段写入
+    function newMaker(uint remoteChainId, bytes32 txId, uint value, address payable focus, address token) private {
+        MakerInfo storage maker = crossChains[remoteChainId].makerTxs[txId];
+        maker.value = value;
+        maker.signatureCount = 0;
+        maker.from = payable(msg.sender);
+        maker.to = focus;
+        maker.takerHash = bytes32(0x0);
+        maker.token = token;
+    }
+
+    function payOut(address token, address payable to, uint value) private {
+        if (token == address(0x0)) {
+            to.transfer(value);
+        } else {
+            require(IERC20(token).transfer(to, value),"token transfer err");
+        }
+    }
+
     struct Recept {
         bytes32 txId;
         bytes32 txHash;
@@ -309,12 +351,32 @@ contract crossDemo{
         crossChains[remoteChainId].anchors[msg.sender].finishCount ++;
 
         if (crossChains[remoteChainId].makerTxs[rtx.txId].signatureCount >= crossChains[remoteChainId].signConfirmCount){
-            rtx.to.transfer(crossChains[remoteChainId].makerTxs[rtx.txId].value);
+            payOut(crossChains[remoteChainId].makerTxs[rtx.txId].token, rtx.to, crossChains[remoteChainId].makerTxs[rtx.txId].value);
             delete crossChains[remoteChainId].makerTxs[rtx.txId];
             emit MakerFinish(rtx.txId,rtx.to);
         }
     }
 
+    //maker在目标链撤销挂单后，锚定节点共同签署退款,防作恶
+    function makerCancel(Recept memory rtx,uint remoteChainId) public onlyAnchor(remoteChainId) payable {
+        require(crossChains[remoteChainId].anchors[msg.sender].status);
+        require(crossChains[remoteChainId].makerTxs[rtx.txId].signatures[msg.sender] != 1);
+        require(crossChains[remoteChainId].makerTxs[rtx.txId].value > 0);
+        require(crossChains[remoteChainId].makerTxs[rtx.txId].from == rtx.from,"from err");
+        require(rtx.to == rtx.from,"to err");
+        require(crossChains[remoteChainId].makerTxs[rtx.txId].takerHash == bytes32(0x0) || crossChains[remoteChainId].makerTxs[rtx.txId].takerHash == rtx.txHash,"txHash err");
+        crossChains[remoteChainId].makerTxs[rtx.txId].signatures[msg.sender] = 1;
+        crossChains[remoteChainId].makerTxs[rtx.txId].signatureCount ++;
+        crossChains[remoteChainId].makerTxs[rtx.txId].takerHash = rtx.txHash;
+        crossChains[remoteChainId].anchors[msg.sender].finishCount ++;
+
+        if (crossChains[remoteChainId].makerTxs[rtx.txId].signatureCount >= crossChains[remoteChainId].signConfirmCount){
+            payOut(crossChains[remoteChainId].makerTxs[rtx.txId].token, rtx.from, crossChains[remoteChainId].makerTxs[rtx.txId].value);
+            delete crossChains[remoteChainId].makerTxs[rtx.txId];
+            emit MakerCancel(rtx.txId,rtx.from);
+        }
+    }
+
     function verifySignAndCount(bytes32 hash, uint remoteChainId, uint[] memory v, bytes32[] memory r, bytes32[] memory s) private returns (uint8) {
         uint64 ret = 0;
         uint64 base = 1;
@@ -370,9 +432,19 @@ contract crossDemo{
         bytes32[] s;
     }
 
+    //消息模式的跨链交易只能由takerMessage接单，否则任何人接单后消息不再执行
+    function isMessage(bytes memory data) private pure returns (bool) {
+        bytes32 word;
+        assembly {
+            word := mload(add(data, 32))
+        }
+        return data.length >= 32 && bytes4(word) == bytes4("xmsg");
+    }
+
     function taker(Order memory ctx,uint remoteChainId) payable public{
         require(ctx.v.length == ctx.r.length,"vrs err");
         require(ctx.v.length == ctx.s.length,"vrs err");
+        require(!isMessage(ctx.data),"message err");
         require(ctx.to == address(0x0) || ctx.to == msg.sender || ctx.from == msg.sender,"to err");
         require(crossChains[remoteChainId].takerTxs[ctx.txId].value == 0 || crossChains[remoteChainId].takerTxs[ctx.txId].from != ctx.from,"txId err");
         if(msg.sender == ctx.from){
@@ -388,15 +460,311 @@ contract crossDemo{
         emit TakerTx(ctx.txId,msg.sender,remoteChainId,ctx.from,ctx.value,ctx.destinationValue);
     }
 
-    function chainId() public pure returns (uint id) {
+    //maker撤销未被接单的挂单，撤销后不能再被接单，锚定节点确认后在源链退款
+    function takerCancel(Order memory ctx,uint remoteChainId) public{
+        require(ctx.v.length == ctx.r.length,"vrs err");
+        require(ctx.v.length == ctx.s.length,"vrs err");
+        require(msg.sender == ctx.from,"from err");
+        require(crossChains[remoteChainId].takerTxs[ctx.txId].value == 0,"txId err");
+        require(verifyOwnerSignAndCount(keccak256(abi.encodePacked(ctx.value, ctx.txId, ctx.txHash, ctx.from, ctx.blockHash, chainId(), ctx.destinationValue,ctx.data)), remoteChainId,ctx.v,ctx.r,ctx.s) >= crossChains[remoteChainId].signConfirmCount,"sign error");
+        crossChains[remoteChainId].takerTxs[ctx.txId] = TakerInfo({value:ctx.value,from:ctx.from});
+        emit TakerCancel(ctx.txId,ctx.from,remoteChainId);
+    }
+
+    //消息模式的跨链交易，data = abi.encode(bytes4("xmsg"), target, gasLimit, payload)
+    //锚定节点验证签名后由messageProxy以payload调用target，执行结果记录在MessageExecuted日志中，调用失败不会回滚接单
+    //target不能是本合约、代理合约或托管的token，否则消息可以转走托管的资产
+    function takerMessage(Order memory ctx,uint remoteChainId) public onlyAnchor(remoteChainId) {
+        require(crossChains[remoteChainId].anchors[msg.sender].status);
+        require(ctx.v.length == ctx.r.length,"vrs err");
+        require(ctx.v.length == ctx.s.length,"vrs err");
+        require(crossChains[remoteChainId].takerTxs[ctx.txId].value == 0,"txId err");
+        require(verifySignAndCount(keccak256(abi.encodePacked(ctx.value, ctx.txId, ctx.txHash, ctx.from, ctx.blockHash, chainId(), ctx.destinationValue,ctx.data)), remoteChainId,ctx.v,ctx.r,ctx.s) >= crossChains[remoteChainId].signConfirmCount,"sign error");
+        (bytes4 magic, address target, uint gasLimit, bytes memory payload) = abi.decode(ctx.data, (bytes4, address, uint, bytes));
+        require(magic == bytes4("xmsg"),"message err");
+        require(target != address(this) && target != address(messageProxy) && !escrowTokens[target],"target err");
+        //经过代理合约的两次调用各保留1/64的gas，保证target得到gasLimit
+        require(gasleft() > gasLimit + gasLimit / 32 + 20000,"gas err");
+        crossChains[remoteChainId].takerTxs[ctx.txId] = TakerInfo({value:ctx.value,from:ctx.from});
+        (bool success, bytes memory result) = messageProxy.execute(target, gasLimit, payload);
+        emit MessageExecuted(ctx.txId,target,success,result);
+        //maker的value在源链支付给执行的锚定节点，ctx.to不在签名中，不能由调用者指定收款人
+        emit TakerTx(ctx.txId,msg.sender,remoteChainId,ctx.from,ctx.value,ctx.destinationValue);
+    }
+
+    struct TokenOrder {
+        uint value;
+        bytes32 txId;
+        bytes32 txHash;
+        address payable from;
+        address to;
+        bytes32 blockHash;
+        uint destinationValue;
+        address token;
+        address destinationToken;
+        bytes data;
+        uint[] v;
+        bytes32[] r;
+        bytes32[] s;
+    }
+
+    //ERC20跨链交易的签名哈希以TOKEN_CTX_DOMAIN开头，与原生币跨链交易的哈希区分
+    bytes32 constant TOKEN_CTX_DOMAIN = keccak256("gbchain.ctx.token");
+
+    function tokenHash(TokenOrder memory ctx) private view returns (bytes32) {
+        return keccak256(abi.encodePacked(TOKEN_CTX_DOMAIN, ctx.value, ctx.txId, ctx.txHash, ctx.from, ctx.blockHash, chainId(), ctx.destinationValue, ctx.token, ctx.destinationToken, ctx.data));
+    }
+
+    //ERC20跨链交易接单，destinationToken为0时以原生币支付
+    function takerToken(TokenOrder memory ctx,uint remoteChainId) payable public{
+        require(ctx.v.length == ctx.r.length,"vrs err");
+        require(ctx.v.length == ctx.s.length,"vrs err");
+        require(!isMessage(ctx.data),"message err");
+        require(ctx.to == address(0x0) || ctx.to == msg.sender || ctx.from == msg.sender,"to err");
+        require(crossChains[remoteChainId].takerTxs[ctx.txId].value == 0 || crossChains[remoteChainId].takerTxs[ctx.txId].from != ctx.from,"txId err");
+        if(msg.sender == ctx.from){
+            require(verifyOwnerSignAndCount(tokenHash(ctx), remoteChainId,ctx.v,ctx.r,ctx.s) >= crossChains[remoteChainId].signConfirmCount,"sign error");
+        } else {
+            require(verifySignAndCount(tokenHash(ctx), remoteChainId,ctx.v,ctx.r,ctx.s) >= crossChains[remoteChainId].signConfirmCount,"sign error");
+            if (ctx.destinationToken == address(0x0)) {
+                require(msg.value >= ctx.destinationValue,"price err");
+            } else {
+                require(IERC20(ctx.destinationToken).transferFrom(msg.sender, ctx.from, ctx.destinationValue),"token transfer err");
+            }
+        }
+        crossChains[remoteChainId].takerTxs[ctx.txId] = TakerInfo({value:ctx.value,from:ctx.from});
+        ctx.from.transfer(msg.value);
+        emit TakerTx(ctx.txId,msg.sender,remoteChainId,ctx.from,ctx.value,ctx.destinationValue);
+    }
+
+    //maker撤销未被接单的ERC20挂单
+    function takerTokenCancel(TokenOrder memory ctx,uint remoteChainId) public{
+        require(ctx.v.length == ctx.r.length,"vrs err");
+        require(ctx.v.length == ctx.s.length,"vrs err");
+        require(msg.sender == ctx.from,"from err");
+        require(crossChains[remoteChainId].takerTxs[ctx.txId].value == 0,"txId err");
+        require(verifyOwnerSignAndCount(tokenHash(ctx), remoteChainId,ctx.v,ctx.r,ctx.s) >= crossChains[remoteChainId].signConfirmCount,"sign error");
+        crossChains[remoteChainId].takerTxs[ctx.txId] = TakerInfo({value:ctx.value,from:ctx.from});
+        emit TakerCancel(ctx.txId,ctx.from,remoteChainId);
+    }
+
+    //BLS聚合签名，anchor的G1公钥由管理员登记，G2聚合公钥由调用者提供并在配对检查中验证
+    mapping (uint => mapping(address => uint[2])) public blsKeys;
+
+    uint constant FIELD_MODULUS = 21888242871839275222246405745257275088696311157297823662689037894645226208583;
+    uint constant CURVE_ORDER = 21888242871839275222246405745257275088548364400416034343698204186575808495617;
+
+    function setAnchorBLSKey(uint remoteChainId, address _anchor, uint[2] memory pk) public onlyOwner {
+        require(crossChains[remoteChainId].anchors[_anchor].remoteChainId == remoteChainId,"not anchors");
+        blsKeys[remoteChainId][_anchor] = pk;
+    }
+
+    struct BLSOrder {
+        uint value;
+        bytes32 txId;
+        bytes32 txHash;
+        address payable from;
+        address to;
+        bytes32 blockHash;
+        uint destinationValue;
+        bytes data;
+        address[] signers;
+        uint[2] sig; //G1 聚合签名
+        uint[4] apk; //G2 signers的聚合公钥
+    }
+
+    function takerBLS(BLSOrder memory ctx,uint remoteChainId) payable public{
+        require(!isMessage(ctx.data),"message err");
+        require(ctx.to == address(0x0) || ctx.to == msg.sender || ctx.from == msg.sender,"to err");
+        require(crossChains[remoteChainId].takerTxs[ctx.txId].value == 0 || crossChains[remoteChainId].takerTxs[ctx.txId].from != ctx.from,"txId err");
+        if(msg.sender != ctx.from){
+            require(msg.value >= ctx.destinationValue,"price err");
+        }
+        bytes32 hash = keccak256(abi.encodePacked(ctx.value, ctx.txId, ctx.txHash, ctx.from, ctx.blockHash, chainId(), ctx.destinationValue,ctx.data));
+        require(verifyAggregatedSignAndCount(hash, remoteChainId, ctx.signers, ctx.sig, ctx.apk) >= crossChains[remoteChainId].signConfirmCount,"sign error");
+        crossChains[remoteChainId].takerTxs[ctx.txId] = TakerInfo({value:ctx.value,from:ctx.from});
+        ctx.from.transfer(msg.value);
+        emit TakerTx(ctx.txId,msg.sender,remoteChainId,ctx.from,ctx.value,ctx.destinationValue);
+    }
+
+    // e(sig + r*apk1, g2) == e(H(hash) + r*g1, apk2), r = keccak256(sig, apk1, apk2, hash)
+    function verifyAggregatedSignAndCount(bytes32 hash, uint remoteChainId, address[] memory signers, uint[2] memory sig, uint[4] memory apk) private returns (uint8) {
+        require(signers.length > 0,"no signers");
+        uint64 ret = 0;
+        uint64 base = 1;
+        uint[2] memory apk1;
+        for (uint i = 0; i < signers.length; i++){
+            Anchor storage anchor = crossChains[remoteChainId].anchors[signers[i]];
+            require(anchor.remoteChainId == remoteChainId && anchor.status,"not anchors");
+            require(ret & (base << anchor.position) == 0,"duplicate signer");
+            uint[2] memory pk = blsKeys[remoteChainId][signers[i]];
+            require(pk[0] != 0 || pk[1] != 0,"no bls key");
+            apk1 = i == 0 ? pk : ecAdd(apk1, pk);
+            anchor.signCount ++;
+            ret = ret | (base << anchor.position);
+        }
+        uint r = uint(keccak256(abi.encodePacked(sig, apk1, apk, hash))) % CURVE_ORDER;
+        uint[2] memory left = ecAdd(sig, ecMul(apk1, r));
+        uint[2] memory right = ecAdd(hashToG1(hash), ecMul([uint(1), uint(2)], r));
+        right[1] = right[1] == 0 ? 0 : FIELD_MODULUS - right[1]; // -right
+        uint[12] memory input = [
+            left[0], left[1],
+            11559732032986387107991004021392285783925812861821192530917403151452391805634,
+            10857046999023057135944570762232829481370756359578518086990519993285655852781,
+            4082367875863433681332203403145435568316851327593401208105741076214120093531,
+            8495653923123431417604973247489272438418190587263600148770280649306958101930,
+            right[0], right[1],
+            apk[0], apk[1], apk[2], apk[3]
+        ];
+        uint[1] memory out;
+        bool success;
+        assembly {
+            success := staticcall(gas(), 8, input, 384, out, 32)
+        }
+        require(success && out[0] == 1,"pairing err");
+        return uint8(bitCount(ret));
+    }
+
+    // try-and-increment, the same as crypto/bls.HashToG1
+    function hashToG1(bytes32 hash) private view returns (uint[2] memory p) {
+        uint x = uint(hash) % FIELD_MODULUS;
+        while (true) {
+            uint y2 = addmod(mulmod(mulmod(x, x, FIELD_MODULUS), x, FIELD_MODULUS), 3, FIELD_MODULUS);
+            uint y = expMod(y2, (FIELD_MODULUS + 1) / 4, FIELD_MODULUS);
+            if (mulmod(y, y, FIELD_MODULUS) == y2) {
+                return [x, y];
+            }
+            x = addmod(x, 1, FIELD_MODULUS);
+        }
+    }
+
+    function ecAdd(uint[2] memory a, uint[2] memory b) private view returns (uint[2] memory c) {
+        uint[4] memory input = [a[0], a[1], b[0], b[1]];
+        bool success;
+        assembly {
+            success := staticcall(gas(), 6, input, 128, c, 64)
+        }
+        require(success,"ecAdd err");
+    }
+
+    function ecMul(uint[2] memory a, uint s) private view returns (uint[2] memory c) {
+        uint[3] memory input = [a[0], a[1], s];
+        bool success;
+        assembly {
+            success := staticcall(gas(), 7, input, 96, c, 64)
+        }
+        require(success,"ecMul err");
+    }
+
+    function expMod(uint b, uint e, uint m) private view returns (uint r) {
+        uint[6] memory input = [uint(32), uint(32), uint(32), b, e, m];
+        uint[1] memory out;
+        bool success;
+        assembly {
+            success := staticcall(gas(), 5, input, 192, out, 32)
+        }
+        require(success,"expMod err");
+        return out[0];
+    }
+
+    function chainId() public view returns (uint id) {
         assembly {
             id := chainid()
         }
     }
 
-    function list() public pure returns (uint ll) {
+    //0x48在本链为NONCE，solc以evmVersion london编译时由basefee()生成
+    function list() public view returns (uint ll) {
         assembly {
-            ll := nonce()
+            ll := basefee()
         }
     }
-}
\ No newline at end of file
+}
+//消息调用的代理合约，由跨链合约创建，只接受跨链合约的调用
+//代理合约不持有资产，消息无法以跨链合约的身份调用token等合约
+contract crossMessageProxy {
+    address public cross;
+
+    constructor() {
+        cross = msg.sender;
+    }
+
+    function execute(address target, uint gasLimit, bytes memory payload) external returns (bool success, bytes memory result) {
+        require(msg.sender == cross,"not cross contract");
+        (success, result) = target.call{gas: gasLimit}(payload);
+    }
+}
+
+//消息模式的参考目标合约，只接受跨链合约的代理合约的调用
+contract crossMessageReceiver {
+    address public cross;
+    address public proxy;
+    bytes public lastMessage;
+    uint public count;
+
+    event Received(bytes message);
+
+    constructor(address _cross) {
+        cross = _cross;
+        proxy = address(crossDemo(_cross).messageProxy());
+    }
+
+    function receiveMessage(bytes memory message) public {
+        require(msg.sender == proxy,"not cross proxy");
+        lastMessage = message;
+        count ++;
+        emit Received(message);
+    }
+}
+
+//ERC20跨链交易的参考token合约，由owner在各条链铸造
+contract crossToken is IERC20 {
+    address public owner;
+    string public name;
+    string public symbol;
+    uint8 public decimals = 18;
+    uint public totalSupply;
+    mapping (address => uint) public balanceOf;
+    mapping (address => mapping (address => uint)) public allowance;
+
+    event Transfer(address indexed from, address indexed to, uint value);
+    event Approval(address indexed owner, address indexed spender, uint value);
+
+    constructor(string memory _name, string memory _symbol) {
+        owner = msg.sender;
+        name = _name;
+        symbol = _symbol;
+    }
+
+    function mint(address to, uint value) public {
+        require(msg.sender == owner,"not owner");
+        totalSupply += value;
+        balanceOf[to] += value;
+        emit Transfer(address(0x0), to, value);
+    }
+
+    function approve(address spender, uint value) public returns (bool) {
+        allowance[msg.sender][spender] = value;
+        emit Approval(msg.sender, spender, value);
+        return true;
+    }
+
+    function transfer(address to, uint value) public override returns (bool) {
+        _transfer(msg.sender, to, value);
+        return true;
+    }
+
+    function transferFrom(address from, address to, uint value) public override returns (bool) {
+        require(allowance[from][msg.sender] >= value,"allowance err");
+        allowance[from][msg.sender] -= value;
+        _transfer(from, to, value);
+        return true;
+    }
+
+    function _transfer(address from, address to, uint value) private {
+        require(balanceOf[from] >= value,"balance err");
+        balanceOf[from] -= value;
+        balanceOf[to] += value;
+        emit Transfer(from, to, value);
+    }
+}
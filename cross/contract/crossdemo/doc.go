@@ -0,0 +1,8 @@
+// Package crossdemo is the Go binding of the cross contract deployed on every chain of the cross pairs.
+//
+// crossDemo.abi and crossDemo.bin are compiled from crossdemo.sol by solc 0.8 with evmVersion
+// london and the optimizer of 200 runs, the opcode 0x48 emitted by basefee() is NONCE in the
+// chains. params.CrossDemoAbi is printed by readAbi.go after the abi is changed.
+package crossdemo
+
+//go:generate abigen --abi crossDemo.abi --bin crossDemo.bin --pkg crossdemo --type CrossDemo --out crossdemo.go
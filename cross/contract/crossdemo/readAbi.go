@@ -1,3 +1,6 @@
+// +build ignore
+
+// readAbi prints the hex encoding of crossDemo.abi, which is params.CrossDemoAbi.
 package main
 
 import (
@@ -0,0 +1,29 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// ChainPair identifies a direction of cross transactions bridged by an anchor,
+// ctxs are made on the Local chain and taken on the Remote chain.
+type ChainPair struct {
+	Local  uint64 `json:"local"`
+	Remote uint64 `json:"remote"`
+}
+
+func NewChainPair(local, remote *big.Int) ChainPair {
+	return ChainPair{Local: local.Uint64(), Remote: remote.Uint64()}
+}
+
+// Reverse returns the opposite direction of the pair
+func (p ChainPair) Reverse() ChainPair {
+	return ChainPair{Local: p.Remote, Remote: p.Local}
+}
+
+func (p ChainPair) LocalID() *big.Int  { return new(big.Int).SetUint64(p.Local) }
+func (p ChainPair) RemoteID() *big.Int { return new(big.Int).SetUint64(p.Remote) }
+
+func (p ChainPair) String() string {
+	return fmt.Sprintf("%d->%d", p.Local, p.Remote)
+}
@@ -22,6 +22,11 @@ type CtxStatus uint8
   |      |                       |saving|
   |      | <-mod-- confirmFinish |      |
   |      | (finished)            |      |
+  |      |                       |      |
+  |      |	 takerCancel --mod-> |      |
+  |      |          (cancelling) |      |
+  |      | <-mod-- makerCancel   |      |
+  |      | (cancelled)           |      |
   |------|                       |------|
 */
 const (
@@ -39,11 +44,15 @@ const (
 	CtxStatusFinishing
 	// CtxStatusFinished is the status code of a cross transaction if make finish confirmed.
 	CtxStatusFinished
+	// CtxStatusCancelling is the status code of a cross transaction if maker cancelled it in remote chain.
+	CtxStatusCancelling
+	// CtxStatusCancelled is the status code of a cross transaction if maker refund confirmed.
+	CtxStatusCancelled
 )
 
 /**
   * state synchronization (P=pending, W=waiting, IL=illegal,
-Eng=executing, Eed=executed, Fng=finishing, Fed=finished)
+Eng=executing, Eed=executed, Fng=finishing, Fed=finished, Cng=cancelling, Ced=cancelled)
   * h means height1(less), H means height2(higher), [S] means in store sync, [R] means in block reorg
   * --------------------------------------------------------------------------------------------------------------------
 	P -> W            W -> IL             W(IL) -> Eng         Eng -> Eed         	  Eed -> Fng             Fng -> Fed
@@ -51,17 +60,56 @@ Eng=executing, Eed=executed, Fng=finishing, Fed=finished)
 [S] P -> W(ok)    [S] W -> IL(ok)     [S] W -> Eng(ok)     [S] Eng -> Eed(ok)     [S] Eed -> Fng(ok)     [S] Fng -> Fed(ok)
     W -> P(cant)      IL -> W(cant)       Eng -> W(cant)       Eed -> Eng(cant)       Fng -> Eed(cant)       Fed -> Fng(cant)
                                       [R] Eng -> W(ok) 						      [R] Fng -> Eed(ok)
+  * --------------------------------------------------------------------------------------------------------------------
+	W(IL) -> Cng       Cng -> Ced
+	h -> h             h -> H
+[S] W -> Cng(ok)   [S] Cng -> Ced(ok)
+    Cng -> W(cant)     Ced -> Cng(cant)
+[R] Cng -> W(ok)   [R] Ced -> Cng(ok)
+  * --------------------------------------------------------------------------------------------------------------------
+	the taker flow (Eng, Eed, Fng, Fed) and the cancel flow (Cng, Ced) are exclusive, a status of one flow
+	is never updated or reorged to a status of the other flow, e.g. Fed -> Cng(cant), Ced -> Eed(cant)
   * --------------------------------------------------------------------------------------------------------------------
  **/
 
 var ctxStatusToString = map[CtxStatus]string{
-	CtxStatusPending:   "pending",
-	CtxStatusWaiting:   "waiting",
-	CtxStatusIllegal:   "illegal",
-	CtxStatusExecuting: "executing",
-	CtxStatusExecuted:  "executed",
-	CtxStatusFinishing: "finishing",
-	CtxStatusFinished:  "finished",
+	CtxStatusPending:    "pending",
+	CtxStatusWaiting:    "waiting",
+	CtxStatusIllegal:    "illegal",
+	CtxStatusExecuting:  "executing",
+	CtxStatusExecuted:   "executed",
+	CtxStatusFinishing:  "finishing",
+	CtxStatusFinished:   "finished",
+	CtxStatusCancelling: "cancelling",
+	CtxStatusCancelled:  "cancelled",
+}
+
+// ctxStatusRank is the order of statuses in their flow, cancelling and cancelled follow
+// waiting and illegal like executing and executed
+var ctxStatusRank = map[CtxStatus]int{
+	CtxStatusPending:    0,
+	CtxStatusWaiting:    1,
+	CtxStatusIllegal:    2,
+	CtxStatusExecuting:  3,
+	CtxStatusExecuted:   4,
+	CtxStatusFinishing:  5,
+	CtxStatusFinished:   6,
+	CtxStatusCancelling: 3,
+	CtxStatusCancelled:  4,
+}
+
+// IsCancel reports whether the status is in the cancel flow
+func (s CtxStatus) IsCancel() bool {
+	return s == CtxStatusCancelling || s == CtxStatusCancelled
+}
+
+// Before reports whether a ctx in status s is updated to t forward, or reorged from t back to s.
+// Statuses of the taker flow and the cancel flow are not ordered with each other.
+func (s CtxStatus) Before(t CtxStatus) bool {
+	if s.IsCancel() != t.IsCancel() && s > CtxStatusIllegal && t > CtxStatusIllegal {
+		return false
+	}
+	return ctxStatusRank[s] < ctxStatusRank[t]
 }
 
 func (s CtxStatus) String() string {
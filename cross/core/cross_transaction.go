@@ -16,6 +16,9 @@ import (
 
 type SignHash func(hash []byte) ([]byte, error)
 
+// SignData signs keccak256(data), signers could check the content of data before signing
+type SignData func(data []byte) ([]byte, error)
+
 type CtxID = common.Hash
 type CtxIDs []CtxID
 
@@ -57,6 +60,8 @@ type ctxdata struct {
 	V *big.Int `json:"v" gencodec:"required"` //chainId
 	R *big.Int `json:"r" gencodec:"required"`
 	S *big.Int `json:"s" gencodec:"required"`
+
+	Tokens []common.Address `json:"tokens,omitempty" rlp:"tail"` // [token, destinationToken] of ERC20 ctxs, empty for native coin
 }
 
 func NewCrossTransaction(amount, charge, networkId *big.Int, id, txHash, bHash common.Hash, from, to common.Address, input []byte) *CrossTransaction {
@@ -104,7 +109,7 @@ func (tx *CrossTransaction) Hash() (h common.Hash) {
 		return hash.(common.Hash)
 	}
 	hash := sha3.NewKeccak256()
-	var b []byte
+	b := tokensPrefix(tx.Data.Tokens)
 	b = append(b, common.LeftPadBytes(tx.Data.Value.Bytes(), 32)...)
 	b = append(b, tx.Data.CTxId.Bytes()...)
 	b = append(b, tx.Data.TxHash.Bytes()...)
@@ -133,7 +138,7 @@ func (tx *CrossTransaction) SignHash() (h common.Hash) {
 		return hash.(common.Hash)
 	}
 	hash := sha3.NewKeccak256()
-	var b []byte
+	b := tokensPrefix(tx.Data.Tokens)
 	b = append(b, common.LeftPadBytes(tx.Data.Value.Bytes(), 32)...)
 	b = append(b, tx.Data.CTxId.Bytes()...)
 	b = append(b, tx.Data.TxHash.Bytes()...)
@@ -152,6 +157,17 @@ func (tx *CrossTransaction) SignHash() (h common.Hash) {
 	return h
 }
 
+// Size returns the encoded storage size of the ctx
+func (tx *CrossTransaction) Size() common.StorageSize {
+	if size := tx.size.Load(); size != nil {
+		return size.(common.StorageSize)
+	}
+	c := types.WriteCounter(0)
+	rlp.Encode(&c, &tx.Data)
+	tx.size.Store(common.StorageSize(c))
+	return common.StorageSize(c)
+}
+
 // Transactions is a Transaction slice type for basic sorting.
 type CrossTransactions []*CrossTransaction
 
@@ -216,6 +232,8 @@ type CtxDatas struct {
 	V []*big.Int `json:"v" gencodec:"required"` //chainId
 	R []*big.Int `json:"r" gencodec:"required"`
 	S []*big.Int `json:"s" gencodec:"required"`
+
+	Tokens []common.Address `json:"tokens,omitempty" rlp:"tail"` // [token, destinationToken] of ERC20 ctxs, empty for native coin
 }
 
 func NewCrossTransactionWithSignatures(ctx *CrossTransaction, num uint64) *CrossTransactionWithSignatures {
@@ -229,6 +247,7 @@ func NewCrossTransactionWithSignatures(ctx *CrossTransaction, num uint64) *Cross
 		DestinationId:    ctx.Data.DestinationId,
 		DestinationValue: ctx.Data.DestinationValue,
 		Input:            ctx.Data.Input,
+		Tokens:           ctx.Data.Tokens,
 	}
 
 	if ctx.Data.V != nil && ctx.Data.R != nil && ctx.Data.S != nil {
@@ -261,7 +280,7 @@ func (cws *CrossTransactionWithSignatures) Hash() (h common.Hash) {
 		return hash.(common.Hash)
 	}
 	hash := sha3.NewKeccak256()
-	var b []byte
+	b := tokensPrefix(cws.Data.Tokens)
 	b = append(b, common.LeftPadBytes(cws.Data.Value.Bytes(), 32)...)
 	b = append(b, cws.Data.CTxId.Bytes()...)
 	b = append(b, cws.Data.TxHash.Bytes()...)
@@ -340,6 +359,7 @@ func (cws *CrossTransactionWithSignatures) CrossTransaction() *CrossTransaction
 			DestinationId:    cws.Data.DestinationId,
 			DestinationValue: cws.Data.DestinationValue,
 			Input:            cws.Data.Input,
+			Tokens:           cws.Data.Tokens,
 		},
 	}
 }
@@ -364,6 +384,7 @@ func (cws *CrossTransactionWithSignatures) Resolution() []*CrossTransaction {
 				V:                cws.Data.V[i],
 				R:                cws.Data.R[i],
 				S:                cws.Data.S[i],
+				Tokens:           cws.Data.Tokens,
 			},
 		})
 	}
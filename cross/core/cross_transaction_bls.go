@@ -0,0 +1,135 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/common/hexutil"
+	"gbchain-org/go-gbchain/common/math"
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/crypto/bls"
+)
+
+var (
+	ErrUnknownBLSSigner = errors.New("bls signature is not signed by known anchors")
+	ErrNotBLSSigner     = errors.New("ctxs are not signed by bls signatures")
+)
+
+// AggregatedSignature is the BLS signatures of a ctx aggregated into one,
+// it is verified by the cross contract with a single pairing check.
+type AggregatedSignature struct {
+	Signers   []common.Address `json:"signers"`
+	Signature hexutil.Bytes    `json:"signature"` // G1 point of the aggregated signature
+	PublicKey hexutil.Bytes    `json:"publicKey"` // G2 point of the aggregated key of signers
+}
+
+// BLSCtxSigner implements CtxSigner by BLS signatures of anchors, R and S hold
+// the G1 point of signature, V is the same as EIP155 (with recovery id 0)
+// so that the chain id of ctx is derived as usual.
+// BLS signatures are not recoverable, the signer is found in the known anchor keys.
+type BLSCtxSigner struct {
+	TokenCtxSigner
+	keys map[common.Address]*bls.PublicKey
+}
+
+func NewBLSCtxSigner(chainId *big.Int, keys map[common.Address]*bls.PublicKey) BLSCtxSigner {
+	return BLSCtxSigner{
+		TokenCtxSigner: NewTokenCtxSigner(chainId),
+		keys:           keys,
+	}
+}
+
+func (s BLSCtxSigner) Equal(s2 CtxSigner) bool {
+	signer, ok := s2.(BLSCtxSigner)
+	return ok && signer.chainId.Cmp(s.chainId) == 0 && len(signer.keys) == len(s.keys)
+}
+
+func (s BLSCtxSigner) Sender(tx *CrossTransaction) (common.Address, error) {
+	if tx.ChainId().Cmp(s.chainId) != 0 {
+		return common.Address{}, types.ErrInvalidChainId
+	}
+	sig, err := s.signature(tx.Data.R, tx.Data.S)
+	if err != nil {
+		return common.Address{}, err
+	}
+	hash := s.Hash(tx)
+	for addr, pk := range s.keys {
+		if bls.Verify(pk, hash[:], sig) {
+			return addr, nil
+		}
+	}
+	return common.Address{}, ErrUnknownBLSSigner
+}
+
+// SignatureValues returns the signature values, sig is the marshaled G1 point of BLS signature
+func (s BLSCtxSigner) SignatureValues(tx *CrossTransaction, sig []byte) (R, S, V *big.Int, err error) {
+	if len(sig) != bls.SignatureLength {
+		return nil, nil, nil, bls.ErrInvalidSignature
+	}
+	R = new(big.Int).SetBytes(sig[:32])
+	S = new(big.Int).SetBytes(sig[32:])
+	V = big.NewInt(35)
+	V.Add(V, s.chainIdMul)
+	return R, S, V, nil
+}
+
+// Aggregate aggregates the signatures of cws, every signature must be signed by known anchors
+func (s BLSCtxSigner) Aggregate(cws *CrossTransactionWithSignatures) (*AggregatedSignature, error) {
+	var (
+		signers []common.Address
+		sigs    []*bls.Signature
+		pks     []*bls.PublicKey
+	)
+	for _, ctx := range cws.Resolution() {
+		signer, err := s.Sender(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sig, _ := s.signature(ctx.Data.R, ctx.Data.S)
+		signers = append(signers, signer)
+		sigs = append(sigs, sig)
+		pks = append(pks, s.keys[signer])
+	}
+	sig, err := bls.AggregateSignatures(sigs...)
+	if err != nil {
+		return nil, err
+	}
+	pk, err := bls.AggregatePublicKeys(pks...)
+	if err != nil {
+		return nil, err
+	}
+	return &AggregatedSignature{Signers: signers, Signature: sig.Marshal(), PublicKey: pk.G2()}, nil
+}
+
+// VerifyAggregated checks the aggregated signature of ctx in the way of cross contract
+func (s BLSCtxSigner) VerifyAggregated(ctx *CrossTransaction, agg *AggregatedSignature) error {
+	var pks []*bls.PublicKey
+	for _, signer := range agg.Signers {
+		pk, ok := s.keys[signer]
+		if !ok {
+			return ErrUnknownBLSSigner
+		}
+		pks = append(pks, pk)
+	}
+	pk, err := bls.AggregatePublicKeys(pks...)
+	if err != nil {
+		return err
+	}
+	sig, err := bls.SignatureFromBytes(agg.Signature)
+	if err != nil {
+		return err
+	}
+	hash := s.Hash(ctx)
+	if !bls.VerifyAggregated(pk, hash[:], sig) {
+		return bls.ErrInvalidSignature
+	}
+	return nil
+}
+
+func (s BLSCtxSigner) signature(r, ss *big.Int) (*bls.Signature, error) {
+	if r == nil || ss == nil {
+		return nil, bls.ErrInvalidSignature
+	}
+	return bls.SignatureFromBytes(append(math.PaddedBigBytes(r, 32), math.PaddedBigBytes(ss, 32)...))
+}
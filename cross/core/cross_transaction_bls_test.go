@@ -0,0 +1,70 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/crypto/bls"
+)
+
+func TestBLSCtxSigning(t *testing.T) {
+	keys := make(map[common.Address]*bls.PublicKey)
+	var secrets []*bls.SecretKey
+	for i := 1; i <= 3; i++ {
+		key, _ := bls.GenerateKey(nil)
+		keys[common.BigToAddress(big.NewInt(int64(i)))] = key.PublicKey()
+		secrets = append(secrets, key)
+	}
+	signer := NewBLSCtxSigner(big.NewInt(18), keys)
+
+	ctx := NewCrossTransaction(big.NewInt(1e18), big.NewInt(2e18), big.NewInt(19),
+		common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03"),
+		common.HexToAddress("0x095e7baea6a6c7c4c2dfeb977efac326af552d87"), common.Address{}, nil)
+
+	var cws *CrossTransactionWithSignatures
+	for i, key := range secrets[:2] {
+		tx, err := SignCtx(ctx, signer, key.SignHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		from, err := CtxSender(signer, tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := common.BigToAddress(big.NewInt(int64(i + 1))); from != want {
+			t.Errorf("expected from %x, got %x", want, from)
+		}
+		if cws == nil {
+			cws = NewCrossTransactionWithSignatures(tx, 0)
+		} else if err := cws.AddSignature(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	agg, err := signer.Aggregate(cws)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(agg.Signers) != 2 {
+		t.Fatalf("expected 2 signers, got %d", len(agg.Signers))
+	}
+	if err := signer.VerifyAggregated(ctx, agg); err != nil {
+		t.Fatal(err)
+	}
+
+	// signed by a key which is not an anchor
+	unknown, _ := bls.GenerateKey(nil)
+	tx, err := SignCtx(ctx, signer, unknown.SignHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CtxSender(signer, tx); err != ErrUnknownBLSSigner {
+		t.Errorf("expected %v, got %v", ErrUnknownBLSSigner, err)
+	}
+	// the aggregated signature claims another signer
+	agg.Signers[1] = common.BigToAddress(big.NewInt(3))
+	if err := signer.VerifyAggregated(ctx, agg); err != bls.ErrInvalidSignature {
+		t.Errorf("expected %v, got %v", bls.ErrInvalidSignature, err)
+	}
+}
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"math/big"
+
+	"gbchain-org/go-gbchain/accounts/abi"
+	"gbchain-org/go-gbchain/common"
+)
+
+// Order is the ctx with signatures in the form of cross contract
+type Order struct {
+	Value            *big.Int
+	TxId             common.Hash
+	TxHash           common.Hash
+	From             common.Address
+	To               common.Address
+	BlockHash        common.Hash
+	DestinationValue *big.Int
+	Data             []byte
+	V                []*big.Int
+	R                [][32]byte
+	S                [][32]byte
+}
+
+func (cws *CrossTransactionWithSignatures) order() Order {
+	cws.lock.RLock()
+	defer cws.lock.RUnlock()
+	ord := Order{
+		Value:            cws.Data.Value,
+		TxId:             cws.Data.CTxId,
+		TxHash:           cws.Data.TxHash,
+		From:             cws.Data.From,
+		To:               cws.Data.To,
+		BlockHash:        cws.Data.BlockHash,
+		DestinationValue: cws.Data.DestinationValue,
+		Data:             cws.Data.Input,
+	}
+	for i := 0; i < cws.signaturesLength(); i++ {
+		var r, s [32]byte
+		copy(r[:], common.LeftPadBytes(cws.Data.R[i].Bytes(), 32))
+		copy(s[:], common.LeftPadBytes(cws.Data.S[i].Bytes(), 32))
+		ord.V = append(ord.V, cws.Data.V[i])
+		ord.R = append(ord.R, r)
+		ord.S = append(ord.S, s)
+	}
+	return ord
+}
+
+// ConstructCancelData packs the takerCancel (or takerTokenCancel) call of ctx, the maker sends it to the
+// contract of destination chain so that the ctx could never be taken, and then refunded by anchors.
+func (cws *CrossTransactionWithSignatures) ConstructCancelData(crossContract abi.ABI) ([]byte, error) {
+	if cws.IsToken() {
+		return crossContract.Pack("takerTokenCancel", cws.tokenOrder(), cws.ChainId())
+	}
+	return crossContract.Pack("takerCancel", cws.order(), cws.ChainId())
+}
@@ -20,7 +20,7 @@ type ctxSigCache struct {
 
 // MakeSigner returns a Signer based on the given chain config and block number.
 func MakeCtxSigner(chainID *big.Int) CtxSigner {
-	return NewEIP155CtxSigner(chainID)
+	return NewTokenCtxSigner(chainID)
 }
 
 // SignTx signs the transaction using the given signer and private key
@@ -33,6 +33,22 @@ func SignCtx(tx *CrossTransaction, s CtxSigner, signHash SignHash) (*CrossTransa
 	return tx.WithSignature(s, sig)
 }
 
+// SignCtxData signs the transaction by its signing data instead of the hash, so that external
+// signers could check the content of ctx. The signer must provide the signing data.
+func SignCtxData(tx *CrossTransaction, s CtxSigner, signData SignData) (*CrossTransaction, error) {
+	ds, ok := s.(interface {
+		SigningData(tx *CrossTransaction) []byte
+	})
+	if !ok {
+		return nil, fmt.Errorf("signing data is not supported by %T", s)
+	}
+	sig, err := signData(ds.SigningData(tx))
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(s, sig)
+}
+
 // Sender returns the address derived from the signature (V, R, S) using secp256k1
 // elliptic curve and an error if it failed deriving or upon an incorrect
 // signature.
@@ -121,6 +137,14 @@ func (s EIP155CtxSigner) SignatureValues(tx *CrossTransaction, sig []byte) (R, S
 
 func (s EIP155CtxSigner) Hash(tx *CrossTransaction) (h common.Hash) {
 	hash := sha3.NewKeccak256()
+	hash.Write(s.SigningData(tx))
+	hash.Sum(h[:0])
+	return h
+}
+
+// SigningData returns the preimage of Hash:
+// value(32) | ctxId(32) | txHash(32) | from(20) | blockHash(32) | destinationId(32) | destinationValue(32) | input
+func (s EIP155CtxSigner) SigningData(tx *CrossTransaction) []byte {
 	var b []byte
 	b = append(b, common.LeftPadBytes(tx.Data.Value.Bytes(), 32)...)
 	b = append(b, tx.Data.CTxId.Bytes()...)
@@ -130,7 +154,5 @@ func (s EIP155CtxSigner) Hash(tx *CrossTransaction) (h common.Hash) {
 	b = append(b, common.LeftPadBytes(tx.Data.DestinationId.Bytes(), 32)...)
 	b = append(b, common.LeftPadBytes(tx.Data.DestinationValue.Bytes(), 32)...)
 	b = append(b, tx.Data.Input...)
-	hash.Write(b)
-	hash.Sum(h[:0])
-	return h
+	return b
 }
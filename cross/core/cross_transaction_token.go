@@ -0,0 +1,190 @@
+package core
+
+import (
+	"math/big"
+
+	"gbchain-org/go-gbchain/accounts/abi"
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/crypto/sha3"
+)
+
+// tokenCtxDomain is keccak256("gbchain.ctx.token"), which leads the hashed data of ERC20 ctxs.
+// The data of native coin ctxs starts with the value, which never reaches the domain, so that
+// the hashes of both kinds never collide.
+var tokenCtxDomain = common.HexToHash("0x945e5c2ff84b11106e53ad234d1fd11aed93b2e1e33a9febbcbe257e0cf20b80")
+
+// MakerToken is the tokens of an ERC20 ctx, decoded from MakerToken log following MakerTx
+type MakerToken struct {
+	CTxId            common.Hash
+	Token            common.Address // token paid by maker, zero for native coin
+	DestinationToken common.Address // token paid by taker in destination chain, zero for native coin
+}
+
+// NewMakerToken decodes MakerToken(txId, token, destToken) log
+func NewMakerToken(l *types.Log) *MakerToken {
+	if len(l.Topics) < 3 || len(l.Data) < common.HashLength {
+		return nil
+	}
+	return &MakerToken{
+		CTxId:            l.Topics[1],
+		Token:            common.BytesToAddress(l.Topics[2].Bytes()),
+		DestinationToken: common.BytesToAddress(l.Data[:common.HashLength]),
+	}
+}
+
+// WithMakerTokens sets the tokens to the ctxs made by the same maker transactions,
+// it must be called before the ctxs are hashed.
+func WithMakerTokens(ctxs []*CrossTransaction, tokens []*MakerToken) {
+	for _, token := range tokens {
+		for _, ctx := range ctxs {
+			if ctx.Data.CTxId == token.CTxId && !(token.Token == common.Address{} && token.DestinationToken == common.Address{}) {
+				ctx.Data.Tokens = []common.Address{token.Token, token.DestinationToken}
+			}
+		}
+	}
+}
+
+func tokensPrefix(tokens []common.Address) []byte {
+	if len(tokens) == 0 {
+		return nil
+	}
+	b := append([]byte{}, tokenCtxDomain.Bytes()...)
+	for _, token := range tokens {
+		b = append(b, token.Bytes()...)
+	}
+	return b
+}
+
+func tokenAt(tokens []common.Address, i int) common.Address {
+	if i < len(tokens) {
+		return tokens[i]
+	}
+	return common.Address{}
+}
+
+// IsToken reports whether the ctx swaps ERC20 tokens in either chain
+func (tx *CrossTransaction) IsToken() bool { return len(tx.Data.Tokens) > 0 }
+
+// Token returns the token paid by maker, zero for native coin
+func (tx *CrossTransaction) Token() common.Address { return tokenAt(tx.Data.Tokens, 0) }
+
+// DestinationToken returns the token paid by taker in destination chain, zero for native coin
+func (tx *CrossTransaction) DestinationToken() common.Address { return tokenAt(tx.Data.Tokens, 1) }
+
+func (cws *CrossTransactionWithSignatures) IsToken() bool { return len(cws.Data.Tokens) > 0 }
+
+func (cws *CrossTransactionWithSignatures) Token() common.Address {
+	return tokenAt(cws.Data.Tokens, 0)
+}
+
+func (cws *CrossTransactionWithSignatures) DestinationToken() common.Address {
+	return tokenAt(cws.Data.Tokens, 1)
+}
+
+// TokenCtxSigner implements CtxSigner for both native coin and ERC20 ctxs. Native coin
+// ctxs are signed the same as EIP155CtxSigner, while ERC20 ctxs cover the tokens
+// in the layout of takerToken of cross contract.
+type TokenCtxSigner struct {
+	EIP155CtxSigner
+}
+
+func NewTokenCtxSigner(chainId *big.Int) TokenCtxSigner {
+	return TokenCtxSigner{EIP155CtxSigner: NewEIP155CtxSigner(chainId)}
+}
+
+func (s TokenCtxSigner) Equal(s2 CtxSigner) bool {
+	signer, ok := s2.(TokenCtxSigner)
+	return ok && signer.chainId.Cmp(s.chainId) == 0
+}
+
+func (s TokenCtxSigner) Sender(tx *CrossTransaction) (common.Address, error) {
+	if tx.ChainId().Cmp(s.chainId) != 0 {
+		return common.Address{}, types.ErrInvalidChainId
+	}
+	V := new(big.Int).Sub(tx.Data.V, s.chainIdMul)
+	V.Sub(V, big8)
+	return types.RecoverPlain(s.Hash(tx), tx.Data.R, tx.Data.S, V, true)
+}
+
+func (s TokenCtxSigner) Hash(tx *CrossTransaction) (h common.Hash) {
+	hash := sha3.NewKeccak256()
+	hash.Write(s.SigningData(tx))
+	hash.Sum(h[:0])
+	return h
+}
+
+// SigningData returns the preimage of Hash, it is the same as EIP155CtxSigner for native coin ctxs, or
+// domain(32) | value(32) | ctxId(32) | txHash(32) | from(20) | blockHash(32) | destinationId(32) |
+// destinationValue(32) | token(20) | destinationToken(20) | input
+func (s TokenCtxSigner) SigningData(tx *CrossTransaction) []byte {
+	if !tx.IsToken() {
+		return s.EIP155CtxSigner.SigningData(tx)
+	}
+	var b []byte
+	b = append(b, tokenCtxDomain.Bytes()...)
+	b = append(b, common.LeftPadBytes(tx.Data.Value.Bytes(), 32)...)
+	b = append(b, tx.Data.CTxId.Bytes()...)
+	b = append(b, tx.Data.TxHash.Bytes()...)
+	b = append(b, tx.Data.From.Bytes()...)
+	b = append(b, tx.Data.BlockHash.Bytes()...)
+	b = append(b, common.LeftPadBytes(tx.Data.DestinationId.Bytes(), 32)...)
+	b = append(b, common.LeftPadBytes(tx.Data.DestinationValue.Bytes(), 32)...)
+	b = append(b, tx.Token().Bytes()...)
+	b = append(b, tx.DestinationToken().Bytes()...)
+	b = append(b, tx.Data.Input...)
+	return b
+}
+
+// TokenOrder is the ERC20 ctx with signatures in the form of cross contract
+type TokenOrder struct {
+	Value            *big.Int
+	TxId             common.Hash
+	TxHash           common.Hash
+	From             common.Address
+	To               common.Address
+	BlockHash        common.Hash
+	DestinationValue *big.Int
+	Token            common.Address
+	DestinationToken common.Address
+	Data             []byte
+	V                []*big.Int
+	R                [][32]byte
+	S                [][32]byte
+}
+
+func (cws *CrossTransactionWithSignatures) tokenOrder() TokenOrder {
+	ord := cws.order()
+	return TokenOrder{
+		Value:            ord.Value,
+		TxId:             ord.TxId,
+		TxHash:           ord.TxHash,
+		From:             ord.From,
+		To:               ord.To,
+		BlockHash:        ord.BlockHash,
+		DestinationValue: ord.DestinationValue,
+		Token:            cws.Token(),
+		DestinationToken: cws.DestinationToken(),
+		Data:             ord.Data,
+		V:                ord.V,
+		R:                ord.R,
+		S:                ord.S,
+	}
+}
+
+// TakerValue returns the native coin sent with the taker call of ctx, it is zero if the taker
+// pays an ERC20 token, which is transferred by the allowance to cross contract instead.
+func (cws *CrossTransactionWithSignatures) TakerValue() *big.Int {
+	if cws.DestinationToken() != (common.Address{}) {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(cws.Data.DestinationValue)
+}
+
+// ConstructTakerData packs the taker call of ctx, or takerToken if the ctx swaps ERC20 tokens
+func (cws *CrossTransactionWithSignatures) ConstructTakerData(crossContract abi.ABI) ([]byte, error) {
+	if cws.IsToken() {
+		return crossContract.Pack("takerToken", cws.tokenOrder(), cws.ChainId())
+	}
+	return crossContract.Pack("taker", cws.order(), cws.ChainId())
+}
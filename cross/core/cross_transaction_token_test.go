@@ -0,0 +1,166 @@
+package core
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"gbchain-org/go-gbchain/accounts/abi"
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/common/hexutil"
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/crypto"
+	"gbchain-org/go-gbchain/params"
+	"gbchain-org/go-gbchain/rlp"
+)
+
+var (
+	testToken     = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	testDestToken = common.HexToAddress("0x2222222222222222222222222222222222222222")
+)
+
+func newTestTokenCtx(tokens ...common.Address) *CrossTransaction {
+	ctx := NewCrossTransaction(big.NewInt(1e18), big.NewInt(2e18), big.NewInt(19),
+		common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03"),
+		common.HexToAddress("0x04"), common.Address{}, []byte("memo"))
+	ctx.Data.Tokens = tokens
+	return ctx
+}
+
+func TestTokenCtxSigning(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	signHash := func(hash []byte) ([]byte, error) {
+		return crypto.Sign(hash, key)
+	}
+
+	var (
+		signer = NewTokenCtxSigner(big.NewInt(18))
+		native = newTestTokenCtx()
+		token  = newTestTokenCtx(testToken, testDestToken)
+	)
+	// native coin ctxs are signed the same as before
+	if signer.Hash(native) != NewEIP155CtxSigner(big.NewInt(18)).Hash(native) {
+		t.Errorf("native ctx hash is changed")
+	}
+	if signer.Hash(token) == signer.Hash(native) || token.Hash() == native.Hash() || token.SignHash() == native.SignHash() {
+		t.Errorf("tokens are not covered by hashes")
+	}
+	if !bytes.HasPrefix(signer.SigningData(token), tokenCtxDomain.Bytes()) {
+		t.Errorf("token signing data without domain")
+	}
+
+	for _, ctx := range []*CrossTransaction{native, token} {
+		signed, err := SignCtx(ctx, signer, signHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if from, err := CtxSender(signer, signed); err != nil || from != addr {
+			t.Errorf("sender mismatch: have %x, want %x, err %v", from, addr, err)
+		}
+	}
+	signed, _ := SignCtx(token, signer, signHash)
+	signed.Data.Tokens = []common.Address{testDestToken, testToken}
+	if from, _ := CtxSender(NewTokenCtxSigner(big.NewInt(18)), signed); from == addr {
+		t.Errorf("swapped tokens are accepted")
+	}
+}
+
+func TestTokenCtxEncode(t *testing.T) {
+	native, token := newTestTokenCtx(), newTestTokenCtx(testToken, common.Address{})
+
+	// the encoding of native coin ctxs is unchanged
+	enc, err := rlp.EncodeToBytes(native)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(enc, mustEncodeLegacy(t, native)) {
+		t.Errorf("native ctx encoding is changed")
+	}
+
+	enc, err = rlp.EncodeToBytes(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dec CrossTransaction
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if !dec.IsToken() || dec.Token() != testToken || dec.DestinationToken() != (common.Address{}) {
+		t.Errorf("tokens mismatch: %v", dec.Data.Tokens)
+	}
+	if dec.Hash() != token.Hash() {
+		t.Errorf("hash mismatch after decoding")
+	}
+
+	cws := NewCrossTransactionWithSignatures(token, 1)
+	if !cws.IsToken() || cws.Token() != testToken || cws.CrossTransaction().Hash() != token.Hash() {
+		t.Errorf("tokens are lost by ctx with signatures")
+	}
+}
+
+// mustEncodeLegacy encodes ctx in the layout without tokens
+func mustEncodeLegacy(t *testing.T, ctx *CrossTransaction) []byte {
+	d := ctx.Data
+	enc, err := rlp.EncodeToBytes([]interface{}{[]interface{}{d.Value, d.CTxId, d.TxHash, d.From, d.To, d.BlockHash,
+		d.DestinationId, d.DestinationValue, d.Input, d.V, d.R, d.S}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return enc
+}
+
+func TestMakerToken(t *testing.T) {
+	l := &types.Log{
+		Topics: []common.Hash{params.MakerTokenTopic, common.HexToHash("0x01"), testToken.Hash()},
+		Data:   testDestToken.Hash().Bytes(),
+	}
+	token := NewMakerToken(l)
+	if token == nil || token.CTxId != l.Topics[1] || token.Token != testToken || token.DestinationToken != testDestToken {
+		t.Fatalf("token mismatch: %+v", token)
+	}
+	if NewMakerToken(&types.Log{Topics: l.Topics}) != nil {
+		t.Errorf("truncated log is decoded")
+	}
+
+	ctxs := []*CrossTransaction{newTestTokenCtx(), newTestTokenCtx()}
+	ctxs[1].Data.CTxId = common.HexToHash("0x05")
+	WithMakerTokens(ctxs, []*MakerToken{token, {CTxId: ctxs[1].Data.CTxId}})
+	if ctxs[0].Token() != testToken || ctxs[0].DestinationToken() != testDestToken {
+		t.Errorf("tokens are not attached: %v", ctxs[0].Data.Tokens)
+	}
+	if ctxs[1].IsToken() {
+		t.Errorf("native coin ctx is marked as token: %v", ctxs[1].Data.Tokens)
+	}
+}
+
+func TestTokenCtxCalls(t *testing.T) {
+	data, _ := hexutil.Decode(params.CrossDemoAbi)
+	crossABI, err := abi.JSON(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		ctx           *CrossTransaction
+		taker, cancel string
+	}{
+		{newTestTokenCtx(), "taker", "takerCancel"},
+		{newTestTokenCtx(testToken, testDestToken), "takerToken", "takerTokenCancel"},
+	} {
+		cws := NewCrossTransactionWithSignatures(tt.ctx, 1)
+		call, err := cws.ConstructTakerData(crossABI)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if method := crossABI.Methods[tt.taker]; !bytes.Equal(call[:4], method.ID()) {
+			t.Errorf("%s: method id mismatch: %x", tt.taker, call[:4])
+		}
+		call, err = cws.ConstructCancelData(crossABI)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if method := crossABI.Methods[tt.cancel]; !bytes.Equal(call[:4], method.ID()) {
+			t.Errorf("%s: method id mismatch: %x", tt.cancel, call[:4])
+		}
+	}
+}
@@ -28,6 +28,11 @@ type CommitEvent struct {
 	InvalidSigIndex []int
 }
 
+// CtxStatusEvent is posted when ctxs are written into the store or their status is changed
+type CtxStatusEvent struct {
+	Ctxs []*CrossTransactionWithSignatures
+}
+
 type NewFinishEvent struct {
 	Finishes []*CrossTransactionModifier
 }
@@ -36,6 +41,26 @@ type ConfirmedFinishEvent struct {
 	Finishes []*CrossTransactionModifier
 }
 
+// NewCancelEvent carries takerCancel logs, the makers cancel their remote ctxs in this chain
+type NewCancelEvent struct {
+	Cancels []*ReceptTransaction
+}
+
+// ConfirmedCancelEvent carries confirmed takerCancel logs, anchors refund the makers in maker chain
+type ConfirmedCancelEvent struct {
+	Cancels []*ReceptTransaction
+}
+
+// MakerCancelEvent carries confirmed makerCancel logs, the local ctxs are refunded to makers
+type MakerCancelEvent struct {
+	Cancels []*CrossTransactionModifier
+}
+
+// EvidenceEvent is posted when anchors are found misbehaving
+type EvidenceEvent struct {
+	Evidences []*Evidence
+}
+
 type NewAnchorEvent struct {
 	ChainInfo []*RemoteChainInfo
 }
@@ -78,11 +103,17 @@ type CrossBlockEvent struct {
 	NewAnchor       NewAnchorEvent
 	ReorgTaker      NewTakerEvent
 	ReorgFinish     NewFinishEvent
+	NewCancel       NewCancelEvent
+	ConfirmedCancel ConfirmedCancelEvent
+	ReorgCancel     NewCancelEvent
+	MakerCancel     MakerCancelEvent
 }
 
 func (e CrossBlockEvent) IsEmpty() bool {
 	return len(e.ConfirmedMaker.Txs)|len(e.ConfirmedTaker.Txs)|
 		len(e.ConfirmedFinish.Finishes)|len(e.NewTaker.Takers)|
 		len(e.NewFinish.Finishes)|len(e.NewAnchor.ChainInfo)|
-		len(e.ReorgTaker.Takers)|len(e.ReorgFinish.Finishes) == 0
+		len(e.ReorgTaker.Takers)|len(e.ReorgFinish.Finishes)|
+		len(e.NewCancel.Cancels)|len(e.ConfirmedCancel.Cancels)|
+		len(e.ReorgCancel.Cancels)|len(e.MakerCancel.Cancels) == 0
 }
@@ -0,0 +1,122 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"gbchain-org/go-gbchain/accounts/abi"
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/crypto"
+)
+
+var ErrInvalidEvidence = errors.New("invalid evidence")
+
+// EvidenceKind is the misbehaviour of anchor proven by an evidence
+type EvidenceKind uint8
+
+const (
+	EvidenceConflict EvidenceKind = iota + 1 // two different ctxs are signed with the same CTxId
+	EvidenceNoMaker                          // a ctx is signed while its maker log does not exist
+)
+
+func (k EvidenceKind) String() string {
+	switch k {
+	case EvidenceConflict:
+		return "conflict"
+	case EvidenceNoMaker:
+		return "noMaker"
+	default:
+		return "unknown"
+	}
+}
+
+// Evidence holds the signed payloads of a misbehaving anchor, the signer is
+// recovered from the payloads by the CtxSigner of the signing chain.
+type Evidence struct {
+	Kind     EvidenceKind
+	Signer   common.Address
+	Ctx      *CrossTransaction
+	Conflict *CrossTransaction `rlp:"nil"` // the other ctx signed with the same CTxId, nil if Kind is not EvidenceConflict
+	Time     uint64            // unix time the evidence is found
+}
+
+// NewConflictEvidence creates the evidence of ctx and conflict signed by the same anchor
+func NewConflictEvidence(signer CtxSigner, ctx, conflict *CrossTransaction, time uint64) (*Evidence, error) {
+	from, err := CtxSender(signer, ctx)
+	if err != nil {
+		return nil, err
+	}
+	ev := &Evidence{Kind: EvidenceConflict, Signer: from, Ctx: ctx, Conflict: conflict, Time: time}
+	return ev, ev.Verify(signer)
+}
+
+// NewNoMakerEvidence creates the evidence of a signed ctx without maker log
+func NewNoMakerEvidence(signer CtxSigner, ctx *CrossTransaction, time uint64) (*Evidence, error) {
+	from, err := CtxSender(signer, ctx)
+	if err != nil {
+		return nil, err
+	}
+	ev := &Evidence{Kind: EvidenceNoMaker, Signer: from, Ctx: ctx, Time: time}
+	return ev, ev.Verify(signer)
+}
+
+// ID identifies the evidence by the kind, signer and the signed ctxs
+func (ev *Evidence) ID() common.Hash {
+	hashes := []common.Hash{ev.Ctx.Hash()}
+	if ev.Conflict != nil { // the order of conflicting ctxs is irrelevant
+		hashes = append(hashes, ev.Conflict.Hash())
+		if bytes.Compare(hashes[0].Bytes(), hashes[1].Bytes()) > 0 {
+			hashes[0], hashes[1] = hashes[1], hashes[0]
+		}
+	}
+	data := append([]byte{byte(ev.Kind)}, ev.Signer.Bytes()...)
+	for _, hash := range hashes {
+		data = append(data, hash.Bytes()...)
+	}
+	return crypto.Keccak256Hash(data)
+}
+
+// Verify checks the payloads are signed by the signer of evidence. A conflict
+// is proven by the payloads only, while the verifier should check the maker
+// log does not exist on chain for EvidenceNoMaker.
+func (ev *Evidence) Verify(signer CtxSigner) error {
+	if ev.Ctx == nil {
+		return fmt.Errorf("%w: missing ctx", ErrInvalidEvidence)
+	}
+	if from, err := CtxSender(signer, ev.Ctx); err != nil || from != ev.Signer {
+		return fmt.Errorf("%w: ctx is not signed by %s", ErrInvalidEvidence, ev.Signer.String())
+	}
+	switch ev.Kind {
+	case EvidenceConflict:
+		if ev.Conflict == nil {
+			return fmt.Errorf("%w: missing conflicting ctx", ErrInvalidEvidence)
+		}
+		if from, err := CtxSender(signer, ev.Conflict); err != nil || from != ev.Signer {
+			return fmt.Errorf("%w: conflicting ctx is not signed by %s", ErrInvalidEvidence, ev.Signer.String())
+		}
+		if ev.Ctx.ID() != ev.Conflict.ID() || !conflicts(ev.Ctx, ev.Conflict) {
+			return fmt.Errorf("%w: ctxs are not conflicting", ErrInvalidEvidence)
+		}
+	case EvidenceNoMaker:
+		if ev.Conflict != nil {
+			return fmt.Errorf("%w: unexpected conflicting ctx", ErrInvalidEvidence)
+		}
+	default:
+		return fmt.Errorf("%w: unknown kind %d", ErrInvalidEvidence, ev.Kind)
+	}
+	return nil
+}
+
+// ConstructRemoveData packs the removeAnchors call of the signer, the contract owner of the ctx chain
+// sends it to remove the anchor from the anchors of destination chain.
+func (ev *Evidence) ConstructRemoveData(crossContract abi.ABI) ([]byte, error) {
+	return crossContract.Pack("removeAnchors", ev.Ctx.DestinationId(), []common.Address{ev.Signer})
+}
+
+// conflicts reports whether the ctxs differ besides the block hash, which is changed by chain reorgs
+func conflicts(a, b *CrossTransaction) bool {
+	x, y := a.Data, b.Data
+	y.BlockHash, y.V, y.R, y.S = x.BlockHash, x.V, x.R, x.S
+	return (&CrossTransaction{Data: x}).Hash() != (&CrossTransaction{Data: y}).Hash()
+}
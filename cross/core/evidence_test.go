@@ -0,0 +1,103 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"gbchain-org/go-gbchain/accounts/abi"
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/common/hexutil"
+	"gbchain-org/go-gbchain/crypto"
+	"gbchain-org/go-gbchain/params"
+	"gbchain-org/go-gbchain/rlp"
+)
+
+func TestEvidence(t *testing.T) {
+	var (
+		key, _ = crypto.GenerateKey()
+		other  = crypto.PubkeyToAddress(key.PublicKey)
+		signer = NewEIP155CtxSigner(big.NewInt(1))
+	)
+	sign := func(value int64, blockHash common.Hash) *CrossTransaction {
+		ctx := NewCrossTransaction(big.NewInt(value), big.NewInt(2e18), big.NewInt(1024),
+			common.HexToHash("0x01"), common.HexToHash("0x02"), blockHash,
+			common.HexToAddress("0x03"), common.Address{}, nil)
+		signed, err := SignCtx(ctx, signer, func(hash []byte) ([]byte, error) { return crypto.Sign(hash, key) })
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	first, second := sign(1e18, common.HexToHash("0x04")), sign(2e18, common.HexToHash("0x04"))
+
+	ev, err := NewConflictEvidence(signer, first, second, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Signer != other {
+		t.Errorf("signer mismatch: have %x, want %x", ev.Signer, other)
+	}
+	reversed, _ := NewConflictEvidence(signer, second, first, 2)
+	if ev.ID() != reversed.ID() {
+		t.Errorf("evidence id depends on the order of ctxs")
+	}
+
+	// evidences are decoded and verified by others
+	enc, err := rlp.EncodeToBytes(ev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dec Evidence
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if err := dec.Verify(signer); err != nil {
+		t.Errorf("verify decoded evidence: %v", err)
+	}
+	if dec.ID() != ev.ID() {
+		t.Errorf("id mismatch after decoding")
+	}
+
+	// invalid evidences
+	if _, err := NewConflictEvidence(signer, first, sign(1e18, common.HexToHash("0x05")), 1); !errors.Is(err, ErrInvalidEvidence) {
+		t.Errorf("reorged ctxs are not conflicting, got %v", err)
+	}
+	forged := &Evidence{Kind: EvidenceConflict, Signer: common.HexToAddress("0x05"), Ctx: first, Conflict: second}
+	if err := forged.Verify(signer); !errors.Is(err, ErrInvalidEvidence) {
+		t.Errorf("evidence of other signer, got %v", err)
+	}
+	noMaker, err := NewNoMakerEvidence(signer, first, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if noMaker.ID() == ev.ID() {
+		t.Errorf("evidences of different kinds have the same id")
+	}
+
+	// the removeAnchors call of signer
+	data, _ := hexutil.Decode(params.CrossDemoAbi)
+	crossABI, err := abi.JSON(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	input, err := ev.ConstructRemoveData(crossABI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	method := crossABI.Methods["removeAnchors"]
+	if !bytes.Equal(input[:4], method.ID()) {
+		t.Errorf("method id mismatch: %x", input[:4])
+	}
+	args, err := method.Inputs.UnpackValues(input[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args[0].(*big.Int).Cmp(big.NewInt(1024)) != 0 {
+		t.Errorf("remote chain mismatch: %v", args[0])
+	}
+	if anchors := args[1].([]common.Address); len(anchors) != 1 || anchors[0] != other {
+		t.Errorf("anchors mismatch: %v", anchors)
+	}
+}
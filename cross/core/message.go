@@ -0,0 +1,110 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"gbchain-org/go-gbchain/accounts/abi"
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/common/hexutil"
+	"gbchain-org/go-gbchain/core/types"
+)
+
+var ErrNotMessage = errors.New("ctx is not a message")
+
+// messageMagic is the leading bytes4 of the Input of message ctxs
+var messageMagic = [4]byte{'x', 'm', 's', 'g'}
+
+// messageArguments is the abi encoding of message, abi.encode(bytes4("xmsg"), target, gasLimit, payload)
+var messageArguments = func() abi.Arguments {
+	bytes4, _ := abi.NewType("bytes4", "", nil)
+	address, _ := abi.NewType("address", "", nil)
+	uint256, _ := abi.NewType("uint256", "", nil)
+	bytesTy, _ := abi.NewType("bytes", "", nil)
+	return abi.Arguments{{Type: bytes4}, {Type: address}, {Type: uint256}, {Type: bytesTy}}
+}()
+
+// Message is the call carried by the Input of ctx, the anchors of destination chain
+// call the target with the payload after the ctx is signed completely.
+type Message struct {
+	Target   common.Address `json:"target"`
+	GasLimit uint64         `json:"gasLimit"`
+	Payload  hexutil.Bytes  `json:"payload"`
+}
+
+// Input encodes the message into the Input of ctx
+func (m *Message) Input() ([]byte, error) {
+	return messageArguments.Pack(messageMagic, m.Target, new(big.Int).SetUint64(m.GasLimit), []byte(m.Payload))
+}
+
+// CallGas returns the gas takerMessage needs to call the target with GasLimit, the call
+// passes through the message proxy and each call keeps 1/64 of the gas available.
+func (m *Message) CallGas() uint64 {
+	return m.GasLimit + m.GasLimit/32
+}
+
+// ParseMessage decodes the message from the Input of ctx, ErrNotMessage is returned
+// if the ctx is a plain value swap.
+func ParseMessage(input []byte) (*Message, error) {
+	if len(input) < common.HashLength || !bytes.Equal(input[:len(messageMagic)], messageMagic[:]) {
+		return nil, ErrNotMessage
+	}
+	values, err := messageArguments.UnpackValues(input)
+	if err != nil {
+		return nil, err
+	}
+	gasLimit := values[2].(*big.Int)
+	if !gasLimit.IsUint64() {
+		return nil, errors.New("message gas limit overflow")
+	}
+	return &Message{
+		Target:   values[1].(common.Address),
+		GasLimit: gasLimit.Uint64(),
+		Payload:  values[3].([]byte),
+	}, nil
+}
+
+// MessageResult is the execution of message in destination chain, decoded from MessageExecuted log
+type MessageResult struct {
+	CTxId   common.Hash    `json:"ctxId"`
+	TxHash  common.Hash    `json:"txHash"` // takerMessage transaction in destination chain
+	Target  common.Address `json:"target"`
+	Success bool           `json:"success"`
+	Result  hexutil.Bytes  `json:"result"` // returned data, or revert data if the call fails
+}
+
+// NewMessageResult decodes MessageExecuted(txId, target, success, result) log
+func NewMessageResult(l *types.Log) *MessageResult {
+	if len(l.Topics) < 3 || len(l.Data) < common.HashLength*3 {
+		return nil
+	}
+	size := new(big.Int).SetBytes(l.Data[common.HashLength*2 : common.HashLength*3])
+	if !size.IsUint64() || uint64(len(l.Data)) < common.HashLength*3+size.Uint64() {
+		return nil
+	}
+	return &MessageResult{
+		CTxId:   l.Topics[1],
+		TxHash:  l.TxHash,
+		Target:  common.BytesToAddress(l.Topics[2].Bytes()),
+		Success: l.Data[common.HashLength-1] == 1,
+		Result:  common.CopyBytes(l.Data[common.HashLength*3 : common.HashLength*3+size.Uint64()]),
+	}
+}
+
+// WithMessageResults sets the results of messages to the takers executing them
+func WithMessageResults(takers []*ReceptTransaction, results []*MessageResult) {
+	for _, result := range results {
+		for _, taker := range takers {
+			if taker.CTxId == result.CTxId && taker.TxHash == result.TxHash {
+				taker.Result = result
+			}
+		}
+	}
+}
+
+// ConstructMessageData packs the takerMessage call of ctx, the anchors of destination
+// chain send it to execute the message.
+func (cws *CrossTransactionWithSignatures) ConstructMessageData(crossContract abi.ABI) ([]byte, error) {
+	return crossContract.Pack("takerMessage", cws.order(), cws.ChainId())
+}
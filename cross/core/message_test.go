@@ -0,0 +1,100 @@
+package core
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"gbchain-org/go-gbchain/accounts/abi"
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/common/hexutil"
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/params"
+)
+
+func TestMessage(t *testing.T) {
+	msg := &Message{Target: common.HexToAddress("0x01"), GasLimit: 100000, Payload: []byte{1, 2, 3}}
+	input, err := msg.Input()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(input[:4], []byte("xmsg")) {
+		t.Errorf("magic mismatch: %x", input[:4])
+	}
+	dec, err := ParseMessage(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dec.Target != msg.Target || dec.GasLimit != msg.GasLimit || !bytes.Equal(dec.Payload, msg.Payload) {
+		t.Errorf("message mismatch: have %+v, want %+v", dec, msg)
+	}
+
+	// plain value swaps are not messages
+	for _, input := range [][]byte{nil, []byte("memo"), common.Hash{}.Bytes()} {
+		if _, err := ParseMessage(input); err != ErrNotMessage {
+			t.Errorf("input %x: have %v, want %v", input, err, ErrNotMessage)
+		}
+	}
+	if _, err := ParseMessage(input[:common.HashLength*3]); err == nil || err == ErrNotMessage {
+		t.Errorf("truncated message is not rejected: %v", err)
+	}
+
+	// the takerMessage call of ctx
+	data, _ := hexutil.Decode(params.CrossDemoAbi)
+	crossABI, err := abi.JSON(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cws := NewCrossTransactionWithSignatures(NewCrossTransaction(big.NewInt(1), big.NewInt(2), big.NewInt(1024),
+		common.HexToHash("0x02"), common.HexToHash("0x03"), common.HexToHash("0x04"),
+		common.HexToAddress("0x05"), common.Address{}, input), 1)
+	call, err := cws.ConstructMessageData(crossABI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method := crossABI.Methods["takerMessage"]; !bytes.Equal(call[:4], method.ID()) {
+		t.Errorf("method id mismatch: %x", call[:4])
+	}
+}
+
+func TestMessageResult(t *testing.T) {
+	event := abi.Arguments{{Type: mustType("bool")}, {Type: mustType("bytes")}}
+	data, err := event.Pack(true, []byte{0xca, 0xfe})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := &types.Log{
+		Topics: []common.Hash{params.MessageTopic, common.HexToHash("0x01"), common.HexToHash("0x02")},
+		Data:   data,
+		TxHash: common.HexToHash("0x03"),
+	}
+	result := NewMessageResult(l)
+	if result == nil {
+		t.Fatal("failed to decode MessageExecuted log")
+	}
+	if result.CTxId != l.Topics[1] || result.Target != common.HexToAddress("0x02") ||
+		!result.Success || !bytes.Equal(result.Result, []byte{0xca, 0xfe}) {
+		t.Errorf("result mismatch: %+v", result)
+	}
+	l.Data = data[:len(data)-common.HashLength]
+	if NewMessageResult(l) != nil {
+		t.Errorf("truncated log is decoded")
+	}
+
+	takers := []*ReceptTransaction{
+		{CTxId: common.HexToHash("0x01"), TxHash: common.HexToHash("0x03")},
+		{CTxId: common.HexToHash("0x04"), TxHash: common.HexToHash("0x05")},
+	}
+	WithMessageResults(takers, []*MessageResult{result})
+	if takers[0].Result != result || takers[1].Result != nil {
+		t.Errorf("results are not attached to takers")
+	}
+}
+
+func mustType(name string) abi.Type {
+	typ, err := abi.NewType(name, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
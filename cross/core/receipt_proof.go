@@ -0,0 +1,143 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/crypto"
+	"gbchain-org/go-gbchain/ethdb/memorydb"
+	"gbchain-org/go-gbchain/params"
+	"gbchain-org/go-gbchain/rlp"
+	"gbchain-org/go-gbchain/trie"
+)
+
+var (
+	ErrInvalidProof        = errors.New("invalid receipt proof")
+	ErrProofHeaderMismatch = fmt.Errorf("[%w]: header is not the block of ctx", ErrInvalidProof)
+	ErrProofMissingReceipt = fmt.Errorf("[%w]: receipt is not in the receipts trie", ErrInvalidProof)
+	ErrProofMissingLog     = fmt.Errorf("[%w]: maker log is not in the receipt", ErrInvalidProof)
+	ErrProofLogMismatch    = fmt.Errorf("[%w]: maker log is different from ctx", ErrInvalidProof)
+)
+
+// ReceiptProof proves that the makerTx log of a ctx is included in a block,
+// Proof is the merkle-patricia path of the receipt in the receipts trie of Header
+type ReceiptProof struct {
+	Header   *types.Header
+	TxIndex  uint
+	LogIndex uint
+	Proof    [][]byte
+}
+
+// proofList collects trie nodes written by trie.Prove
+type proofList [][]byte
+
+func (n *proofList) Put(key []byte, value []byte) error {
+	*n = append(*n, value)
+	return nil
+}
+
+func (n *proofList) Delete(key []byte) error {
+	panic("not supported")
+}
+
+// NewReceiptProof creates the proof of receipts[txIndex] in the block of header
+func NewReceiptProof(header *types.Header, receipts types.Receipts, txIndex, logIndex uint) (*ReceiptProof, error) {
+	if int(txIndex) >= receipts.Len() {
+		return nil, ErrProofMissingReceipt
+	}
+	tr := new(trie.Trie)
+	keybuf := new(bytes.Buffer)
+	for i := 0; i < receipts.Len(); i++ {
+		keybuf.Reset()
+		rlp.Encode(keybuf, uint(i))
+		tr.Update(keybuf.Bytes(), receipts.GetRlp(i))
+	}
+	if tr.Hash() != header.ReceiptHash {
+		return nil, ErrProofMissingReceipt
+	}
+	key, _ := rlp.EncodeToBytes(txIndex)
+	var proof proofList
+	if err := tr.Prove(key, 0, &proof); err != nil {
+		return nil, err
+	}
+	return &ReceiptProof{Header: header, TxIndex: txIndex, LogIndex: logIndex, Proof: proof}, nil
+}
+
+// Receipt verifies the proof against the receipts root of header and returns the proven receipt
+func (p *ReceiptProof) Receipt() (*types.Receipt, error) {
+	db := memorydb.New()
+	for _, node := range p.Proof {
+		db.Put(crypto.Keccak256(node), node)
+	}
+	key, _ := rlp.EncodeToBytes(p.TxIndex)
+	value, _, err := trie.VerifyProof(p.Header.ReceiptHash, key, db)
+	if err != nil {
+		return nil, fmt.Errorf("[%w]: %v", ErrInvalidProof, err)
+	}
+	if value == nil {
+		return nil, ErrProofMissingReceipt
+	}
+	var receipt types.Receipt
+	if err := rlp.DecodeBytes(value, &receipt); err != nil {
+		return nil, fmt.Errorf("[%w]: %v", ErrInvalidProof, err)
+	}
+	return &receipt, nil
+}
+
+// Verify checks that the ctx is emitted as makerTx log by contract in the proven receipt,
+// the header should be checked by the caller that it is trusted by the local side.
+func (p *ReceiptProof) Verify(ctx *CrossTransaction, contract common.Address) error {
+	if p.Header == nil || p.Header.Hash() != ctx.BlockHash() {
+		return ErrProofHeaderMismatch
+	}
+	receipt, err := p.Receipt()
+	if err != nil {
+		return err
+	}
+	if int(p.LogIndex) >= len(receipt.Logs) {
+		return ErrProofMissingLog
+	}
+	v := receipt.Logs[p.LogIndex]
+	if v.Address != contract || len(v.Topics) < 3 || v.Topics[0] != params.MakerTopic || v.Topics[1] != ctx.ID() {
+		return ErrProofMissingLog
+	}
+	if !makerLogMatch(v, ctx) || !makerTokenMatch(receipt.Logs, contract, ctx) {
+		return ErrProofLogMismatch
+	}
+	return nil
+}
+
+// makerTokenMatch compares the tokens of ctx with MakerToken log in the receipt, native coin
+// ctxs must not have the log.
+func makerTokenMatch(logs []*types.Log, contract common.Address, ctx *CrossTransaction) bool {
+	for _, v := range logs {
+		if v.Address == contract && len(v.Topics) > 1 && v.Topics[0] == params.MakerTokenTopic && v.Topics[1] == ctx.ID() {
+			token := NewMakerToken(v)
+			return token != nil && token.Token == ctx.Token() && token.DestinationToken == ctx.DestinationToken() &&
+				ctx.IsToken() == (token.Token != common.Address{} || token.DestinationToken != common.Address{})
+		}
+	}
+	return !ctx.IsToken()
+}
+
+// makerLogMatch compares MakerTx(txId, from, to, remoteChainId, value, destValue, data) log with ctx
+func makerLogMatch(v *types.Log, ctx *CrossTransaction) bool {
+	if len(v.Data) < common.HashLength*6 {
+		return false
+	}
+	var from, to common.Address
+	copy(from[:], v.Topics[2][common.HashLength-common.AddressLength:])
+	copy(to[:], v.Data[common.HashLength-common.AddressLength:common.HashLength])
+	count := common.BytesToHash(v.Data[common.HashLength*5 : common.HashLength*6]).Big().Uint64()
+	if uint64(len(v.Data)) < common.HashLength*6+count {
+		return false
+	}
+	return from == ctx.Data.From && to == ctx.Data.To &&
+		common.BytesToHash(v.Data[common.HashLength:common.HashLength*2]).Big().Cmp(ctx.Data.DestinationId) == 0 &&
+		common.BytesToHash(v.Data[common.HashLength*2:common.HashLength*3]).Big().Cmp(ctx.Data.Value) == 0 &&
+		common.BytesToHash(v.Data[common.HashLength*3:common.HashLength*4]).Big().Cmp(ctx.Data.DestinationValue) == 0 &&
+		bytes.Equal(v.Data[common.HashLength*6:common.HashLength*6+count], ctx.Data.Input)
+}
@@ -0,0 +1,98 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/params"
+)
+
+func makerLog(contract common.Address, ctx *CrossTransaction) *types.Log {
+	var data []byte
+	data = append(data, common.LeftPadBytes(ctx.Data.To.Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(ctx.Data.DestinationId.Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(ctx.Data.Value.Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(ctx.Data.DestinationValue.Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(big.NewInt(192).Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(big.NewInt(int64(len(ctx.Data.Input))).Bytes(), 32)...)
+	data = append(data, ctx.Data.Input...)
+	return &types.Log{
+		Address: contract,
+		Topics:  []common.Hash{params.MakerTopic, ctx.ID(), common.BytesToHash(ctx.Data.From.Bytes())},
+		Data:    data,
+	}
+}
+
+func TestReceiptProof_Verify(t *testing.T) {
+	contract := common.HexToAddress("0x8eefa4bfea64f2a89f3064d48646415168662a1e")
+	ctx := NewCrossTransaction(big.NewInt(1e18), big.NewInt(2e18), big.NewInt(1024),
+		common.HexToHash("0x01"), common.HexToHash("0x02"), common.Hash{},
+		common.HexToAddress("0x095e7baea6a6c7c4c2dfeb977efac326af552d87"), common.Address{}, []byte("input"))
+
+	var receipts types.Receipts
+	for i := 0; i < 20; i++ {
+		receipt := types.NewReceipt(nil, false, uint64(21000*(i+1)))
+		receipt.Logs = []*types.Log{}
+		if i == 7 {
+			receipt.Logs = append(receipt.Logs, &types.Log{Address: contract, Topics: []common.Hash{params.TakerTopic}}, makerLog(contract, ctx))
+		}
+		receipts = append(receipts, receipt)
+	}
+	header := &types.Header{Number: big.NewInt(100), ReceiptHash: types.DeriveSha(receipts)}
+	ctx.Data.BlockHash = header.Hash()
+
+	proof, err := NewReceiptProof(header, receipts, 7, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := proof.Verify(ctx, contract); err != nil {
+		t.Fatal(err)
+	}
+
+	// wrong contract
+	if err := proof.Verify(ctx, common.Address{}); !errors.Is(err, ErrProofMissingLog) {
+		t.Errorf("expected %v, got %v", ErrProofMissingLog, err)
+	}
+	// wrong log index
+	if err := (&ReceiptProof{Header: header, TxIndex: 7, LogIndex: 0, Proof: proof.Proof}).Verify(ctx, contract); !errors.Is(err, ErrProofMissingLog) {
+		t.Errorf("expected %v, got %v", ErrProofMissingLog, err)
+	}
+	// ctx fields are different from the log
+	fake := NewCrossTransaction(big.NewInt(1e18), big.NewInt(1), big.NewInt(1024),
+		ctx.ID(), ctx.Data.TxHash, ctx.BlockHash(), ctx.Data.From, ctx.Data.To, ctx.Data.Input)
+	if err := proof.Verify(fake, contract); !errors.Is(err, ErrProofLogMismatch) {
+		t.Errorf("expected %v, got %v", ErrProofLogMismatch, err)
+	}
+	// proof is not from the block of ctx
+	other := *header
+	other.Number = big.NewInt(101)
+	if err := (&ReceiptProof{Header: &other, TxIndex: 7, LogIndex: 1, Proof: proof.Proof}).Verify(ctx, contract); !errors.Is(err, ErrProofHeaderMismatch) {
+		t.Errorf("expected %v, got %v", ErrProofHeaderMismatch, err)
+	}
+	// proof nodes are not of the receipt
+	if err := (&ReceiptProof{Header: header, TxIndex: 8, LogIndex: 1, Proof: proof.Proof}).Verify(ctx, contract); !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("expected %v, got %v", ErrInvalidProof, err)
+	}
+	// tampered proof nodes
+	tampered := make([][]byte, len(proof.Proof))
+	copy(tampered, proof.Proof)
+	tampered[0] = append([]byte{}, tampered[0]...)
+	tampered[0][len(tampered[0])-1] ^= 0xff
+	if err := (&ReceiptProof{Header: header, TxIndex: 7, LogIndex: 1, Proof: tampered}).Verify(ctx, contract); !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("expected %v, got %v", ErrInvalidProof, err)
+	}
+}
+
+func TestNewReceiptProof_WrongReceipts(t *testing.T) {
+	receipts := types.Receipts{types.NewReceipt(nil, false, 21000)}
+	header := &types.Header{Number: big.NewInt(1), ReceiptHash: types.EmptyRootHash}
+	if _, err := NewReceiptProof(header, receipts, 0, 0); !errors.Is(err, ErrProofMissingReceipt) {
+		t.Errorf("expected %v, got %v", ErrProofMissingReceipt, err)
+	}
+	if _, err := NewReceiptProof(header, receipts, 1, 0); !errors.Is(err, ErrProofMissingReceipt) {
+		t.Errorf("expected %v, got %v", ErrProofMissingReceipt, err)
+	}
+}
@@ -24,6 +24,7 @@ type ReceptTransaction struct {
 	To            common.Address `json:"to" gencodec:"required"`            //Token buyer
 	DestinationId *big.Int       `json:"destinationId" gencodec:"required"` //Message destination networkId
 	ChainId       *big.Int       `json:"chainId" gencodec:"required"`
+	Result        *MessageResult `json:"result,omitempty" rlp:"-"` // execution of message ctx, nil for value swaps
 }
 
 func NewReceptTransaction(id, txHash common.Hash, from, to common.Address, remoteChainId, chainId *big.Int) *ReceptTransaction {
@@ -53,6 +54,21 @@ func (rtx ReceptTransaction) Check(maker *CrossTransactionWithSignatures) error
 	return nil
 }
 
+// CheckCancel checks the recept of takerCancel, it is sent by the maker of ctx to refund itself.
+// The expiry of ctx is checked by anchors before they refund the maker.
+func (rtx ReceptTransaction) CheckCancel(maker *CrossTransactionWithSignatures) error {
+	if maker == nil {
+		return ErrInvalidRecept
+	}
+	if maker.DestinationId().Cmp(rtx.ChainId) != 0 {
+		return ErrChainIdMissMatch
+	}
+	if maker.Data.From != rtx.From || rtx.To != rtx.From {
+		return ErrFromMissMatch
+	}
+	return nil
+}
+
 type Recept struct {
 	TxId   common.Hash
 	TxHash common.Hash
@@ -62,11 +78,19 @@ type Recept struct {
 }
 
 func (rtx *ReceptTransaction) ConstructData(crossContract abi.ABI) ([]byte, error) {
-	rep := Recept{
+	return crossContract.Pack("makerFinish", rtx.recept(), rtx.ChainId)
+}
+
+// ConstructCancelData packs the makerCancel call of a takerCancel recept, which refunds the maker
+func (rtx *ReceptTransaction) ConstructCancelData(crossContract abi.ABI) ([]byte, error) {
+	return crossContract.Pack("makerCancel", rtx.recept(), rtx.ChainId)
+}
+
+func (rtx *ReceptTransaction) recept() Recept {
+	return Recept{
 		TxId:   rtx.CTxId,
 		TxHash: rtx.TxHash,
 		From:   rtx.From,
 		To:     rtx.To,
 	}
-	return crossContract.Pack("makerFinish", rep, rtx.ChainId)
 }
@@ -0,0 +1,261 @@
+package crosstest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"gbchain-org/go-gbchain/accounts"
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/common/math"
+	"gbchain-org/go-gbchain/core"
+	"gbchain-org/go-gbchain/core/state"
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/core/vm"
+	"gbchain-org/go-gbchain/cross"
+	"gbchain-org/go-gbchain/cross/backend"
+	crossdb "gbchain-org/go-gbchain/cross/database"
+	"gbchain-org/go-gbchain/cross/trigger/simpletrigger"
+	"gbchain-org/go-gbchain/cross/trigger/simpletrigger/executor"
+	"gbchain-org/go-gbchain/cross/trigger/simpletrigger/retriever"
+	"gbchain-org/go-gbchain/cross/trigger/simpletrigger/subscriber"
+	"gbchain-org/go-gbchain/crypto"
+	"gbchain-org/go-gbchain/eth/gasprice"
+	"gbchain-org/go-gbchain/ethclient/crossclient"
+	"gbchain-org/go-gbchain/internal/ethapi"
+	"gbchain-org/go-gbchain/node"
+	"gbchain-org/go-gbchain/p2p"
+	"gbchain-org/go-gbchain/p2p/enode"
+	"gbchain-org/go-gbchain/params"
+	"gbchain-org/go-gbchain/rpc"
+)
+
+// Anchor is an anchor node running the cross service of the chain pair, it follows
+// both chains by replicas and sends its transactions to the pending ones of chains.
+type Anchor struct {
+	Key     *ecdsa.PrivateKey
+	Address common.Address
+
+	node    *node.Node
+	service *backend.CrossService
+	chains  map[uint64]*anchorChain
+}
+
+// newAnchor starts the anchor node of key in datadir, the anchors and contracts are
+// registered in main and sub before.
+func newAnchor(key *ecdsa.PrivateKey, datadir string, main, sub *Chain, anchors []common.Address, confirmations []cross.ConfirmPolicy) (*Anchor, error) {
+	a := &Anchor{
+		Key:     key,
+		Address: crypto.PubkeyToAddress(key.PublicKey),
+		chains:  make(map[uint64]*anchorChain),
+	}
+	for _, chain := range []*Chain{main, sub} {
+		replica, err := chain.newReplica()
+		if err != nil {
+			return nil, err
+		}
+		a.chains[chain.ChainID().Uint64()] = newAnchorChain(chain, replica)
+	}
+	n, err := node.New(&node.Config{
+		DataDir: datadir,
+		NoUSB:   true,
+		P2P: p2p.Config{
+			PrivateKey:  key,
+			MaxPeers:    len(anchors),
+			NoDiscovery: true,
+			NoDial:      true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	config := cross.DefaultConfig
+	config.MainContract, config.SubContract = main.Contract(), sub.Contract()
+	config.Signer = a.Address
+	config.Anchors = anchors
+	config.Confirmations = confirmations
+	err = n.Register(func(ctx *node.ServiceContext) (node.Service, error) {
+		signer := cross.NewKeySigner(key)
+		mainCtx, err := a.chains[main.ChainID().Uint64()].serviceContext(ctx, config, signer, "mainqueue")
+		if err != nil {
+			return nil, err
+		}
+		subCtx, err := a.chains[sub.ChainID().Uint64()].serviceContext(ctx, config, signer, "subqueue")
+		if err != nil {
+			return nil, err
+		}
+		a.service, err = backend.NewCrossService(ctx, []cross.ServicePair{{Main: mainCtx, Sub: subCtx}}, config)
+		return a.service, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := n.Start(); err != nil {
+		return nil, err
+	}
+	a.node = n
+	return a, nil
+}
+
+// Service returns the cross service of anchor.
+func (a *Anchor) Service() *backend.CrossService {
+	return a.service
+}
+
+// ID returns the node id of anchor, which is known by the other anchors.
+func (a *Anchor) ID() enode.ID {
+	return enode.PubkeyToIDV4(&a.Key.PublicKey)
+}
+
+// Admin returns the client of admin apis of anchor.
+func (a *Anchor) Admin() (*crossclient.Client, error) {
+	client, err := a.node.Attach()
+	if err != nil {
+		return nil, err
+	}
+	return crossclient.NewClient(client), nil
+}
+
+// Client returns the client of the public cross apis registered in the chain of chainID.
+func (a *Anchor) Client(chainID uint64) (*crossclient.Client, error) {
+	chain, ok := a.chains[chainID]
+	if !ok {
+		return nil, fmt.Errorf("chain %d is not followed by anchor", chainID)
+	}
+	return crossclient.NewClient(rpc.DialInProc(chain.server)), nil
+}
+
+// connect runs the anchor protocol between a and b over a message pipe, it returns the
+// pipe which disconnects them when closed.
+func (a *Anchor) connect(b *Anchor) *p2p.MsgPipeRW {
+	ar, br := p2p.MsgPipe()
+	run := func(local, remote *Anchor, rw p2p.MsgReadWriter) {
+		protocol := local.service.Protocols()[0]
+		peer := p2p.NewPeer(remote.ID(), remote.Address.String(), []p2p.Cap{{Name: protocol.Name, Version: protocol.Version}})
+		protocol.Run(peer, rw)
+	}
+	go run(a, b, ar)
+	go run(b, a, br)
+	return ar
+}
+
+// stop stops the anchor node, the replicas are stopped with chains.
+func (a *Anchor) stop() error {
+	return a.node.Stop()
+}
+
+// anchorChain is the chain seen by anchor, which implements simpletrigger.GBChain by the
+// replica of anchor and the transaction pool of chain.
+type anchorChain struct {
+	*Chain
+	replica *core.BlockChain
+	oracle  *gasprice.Oracle
+	manager *accounts.Manager
+	server  *rpc.Server
+}
+
+func newAnchorChain(chain *Chain, replica *core.BlockChain) *anchorChain {
+	c := &anchorChain{
+		Chain:   chain,
+		replica: replica,
+		manager: accounts.NewManager(&accounts.Config{}),
+		server:  rpc.NewServer(),
+	}
+	c.oracle = gasprice.NewOracle(&oracleBackend{chain: c}, gasprice.Config{Blocks: 1, Default: big.NewInt(params.GWei)})
+	return c
+}
+
+// serviceContext creates the cross context of chain like the credit chains of gbchain.
+func (c *anchorChain) serviceContext(node *node.ServiceContext, config cross.Config, signer *cross.KeySigner, queue string) (*cross.ServiceContext, error) {
+	edb, err := crossdb.OpenEtherDB(node, queue)
+	if err != nil {
+		return nil, err
+	}
+	qdb, err := crossdb.NewQueueDB(edb)
+	if err != nil {
+		return nil, err
+	}
+	confirmer, err := simpletrigger.NewConfirmer(config.ConfirmPolicy(c.ChainID()), c.replica, c.ChainConfig())
+	if err != nil {
+		return nil, err
+	}
+	ctx := &cross.ServiceContext{ProtocolChain: simpletrigger.NewSimpleProtocolChain(c), Config: &config, Contract: c.Contract()}
+	ctx.Executor, err = executor.NewSimpleExecutor(c, config.Signer, signer, c.Contract(), qdb, executor.BumpConfig{})
+	if err != nil {
+		return nil, err
+	}
+	ctx.Retriever = retriever.NewSimpleRetriever(c.replica, c.Chain, c.Contract(), ctx.Config, c.ChainConfig(), confirmer)
+	ctx.Subscriber = subscriber.NewSimpleSubscriber(c.Contract(), c.replica, confirmer, edb)
+	return ctx, nil
+}
+
+func (c *anchorChain) BlockChain() *core.BlockChain {
+	return c.replica
+}
+
+func (c *anchorChain) GasOracle() *gasprice.Oracle {
+	return c.oracle
+}
+
+func (c *anchorChain) ProtocolManager() simpletrigger.ProtocolManager {
+	return c.Chain
+}
+
+func (c *anchorChain) AccountManager() *accounts.Manager {
+	return c.manager
+}
+
+func (c *anchorChain) RegisterAPIs(apis []rpc.API) {
+	for _, api := range apis {
+		if err := c.server.RegisterName(api.Namespace, api.Service); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func (c *anchorChain) GetEVM(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header, vmCfg vm.Config) (*vm.EVM, func() error, error) {
+	state.SetBalance(msg.From(), math.MaxBig256)
+	context := core.NewEVMContext(msg, header, c.replica, nil)
+	return vm.NewEVM(context, state, c.ChainConfig(), vmCfg), func() error { return nil }, nil
+}
+
+func (c *anchorChain) StateAndHeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*state.StateDB, *types.Header, error) {
+	header := c.headerByNumber(blockNr)
+	if header == nil {
+		return nil, nil, fmt.Errorf("header %d not found", blockNr)
+	}
+	statedb, err := c.replica.StateAt(header.Root)
+	return statedb, header, err
+}
+
+// headerByNumber returns the header of replica, the pending block is the head.
+func (c *anchorChain) headerByNumber(blockNr rpc.BlockNumber) *types.Header {
+	if blockNr == rpc.LatestBlockNumber || blockNr == rpc.PendingBlockNumber {
+		return c.replica.CurrentHeader()
+	}
+	return c.replica.GetHeaderByNumber(uint64(blockNr))
+}
+
+// oracleBackend serves the gas price oracle by the replica of anchor.
+type oracleBackend struct {
+	ethapi.Backend
+	chain *anchorChain
+}
+
+func (b *oracleBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	return b.chain.headerByNumber(number), nil
+}
+
+func (b *oracleBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
+	header := b.chain.headerByNumber(number)
+	if header == nil {
+		return nil, nil
+	}
+	return b.chain.replica.GetBlock(header.Hash(), header.Number.Uint64()), nil
+}
+
+func (b *oracleBackend) ChainConfig() *params.ChainConfig {
+	return b.chain.ChainConfig()
+}
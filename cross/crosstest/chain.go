@@ -0,0 +1,476 @@
+package crosstest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"gbchain-org/go-gbchain"
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/common/math"
+	"gbchain-org/go-gbchain/consensus"
+	"gbchain-org/go-gbchain/consensus/ethash"
+	"gbchain-org/go-gbchain/core"
+	"gbchain-org/go-gbchain/core/rawdb"
+	"gbchain-org/go-gbchain/core/state"
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/core/vm"
+	"gbchain-org/go-gbchain/ethdb"
+	"gbchain-org/go-gbchain/params"
+
+	"gbchain-org/go-gbchain/accounts/abi/bind"
+	"gbchain-org/go-gbchain/cross/contract/crossdemo"
+	"gbchain-org/go-gbchain/cross/trigger/simpletrigger"
+)
+
+const (
+	blockPeriod   = 10 // seconds between the timestamps of mined blocks
+	blockGasLimit = 30000000
+)
+
+var (
+	errNoLogFilter         = errors.New("log filtering is not supported, read the receipts instead")
+	errGasEstimationFailed = errors.New("gas required exceeds allowance or always failing transaction")
+)
+
+// This nil assignment ensures compile time that Chain implements bind.ContractBackend.
+var _ bind.ContractBackend = (*Chain)(nil)
+
+// Chain is an in-memory chain whose blocks are mined on demand by Commit, like the
+// SimulatedBackend. The anchors follow it by replicas of their own, every mined block
+// is imported into the replicas, so that each anchor sees the chain as a separate node.
+type Chain struct {
+	config *params.ChainConfig
+	engine consensus.Engine
+	gspec  *core.Genesis
+	db     ethdb.Database
+	chain  *core.BlockChain
+
+	replicas []*core.BlockChain
+	pending  []*types.Transaction // transactions waiting to be mined, a tiny txpool
+
+	contract  common.Address // cross contract deployed by harness
+	crossDemo *crossdemo.CrossDemo
+
+	mu sync.Mutex
+}
+
+// NewChain creates a chain of chainID, the accounts of alloc are funded in genesis.
+func NewChain(chainID *big.Int, alloc core.GenesisAlloc) (*Chain, error) {
+	config := *params.AllScryptProtocolChanges
+	config.ChainID = new(big.Int).Set(chainID)
+	config.SingularityBlock = common.Big0 // cross contract logs are reported since genesis
+
+	c := &Chain{
+		config: &config,
+		engine: ethash.NewFaker(),
+		gspec:  &core.Genesis{Config: &config, GasLimit: blockGasLimit, Alloc: alloc},
+		db:     rawdb.NewMemoryDatabase(),
+	}
+	c.gspec.MustCommit(c.db)
+	// the states of miner are kept on disk, the reorged forks are generated from them
+	chain, err := core.NewBlockChain(c.db, &core.CacheConfig{TrieDirtyDisabled: true}, c.config, c.engine, vm.Config{}, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.chain = chain
+	return c, nil
+}
+
+// ChainID returns the chain id of chain.
+func (c *Chain) ChainID() *big.Int {
+	return new(big.Int).Set(c.config.ChainID)
+}
+
+// ChainConfig returns the chain config of chain.
+func (c *Chain) ChainConfig() *params.ChainConfig {
+	return c.config
+}
+
+// Blockchain returns the blockchain of miner.
+func (c *Chain) Blockchain() *core.BlockChain {
+	return c.chain
+}
+
+// Contract returns the address of cross contract.
+func (c *Chain) Contract() common.Address {
+	return c.contract
+}
+
+// CrossDemo returns the binding of cross contract.
+func (c *Chain) CrossDemo() *crossdemo.CrossDemo {
+	return c.crossDemo
+}
+
+// Close stops the blockchains of miner and replicas, the cross subscribers of replicas are stopped too.
+func (c *Chain) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, replica := range c.replicas {
+		replica.Stop()
+	}
+	c.chain.Stop()
+}
+
+// newReplica creates a blockchain which follows the chain from its current head.
+func (c *Chain) newReplica() (*core.BlockChain, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	db := rawdb.NewMemoryDatabase()
+	c.gspec.MustCommit(db)
+	replica, err := core.NewBlockChain(db, nil, c.config, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		return nil, err
+	}
+	var blocks []*types.Block
+	for n := uint64(1); n <= c.chain.CurrentBlock().NumberU64(); n++ {
+		blocks = append(blocks, c.chain.GetBlockByNumber(n))
+	}
+	if _, err := replica.InsertChain(blocks); err != nil {
+		replica.Stop()
+		return nil, err
+	}
+	c.replicas = append(c.replicas, replica)
+	return replica, nil
+}
+
+// Commit mines the pending transactions into a new block, the block is imported into
+// the replicas before it returns. The transactions with future nonces are kept pending,
+// the other invalid ones are dropped like txpool.
+func (c *Chain) Commit() *types.Block {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	block, pending, err := c.mine(c.pending)
+	if err != nil {
+		panic(err) // This cannot happen unless the simulator is wrong, fail in that case
+	}
+	c.pending = pending
+	if err := c.insert(types.Blocks{block}); err != nil {
+		panic(err)
+	}
+	return block
+}
+
+// CommitN mines n blocks.
+func (c *Chain) CommitN(n int) {
+	for i := 0; i < n; i++ {
+		c.Commit()
+	}
+}
+
+// Confirm mines the blocks which confirm the logs of current head for anchors.
+func (c *Chain) Confirm() {
+	c.CommitN(simpletrigger.DefaultConfirmDepth)
+}
+
+// Reorg replaces the last depth blocks by a heavier fork of depth+1 empty blocks, the
+// transactions of replaced blocks are dropped and returned, they could be sent again.
+func (c *Chain) Reorg(depth int) (types.Transactions, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	head := c.chain.CurrentBlock().NumberU64()
+	if depth <= 0 || uint64(depth) > head {
+		return nil, fmt.Errorf("invalid reorg depth %d, head is %d", depth, head)
+	}
+	ancestor := c.chain.GetBlockByNumber(head - uint64(depth))
+
+	var dropped types.Transactions
+	for n := ancestor.NumberU64() + 1; n <= head; n++ {
+		dropped = append(dropped, c.chain.GetBlockByNumber(n).Transactions()...)
+	}
+	blocks, _ := core.GenerateChain(c.config, ancestor, c.engine, c.db, depth+1, func(i int, b *core.BlockGen) {
+		b.SetExtra([]byte("reorg")) // differ from the replaced empty blocks
+	})
+	if err := c.insert(blocks); err != nil {
+		return nil, err
+	}
+	if c.chain.CurrentBlock().Hash() != blocks[len(blocks)-1].Hash() {
+		return nil, errors.New("fork is not canonical")
+	}
+	return dropped, nil
+}
+
+// checkReceipt returns error if tx is not mined or failed.
+func (c *Chain) checkReceipt(tx *types.Transaction) error {
+	receipt, err := c.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		return fmt.Errorf("transaction %s is not mined: %v", tx.Hash().String(), err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("transaction %s failed", tx.Hash().String())
+	}
+	return nil
+}
+
+// insert imports blocks into the miner and replicas.
+func (c *Chain) insert(blocks types.Blocks) error {
+	if _, err := c.chain.InsertChain(blocks); err != nil {
+		return err
+	}
+	for _, replica := range c.replicas {
+		if _, err := replica.InsertChain(blocks); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mine assembles a block of txs on top of head, the transactions with future nonces are returned.
+func (c *Chain) mine(txs []*types.Transaction) (*types.Block, []*types.Transaction, error) {
+	parent := c.chain.CurrentBlock()
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number(), common.Big1),
+		GasLimit:   parent.GasLimit(),
+		Time:       parent.Time() + blockPeriod,
+	}
+	header.Difficulty = c.engine.CalcDifficulty(c.chain, header.Time, parent.Header())
+
+	statedb, err := c.chain.StateAt(parent.Root())
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.SliceStable(txs, func(i, j int) bool { return txs[i].Nonce() < txs[j].Nonce() })
+	var (
+		gp       = new(core.GasPool).AddGas(header.GasLimit)
+		included []*types.Transaction
+		receipts []*types.Receipt
+		future   []*types.Transaction
+	)
+	for _, tx := range txs {
+		statedb.Prepare(tx.Hash(), common.Hash{}, len(included))
+		snap := statedb.Snapshot()
+		receipt, err := core.ApplyTransaction(c.config, c.chain, &header.Coinbase, gp, statedb, header, tx, &header.GasUsed, vm.Config{})
+		switch err {
+		case nil:
+			included, receipts = append(included, tx), append(receipts, receipt)
+		case core.ErrNonceTooHigh, core.ErrGasLimitReached:
+			statedb.RevertToSnapshot(snap)
+			future = append(future, tx)
+		default:
+			statedb.RevertToSnapshot(snap)
+		}
+	}
+	block, err := c.engine.FinalizeAndAssemble(c.chain, header, statedb, included, nil, receipts)
+	return block, future, err
+}
+
+// pendingNonce returns the next nonce of account after its pending transactions.
+func (c *Chain) pendingNonce(account common.Address) (uint64, error) {
+	statedb, err := c.chain.State()
+	if err != nil {
+		return 0, err
+	}
+	var (
+		signer = types.MakeSigner(c.config)
+		nonce  = statedb.GetNonce(account)
+	)
+	for _, tx := range c.pending {
+		if from, _ := types.Sender(signer, tx); from == account && tx.Nonce() >= nonce {
+			nonce = tx.Nonce() + 1
+		}
+	}
+	return nonce, nil
+}
+
+// add adds tx into the pending transactions, it replaces the pending one with the same nonce.
+func (c *Chain) add(tx *types.Transaction) error {
+	signer := types.MakeSigner(c.config)
+	from, err := types.Sender(signer, tx)
+	if err != nil {
+		return fmt.Errorf("invalid transaction: %v", err)
+	}
+	statedb, err := c.chain.State()
+	if err != nil {
+		return err
+	}
+	if tx.Nonce() < statedb.GetNonce(from) {
+		return core.ErrNonceTooLow
+	}
+	for i, pending := range c.pending {
+		if sender, _ := types.Sender(signer, pending); sender == from && pending.Nonce() == tx.Nonce() {
+			c.pending[i] = tx
+			return nil
+		}
+	}
+	c.pending = append(c.pending, tx)
+	return nil
+}
+
+// ProtocolManager of anchors, the transactions of executors are sent to the pending ones
+
+func (c *Chain) NetworkId() uint64 {
+	return c.config.ChainID.Uint64()
+}
+
+func (c *Chain) GetNonce(address common.Address) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	nonce, _ := c.pendingNonce(address)
+	return nonce
+}
+
+func (c *Chain) AddLocals(txs []*types.Transaction) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, tx := range txs {
+		c.add(tx)
+	}
+}
+
+func (c *Chain) Pending() (map[common.Address]types.Transactions, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	signer := types.MakeSigner(c.config)
+	pending := make(map[common.Address]types.Transactions)
+	for _, tx := range c.pending {
+		from, _ := types.Sender(signer, tx)
+		pending[from] = append(pending[from], tx)
+	}
+	return pending, nil
+}
+
+func (c *Chain) CanAcceptTxs() bool {
+	return true
+}
+
+// ContractBackend, the contracts are called on the state of head
+
+// CodeAt returns the code associated with a certain account in the blockchain.
+func (c *Chain) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	statedb, _, err := c.stateAt(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return statedb.GetCode(contract), nil
+}
+
+// PendingCodeAt returns the code associated with an account at head, the pending contracts are not deployed.
+func (c *Chain) PendingCodeAt(ctx context.Context, contract common.Address) ([]byte, error) {
+	return c.CodeAt(ctx, contract, nil)
+}
+
+// BalanceAt returns the wei balance of a certain account in the blockchain.
+func (c *Chain) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	statedb, _, err := c.stateAt(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return statedb.GetBalance(account), nil
+}
+
+// CallContract executes a contract call.
+func (c *Chain) CallContract(ctx context.Context, call gbchian.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	statedb, header, err := c.stateAt(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	res, _, failed, err := c.callContract(call, header, statedb)
+	if err == nil && failed {
+		err = errors.New("execution reverted")
+	}
+	return res, err
+}
+
+// PendingNonceAt returns the nonce of account after its pending transactions.
+func (c *Chain) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pendingNonce(account)
+}
+
+// SuggestGasPrice returns the gas price accepted by the executors of anchors.
+func (c *Chain) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(params.GWei), nil
+}
+
+// EstimateGas executes the call against head and returns the lowest gas allowance it succeeds with.
+func (c *Chain) EstimateGas(ctx context.Context, call gbchian.CallMsg) (uint64, error) {
+	statedb, header, err := c.stateAt(nil)
+	if err != nil {
+		return 0, err
+	}
+	lo, hi := params.TxGas-1, header.GasLimit
+	if call.Gas >= params.TxGas {
+		hi = call.Gas
+	}
+	cap := hi
+	executable := func(gas uint64) bool {
+		call.Gas = gas
+		snapshot := statedb.Snapshot()
+		_, _, failed, err := c.callContract(call, header, statedb)
+		statedb.RevertToSnapshot(snapshot)
+		return err == nil && !failed
+	}
+	for lo+1 < hi {
+		mid := (hi + lo) / 2
+		if !executable(mid) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	if hi == cap && !executable(hi) {
+		return 0, errGasEstimationFailed
+	}
+	return hi, nil
+}
+
+// SendTransaction adds tx into the pending transactions, which are mined by Commit.
+func (c *Chain) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.add(tx)
+}
+
+// TransactionReceipt returns the receipt of a mined transaction.
+func (c *Chain) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	receipt, _, _, _ := rawdb.ReadReceipt(c.db, txHash, c.config)
+	if receipt == nil {
+		return nil, gbchian.NotFound
+	}
+	return receipt, nil
+}
+
+func (c *Chain) FilterLogs(ctx context.Context, query gbchian.FilterQuery) ([]types.Log, error) {
+	return nil, errNoLogFilter
+}
+
+func (c *Chain) SubscribeFilterLogs(ctx context.Context, query gbchian.FilterQuery, ch chan<- types.Log) (gbchian.Subscription, error) {
+	return nil, errNoLogFilter
+}
+
+// stateAt returns the state of block number, nil for head.
+func (c *Chain) stateAt(number *big.Int) (*state.StateDB, *types.Header, error) {
+	header := c.chain.CurrentHeader()
+	if number != nil {
+		if header = c.chain.GetHeaderByNumber(number.Uint64()); header == nil {
+			return nil, nil, gbchian.NotFound
+		}
+	}
+	statedb, err := c.chain.StateAt(header.Root)
+	return statedb, header, err
+}
+
+// callContract executes call on statedb, which is modified during execution.
+func (c *Chain) callContract(call gbchian.CallMsg, header *types.Header, statedb *state.StateDB) ([]byte, uint64, bool, error) {
+	if call.GasPrice == nil {
+		call.GasPrice = big.NewInt(1)
+	}
+	if call.Gas == 0 {
+		call.Gas = header.GasLimit
+	}
+	if call.Value == nil {
+		call.Value = new(big.Int)
+	}
+	statedb.SetBalance(call.From, math.MaxBig256)
+	msg := types.NewMessage(call.From, call.To, 0, call.Value, call.Gas, call.GasPrice, call.Data, false)
+	evm := vm.NewEVM(core.NewEVMContext(msg, header, c.chain, nil), statedb, c.config, vm.Config{})
+	return core.NewStateTransition(evm, msg, new(core.GasPool).AddGas(math.MaxUint64)).TransitionDb()
+}
@@ -0,0 +1,344 @@
+// Package crosstest runs the cross service of a main chain and a sub chain in process,
+// the chains are mined on demand and the anchors are connected by message pipes. It is
+// used to test the whole life of ctxs, from maker to finish, including chain reorgs.
+//
+// The anchors are connected by p2p.MsgPipe running the anchor protocol directly, as the
+// protocol tests of eth, les and cross/backend do, instead of by the p2p/simulations
+// adapters. The adapters boot their own nodes from services registered process-wide by
+// name, which could only reach the chains and keys of a harness through globals, and
+// several harnesses of a test binary would register the same services again. The anchor
+// protocol does not depend on the RLPx transport, which is what the adapters add.
+package crosstest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gbchain-org/go-gbchain"
+	"gbchain-org/go-gbchain/accounts/abi/bind"
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/core"
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/cross"
+	"gbchain-org/go-gbchain/cross/backend"
+	"gbchain-org/go-gbchain/cross/contract/crossdemo"
+	cc "gbchain-org/go-gbchain/cross/core"
+	"gbchain-org/go-gbchain/crypto"
+	"gbchain-org/go-gbchain/p2p"
+	"gbchain-org/go-gbchain/params"
+)
+
+const pollInterval = 50 * time.Millisecond
+
+var (
+	defaultBalance  = new(big.Int).Mul(big.NewInt(1e6), big.NewInt(params.Ether))
+	defaultMaxValue = new(big.Int).Mul(big.NewInt(1e6), big.NewInt(params.Ether))
+)
+
+// Config is the configuration of harness, the zero values are replaced by defaults.
+type Config struct {
+	MainID     *big.Int // chain id of main chain, 1 by default
+	SubID      *big.Int // chain id of sub chain, 2 by default
+	Anchors    int      // number of anchors, 3 by default
+	Signatures int      // signatures required by ctxs, 2 by default
+	Accounts   int      // number of funded accounts making and taking ctxs, 2 by default
+
+	Confirmations []cross.ConfirmPolicy // confirmation policies of chains used by anchors
+}
+
+func (config *Config) sanitize() {
+	if config.MainID == nil {
+		config.MainID = big.NewInt(1)
+	}
+	if config.SubID == nil {
+		config.SubID = big.NewInt(2)
+	}
+	if config.Anchors <= 0 {
+		config.Anchors = 3
+	}
+	if config.Signatures <= 0 {
+		config.Signatures = 2
+	}
+	if config.Signatures > config.Anchors {
+		config.Signatures = config.Anchors
+	}
+	if config.Accounts <= 0 {
+		config.Accounts = 2
+	}
+}
+
+// Harness is a chain pair bridged by the anchors running in process.
+type Harness struct {
+	Main     *Chain
+	Sub      *Chain
+	Owner    *ecdsa.PrivateKey   // owner of cross contracts
+	Accounts []*ecdsa.PrivateKey // funded accounts in both chains
+	Anchors  []*Anchor
+
+	datadir string
+	pipes   []*p2p.MsgPipeRW
+}
+
+// New creates the chain pair of config, deploys and registers the cross contracts, and
+// starts the anchors connected with each other.
+func New(config Config) (h *Harness, err error) {
+	config.sanitize()
+	h = new(Harness)
+	defer func() {
+		if err != nil {
+			h.Close()
+		}
+	}()
+	if h.datadir, err = ioutil.TempDir("", "crosstest"); err != nil {
+		return nil, err
+	}
+
+	var (
+		alloc   = make(core.GenesisAlloc)
+		anchors = make([]*ecdsa.PrivateKey, config.Anchors)
+		addrs   = make([]common.Address, config.Anchors)
+		fund    = func() (*ecdsa.PrivateKey, common.Address) {
+			key, _ := crypto.GenerateKey()
+			addr := crypto.PubkeyToAddress(key.PublicKey)
+			alloc[addr] = core.GenesisAccount{Balance: defaultBalance}
+			return key, addr
+		}
+	)
+	h.Owner, _ = fund()
+	for i := 0; i < config.Accounts; i++ {
+		key, _ := fund()
+		h.Accounts = append(h.Accounts, key)
+	}
+	for i := range anchors {
+		anchors[i], addrs[i] = fund()
+	}
+
+	if h.Main, err = NewChain(config.MainID, alloc); err != nil {
+		return nil, err
+	}
+	if h.Sub, err = NewChain(config.SubID, alloc); err != nil {
+		return nil, err
+	}
+	for _, chain := range []*Chain{h.Main, h.Sub} {
+		if err := h.deploy(chain, h.Remote(chain).ChainID(), uint8(config.Signatures), addrs); err != nil {
+			return nil, fmt.Errorf("deploy contract in chain %d failed: %v", chain.ChainID(), err)
+		}
+	}
+
+	for i, key := range anchors {
+		anchor, err := newAnchor(key, filepath.Join(h.datadir, fmt.Sprintf("anchor%d", i)), h.Main, h.Sub, addrs, config.Confirmations)
+		if err != nil {
+			return nil, err
+		}
+		h.Anchors = append(h.Anchors, anchor)
+	}
+	for i, a := range h.Anchors {
+		for _, b := range h.Anchors[i+1:] {
+			h.pipes = append(h.pipes, a.connect(b))
+		}
+	}
+	return h, h.waitPeers(5 * time.Second)
+}
+
+// deploy deploys the cross contract in chain and registers the remote chain.
+func (h *Harness) deploy(chain *Chain, remoteID *big.Int, signatures uint8, anchors []common.Address) error {
+	opts := bind.NewKeyedTransactor(h.Owner)
+	addr, _, contract, err := crossdemo.DeployCrossDemo(opts, chain)
+	if err != nil {
+		return err
+	}
+	chain.Commit()
+	tx, err := contract.ChainRegister(opts, remoteID, defaultMaxValue, signatures, anchors)
+	if err != nil {
+		return err
+	}
+	chain.Commit()
+	if err := chain.checkReceipt(tx); err != nil {
+		return err
+	}
+	chain.contract, chain.crossDemo = addr, contract
+	return nil
+}
+
+// waitPeers waits until every anchor is connected with all the others.
+func (h *Harness) waitPeers(timeout time.Duration) error {
+	return poll(timeout, func() (bool, error) {
+		for _, anchor := range h.Anchors {
+			admin, err := anchor.Admin()
+			if err != nil {
+				return false, err
+			}
+			peers, err := admin.Peers(context.Background())
+			admin.Close()
+			if err != nil {
+				return false, err
+			}
+			if len(peers) < len(h.Anchors)-1 {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+}
+
+// Remote returns the other chain of the chain pair.
+func (h *Harness) Remote(chain *Chain) *Chain {
+	if chain == h.Main {
+		return h.Sub
+	}
+	return h.Main
+}
+
+// Maker sends the maker transaction of key in chain, which pays value for destValue paid
+// in the remote chain. The transaction is mined but not confirmed, the id of ctx is returned.
+func (h *Harness) Maker(chain *Chain, key *ecdsa.PrivateKey, value, destValue *big.Int) (common.Hash, error) {
+	opts := bind.NewKeyedTransactor(key)
+	opts.Value = value
+	tx, err := chain.CrossDemo().MakerStart(opts, h.Remote(chain).ChainID(), destValue, common.Address{}, nil)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	chain.Commit()
+	receipt, err := chain.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		return common.Hash{}, err
+	}
+	for _, log := range receipt.Logs {
+		if len(log.Topics) > 1 && log.Topics[0] == params.MakerTopic {
+			return log.Topics[1], nil
+		}
+	}
+	return common.Hash{}, errors.New("maker log is not found")
+}
+
+// Ctx returns the ctx made in chain from the store of anchor.
+func (h *Harness) Ctx(anchor *Anchor, chain *Chain, id common.Hash) (*backend.RPCCrossTransaction, error) {
+	client, err := anchor.Client(chain.ChainID().Uint64())
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	return client.CtxGet(context.Background(), id, h.Remote(chain).ChainID())
+}
+
+// WaitCtx waits until the ctx made in chain reaches status in all anchors.
+func (h *Harness) WaitCtx(chain *Chain, id common.Hash, status cc.CtxStatus, timeout time.Duration) error {
+	var last cc.CtxStatus
+	err := poll(timeout, func() (bool, error) {
+		for _, anchor := range h.Anchors {
+			rtx, err := h.Ctx(anchor, chain, id)
+			if err == gbchian.NotFound {
+				return false, nil
+			}
+			if err != nil {
+				return false, err
+			}
+			if last = rtx.Status; last != status {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("ctx %s is not %s but %s: %v", id.String(), status, last, err)
+	}
+	return nil
+}
+
+// Taker takes the waiting ctx made in chain by key in the remote chain, the taker
+// transaction is mined but not confirmed.
+func (h *Harness) Taker(chain *Chain, key *ecdsa.PrivateKey, id common.Hash) (*types.Transaction, error) {
+	rtx, err := h.Ctx(h.Anchors[0], chain, id)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		remote = h.Remote(chain)
+		v      = make([]*big.Int, len(rtx.V))
+		r, s   = make([][32]byte, len(rtx.R)), make([][32]byte, len(rtx.S))
+		opts   = bind.NewKeyedTransactor(key)
+	)
+	for i := range rtx.V {
+		v[i], r[i], s[i] = rtx.V[i].ToInt(), common.BigToHash(rtx.R[i].ToInt()), common.BigToHash(rtx.S[i].ToInt())
+	}
+	opts.Value = rtx.DestinationValue.ToInt()
+	tx, err := remote.CrossDemo().Taker(opts, crossdemo.CrossDemoOrder{
+		Value:            rtx.Value.ToInt(),
+		TxId:             rtx.CTxId,
+		TxHash:           rtx.TxHash,
+		From:             rtx.From,
+		To:               rtx.To,
+		BlockHash:        rtx.BlockHash,
+		DestinationValue: rtx.DestinationValue.ToInt(),
+		Data:             rtx.Input,
+		V:                v,
+		R:                r,
+		S:                s,
+	}, chain.ChainID())
+	if err != nil {
+		return nil, err
+	}
+	remote.Commit()
+	return tx, remote.checkReceipt(tx)
+}
+
+// Finish confirms the taker of ctx made in chain, then mines the chain until the
+// makerFinish transactions of anchors are confirmed.
+func (h *Harness) Finish(chain *Chain, id common.Hash, timeout time.Duration) error {
+	h.Remote(chain).Confirm()
+	deadline := time.Now().Add(timeout)
+	for {
+		rtx, err := h.Ctx(h.Anchors[0], chain, id)
+		if err != nil && err != gbchian.NotFound {
+			return err
+		}
+		if rtx != nil && rtx.Status == cc.CtxStatusFinished {
+			return h.WaitCtx(chain, id, cc.CtxStatusFinished, time.Until(deadline))
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("ctx %s is not finished", id.String())
+		}
+		chain.Commit()
+		time.Sleep(pollInterval)
+	}
+}
+
+// Close disconnects and stops the anchors, then stops the chains.
+func (h *Harness) Close() {
+	for _, pipe := range h.pipes {
+		pipe.Close()
+	}
+	for _, anchor := range h.Anchors {
+		anchor.stop()
+	}
+	for _, chain := range []*Chain{h.Main, h.Sub} {
+		if chain != nil {
+			chain.Close()
+		}
+	}
+	if h.datadir != "" {
+		os.RemoveAll(h.datadir)
+	}
+}
+
+// poll calls fn every pollInterval until it is done or timeout.
+func poll(timeout time.Duration, fn func() (bool, error)) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		done, err := fn()
+		if err != nil || done {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return errors.New("timeout")
+		}
+		time.Sleep(pollInterval)
+	}
+}
@@ -0,0 +1,379 @@
+package crosstest
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"gbchain-org/go-gbchain"
+	"gbchain-org/go-gbchain/accounts/abi"
+	"gbchain-org/go-gbchain/accounts/abi/bind"
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/cross"
+	"gbchain-org/go-gbchain/cross/contract/crossdemo"
+	cc "gbchain-org/go-gbchain/cross/core"
+	"gbchain-org/go-gbchain/cross/trigger/simpletrigger/retriever"
+	"gbchain-org/go-gbchain/crypto"
+	"gbchain-org/go-gbchain/crypto/bls"
+	"gbchain-org/go-gbchain/params"
+)
+
+const testTimeout = 20 * time.Second
+
+var (
+	testValue     = big.NewInt(params.Ether)
+	testDestValue = big.NewInt(2 * params.Ether)
+)
+
+func newTestHarness(t *testing.T) *Harness {
+	h, err := New(Config{})
+	if err != nil {
+		t.Fatalf("can't create harness: %v", err)
+	}
+	return h
+}
+
+func TestMakerTakerFinish(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.Close()
+	maker, taker := h.Accounts[0], h.Accounts[1]
+
+	id, err := h.Maker(h.Main, maker, testValue, testDestValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Main.Confirm()
+	if err := h.WaitCtx(h.Main, id, cc.CtxStatusWaiting, testTimeout); err != nil {
+		t.Fatal(err)
+	}
+
+	before, _ := h.Sub.BalanceAt(context.Background(), crypto.PubkeyToAddress(maker.PublicKey), nil)
+	if _, err := h.Taker(h.Main, taker, id); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.WaitCtx(h.Main, id, cc.CtxStatusExecuting, testTimeout); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Finish(h.Main, id, testTimeout); err != nil {
+		t.Fatal(err)
+	}
+
+	// the maker is paid by taker in the sub chain
+	after, _ := h.Sub.BalanceAt(context.Background(), crypto.PubkeyToAddress(maker.PublicKey), nil)
+	if paid := new(big.Int).Sub(after, before); paid.Cmp(testDestValue) != 0 {
+		t.Errorf("maker paid mismatch: have %v, want %v", paid, testDestValue)
+	}
+}
+
+func TestMakerReorg(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.Close()
+
+	id, err := h.Maker(h.Main, h.Accounts[0], testValue, testDestValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the maker is dropped by reorg before it is confirmed
+	h.Main.CommitN(2)
+	dropped, err := h.Main.Reorg(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dropped) != 1 {
+		t.Fatalf("dropped transactions mismatch: have %d, want 1", len(dropped))
+	}
+	h.Main.Confirm()
+	time.Sleep(time.Second)
+	for _, anchor := range h.Anchors {
+		if _, err := h.Ctx(anchor, h.Main, id); err != gbchian.NotFound {
+			t.Fatalf("ctx of reorged maker is stored: %v", err)
+		}
+	}
+}
+
+func TestRescan(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.Close()
+
+	id, err := h.Maker(h.Main, h.Accounts[0], testValue, testDestValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Main.Confirm()
+	if err := h.WaitCtx(h.Main, id, cc.CtxStatusWaiting, testTimeout); err != nil {
+		t.Fatal(err)
+	}
+
+	// the replayed maker doesn't change the stored ctx
+	admin, err := h.Anchors[0].Admin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer admin.Close()
+	if err := admin.Rescan(context.Background(), h.Main.ChainID(), 1); err != nil {
+		t.Fatalf("rescan failed: %v", err)
+	}
+	if err := admin.Rescan(context.Background(), big.NewInt(100), 1); err == nil {
+		t.Fatal("rescan of unknown chain succeeded")
+	}
+	h.Main.Confirm()
+	if err := h.WaitCtx(h.Main, id, cc.CtxStatusWaiting, testTimeout); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConfirmPolicy(t *testing.T) {
+	h, err := New(Config{Confirmations: []cross.ConfirmPolicy{{ChainID: 1, Type: cross.ConfirmDepth, Depth: 2}}})
+	if err != nil {
+		t.Fatalf("can't create harness: %v", err)
+	}
+	defer h.Close()
+
+	id, err := h.Maker(h.Main, h.Accounts[0], testValue, testDestValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the maker is confirmed by 2 blocks instead of the default depth
+	h.Main.CommitN(2)
+	if err := h.WaitCtx(h.Main, id, cc.CtxStatusWaiting, testTimeout); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadAnchors(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.Close()
+
+	anchors, required, err := retriever.ReadAnchors(h.Main.db, h.Main.Contract(), h.Sub.ChainID().Uint64())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if required != 2 { // default signatures of harness
+		t.Errorf("required signatures mismatch: have %d, want %d", required, 2)
+	}
+	set := retriever.NewAnchorSet(anchors)
+	for _, anchor := range h.Anchors {
+		if !set.IsAnchor(anchor.Address) {
+			t.Errorf("anchor %s is missing", anchor.Address.String())
+		}
+	}
+	if len(anchors) != len(h.Anchors) {
+		t.Errorf("anchors mismatch: have %d, want %d", len(anchors), len(h.Anchors))
+	}
+	// the chain is not registered
+	if _, _, err := retriever.ReadAnchors(h.Main.db, h.Main.Contract(), 100); err == nil {
+		t.Error("anchors of unregistered chain are read")
+	}
+}
+
+func TestContractMethods(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.Close()
+
+	parsed, err := abi.JSON(strings.NewReader(crossdemo.CrossDemoABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := h.Main.CodeAt(context.Background(), h.Main.Contract(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// every method of abi is dispatched by the deployed code, the leading zeros of
+	// selectors are trimmed by the compiler
+	for name, method := range parsed.Methods {
+		if !bytes.Contains(code, bytes.TrimLeft(method.ID(), "\x00")) {
+			t.Errorf("method %s is not in the contract code", name)
+		}
+	}
+}
+
+func TestTakerBLS(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.Close()
+
+	// register the bls keys of anchors in the sub chain
+	var (
+		opts = bind.NewKeyedTransactor(h.Owner)
+		keys = make(map[common.Address]*bls.SecretKey)
+		pks  = make(map[common.Address]*bls.PublicKey)
+	)
+	for _, anchor := range h.Anchors {
+		key, err := bls.GenerateKey(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[anchor.Address], pks[anchor.Address] = key, key.PublicKey()
+		g1 := key.PublicKey().G1()
+		tx, err := h.Sub.CrossDemo().SetAnchorBLSKey(opts, h.Main.ChainID(), anchor.Address,
+			[2]*big.Int{new(big.Int).SetBytes(g1[:32]), new(big.Int).SetBytes(g1[32:])})
+		if err != nil {
+			t.Fatal(err)
+		}
+		h.Sub.Commit()
+		if err := h.Sub.checkReceipt(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the ctx made in main chain is signed by the first two anchors
+	maker := crypto.PubkeyToAddress(h.Accounts[0].PublicKey)
+	ctx := cc.NewCrossTransaction(testValue, testDestValue, h.Sub.ChainID(), common.Hash{1}, common.Hash{2}, common.Hash{3}, maker, common.Address{}, nil)
+	var (
+		signer  = cc.NewBLSCtxSigner(h.Main.ChainID(), pks)
+		hash    = signer.Hash(ctx)
+		signers = []common.Address{h.Anchors[0].Address, h.Anchors[1].Address}
+		sigs    []*bls.Signature
+		apks    []*bls.PublicKey
+	)
+	for _, addr := range signers {
+		sigs = append(sigs, keys[addr].Sign(hash[:]))
+		apks = append(apks, pks[addr])
+	}
+	sig, _ := bls.AggregateSignatures(sigs...)
+	apk, _ := bls.AggregatePublicKeys(apks...)
+	if !bls.VerifyAggregated(apk, hash[:], sig) {
+		t.Fatal("aggregated signature is invalid")
+	}
+	words := func(b []byte) []*big.Int {
+		var ws []*big.Int
+		for i := 0; i < len(b); i += 32 {
+			ws = append(ws, new(big.Int).SetBytes(b[i:i+32]))
+		}
+		return ws
+	}
+	sw, pw := words(sig.Marshal()), words(apk.G2())
+
+	opts = bind.NewKeyedTransactor(h.Accounts[1])
+	opts.Value = testDestValue
+	opts.GasLimit = 1000000
+	tx, err := h.Sub.CrossDemo().TakerBLS(opts, crossdemo.CrossDemoBLSOrder{
+		Value:            ctx.Data.Value,
+		TxId:             ctx.Data.CTxId,
+		TxHash:           ctx.Data.TxHash,
+		From:             ctx.Data.From,
+		To:               ctx.Data.To,
+		BlockHash:        ctx.Data.BlockHash,
+		DestinationValue: ctx.Data.DestinationValue,
+		Data:             ctx.Data.Input,
+		Signers:          signers,
+		Sig:              [2]*big.Int{sw[0], sw[1]},
+		Apk:              [4]*big.Int{pw[0], pw[1], pw[2], pw[3]},
+	}, h.Main.ChainID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Sub.Commit()
+	if err := h.Sub.checkReceipt(tx); err != nil {
+		t.Fatal(err)
+	}
+	value, err := h.Sub.CrossDemo().GetTakerTx(nil, ctx.Data.CTxId, maker, h.Main.ChainID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value.Cmp(testValue) != 0 {
+		t.Errorf("taken value mismatch: have %v, want %v", value, testValue)
+	}
+}
+
+// messageOrder returns the message ctx of target made in main chain, which is signed by the anchors
+func messageOrder(t *testing.T, h *Harness, id common.Hash, target common.Address) crossdemo.CrossDemoOrder {
+	msg := &cc.Message{Target: target, GasLimit: 100000, Payload: []byte{1}}
+	input, err := msg.Input()
+	if err != nil {
+		t.Fatal(err)
+	}
+	maker := crypto.PubkeyToAddress(h.Accounts[0].PublicKey)
+	ctx := cc.NewCrossTransaction(testValue, testDestValue, h.Sub.ChainID(), id, common.Hash{2}, common.Hash{3}, maker, common.Address{}, input)
+	order := crossdemo.CrossDemoOrder{
+		Value:            ctx.Data.Value,
+		TxId:             ctx.Data.CTxId,
+		TxHash:           ctx.Data.TxHash,
+		From:             ctx.Data.From,
+		BlockHash:        ctx.Data.BlockHash,
+		DestinationValue: ctx.Data.DestinationValue,
+		Data:             ctx.Data.Input,
+	}
+	signer := cc.NewEIP155CtxSigner(h.Main.ChainID())
+	for _, anchor := range h.Anchors {
+		signed, err := cc.SignCtx(ctx, signer, func(hash []byte) ([]byte, error) { return crypto.Sign(hash, anchor.Key) })
+		if err != nil {
+			t.Fatal(err)
+		}
+		order.V = append(order.V, signed.Data.V)
+		order.R = append(order.R, common.BigToHash(signed.Data.R))
+		order.S = append(order.S, common.BigToHash(signed.Data.S))
+	}
+	return order
+}
+
+func TestTakerMessage(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.Close()
+
+	proxy, err := h.Sub.CrossDemo().MessageProxy(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := bind.NewKeyedTransactor(h.Anchors[0].Key)
+	opts.GasLimit = 1000000
+	take := func(id common.Hash, target common.Address) (*types.Receipt, error) {
+		tx, err := h.Sub.CrossDemo().TakerMessage(opts, messageOrder(t, h, id, target), h.Main.ChainID())
+		if err != nil {
+			t.Fatal(err)
+		}
+		h.Sub.Commit()
+		if err := h.Sub.checkReceipt(tx); err != nil {
+			return nil, err
+		}
+		return h.Sub.TransactionReceipt(context.Background(), tx.Hash())
+	}
+
+	// the message can't call the cross contract or its proxy
+	if _, err := take(common.Hash{1}, h.Sub.Contract()); err == nil {
+		t.Error("message to cross contract is executed")
+	}
+	if _, err := take(common.Hash{2}, proxy); err == nil {
+		t.Error("message to message proxy is executed")
+	}
+
+	// the message can't be taken by taker, or it would never be executed
+	target := common.Address{0xff}
+	takerOpts := bind.NewKeyedTransactor(h.Accounts[1])
+	takerOpts.GasLimit, takerOpts.Value = 1000000, testDestValue
+	tx, err := h.Sub.CrossDemo().Taker(takerOpts, messageOrder(t, h, common.Hash{3}, target), h.Main.ChainID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Sub.Commit()
+	if err := h.Sub.checkReceipt(tx); err == nil {
+		t.Error("message is taken by taker")
+	}
+
+	receipt, err := take(common.Hash{3}, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		executed *crossdemo.CrossDemoMessageExecuted
+		taken    *crossdemo.CrossDemoTakerTx
+	)
+	for _, l := range receipt.Logs {
+		if ev, err := h.Sub.CrossDemo().ParseMessageExecuted(*l); err == nil {
+			executed = ev
+		}
+		if ev, err := h.Sub.CrossDemo().ParseTakerTx(*l); err == nil {
+			taken = ev
+		}
+	}
+	if executed == nil || executed.Target != target || !executed.Success {
+		t.Errorf("message execution mismatch: %+v", executed)
+	}
+	// the maker's value is paid to the executing anchor
+	if anchor := crypto.PubkeyToAddress(h.Anchors[0].Key.PublicKey); taken == nil || taken.To != anchor {
+		t.Errorf("message taker mismatch: %+v, want %s", taken, anchor.String())
+	}
+}
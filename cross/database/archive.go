@@ -0,0 +1,304 @@
+package db
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/ethdb"
+	"gbchain-org/go-gbchain/log"
+	"gbchain-org/go-gbchain/rlp"
+
+	cc "gbchain-org/go-gbchain/cross/core"
+
+	"github.com/golang/snappy"
+)
+
+// offsetSize is the size of index entries, which are the end offsets of items in the data file
+const offsetSize = 8
+
+var (
+	archivePrefix   = []byte("_archive")      // archivePrefix + chainID + ctxID -> item number
+	archiveStatsKey = []byte("_archiveStats") // rlp of ArchiveStats
+
+	errArchiveClosed = errors.New("archive is closed")
+)
+
+// ArchiveStats is the accumulated size of ctxs archived, Size is the bytes the ctxs took in
+// the live index, and Archived is the bytes appended to the archive.
+type ArchiveStats struct {
+	Ctxs     uint64
+	Size     uint64
+	Archived uint64
+}
+
+// CtxArchive is an append-only archive of the finished ctxs moved out of the live index.
+// It is modelled on the freezer tables of rawdb: the snappy compressed RLP of ctxs are
+// appended to the data file, and the index file keeps the end offset of every item after
+// a zero entry. The item numbers of ctxs are kept in the key-value db.
+type CtxArchive struct {
+	db    ethdb.KeyValueStore
+	index *os.File
+	data  *os.File
+	items uint64 // number of items stored
+	size  uint64 // bytes of data file
+	stats ArchiveStats
+
+	logger log.Logger
+	lock   sync.RWMutex
+}
+
+// OpenCtxArchive opens the archive in the directory name of ctx, the item numbers of
+// ctxs are kept in db.
+func OpenCtxArchive(ctx ServiceContext, db ethdb.KeyValueStore, name string) (*CtxArchive, error) {
+	path := filepath.Join(os.TempDir(), name)
+	if ctx != nil && len(ctx.ResolvePath(name)) > 0 {
+		path = ctx.ResolvePath(name)
+	}
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, err
+	}
+	index, err := os.OpenFile(filepath.Join(path, "ctxs.cidx"), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.OpenFile(filepath.Join(path, "ctxs.cdat"), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		index.Close()
+		return nil, err
+	}
+	a := &CtxArchive{db: db, index: index, data: data, logger: log.New("X-module", "archive")}
+	if err := a.repair(); err != nil {
+		a.Close()
+		return nil, fmt.Errorf("open archive %s: %w", path, err)
+	}
+	if enc, err := db.Get(archiveStatsKey); err == nil {
+		if err := rlp.DecodeBytes(enc, &a.stats); err != nil {
+			a.logger.Warn("Failed to decode archive stats", "error", err)
+		}
+	}
+	return a, nil
+}
+
+// repair truncates the index and data files until they are consistent, the items appended
+// partially before crash are dropped.
+func (a *CtxArchive) repair() error {
+	stat, err := a.index.Stat()
+	if err != nil {
+		return err
+	}
+	if stat.Size() == 0 {
+		if _, err := a.index.Write(make([]byte, offsetSize)); err != nil {
+			return err
+		}
+	}
+	entries := stat.Size() / offsetSize
+	if entries == 0 {
+		entries = 1
+	}
+	if stat, err = a.data.Stat(); err != nil {
+		return err
+	}
+	dataSize := uint64(stat.Size())
+
+	// drop the index entries beyond the data file
+	last, err := a.offset(uint64(entries - 1))
+	if err != nil {
+		return err
+	}
+	for last > dataSize {
+		entries--
+		if last, err = a.offset(uint64(entries - 1)); err != nil {
+			return err
+		}
+	}
+	if err := a.index.Truncate(entries * offsetSize); err != nil {
+		return err
+	}
+	// drop the data not indexed
+	if last < dataSize {
+		a.logger.Warn("Truncating dangling archive data", "indexed", common.StorageSize(last), "stored", common.StorageSize(dataSize))
+		if err := a.data.Truncate(int64(last)); err != nil {
+			return err
+		}
+	}
+	a.items, a.size = uint64(entries-1), last
+	return nil
+}
+
+// offset reads the index entry i, which is the end offset of item i-1
+func (a *CtxArchive) offset(i uint64) (uint64, error) {
+	var buf [offsetSize]byte
+	if _, err := a.index.ReadAt(buf[:], int64(i*offsetSize)); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(buf[:]), nil
+}
+
+func archiveKey(chainID *big.Int, id common.Hash) []byte {
+	key := make([]byte, len(archivePrefix)+8+common.HashLength)
+	copy(key, archivePrefix)
+	binary.BigEndian.PutUint64(key[len(archivePrefix):], chainID.Uint64())
+	copy(key[len(archivePrefix)+8:], id.Bytes())
+	return key
+}
+
+// Append archives the ctxs of chain, the files are synced before the ctxs are indexed
+// so the ctxs could be removed from the live index once it returns.
+func (a *CtxArchive) Append(chainID *big.Int, ctxs []*cc.CrossTransactionWithSignatures) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if a.data == nil {
+		return errArchiveClosed
+	}
+	var (
+		batch = a.db.NewBatch()
+		stats = a.stats
+		data  []byte
+		index []byte
+		size  = a.size
+	)
+	for i, ctx := range ctxs {
+		enc, err := rlp.EncodeToBytes(ctx)
+		if err != nil {
+			return err
+		}
+		blob := snappy.Encode(nil, enc)
+		data = append(data, blob...)
+		size += uint64(len(blob))
+
+		var offset [offsetSize]byte
+		binary.BigEndian.PutUint64(offset[:], size)
+		index = append(index, offset[:]...)
+
+		var item [8]byte
+		binary.BigEndian.PutUint64(item[:], a.items+uint64(i))
+		if err := batch.Put(archiveKey(chainID, ctx.ID()), item[:]); err != nil {
+			return err
+		}
+		stats.Ctxs++
+		stats.Size += uint64(len(enc))
+		stats.Archived += uint64(len(blob))
+	}
+	if _, err := a.data.WriteAt(data, int64(a.size)); err != nil {
+		return err
+	}
+	if _, err := a.index.WriteAt(index, int64((a.items+1)*offsetSize)); err != nil {
+		return err
+	}
+	if err := a.data.Sync(); err != nil {
+		return err
+	}
+	if err := a.index.Sync(); err != nil {
+		return err
+	}
+	enc, err := rlp.EncodeToBytes(&stats)
+	if err != nil {
+		return err
+	}
+	if err := batch.Put(archiveStatsKey, enc); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	a.items += uint64(len(ctxs))
+	a.size, a.stats = size, stats
+	return nil
+}
+
+// Get returns the archived ctx of chain
+func (a *CtxArchive) Get(chainID *big.Int, id common.Hash) (*cc.CrossTransactionWithSignatures, error) {
+	enc, err := a.db.Get(archiveKey(chainID, id))
+	if err != nil {
+		return nil, err
+	}
+	item := binary.BigEndian.Uint64(enc)
+
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	if a.data == nil {
+		return nil, errArchiveClosed
+	}
+	if item >= a.items {
+		return nil, fmt.Errorf("archive item %d out of bounds %d", item, a.items)
+	}
+	start, err := a.offset(item)
+	if err != nil {
+		return nil, err
+	}
+	end, err := a.offset(item + 1)
+	if err != nil {
+		return nil, err
+	}
+	blob := make([]byte, end-start)
+	if _, err := a.data.ReadAt(blob, int64(start)); err != nil {
+		return nil, err
+	}
+	if enc, err = snappy.Decode(nil, blob); err != nil {
+		return nil, err
+	}
+	ctx := new(cc.CrossTransactionWithSignatures)
+	if err := rlp.DecodeBytes(enc, ctx); err != nil {
+		return nil, err
+	}
+	return ctx, nil
+}
+
+// Iterate calls fn with the archived ctxs of chain until fn returns false
+func (a *CtxArchive) Iterate(chainID *big.Int, fn func(ctx *cc.CrossTransactionWithSignatures) bool) error {
+	prefix := archiveKey(chainID, common.Hash{})[:len(archivePrefix)+8]
+	it := a.db.NewIteratorWithPrefix(prefix)
+	defer it.Release()
+
+	for it.Next() {
+		ctx, err := a.Get(chainID, common.BytesToHash(it.Key()[len(prefix):]))
+		if err != nil {
+			return err
+		}
+		if !fn(ctx) {
+			return nil
+		}
+	}
+	return it.Error()
+}
+
+// Has reports whether the ctx of chain is archived
+func (a *CtxArchive) Has(chainID *big.Int, id common.Hash) bool {
+	ok, _ := a.db.Has(archiveKey(chainID, id))
+	return ok
+}
+
+// Stats returns the accumulated size of ctxs archived
+func (a *CtxArchive) Stats() ArchiveStats {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	return a.stats
+}
+
+// Close closes the files of archive, the db is closed by its owner.
+func (a *CtxArchive) Close() error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	var errs []error
+	for _, f := range []*os.File{a.data, a.index} {
+		if f != nil {
+			if err := f.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	a.data, a.index = nil, nil
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/cross/core"
+	"gbchain-org/go-gbchain/ethdb"
+	"gbchain-org/go-gbchain/ethdb/memorydb"
+	"github.com/stretchr/testify/assert"
+)
+
+type testArchiveContext string
+
+func (ctx testArchiveContext) ResolvePath(name string) string {
+	return filepath.Join(string(ctx), name)
+}
+
+func (ctx testArchiveContext) OpenDatabase(string, int, int, string) (ethdb.Database, error) {
+	return nil, nil
+}
+
+func newArchivedCtx(id string, number uint64) *core.CrossTransactionWithSignatures {
+	return &core.CrossTransactionWithSignatures{
+		Data:     core.CtxDatas{CTxId: common.BytesToHash([]byte(id)), Value: big.NewInt(1), Input: make([]byte, 256)},
+		Status:   core.CtxStatusFinished,
+		BlockNum: number,
+	}
+}
+
+func TestCtxArchive(t *testing.T) {
+	datadir, err := ioutil.TempDir("", "archive")
+	assert.NoError(t, err)
+	defer os.RemoveAll(datadir)
+
+	db := memorydb.New()
+	defer db.Close()
+	chainID := big.NewInt(1)
+
+	archive, err := OpenCtxArchive(testArchiveContext(datadir), db, "archive")
+	assert.NoError(t, err)
+	assert.NoError(t, archive.Append(chainID, []*core.CrossTransactionWithSignatures{newArchivedCtx("1", 1), newArchivedCtx("2", 2)}))
+	assert.NoError(t, archive.Append(chainID, []*core.CrossTransactionWithSignatures{newArchivedCtx("3", 3)}))
+	assert.NoError(t, archive.Close())
+
+	// append a partial item after the last one
+	data, err := os.OpenFile(filepath.Join(datadir, "archive", "ctxs.cdat"), os.O_WRONLY|os.O_APPEND, 0644)
+	assert.NoError(t, err)
+	_, err = data.Write([]byte{1, 2, 3})
+	assert.NoError(t, err)
+	data.Close()
+
+	archive, err = OpenCtxArchive(testArchiveContext(datadir), db, "archive")
+	assert.NoError(t, err)
+	defer archive.Close()
+	for i, id := range []string{"1", "2", "3"} {
+		ctx, err := archive.Get(chainID, common.BytesToHash([]byte(id)))
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(i+1), ctx.BlockNum)
+		assert.Equal(t, core.CtxStatusFinished, ctx.Status)
+	}
+	assert.False(t, archive.Has(big.NewInt(2), common.BytesToHash([]byte("1"))))
+
+	stats := archive.Stats()
+	assert.Equal(t, uint64(3), stats.Ctxs)
+	assert.True(t, stats.Archived < stats.Size)
+
+	// the archived ctxs are read as the finished ones
+	txLogs, err := NewTransactionLogs(db)
+	assert.NoError(t, err)
+	txLogs.SetArchive(archive)
+	l := txLogs.Get(chainID)
+	assert.True(t, l.IsFinish(common.BytesToHash([]byte("2"))))
+	ctx, ok := l.GetFinish(common.BytesToHash([]byte("3")))
+	assert.True(t, ok)
+	assert.Equal(t, uint64(3), ctx.BlockNum)
+	assert.False(t, l.IsFinish(common.BytesToHash([]byte("4"))))
+}
@@ -54,13 +54,24 @@ type CtxDB interface {
 	Clean() error
 }
 
+// OpenStormDB opens the storm database and upgrades it to the latest schema version
 func OpenStormDB(ctx ServiceContext, name string) (*storm.DB, error) {
-	if ctx == nil || len(ctx.ResolvePath(name)) == 0 {
-		return storm.Open(filepath.Join(os.TempDir(), name))
+	path := filepath.Join(os.TempDir(), name)
+	if ctx != nil && len(ctx.ResolvePath(name)) > 0 {
+		path = ctx.ResolvePath(name)
 	}
-	return storm.Open(ctx.ResolvePath(name))
+	db, err := storm.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := upgradeStorm(db, stormMigrations); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("open %s: %w", name, err)
+	}
+	return db, nil
 }
 
+// OpenEtherDB opens the key-value database and upgrades it to the latest schema version
 func OpenEtherDB(ctx ServiceContext, name string) (ethdb.Database, error) {
 	if ctx == nil {
 		return rawdb.NewMemoryDatabase(), nil
@@ -70,5 +81,9 @@ func OpenEtherDB(ctx ServiceContext, name string) (ethdb.Database, error) {
 	if err != nil {
 		return nil, ErrCtxDbFailure{msg: "OpenEtherDB fail", err: err}
 	}
+	if err := upgradeKV(db, kvMigrations); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("open %s: %w", name, err)
+	}
 	return db, nil
 }
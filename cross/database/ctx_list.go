@@ -106,6 +106,8 @@ type CrossTransactionIndexed struct {
 	DestinationId    *big.Int
 	DestinationValue *big.Int `storm:"index"`
 	Input            []byte
+	Token            common.Address `storm:"index"` // zero for native coin
+	DestinationToken common.Address `storm:"index"`
 
 	V []*big.Int
 	R []*big.Int
@@ -126,6 +128,8 @@ func NewCrossTransactionIndexed(ctx *cc.CrossTransactionWithSignatures) *CrossTr
 		DestinationId:    ctx.Data.DestinationId,
 		DestinationValue: ctx.Data.DestinationValue,
 		Input:            ctx.Data.Input,
+		Token:            ctx.Token(),
+		DestinationToken: ctx.DestinationToken(),
 		V:                ctx.Data.V,
 		R:                ctx.Data.R,
 		S:                ctx.Data.S,
@@ -150,10 +154,18 @@ func (c CrossTransactionIndexed) ToCrossTransaction() *cc.CrossTransactionWithSi
 			V:                c.V,
 			R:                c.R,
 			S:                c.S,
+			Tokens:           c.tokens(),
 		},
 	}
 }
 
+func (c CrossTransactionIndexed) tokens() []common.Address {
+	if c.Token == (common.Address{}) && c.DestinationToken == (common.Address{}) {
+		return nil
+	}
+	return []common.Address{c.Token, c.DestinationToken}
+}
+
 type IndexDbCache lru.ARCCache
 
 func newIndexDbCache(cap int) *IndexDbCache {
@@ -186,3 +198,7 @@ func (m *IndexDbCache) Put(index FieldName, key interface{}, ctx *CrossTransacti
 func (m *IndexDbCache) Remove(index FieldName, key interface{}) {
 	(*lru.ARCCache)(m).Remove(indexCacheKey(index, key))
 }
+
+func (m *IndexDbCache) Purge() {
+	(*lru.ARCCache)(m).Purge()
+}
@@ -0,0 +1,59 @@
+package db
+
+import (
+	"math/big"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/ethdb"
+	"gbchain-org/go-gbchain/rlp"
+
+	cc "gbchain-org/go-gbchain/cross/core"
+)
+
+// evidencePrefix + chainID + evidenceID -> rlp encoded cc.Evidence
+var evidencePrefix = []byte("evidence")
+
+// EvidenceStore keeps the evidences of misbehaving anchors by the chain ctxs are signed for
+type EvidenceStore struct {
+	db ethdb.KeyValueStore
+}
+
+func NewEvidenceStore(db ethdb.KeyValueStore) *EvidenceStore {
+	return &EvidenceStore{db: db}
+}
+
+func evidenceChainPrefix(chainID *big.Int) []byte {
+	return append(append([]byte{}, evidencePrefix...), common.LeftPadBytes(chainID.Bytes(), 8)...)
+}
+
+func evidenceKey(chainID *big.Int, id common.Hash) []byte {
+	return append(evidenceChainPrefix(chainID), id.Bytes()...)
+}
+
+// Add stores the evidence, it returns false if the evidence exists already
+func (s *EvidenceStore) Add(chainID *big.Int, ev *cc.Evidence) (bool, error) {
+	key := evidenceKey(chainID, ev.ID())
+	if ok, err := s.db.Has(key); err != nil || ok {
+		return false, err
+	}
+	enc, err := rlp.EncodeToBytes(ev)
+	if err != nil {
+		return false, err
+	}
+	return true, s.db.Put(key, enc)
+}
+
+// List returns the evidences of chain in the order of IDs
+func (s *EvidenceStore) List(chainID *big.Int) ([]*cc.Evidence, error) {
+	var evidences []*cc.Evidence
+	it := s.db.NewIteratorWithPrefix(evidenceChainPrefix(chainID))
+	defer it.Release()
+	for it.Next() {
+		var ev cc.Evidence
+		if err := rlp.DecodeBytes(it.Value(), &ev); err != nil {
+			return nil, err
+		}
+		evidences = append(evidences, &ev)
+	}
+	return evidences, it.Error()
+}
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"math/big"
+	"testing"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/cross/core"
+	"gbchain-org/go-gbchain/crypto"
+	"gbchain-org/go-gbchain/ethdb/memorydb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvidenceStore(t *testing.T) {
+	db := memorydb.New()
+	defer db.Close()
+
+	key, _ := crypto.GenerateKey()
+	signer := core.NewEIP155CtxSigner(big.NewInt(1))
+	ctx, err := core.SignCtx(core.NewCrossTransaction(big.NewInt(1), big.NewInt(2), big.NewInt(2),
+		common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03"),
+		common.HexToAddress("0x04"), common.Address{}, nil),
+		signer, func(hash []byte) ([]byte, error) { return crypto.Sign(hash, key) })
+	assert.NoError(t, err)
+	ev, err := core.NewNoMakerEvidence(signer, ctx, 1)
+	assert.NoError(t, err)
+
+	store := NewEvidenceStore(db)
+	added, err := store.Add(big.NewInt(1), ev)
+	assert.NoError(t, err)
+	assert.True(t, added)
+	added, err = store.Add(big.NewInt(1), ev)
+	assert.NoError(t, err)
+	assert.False(t, added)
+
+	// reopen the store
+	list, err := NewEvidenceStore(db).List(big.NewInt(1))
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(list)) {
+		assert.Equal(t, ev.ID(), list[0].ID())
+		assert.NoError(t, list[0].Verify(signer))
+	}
+	list, err = store.List(big.NewInt(2))
+	assert.NoError(t, err)
+	assert.Empty(t, list)
+}
@@ -0,0 +1,250 @@
+package db
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math/big"
+
+	"gbchain-org/go-gbchain/rlp"
+
+	cc "gbchain-org/go-gbchain/cross/core"
+
+	"github.com/asdine/storm/v3/q"
+	"golang.org/x/crypto/sha3"
+)
+
+// exportVersion is the version of the format of exported ctxs
+const exportVersion = 1
+
+// The kinds of exported ctxs
+const (
+	ExportStore    uint8 = iota // ctx in the cross store
+	ExportFinished              // ctx in the finished log or archive
+)
+
+var (
+	errExportVersion   = errors.New("unsupported export version")
+	errExportChecksum  = errors.New("export checksum mismatch")
+	errExportTruncated = errors.New("export is truncated, checksum missing")
+)
+
+// ExportHeader is the first item of exported ctxs, ChainID and RemoteID are the chain
+// pair of which the ctxs of both directions are exported.
+type ExportHeader struct {
+	Version  uint64
+	ChainID  *big.Int
+	RemoteID *big.Int
+}
+
+type exportEntry struct {
+	Kind uint8
+	Ctx  *cc.CrossTransactionWithSignatures
+}
+
+// CtxExporter writes ctxs into a stream of RLP items, the header is followed by the
+// entries of ctxs and the keccak256 checksum of all the items before it.
+type CtxExporter struct {
+	w      io.Writer
+	hasher hash.Hash
+	count  int
+}
+
+// NewCtxExporter writes the header of chain pair into w
+func NewCtxExporter(w io.Writer, chainID, remoteID *big.Int) (*CtxExporter, error) {
+	e := &CtxExporter{w: w, hasher: sha3.NewLegacyKeccak256()}
+	if err := e.write(&ExportHeader{Version: exportVersion, ChainID: chainID, RemoteID: remoteID}); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *CtxExporter) write(val interface{}) error {
+	enc, err := rlp.EncodeToBytes(val)
+	if err != nil {
+		return err
+	}
+	e.hasher.Write(enc)
+	_, err = e.w.Write(enc)
+	return err
+}
+
+// Export writes the ctx of kind
+func (e *CtxExporter) Export(kind uint8, ctx *cc.CrossTransactionWithSignatures) error {
+	if err := e.write(&exportEntry{Kind: kind, Ctx: ctx}); err != nil {
+		return err
+	}
+	e.count++
+	return nil
+}
+
+// Count returns the count of ctxs exported
+func (e *CtxExporter) Count() int {
+	return e.count
+}
+
+// Close writes the checksum of exported items, the underlying writer is not closed.
+func (e *CtxExporter) Close() error {
+	return rlp.Encode(e.w, e.hasher.Sum(nil))
+}
+
+// CtxImporter reads the ctxs written by CtxExporter. The checksum is verified when
+// the last ctx is read, so the ctxs should not be trusted before Next returns io.EOF.
+type CtxImporter struct {
+	Header ExportHeader
+
+	stream *rlp.Stream
+	hasher hash.Hash
+}
+
+// NewCtxImporter reads the header of the exported ctxs in r
+func NewCtxImporter(r io.Reader) (*CtxImporter, error) {
+	i := &CtxImporter{stream: rlp.NewStream(r, 0), hasher: sha3.NewLegacyKeccak256()}
+	raw, err := i.stream.Raw()
+	if err != nil {
+		return nil, err
+	}
+	i.hasher.Write(raw)
+	if err := rlp.DecodeBytes(raw, &i.Header); err != nil {
+		return nil, err
+	}
+	if i.Header.Version != exportVersion {
+		return nil, fmt.Errorf("%w: %d", errExportVersion, i.Header.Version)
+	}
+	return i, nil
+}
+
+// Next returns the next ctx and its kind, it returns io.EOF after the checksum of all
+// the ctxs is verified.
+func (i *CtxImporter) Next() (uint8, *cc.CrossTransactionWithSignatures, error) {
+	kind, _, err := i.stream.Kind()
+	if err == io.EOF {
+		return 0, nil, errExportTruncated
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	raw, err := i.stream.Raw()
+	if err != nil {
+		return 0, nil, err
+	}
+	if kind != rlp.List { // the checksum
+		var sum []byte
+		if err := rlp.DecodeBytes(raw, &sum); err != nil {
+			return 0, nil, err
+		}
+		if !bytes.Equal(sum, i.hasher.Sum(nil)) {
+			return 0, nil, errExportChecksum
+		}
+		return 0, nil, io.EOF
+	}
+	i.hasher.Write(raw)
+	var entry exportEntry
+	if err := rlp.DecodeBytes(raw, &entry); err != nil {
+		return 0, nil, err
+	}
+	return entry.Kind, entry.Ctx, nil
+}
+
+// ExportCtxs exports the ctxs of chain to remoteID, which are stored in store and the
+// finished ones moved into txLog and archive. The archive could be nil.
+func ExportCtxs(e *CtxExporter, store CtxDB, txLog *TransactionLog, archive *CtxArchive, remoteID *big.Int) error {
+	for page := 1; ; page++ {
+		ctxs := store.Query(migrateBatchSize, page, nil, false, q.Eq(DestinationId, remoteID))
+		if len(ctxs) == 0 {
+			break
+		}
+		for _, ctx := range ctxs {
+			if err := e.Export(ExportStore, ctx); err != nil {
+				return err
+			}
+		}
+	}
+	var err error
+	finished := func(ctx *cc.CrossTransactionWithSignatures) bool {
+		if ctx.DestinationId().Cmp(remoteID) == 0 {
+			err = e.Export(ExportFinished, ctx)
+		}
+		return err == nil
+	}
+	if iterErr := txLog.Iterate(finished); iterErr != nil {
+		return iterErr
+	}
+	if err != nil || archive == nil {
+		return err
+	}
+	if iterErr := archive.Iterate(store.ChainID(), finished); iterErr != nil {
+		return iterErr
+	}
+	return err
+}
+
+// ImportCtxs writes the ctxs read by i into the stores of their chains and the finished
+// logs, the ctxs existed are not replaced. Each ctx is checked by verify if it's not nil,
+// and nothing is written unless all the ctxs are verified and the checksum matches, so the
+// ctxs are held in memory until the end of i. It returns the count of ctxs imported.
+func ImportCtxs(i *CtxImporter, verify func(*cc.CrossTransactionWithSignatures) error, stores func(chainID *big.Int) CtxDB, logs *TransactionLogs) (int, error) {
+	var entries []exportEntry
+	for {
+		kind, ctx, err := i.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		if kind != ExportStore && kind != ExportFinished {
+			return 0, fmt.Errorf("unknown kind %d of ctx %s", kind, ctx.ID().String())
+		}
+		if verify != nil {
+			if err := verify(ctx); err != nil {
+				return 0, err
+			}
+		}
+		entries = append(entries, exportEntry{Kind: kind, Ctx: ctx})
+	}
+
+	var (
+		count   int
+		batches = make(map[uint64][]*cc.CrossTransactionWithSignatures)
+		flush   = func(chainID *big.Int) error {
+			if err := stores(chainID).Writes(batches[chainID.Uint64()], false); err != nil {
+				return err
+			}
+			delete(batches, chainID.Uint64())
+			return nil
+		}
+		finished *TransactionLog // the finished logs of chains share the trie
+	)
+	for _, entry := range entries {
+		chainID := entry.Ctx.ChainId()
+		switch entry.Kind {
+		case ExportStore:
+			batches[chainID.Uint64()] = append(batches[chainID.Uint64()], entry.Ctx)
+			if len(batches[chainID.Uint64()]) >= migrateBatchSize {
+				if err := flush(chainID); err != nil {
+					return count, err
+				}
+			}
+		case ExportFinished:
+			finished = logs.Get(chainID)
+			if err := finished.AddFinish(entry.Ctx); err != nil {
+				return count, err
+			}
+		}
+		count++
+	}
+	for _, batch := range batches {
+		if err := flush(batch[0].ChainId()); err != nil {
+			return count, err
+		}
+	}
+	if finished != nil {
+		if _, err := finished.Commit(); err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
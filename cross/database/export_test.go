@@ -0,0 +1,172 @@
+package db
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/ethdb/memorydb"
+
+	cc "gbchain-org/go-gbchain/cross/core"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newExportedCtx(id int64, chainID, remoteID int64, status cc.CtxStatus) *cc.CrossTransactionWithSignatures {
+	return &cc.CrossTransactionWithSignatures{
+		Data: cc.CtxDatas{
+			CTxId:            common.BigToHash(big.NewInt(id)),
+			Value:            big.NewInt(id),
+			DestinationId:    big.NewInt(remoteID),
+			DestinationValue: big.NewInt(id),
+			V:                []*big.Int{big.NewInt(chainID*2 + 35)},
+			R:                []*big.Int{big.NewInt(id)},
+			S:                []*big.Int{big.NewInt(id)},
+		},
+		Status:   status,
+		BlockNum: uint64(id),
+	}
+}
+
+func TestExportImportCtxs(t *testing.T) {
+	datadir, err := ioutil.TempDir("", "export")
+	assert.NoError(t, err)
+	defer os.RemoveAll(datadir)
+
+	var (
+		chainID, remoteID = big.NewInt(1), big.NewInt(2)
+		db                = memorydb.New()
+		local             = NewKVIndexDB(chainID, db, 0)
+		remote            = NewKVIndexDB(remoteID, db, 0)
+	)
+	logs, err := NewTransactionLogs(db)
+	assert.NoError(t, err)
+	archive, err := OpenCtxArchive(testArchiveContext(datadir), db, "archive")
+	assert.NoError(t, err)
+	defer archive.Close()
+
+	stored := []*cc.CrossTransactionWithSignatures{
+		newExportedCtx(1, 1, 2, cc.CtxStatusWaiting),
+		newExportedCtx(2, 1, 2, cc.CtxStatusExecuted),
+		newExportedCtx(3, 1, 3, cc.CtxStatusWaiting), // to other chain
+	}
+	assert.NoError(t, local.Writes(stored, false))
+	assert.NoError(t, remote.Writes([]*cc.CrossTransactionWithSignatures{newExportedCtx(4, 2, 1, cc.CtxStatusWaiting)}, false))
+
+	finished := newExportedCtx(5, 1, 2, cc.CtxStatusFinished)
+	assert.NoError(t, logs.Get(chainID).AddFinish(finished))
+	_, err = logs.Get(chainID).Commit()
+	assert.NoError(t, err)
+	archived := newExportedCtx(6, 1, 2, cc.CtxStatusFinished)
+	assert.NoError(t, archive.Append(chainID, []*cc.CrossTransactionWithSignatures{archived}))
+
+	var buf bytes.Buffer
+	exporter, err := NewCtxExporter(&buf, chainID, remoteID)
+	assert.NoError(t, err)
+	assert.NoError(t, ExportCtxs(exporter, local, logs.Get(chainID), archive, remoteID))
+	assert.NoError(t, ExportCtxs(exporter, remote, logs.Get(remoteID), nil, chainID))
+	assert.NoError(t, exporter.Close())
+	assert.Equal(t, 5, exporter.Count())
+
+	// import into an empty node
+	var (
+		newDB    = memorydb.New()
+		newStore = func(chainID *big.Int) CtxDB { return NewKVIndexDB(chainID, newDB, 0) }
+	)
+	newLogs, err := NewTransactionLogs(newDB)
+	assert.NoError(t, err)
+	importer, err := NewCtxImporter(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, chainID, importer.Header.ChainID)
+	assert.Equal(t, remoteID, importer.Header.RemoteID)
+	count, err := ImportCtxs(importer, nil, newStore, newLogs)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, count)
+
+	assert.Equal(t, 2, newStore(chainID).Count())
+	assert.False(t, newStore(chainID).Has(stored[2].ID()))
+	assert.Equal(t, 1, newStore(remoteID).Count())
+	for _, ctx := range []*cc.CrossTransactionWithSignatures{finished, archived} {
+		ctx, ok := newLogs.Get(chainID).GetFinish(ctx.ID())
+		assert.True(t, ok)
+		assert.Equal(t, cc.CtxStatusFinished, ctx.Status)
+	}
+	// the finished log is committed
+	newLogs, err = NewTransactionLogs(newDB)
+	assert.NoError(t, err)
+	assert.True(t, newLogs.Get(chainID).IsFinish(finished.ID()))
+}
+
+func TestCtxImporterChecksum(t *testing.T) {
+	var buf bytes.Buffer
+	exporter, err := NewCtxExporter(&buf, big.NewInt(1), big.NewInt(2))
+	assert.NoError(t, err)
+	for i := int64(1); i <= 3; i++ {
+		assert.NoError(t, exporter.Export(ExportStore, newExportedCtx(i, 1, 2, cc.CtxStatusWaiting)))
+	}
+	size := buf.Len()
+	assert.NoError(t, exporter.Close())
+
+	readAll := func(enc []byte) error {
+		importer, err := NewCtxImporter(bytes.NewReader(enc))
+		if err != nil {
+			return err
+		}
+		for {
+			if _, _, err := importer.Next(); err != nil {
+				return err
+			}
+		}
+	}
+	assert.Equal(t, io.EOF, readAll(buf.Bytes()))
+
+	// the checksum is missing
+	assert.Equal(t, errExportTruncated, readAll(buf.Bytes()[:size]))
+
+	// the value of last ctx is changed
+	enc := common.CopyBytes(buf.Bytes())
+	enc[size-1]++
+	assert.Equal(t, errExportChecksum, readAll(enc))
+}
+
+func TestImportCtxs_Unverified(t *testing.T) {
+	var buf bytes.Buffer
+	exporter, err := NewCtxExporter(&buf, big.NewInt(1), big.NewInt(2))
+	assert.NoError(t, err)
+	for i := int64(1); i <= 3; i++ {
+		assert.NoError(t, exporter.Export(ExportStore, newExportedCtx(i, 1, 2, cc.CtxStatusWaiting)))
+	}
+	size := buf.Len()
+	assert.NoError(t, exporter.Close())
+
+	errReject := errors.New("rejected")
+	for _, tt := range []struct {
+		enc    []byte
+		verify func(*cc.CrossTransactionWithSignatures) error
+		err    error
+	}{
+		{buf.Bytes()[:size], nil, errExportTruncated},
+		// the ctxs verified are not written if a later one is rejected
+		{buf.Bytes(), func(ctx *cc.CrossTransactionWithSignatures) error {
+			if ctx.BlockNum == 3 {
+				return errReject
+			}
+			return nil
+		}, errReject},
+	} {
+		db := memorydb.New()
+		store := NewKVIndexDB(big.NewInt(1), db, 0)
+		logs, err := NewTransactionLogs(db)
+		assert.NoError(t, err)
+		importer, err := NewCtxImporter(bytes.NewReader(tt.enc))
+		assert.NoError(t, err)
+		_, err = ImportCtxs(importer, tt.verify, func(*big.Int) CtxDB { return store }, logs)
+		assert.Equal(t, tt.err, err)
+		assert.Equal(t, 0, store.Count())
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sync"
 
 	"gbchain-org/go-gbchain/common"
 	"gbchain-org/go-gbchain/log"
@@ -21,6 +22,10 @@ type indexDB struct {
 	db      storm.Node
 	cache   *IndexDbCache
 	logger  log.Logger
+
+	// lock orders the cache misses after the writes, the ctxs read from db are
+	// cached only if no write could evict them before they are put.
+	lock sync.RWMutex
 }
 
 type FieldName = string
@@ -34,7 +39,10 @@ const (
 	FromField        FieldName = "From"
 	ToField          FieldName = "To"
 	DestinationValue FieldName = "DestinationValue"
+	DestinationId    FieldName = "DestinationId"
 	BlockNumField    FieldName = "BlockNum"
+	TokenField       FieldName = "Token"
+	DestinationToken FieldName = "DestinationToken"
 )
 
 func NewIndexDB(chainID *big.Int, rootDB *storm.DB, cacheSize uint64) *indexDB {
@@ -74,6 +82,9 @@ func (d *indexDB) Repair() error {
 }
 
 func (d *indexDB) Clean() error {
+	if d.cache != nil {
+		d.cache.Purge()
+	}
 	return d.db.Drop(&CrossTransactionIndexed{})
 }
 
@@ -93,6 +104,9 @@ func (d *indexDB) Write(ctx *cc.CrossTransactionWithSignatures) error {
 
 func (d *indexDB) Writes(ctxList []*cc.CrossTransactionWithSignatures, replaceable bool) (err error) {
 	d.logger.Debug("write cross transaction", "count", len(ctxList), "replaceable", replaceable)
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
 	tx, err := d.db.Begin(true)
 	if err != nil {
 		return ErrCtxDbFailure{"begin transaction failed", err}
@@ -109,12 +123,13 @@ func (d *indexDB) Writes(ctxList []*cc.CrossTransactionWithSignatures, replaceab
 		if new.BlockNum < old.BlockNum {
 			return false
 		}
-		if new.Status <= old.Status { //TODO:无法解决同步其他节点时，其他节点回滚的状态
+		if !cc.CtxStatus(old.Status).Before(cc.CtxStatus(new.Status)) { //TODO:无法解决同步其他节点时，其他节点回滚的状态
 			return false
 		}
 		return true
 	}
 
+	var written []*CrossTransactionIndexed
 	for _, ctx := range ctxList {
 		//if d.txLog.IsFinish(ctx.ID()) {
 		//	continue
@@ -148,13 +163,13 @@ func (d *indexDB) Writes(ctxList []*cc.CrossTransactionWithSignatures, replaceab
 			continue
 		}
 
-		if d.cache != nil {
-			d.cache.Remove(CtxIdIndex, ctx.ID())
-			d.cache.Remove(CtxIdIndex, ctx.Data.TxHash)
-		}
+		written = append(written, new)
 	}
-
-	return tx.Commit()
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	d.uncache(written...)
+	return nil
 }
 
 func (d *indexDB) Read(ctxId common.Hash) (*cc.CrossTransactionWithSignatures, error) {
@@ -172,6 +187,8 @@ func (d *indexDB) One(field FieldName, key interface{}) *cc.CrossTransactionWith
 			return ctx.ToCrossTransaction()
 		}
 	}
+	d.lock.RLock()
+	defer d.lock.RUnlock()
 	var ctx CrossTransactionIndexed
 	if err := d.db.One(field, key, &ctx); err != nil {
 		return nil
@@ -189,6 +206,8 @@ func (d *indexDB) get(ctxId common.Hash) (*CrossTransactionIndexed, error) {
 			return ctx, nil
 		}
 	}
+	d.lock.RLock()
+	defer d.lock.RUnlock()
 
 	var ctx CrossTransactionIndexed
 	if err := d.db.One(CtxIdIndex, ctxId, &ctx); err != nil {
@@ -210,12 +229,16 @@ func (d *indexDB) Updates(idList []common.Hash, updaters []func(ctx *CrossTransa
 	if len(idList) != len(updaters) {
 		return ErrCtxDbFailure{err: errors.New("invalid updates params")}
 	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
 	tx, err := d.db.Begin(true)
 	if err != nil {
 		return ErrCtxDbFailure{"begin transaction failed", err}
 	}
 	defer tx.Rollback()
 
+	updated := make([]*CrossTransactionIndexed, 0, len(idList))
 	for i, id := range idList {
 		var ctx CrossTransactionIndexed
 		if err = tx.One(CtxIdIndex, id, &ctx); err != nil {
@@ -225,35 +248,51 @@ func (d *indexDB) Updates(idList []common.Hash, updaters []func(ctx *CrossTransa
 		if err = tx.Update(&ctx); err != nil {
 			return ErrCtxDbFailure{"transaction update failed", err}
 		}
-		if d.cache != nil {
-			d.cache.Remove(CtxIdIndex, id)
-			d.cache.Remove(TxHashIndex, ctx.TxHash)
-		}
+		updated = append(updated, &ctx)
 	}
-	return tx.Commit()
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	d.uncache(updated...)
+	return nil
 }
 
 func (d *indexDB) Deletes(idList []common.Hash) (err error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
 	tx, err := d.db.Begin(true)
 	if err != nil {
 		return ErrCtxDbFailure{"begin transaction failed", err}
 	}
 	defer tx.Rollback()
+	var deleted []*CrossTransactionIndexed
 	for _, id := range idList {
 		var ctx CrossTransactionIndexed
 		if err = tx.One(CtxIdIndex, id, &ctx); err != nil {
 			continue
 		}
-		if d.cache != nil {
-			d.cache.Remove(CtxIdIndex, id)
-			d.cache.Remove(TxHashIndex, ctx.TxHash)
-		}
 		if err = tx.DeleteStruct(&ctx); err != nil {
 			return ErrCtxDbFailure{"transaction delete failed", err}
 		}
+		deleted = append(deleted, &ctx)
 	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	d.uncache(deleted...)
+	return nil
+}
 
-	return tx.Commit()
+// uncache evicts the written ctxs from cache after they are committed.
+func (d *indexDB) uncache(ctxs ...*CrossTransactionIndexed) {
+	if d.cache == nil {
+		return
+	}
+	for _, ctx := range ctxs {
+		d.cache.Remove(CtxIdIndex, ctx.CtxId)
+		d.cache.Remove(TxHashIndex, ctx.TxHash)
+	}
 }
 
 func (d *indexDB) Has(id common.Hash) bool {
@@ -8,6 +8,7 @@ import (
 
 	"gbchain-org/go-gbchain/common"
 	cc "gbchain-org/go-gbchain/cross/core"
+	"gbchain-org/go-gbchain/ethdb/memorydb"
 
 	"github.com/asdine/storm/v3"
 	"github.com/asdine/storm/v3/q"
@@ -22,6 +23,40 @@ func setupIndexDB(t interface{}) *storm.DB {
 	return rootDB
 }
 
+// ctxDBBackend opens the stores of an implementation of CtxDB
+type ctxDBBackend struct {
+	open    func(chainID *big.Int, cacheSize uint64) CtxDB
+	restart func() // closes and reopens the database under the stores
+}
+
+// testCtxDBs runs the test with the storm and key-value implementations of CtxDB,
+// the stores opened should be cleaned by the test.
+func testCtxDBs(t *testing.T, test func(t *testing.T, backend *ctxDBBackend)) {
+	t.Run("storm", func(t *testing.T) {
+		root := setupIndexDB(t)
+		defer func() { root.Close() }()
+		test(t, &ctxDBBackend{
+			open: func(chainID *big.Int, cacheSize uint64) CtxDB {
+				return NewIndexDB(chainID, root, cacheSize)
+			},
+			restart: func() {
+				root.Close()
+				root = setupIndexDB(t)
+			},
+		})
+	})
+	t.Run("kv", func(t *testing.T) {
+		kv := memorydb.New()
+		defer kv.Close()
+		test(t, &ctxDBBackend{
+			open: func(chainID *big.Int, cacheSize uint64) CtxDB {
+				return NewKVIndexDB(chainID, kv, cacheSize)
+			},
+			restart: func() {},
+		})
+	})
+}
+
 func generateCtx(n int) []*cc.CrossTransactionWithSignatures {
 	ctxList := make([]*cc.CrossTransactionWithSignatures, n)
 	for i := 0; i < n; i++ {
@@ -47,96 +82,129 @@ func generateCtx(n int) []*cc.CrossTransactionWithSignatures {
 }
 
 func TestIndexDB_One(t *testing.T) {
-	root := setupIndexDB(t)
-	defer root.Close()
-	ctxList := generateCtx(2)
-	db := NewIndexDB(big.NewInt(1), root, 0)
-	db.db.Drop(&CrossTransactionIndexed{})
-
-	assert.NoError(t, db.Write(ctxList[0]))
-	assert.Equal(t, db.One(TxHashIndex, ctxList[0].Data.TxHash), ctxList[0])
-	assert.Equal(t, db.One(CtxIdIndex, ctxList[0].Data.CTxId), ctxList[0])
-}
+	testCtxDBs(t, func(t *testing.T, backend *ctxDBBackend) {
+		ctxList := generateCtx(3)
+		token, destToken := common.HexToAddress("0x1111"), common.HexToAddress("0x2222")
+		ctxList[1].Data.Tokens = []common.Address{token, destToken}
+		ctxList[2].Data.Tokens = []common.Address{{}, destToken}
+		db := backend.open(big.NewInt(1), 0)
+		db.Clean()
 
-func TestIndexDB_ReadWrite(t *testing.T) {
-	root := setupIndexDB(t)
-
-	ctxList := generateCtx(2)
-
-	testFunction1 := func(t *testing.T, db *indexDB) {
 		assert.NoError(t, db.Write(ctxList[0]))
-		assert.EqualValues(t, db.Count(q.Eq(StatusField, cc.CtxStatusPending)), 1)
-
-		assert.NoError(t, db.Write(ctxList[1]))
-		ctx, err := db.Read(ctxList[1].ID())
-		assert.NoError(t, err, "")
-		assert.Equal(t, ctxList[1], ctx, "")
-	}
-	// Write without cache
-	{
-		db := NewIndexDB(big.NewInt(1), root, 0)
-		db.db.Drop(&CrossTransactionIndexed{})
-		testFunction1(t, db)
-	}
+		assert.Equal(t, db.One(TxHashIndex, ctxList[0].Data.TxHash), ctxList[0])
+		assert.Equal(t, db.One(CtxIdIndex, ctxList[0].Data.CTxId), ctxList[0])
+		assert.Nil(t, db.One(TxHashIndex, common.BigToHash(big.NewInt(100))))
+
+		// one by indexed and not indexed fields
+		assert.NoError(t, db.Writes(ctxList[1:], false))
+		assert.Equal(t, ctxList[1], db.One(FromField, ctxList[1].Data.From))
+		assert.Equal(t, ctxList[1], db.One(DestinationId, ctxList[1].Data.DestinationId))
+
+		// by tokens, zero for native coin
+		assert.Equal(t, ctxList[1], db.One(TokenField, token))
+		assert.Equal(t, 2, db.Count(q.Eq(DestinationToken, destToken)))
+		assert.Equal(t, 1, db.Count(q.Eq(DestinationToken, common.Address{})))
+	})
+}
 
-	// Write with cache
-	{
-		db := NewIndexDB(big.NewInt(2), root, 10)
-		db.db.Drop(&CrossTransactionIndexed{})
-		testFunction1(t, db)
-	}
+func TestIndexDB_ReadWrite(t *testing.T) {
+	testCtxDBs(t, func(t *testing.T, backend *ctxDBBackend) {
+		ctxList := generateCtx(2)
+
+		testFunction1 := func(t *testing.T, db CtxDB) {
+			assert.NoError(t, db.Write(ctxList[0]))
+			assert.EqualValues(t, db.Count(q.Eq(StatusField, cc.CtxStatusPending)), 1)
+
+			assert.NoError(t, db.Write(ctxList[1]))
+			ctx, err := db.Read(ctxList[1].ID())
+			assert.NoError(t, err, "")
+			assert.Equal(t, ctxList[1], ctx, "")
+			assert.True(t, db.Has(ctxList[1].ID()))
+
+			assert.False(t, db.Has(common.BigToHash(big.NewInt(100))))
+			_, err = db.Read(common.BigToHash(big.NewInt(100)))
+			assert.Error(t, err)
+		}
+		// Write without cache
+		{
+			db := backend.open(big.NewInt(1), 0)
+			db.Clean()
+			testFunction1(t, db)
+		}
 
-	// Write in restart db
-	{
-		root.Close()
-		root = setupIndexDB(t)
-		db := NewIndexDB(big.NewInt(2), root, 10)
+		// Write with cache
+		{
+			db := backend.open(big.NewInt(2), 10)
+			db.Clean()
+			testFunction1(t, db)
+		}
 
-		assert.NoError(t, db.Load(), "load occurs an error")
-		assert.Equal(t, 2, db.Count(q.Eq(StatusField, cc.CtxStatusPending)))
-		db.db.Drop(&CrossTransactionIndexed{})
-	}
+		// Write in restart db
+		{
+			backend.restart()
+			db := backend.open(big.NewInt(2), 10)
 
-	// Concurrent Write
-	{
-		ctxList := generateCtx(40)
-		db := NewIndexDB(big.NewInt(3), root, 10)
-		db.db.Drop(&CrossTransactionIndexed{})
-		var wg sync.WaitGroup
-		wg.Add(2)
-		go func() {
-			defer wg.Done()
-			for i := 0; i < 20; i++ {
-				assert.NoError(t, db.Write(ctxList[i]))
-			}
-		}()
-		go func() {
-			defer wg.Done()
-			for i := 20; i < 40; i++ {
-				assert.NoError(t, db.Write(ctxList[i]))
-			}
-		}()
-		wg.Wait()
-		assert.Equal(t, 40, db.Count(q.Eq(StatusField, cc.CtxStatusPending)))
-		count, err := db.db.Count(&CrossTransactionIndexed{})
-		assert.NoError(t, err)
-		assert.Equal(t, 40, count)
-	}
+			assert.NoError(t, db.Load(), "load occurs an error")
+			assert.Equal(t, 2, db.Count(q.Eq(StatusField, cc.CtxStatusPending)))
+			db.Clean()
+		}
 
-	root.Close()
+		// Concurrent Write
+		{
+			ctxList := generateCtx(40)
+			db := backend.open(big.NewInt(3), 10)
+			db.Clean()
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 20; i++ {
+					assert.NoError(t, db.Write(ctxList[i]))
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				for i := 20; i < 40; i++ {
+					assert.NoError(t, db.Write(ctxList[i]))
+				}
+			}()
+			wg.Wait()
+			assert.Equal(t, 40, db.Count(q.Eq(StatusField, cc.CtxStatusPending)))
+			assert.Equal(t, 40, db.Count())
+		}
+	})
 }
 
 func TestIndexDB_Update(t *testing.T) {
-	cws := generateCtx(1)[0]
-	ctxID := cws.ID()
-	cws.Status = cc.CtxStatusPending
-	rootDB := setupIndexDB(t)
-	defer rootDB.Close()
-	db := NewIndexDB(big.NewInt(1), rootDB, 20)
-	db.Clean()
-
-	Update := func(store CtxDB, cws *cc.CrossTransactionWithSignatures) error {
-		return store.Update(cws.ID(), func(ctx *CrossTransactionIndexed) {
+	testCtxDBs(t, func(t *testing.T, backend *ctxDBBackend) {
+		cws := generateCtx(1)[0]
+		ctxID := cws.ID()
+		cws.Status = cc.CtxStatusPending
+		db := backend.open(big.NewInt(1), 20)
+		db.Clean()
+
+		Update := func(store CtxDB, cws *cc.CrossTransactionWithSignatures) error {
+			return store.Update(cws.ID(), func(ctx *CrossTransactionIndexed) {
+				ctx.Status = uint8(cws.Status)
+				ctx.BlockNum = cws.BlockNum
+				ctx.From = cws.Data.From
+				ctx.To = cws.Data.To
+				ctx.BlockHash = cws.Data.BlockHash
+				ctx.DestinationId = cws.Data.DestinationId
+				ctx.Value = cws.Data.Value
+				ctx.DestinationValue = cws.Data.DestinationValue
+				ctx.Input = cws.Data.Input
+				ctx.V = cws.Data.V
+				ctx.R = cws.Data.R
+				ctx.S = cws.Data.S
+			})
+		}
+
+		assert.NoError(t, db.Writes([]*cc.CrossTransactionWithSignatures{cws}, false))
+		cws.Status = cc.CtxStatusFinishing
+		assert.NoError(t, Update(db, cws))
+		assert.Equal(t, cc.CtxStatusFinishing, db.One(CtxIdIndex, ctxID).Status)
+		assert.NoError(t, db.Update(ctxID, func(ctx *CrossTransactionIndexed) {
 			ctx.Status = uint8(cws.Status)
 			ctx.BlockNum = cws.BlockNum
 			ctx.From = cws.Data.From
@@ -149,162 +217,192 @@ func TestIndexDB_Update(t *testing.T) {
 			ctx.V = cws.Data.V
 			ctx.R = cws.Data.R
 			ctx.S = cws.Data.S
-		})
-	}
-
-	assert.NoError(t, db.Writes([]*cc.CrossTransactionWithSignatures{cws}, false))
-	cws.Status = cc.CtxStatusFinishing
-	assert.NoError(t, Update(db, cws))
-	{
-		var ctx CrossTransactionIndexed
-		assert.NoError(t, db.db.One(CtxIdIndex, ctxID, &ctx))
-		assert.Equal(t, cc.CtxStatusFinishing, cc.CtxStatus(ctx.Status))
-	}
-	assert.NoError(t, db.Update(ctxID, func(ctx *CrossTransactionIndexed) {
-		ctx.Status = uint8(cws.Status)
-		ctx.BlockNum = cws.BlockNum
-		ctx.From = cws.Data.From
-		ctx.To = cws.Data.To
-		ctx.BlockHash = cws.Data.BlockHash
-		ctx.DestinationId = cws.Data.DestinationId
-		ctx.Value = cws.Data.Value
-		ctx.DestinationValue = cws.Data.DestinationValue
-		ctx.Input = cws.Data.Input
-		ctx.V = cws.Data.V
-		ctx.R = cws.Data.R
-		ctx.S = cws.Data.S
-	}))
-	{
-		var ctx CrossTransactionIndexed
-		assert.NoError(t, db.db.One(CtxIdIndex, ctxID, &ctx))
-		assert.Equal(t, cc.CtxStatusFinishing, cc.CtxStatus(ctx.Status))
-	}
-
+		}))
+		assert.Equal(t, cc.CtxStatusFinishing, db.One(CtxIdIndex, ctxID).Status)
+		assert.Error(t, db.Update(common.BigToHash(big.NewInt(100)), func(*CrossTransactionIndexed) {}))
+	})
 }
 
 func TestIndexDB_Query(t *testing.T) {
-	ctxList := generateCtx(100)
-	rootDB := setupIndexDB(t)
-	defer rootDB.Close()
-
-	db := NewIndexDB(big.NewInt(1), rootDB, 20)
-	db.Clean()
-	for _, ctx := range ctxList {
-		assert.NoError(t, db.Write(ctx))
-	}
+	testCtxDBs(t, func(t *testing.T, backend *ctxDBBackend) {
+		ctxList := generateCtx(100)
+		db := backend.open(big.NewInt(1), 20)
+		db.Clean()
+		for _, ctx := range ctxList {
+			assert.NoError(t, db.Write(ctx))
+		}
 
-	{
-		assert.EqualValues(t, 99, db.Height())
-	}
+		{
+			assert.EqualValues(t, 99, db.Height())
+		}
 
-	// query without filter
-	{
-		list := db.Query(50, 1, []FieldName{PriceIndex}, false)
-		assert.Equal(t, 50, len(list))
-		for i := 1; i < 50; i++ {
-			price1, _ := list[i-1].Price().Float64()
-			price2, _ := list[i].Price().Float64()
-			assert.LessOrEqual(t, price1, price2)
+		// query without filter
+		{
+			list := db.Query(50, 1, []FieldName{PriceIndex}, false)
+			assert.Equal(t, 50, len(list))
+			for i := 1; i < 50; i++ {
+				price1, _ := list[i-1].Price().Float64()
+				price2, _ := list[i].Price().Float64()
+				assert.LessOrEqual(t, price1, price2)
+			}
 		}
-	}
 
-	// query last 5
-	{
-		list := db.Query(5, 4, []FieldName{PriceIndex}, false)
-		assert.Equal(t, 5, len(list))
-		list = db.Query(50, 5, []FieldName{PriceIndex}, false)
-		assert.Equal(t, 0, len(list))
-	}
+		// query last 5
+		{
+			list := db.Query(5, 4, []FieldName{PriceIndex}, false)
+			assert.Equal(t, 5, len(list))
+			list = db.Query(50, 5, []FieldName{PriceIndex}, false)
+			assert.Equal(t, 0, len(list))
+			assert.Nil(t, db.Query(5, 0, nil, false))
+		}
 
-	// update status
-	{
-		assert.NoError(t, db.Update(ctxList[0].ID(), func(ctx *CrossTransactionIndexed) {
-			ctx.Status = uint8(cc.CtxStatusFinished)
-		}))
-		list := db.Query(100, 1, []FieldName{PriceIndex}, false, q.Eq(StatusField, cc.CtxStatusFinished))
-		assert.Equal(t, 1, len(list))
-	}
+		// query by page in the order of indexed field
+		{
+			list := db.Query(5, 2, []FieldName{BlockNumField}, false)
+			assert.Equal(t, 5, len(list))
+			for i, ctx := range list {
+				assert.Equal(t, ctxList[5+i].ID(), ctx.ID())
+			}
+			list = db.Query(5, 1, []FieldName{BlockNumField}, true)
+			assert.Equal(t, 5, len(list))
+			assert.Equal(t, ctxList[99].ID(), list[0].ID())
+		}
 
-	// query DestinationValue
-	{
-		assert.NotNil(t, db.Query(0, 0, []FieldName{PriceIndex}, false, q.Eq(StatusField, cc.CtxStatusPending), q.Gte(DestinationValue, ctxList[10].Data.DestinationValue)))
-	}
+		// update status
+		{
+			assert.NoError(t, db.Update(ctxList[0].ID(), func(ctx *CrossTransactionIndexed) {
+				ctx.Status = uint8(cc.CtxStatusFinished)
+			}))
+			list := db.Query(100, 1, []FieldName{PriceIndex}, false, q.Eq(StatusField, cc.CtxStatusFinished))
+			assert.Equal(t, 1, len(list))
+		}
 
-	{
-		assert.NotNil(t, db.Query(0, 0, nil, false, q.Eq(FromField, common.BigToAddress(big.NewInt(10)))))
-	}
+		// query DestinationValue
+		{
+			assert.NotNil(t, db.Query(0, 0, []FieldName{PriceIndex}, false, q.Eq(StatusField, cc.CtxStatusPending), q.Gte(DestinationValue, ctxList[10].Data.DestinationValue)))
+
+			list := db.Query(0, 0, []FieldName{DestinationValue}, true, q.Gte(DestinationValue, ctxList[10].Data.DestinationValue))
+			for i := 1; i < len(list); i++ {
+				assert.True(t, list[i-1].Data.DestinationValue.Cmp(list[i].Data.DestinationValue) >= 0)
+			}
+			assert.Equal(t, len(list), db.Count(q.Gte(DestinationValue, ctxList[10].Data.DestinationValue)))
+		}
 
+		{
+			assert.NotNil(t, db.Query(0, 0, nil, false, q.Eq(FromField, common.BigToAddress(big.NewInt(10)))))
+			assert.Equal(t, 1, db.Count(q.Eq(FromField, common.BigToAddress(big.NewInt(10)))))
+		}
+	})
 }
 
-func TestIndexDB_Writes(t *testing.T) {
-	ctxList := generateCtx(10)
-	rootDB := setupIndexDB(t)
-	defer rootDB.Close()
+func TestIndexDB_RangeByNumber(t *testing.T) {
+	testCtxDBs(t, func(t *testing.T, backend *ctxDBBackend) {
+		ctxList := generateCtx(20)
+		for i, ctx := range ctxList {
+			ctx.BlockNum = uint64(i / 2) // two ctxs in a block
+		}
+		db := backend.open(big.NewInt(1), 0)
+		db.Clean()
+		assert.NoError(t, db.Writes(ctxList, false))
+
+		// range takes all the ctxs of the last block
+		list := db.RangeByNumber(2, 8, 3)
+		assert.Equal(t, 4, len(list))
+		assert.EqualValues(t, 2, list[0].BlockNum)
+		assert.EqualValues(t, 3, list[3].BlockNum)
+		assert.Equal(t, 14, len(db.RangeByNumber(2, 8, 0)))
+	})
+}
 
-	db := NewIndexDB(big.NewInt(1), rootDB, 20)
-	db.Clean()
+func TestIndexDB_Writes(t *testing.T) {
+	testCtxDBs(t, func(t *testing.T, backend *ctxDBBackend) {
+		ctxList := generateCtx(10)
+		db := backend.open(big.NewInt(1), 20)
+		db.Clean()
 
-	assert.NoError(t, db.Writes(ctxList, false))
-	assert.Equal(t, 10, db.Count())
+		assert.NoError(t, db.Writes(ctxList, false))
+		assert.Equal(t, 10, db.Count())
 
-	// replace to waiting
-	for _, ctx := range ctxList[0:6] {
-		ctx.Status = cc.CtxStatusWaiting
-	}
+		// replace to waiting
+		for _, ctx := range ctxList[0:6] {
+			ctx.Status = cc.CtxStatusWaiting
+		}
 
-	assert.NoError(t, db.Writes(ctxList, true))
-	assert.Equal(t, 6, db.Count(q.Eq(StatusField, cc.CtxStatusWaiting)))
+		assert.NoError(t, db.Writes(ctxList, true))
+		assert.Equal(t, 6, db.Count(q.Eq(StatusField, cc.CtxStatusWaiting)))
 
-	// replace to finishing with number++
-	for _, ctx := range ctxList[0:3] {
-		ctx.Status = cc.CtxStatusFinishing
-	}
+		// replace to finishing with number++
+		for _, ctx := range ctxList[0:3] {
+			ctx.Status = cc.CtxStatusFinishing
+		}
 
-	// replace to finishing without number
-	for _, ctx := range ctxList[3:6] {
-		ctx.Status = cc.CtxStatusFinishing
-		ctx.BlockNum--
-	}
+		// replace to finishing without number
+		for _, ctx := range ctxList[3:6] {
+			ctx.Status = cc.CtxStatusFinishing
+			ctx.BlockNum--
+		}
 
-	assert.NoError(t, db.Writes(ctxList, true))
-	assert.Equal(t, 3, db.Count(q.Eq(StatusField, cc.CtxStatusFinishing)))
+		assert.NoError(t, db.Writes(ctxList, true))
+		assert.Equal(t, 3, db.Count(q.Eq(StatusField, cc.CtxStatusFinishing)))
 
-	// check cache
-	for _, ctx := range ctxList[0:3] {
-		assert.Equal(t, cc.CtxStatusFinishing, db.One(CtxIdIndex, ctx.ID()).Status)
-	}
-	for _, ctx := range ctxList[3:6] {
-		assert.Equal(t, cc.CtxStatusWaiting, db.One(CtxIdIndex, ctx.ID()).Status)
-	}
+		// check cache
+		for _, ctx := range ctxList[0:3] {
+			assert.Equal(t, cc.CtxStatusFinishing, db.One(CtxIdIndex, ctx.ID()).Status)
+		}
+		for _, ctx := range ctxList[3:6] {
+			assert.Equal(t, cc.CtxStatusWaiting, db.One(CtxIdIndex, ctx.ID()).Status)
+		}
+	})
 }
 
 func TestIndexDB_Updates(t *testing.T) {
-	ctxList := generateCtx(10)
-	rootDB := setupIndexDB(t)
-	defer rootDB.Close()
-
-	db := NewIndexDB(big.NewInt(1), rootDB, 20)
-	db.Clean()
-
-	assert.NoError(t, db.Writes(ctxList, false))
-	assert.Equal(t, 10, db.Count())
+	testCtxDBs(t, func(t *testing.T, backend *ctxDBBackend) {
+		ctxList := generateCtx(10)
+		db := backend.open(big.NewInt(1), 20)
+		db.Clean()
+
+		assert.NoError(t, db.Writes(ctxList, false))
+		assert.Equal(t, 10, db.Count())
+
+		var (
+			ids      []common.Hash
+			updaters []func(ctx *CrossTransactionIndexed)
+		)
+
+		for _, ctx := range ctxList[0:6] {
+			ids = append(ids, ctx.ID())
+			updaters = append(updaters, func(ctx *CrossTransactionIndexed) {
+				ctx.Status = uint8(cc.CtxStatusWaiting)
+			})
+		}
 
-	var (
-		ids      []common.Hash
-		updaters []func(ctx *CrossTransactionIndexed)
-	)
+		assert.NoError(t, db.Updates(ids, updaters))
+		assert.Equal(t, 6, db.Count(q.Eq(StatusField, cc.CtxStatusWaiting)))
 
-	for _, ctx := range ctxList[0:6] {
-		ids = append(ids, ctx.ID())
-		updaters = append(updaters, func(ctx *CrossTransactionIndexed) {
-			ctx.Status = uint8(cc.CtxStatusWaiting)
-		})
-	}
-
-	assert.NoError(t, db.Updates(ids, updaters))
-	assert.Equal(t, 6, db.Count(q.Eq(StatusField, cc.CtxStatusWaiting)))
+		for _, ctx := range ctxList[0:6] {
+			assert.Equal(t, cc.CtxStatusWaiting, db.One(CtxIdIndex, ctx.ID()).Status)
+		}
+	})
+}
 
-	for _, ctx := range ctxList[0:6] {
-		assert.Equal(t, cc.CtxStatusWaiting, db.One(CtxIdIndex, ctx.ID()).Status)
-	}
+func TestIndexDB_Deletes(t *testing.T) {
+	testCtxDBs(t, func(t *testing.T, backend *ctxDBBackend) {
+		ctxList := generateCtx(10)
+		db := backend.open(big.NewInt(1), 20)
+		db.Clean()
+		assert.NoError(t, db.Writes(ctxList, false))
+
+		assert.NoError(t, db.Deletes([]common.Hash{ctxList[8].ID(), ctxList[9].ID(), common.BigToHash(big.NewInt(100))}))
+		assert.Equal(t, 8, db.Count())
+		assert.False(t, db.Has(ctxList[9].ID()))
+		assert.EqualValues(t, 7, db.Height())
+
+		assert.NoError(t, db.Repair())
+		assert.Equal(t, 8, db.Count())
+		assert.Equal(t, ctxList[7], db.One(TxHashIndex, ctxList[7].Data.TxHash))
+
+		assert.NoError(t, db.Clean())
+		assert.Equal(t, 0, db.Count())
+		assert.False(t, db.Has(ctxList[0].ID()))
+	})
 }
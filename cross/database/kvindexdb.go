@@ -0,0 +1,750 @@
+package db
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"math/big"
+	"reflect"
+	"sort"
+	"sync"
+	"unsafe"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/common/math"
+	"gbchain-org/go-gbchain/ethdb"
+	"gbchain-org/go-gbchain/log"
+
+	cc "gbchain-org/go-gbchain/cross/core"
+
+	"github.com/asdine/storm/v3/q"
+)
+
+// key schema of kvIndexDB, all the keys of a chain are prefixed by "ctx" + chainID:
+//
+//	p + pk         -> json encoded CrossTransactionIndexed
+//	c + ctxID      -> pk
+//	i + f + v + pk -> nil, secondary index of field f with value v
+//	n              -> the last pk
+var (
+	kvRecordPrefix = []byte("p")
+	kvIDPrefix     = []byte("c")
+	kvIndexPrefix  = []byte("i")
+	kvSequenceKey  = []byte("n")
+
+	errKVCtxNotFound = errors.New("ctx not found")
+)
+
+// kvIndexes are the secondary indexes of kvIndexDB, the values must be encoded in fixed length
+// so that the keys are sorted by the values.
+var kvIndexes = map[FieldName]byte{
+	TxHashIndex:      'h',
+	FromField:        'f',
+	ToField:          't',
+	StatusField:      's',
+	BlockNumField:    'b',
+	DestinationValue: 'v',
+	TokenField:       'k',
+	DestinationToken: 'K',
+}
+
+// kvIndexDB is a CtxDB on top of ethdb.KeyValueStore, which could share the database of node.
+type kvIndexDB struct {
+	chainID *big.Int
+	db      ethdb.KeyValueStore
+	prefix  []byte
+	cache   *IndexDbCache
+	lock    sync.RWMutex
+	logger  log.Logger
+}
+
+func NewKVIndexDB(chainID *big.Int, db ethdb.KeyValueStore, cacheSize uint64) *kvIndexDB {
+	prefix := append([]byte("ctx"), common.LeftPadBytes(chainID.Bytes(), 8)...)
+	log.Info("Open KVIndexDB", "chainID", chainID, "cacheSize", cacheSize)
+	return &kvIndexDB{
+		chainID: chainID,
+		db:      db,
+		prefix:  prefix,
+		cache:   newIndexDbCache(int(cacheSize)),
+		logger:  log.New("name", "chain"+chainID.String()),
+	}
+}
+
+func (d *kvIndexDB) key(parts ...[]byte) []byte {
+	return append(common.CopyBytes(d.prefix), bytes.Join(parts, nil)...)
+}
+
+func encodePK(pk uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, pk)
+	return b
+}
+
+// encodeIndexValue encodes the value of indexed field, it returns false if the field is not indexed
+// or the type of value is mismatched.
+func encodeIndexValue(field FieldName, value interface{}) ([]byte, bool) {
+	switch v := value.(type) {
+	case common.Hash:
+		if field == TxHashIndex {
+			return v.Bytes(), true
+		}
+	case common.Address:
+		if field == FromField || field == ToField || field == TokenField || field == DestinationToken {
+			return v.Bytes(), true
+		}
+	case cc.CtxStatus:
+		if field == StatusField {
+			return []byte{uint8(v)}, true
+		}
+	case uint8:
+		if field == StatusField {
+			return []byte{v}, true
+		}
+	case uint64:
+		if field == BlockNumField {
+			return encodePK(v), true
+		}
+	case *big.Int:
+		if field == DestinationValue {
+			if v == nil {
+				v = new(big.Int)
+			}
+			return math.PaddedBigBytes(v, common.HashLength), true
+		}
+	}
+	return nil, false
+}
+
+func (d *kvIndexDB) indexKeys(ctx *CrossTransactionIndexed) [][]byte {
+	values := map[FieldName]interface{}{
+		TxHashIndex:      ctx.TxHash,
+		FromField:        ctx.From,
+		ToField:          ctx.To,
+		StatusField:      ctx.Status,
+		BlockNumField:    ctx.BlockNum,
+		DestinationValue: ctx.DestinationValue,
+		TokenField:       ctx.Token,
+		DestinationToken: ctx.DestinationToken,
+	}
+	keys := make([][]byte, 0, len(values))
+	for field, value := range values {
+		v, _ := encodeIndexValue(field, value)
+		keys = append(keys, d.key(kvIndexPrefix, []byte{kvIndexes[field]}, v, encodePK(ctx.PK)))
+	}
+	return keys
+}
+
+func (d *kvIndexDB) ChainID() *big.Int {
+	return d.chainID
+}
+
+func (d *kvIndexDB) Count(filter ...q.Matcher) int {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	// count the keys without decoding the records if the filter is served by an index
+	if len(filter) == 0 {
+		return d.countKeys(kvIDPrefix)
+	}
+	_, prefix, indexed := d.indexedEq(filter)
+	if indexed && len(filter) == 1 {
+		return d.countKeys(prefix)
+	}
+	if !indexed {
+		prefix = kvRecordPrefix
+	}
+	var count int
+	d.scan(prefix, indexed, filter, func(*CrossTransactionIndexed) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+// Height returns the max number in the BlockNum index, the database can't iterate in reverse,
+// so the number is found by seeking the index bit by bit from the highest.
+func (d *kvIndexDB) Height() uint64 {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	prefix := d.key(kvIndexPrefix, []byte{kvIndexes[BlockNumField]})
+	var height uint64
+	for bit := 63; bit >= 0; bit-- {
+		if number := height | 1<<uint(bit); d.hasFrom(prefix, encodePK(number)) {
+			height = number
+		}
+	}
+	return height
+}
+
+func (d *kvIndexDB) Write(ctx *cc.CrossTransactionWithSignatures) error {
+	return d.Writes([]*cc.CrossTransactionWithSignatures{ctx}, true)
+}
+
+func (d *kvIndexDB) Writes(ctxList []*cc.CrossTransactionWithSignatures, replaceable bool) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.logger.Debug("write cross transaction", "count", len(ctxList), "replaceable", replaceable)
+
+	seq, err := d.sequence()
+	if err != nil {
+		return err
+	}
+	batch := d.db.NewBatch()
+	written := make(map[common.Hash]*CrossTransactionIndexed, len(ctxList)) // ctxs written in this batch
+	for _, ctx := range ctxList {
+		new := NewCrossTransactionIndexed(ctx)
+		old := written[ctx.ID()]
+		if old == nil {
+			if old, err = d.read(ctx.ID()); err != nil && err != errKVCtxNotFound {
+				return err
+			}
+		}
+		switch {
+		case old == nil:
+			seq++
+			new.PK = seq
+			d.logger.Trace("add new cross transaction",
+				"id", ctx.ID().String(), "status", ctx.Status.String(), "number", ctx.BlockNum)
+
+		case replaceable && new.Status != uint8(cc.CtxStatusPending) && new.BlockNum >= old.BlockNum && cc.CtxStatus(old.Status).Before(cc.CtxStatus(new.Status)):
+			new.PK = old.PK
+			d.logger.Trace("replace cross transaction", "id", ctx.ID().String(),
+				"old_status", cc.CtxStatus(old.Status).String(), "new_status", ctx.Status.String(),
+				"old_height", old.BlockNum, "new_height", ctx.BlockNum)
+			if err := d.deleteIndexes(batch, old); err != nil {
+				return err
+			}
+
+		default:
+			d.logger.Trace("can't add or replace cross transaction", "id", ctx.ID().String(),
+				"old_status", cc.CtxStatus(old.Status).String(), "new_status", ctx.Status.String(),
+				"old_height", old.BlockNum, "new_height", ctx.BlockNum, "replaceable", replaceable)
+			continue
+		}
+		if err := d.put(batch, new); err != nil {
+			return err
+		}
+		written[ctx.ID()] = new
+	}
+	if err := batch.Put(d.key(kvSequenceKey), encodePK(seq)); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return ErrCtxDbFailure{"write batch failed", err}
+	}
+	for _, ctx := range written {
+		d.uncache(ctx)
+	}
+	return nil
+}
+
+func (d *kvIndexDB) Read(ctxId common.Hash) (*cc.CrossTransactionWithSignatures, error) {
+	ctx, err := d.get(ctxId)
+	if err != nil {
+		return nil, err
+	}
+	return ctx.ToCrossTransaction(), nil
+}
+
+func (d *kvIndexDB) One(field FieldName, key interface{}) *cc.CrossTransactionWithSignatures {
+	if d.cache != nil {
+		if ctx := d.cache.Get(field, key); ctx != nil {
+			return ctx.ToCrossTransaction()
+		}
+	}
+
+	var ctx *CrossTransactionIndexed
+	if id, ok := key.(common.Hash); ok && field == CtxIdIndex {
+		ctx, _ = d.get(id)
+	} else if v, ok := encodeIndexValue(field, key); ok {
+		d.lock.RLock()
+		it := d.db.NewIteratorWithPrefix(d.key(kvIndexPrefix, []byte{kvIndexes[field]}, v))
+		if it.Next() {
+			ctx, _ = d.readPK(it.Key()[len(it.Key())-8:])
+		}
+		it.Release()
+		d.lock.RUnlock()
+	} else {
+		// not indexed field, find the first matched ctx
+		d.lock.RLock()
+		d.scan(kvRecordPrefix, false, []q.Matcher{q.Eq(field, key)}, func(c *CrossTransactionIndexed) bool {
+			ctx = c
+			return false
+		})
+		d.lock.RUnlock()
+	}
+	if ctx == nil {
+		return nil
+	}
+	if d.cache != nil {
+		d.cache.Put(field, key, ctx)
+	}
+	return ctx.ToCrossTransaction()
+}
+
+func (d *kvIndexDB) get(ctxId common.Hash) (*CrossTransactionIndexed, error) {
+	if d.cache != nil {
+		if ctx := d.cache.Get(CtxIdIndex, ctxId); ctx != nil {
+			return ctx, nil
+		}
+	}
+	d.lock.RLock()
+	ctx, err := d.read(ctxId)
+	d.lock.RUnlock()
+	if err != nil {
+		return nil, ErrCtxDbFailure{fmt.Sprintf("get ctx:%s failed", ctxId.String()), err}
+	}
+	if d.cache != nil {
+		d.cache.Put(CtxIdIndex, ctxId, ctx)
+	}
+	return ctx, nil
+}
+
+func (d *kvIndexDB) Update(id common.Hash, updater func(ctx *CrossTransactionIndexed)) error {
+	return d.Updates([]common.Hash{id}, []func(ctx *CrossTransactionIndexed){updater})
+}
+
+func (d *kvIndexDB) Updates(idList []common.Hash, updaters []func(ctx *CrossTransactionIndexed)) error {
+	if len(idList) != len(updaters) {
+		return ErrCtxDbFailure{err: errors.New("invalid updates params")}
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	batch := d.db.NewBatch()
+	updated := make(map[common.Hash]*CrossTransactionIndexed, len(idList))
+	for i, id := range idList {
+		ctx := updated[id]
+		if ctx == nil {
+			old, err := d.read(id)
+			if err != nil {
+				return ErrCtxDbFailure{"transaction want to be updated is not exist", err}
+			}
+			if err := d.deleteIndexes(batch, old); err != nil {
+				return err
+			}
+			ctx = old
+		}
+		updaters[i](ctx)
+		updated[id] = ctx
+	}
+	for _, ctx := range updated {
+		if err := d.put(batch, ctx); err != nil {
+			return ErrCtxDbFailure{"transaction update failed", err}
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return ErrCtxDbFailure{"write batch failed", err}
+	}
+	for _, ctx := range updated {
+		d.uncache(ctx)
+	}
+	return nil
+}
+
+func (d *kvIndexDB) Deletes(idList []common.Hash) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	batch := d.db.NewBatch()
+	var deleted []*CrossTransactionIndexed
+	for _, id := range idList {
+		ctx, err := d.read(id)
+		if err != nil {
+			continue
+		}
+		if err := d.deleteIndexes(batch, ctx); err != nil {
+			return err
+		}
+		if err := batch.Delete(d.key(kvRecordPrefix, encodePK(ctx.PK))); err != nil {
+			return err
+		}
+		if err := batch.Delete(d.key(kvIDPrefix, id.Bytes())); err != nil {
+			return err
+		}
+		deleted = append(deleted, ctx)
+	}
+	if err := batch.Write(); err != nil {
+		return ErrCtxDbFailure{"transaction delete failed", err}
+	}
+	for _, ctx := range deleted {
+		d.uncache(ctx)
+	}
+	return nil
+}
+
+func (d *kvIndexDB) Has(id common.Hash) bool {
+	_, err := d.get(id)
+	return err == nil
+}
+
+func (d *kvIndexDB) Query(pageSize int, startPage int, orderBy []FieldName, reverse bool, filter ...q.Matcher) []*cc.CrossTransactionWithSignatures {
+	if pageSize > 0 && startPage <= 0 {
+		return nil
+	}
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	var (
+		ctxs   []*CrossTransactionIndexed
+		prefix = kvRecordPrefix
+		sorted = len(orderBy) == 0
+	)
+	if field, eq, ok := d.indexedEq(filter); ok {
+		// iterate the ctxs with the value of filter, they are in the order of pk
+		prefix, sorted = eq, sorted || len(orderBy) == 1 && orderBy[0] == field
+	} else if len(orderBy) == 1 {
+		// iterate the secondary index if ctxs are ordered by an indexed field
+		if f, ok := kvIndexes[orderBy[0]]; ok {
+			prefix, sorted = append(common.CopyBytes(kvIndexPrefix), f), true
+		}
+	}
+	// stop iteration once the page is filled
+	limit := -1
+	if sorted && !reverse && pageSize > 0 {
+		limit = pageSize * startPage
+	}
+	d.scan(prefix, prefix[0] == kvIndexPrefix[0], filter, func(ctx *CrossTransactionIndexed) bool {
+		ctxs = append(ctxs, ctx)
+		return limit < 0 || len(ctxs) < limit
+	})
+	if !sorted {
+		sort.SliceStable(ctxs, func(i, j int) bool {
+			for _, field := range orderBy {
+				if c := compareField(ctxs[i], ctxs[j], field); c != 0 {
+					return c < 0
+				}
+			}
+			return false
+		})
+	}
+	if reverse {
+		for i, j := 0, len(ctxs)-1; i < j; i, j = i+1, j-1 {
+			ctxs[i], ctxs[j] = ctxs[j], ctxs[i]
+		}
+	}
+	if pageSize > 0 {
+		skip := pageSize * (startPage - 1)
+		if skip >= len(ctxs) {
+			return []*cc.CrossTransactionWithSignatures{}
+		}
+		ctxs = ctxs[skip:]
+		if len(ctxs) > pageSize {
+			ctxs = ctxs[:pageSize]
+		}
+	}
+
+	results := make([]*cc.CrossTransactionWithSignatures, len(ctxs))
+	for i, ctx := range ctxs {
+		results[i] = ctx.ToCrossTransaction()
+	}
+	return results
+}
+
+func (d *kvIndexDB) RangeByNumber(begin, end uint64, limit int) []*cc.CrossTransactionWithSignatures {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	prefix := d.key(kvIndexPrefix, []byte{kvIndexes[BlockNumField]})
+	it := d.db.NewIteratorWithStart(append(common.CopyBytes(prefix), encodePK(begin)...))
+	defer it.Release()
+
+	var results []*cc.CrossTransactionWithSignatures
+	for it.Next() {
+		key := it.Key()
+		if !bytes.HasPrefix(key, prefix) {
+			break
+		}
+		number := binary.BigEndian.Uint64(key[len(prefix):])
+		if number > end {
+			break
+		}
+		// take all the ctxs in the block of the last ctx
+		if limit > 0 && len(results) >= limit && results[len(results)-1].BlockNum != number {
+			break
+		}
+		if ctx, err := d.readPK(key[len(key)-8:]); err == nil {
+			results = append(results, ctx.ToCrossTransaction())
+		}
+	}
+	return results
+}
+
+func (d *kvIndexDB) Load() error {
+	return nil
+}
+
+// Repair rebuilds the secondary indexes from the records
+func (d *kvIndexDB) Repair() error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	batch := d.db.NewBatch()
+	it := d.db.NewIteratorWithPrefix(d.key(kvIndexPrefix))
+	for it.Next() {
+		if err := batch.Delete(common.CopyBytes(it.Key())); err != nil {
+			it.Release()
+			return err
+		}
+	}
+	it.Release()
+	var err error
+	d.scan(kvRecordPrefix, false, nil, func(ctx *CrossTransactionIndexed) bool {
+		for _, key := range d.indexKeys(ctx) {
+			if err = batch.Put(key, nil); err != nil {
+				return false
+			}
+		}
+		if err = batch.Put(d.key(kvIDPrefix, ctx.CtxId.Bytes()), encodePK(ctx.PK)); err != nil {
+			return false
+		}
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err = batch.Write(); err != nil {
+				return false
+			}
+			batch.Reset()
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+func (d *kvIndexDB) Clean() error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	batch := d.db.NewBatch()
+	it := d.db.NewIteratorWithPrefix(d.prefix)
+	defer it.Release()
+	for it.Next() {
+		if err := batch.Delete(common.CopyBytes(it.Key())); err != nil {
+			return err
+		}
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	if d.cache != nil {
+		d.cache.Purge()
+	}
+	return nil
+}
+
+// Close does nothing, the database is closed by its owner
+func (d *kvIndexDB) Close() error {
+	return nil
+}
+
+func (d *kvIndexDB) sequence() (uint64, error) {
+	enc, err := d.db.Get(d.key(kvSequenceKey))
+	if err != nil || len(enc) != 8 {
+		if has, _ := d.db.Has(d.key(kvSequenceKey)); has {
+			return 0, ErrCtxDbFailure{"invalid sequence", err}
+		}
+		return 0, nil
+	}
+	return binary.BigEndian.Uint64(enc), nil
+}
+
+func (d *kvIndexDB) read(ctxId common.Hash) (*CrossTransactionIndexed, error) {
+	pk, err := d.db.Get(d.key(kvIDPrefix, ctxId.Bytes()))
+	if err != nil || len(pk) == 0 {
+		return nil, errKVCtxNotFound
+	}
+	return d.readPK(pk)
+}
+
+func (d *kvIndexDB) readPK(pk []byte) (*CrossTransactionIndexed, error) {
+	enc, err := d.db.Get(d.key(kvRecordPrefix, pk))
+	if err != nil || len(enc) == 0 {
+		return nil, errKVCtxNotFound
+	}
+	var ctx CrossTransactionIndexed
+	if err := json.Unmarshal(enc, &ctx); err != nil {
+		return nil, err
+	}
+	return &ctx, nil
+}
+
+func (d *kvIndexDB) put(batch ethdb.Batch, ctx *CrossTransactionIndexed) error {
+	enc, err := json.Marshal(ctx)
+	if err != nil {
+		return err
+	}
+	pk := encodePK(ctx.PK)
+	if err := batch.Put(d.key(kvRecordPrefix, pk), enc); err != nil {
+		return err
+	}
+	if err := batch.Put(d.key(kvIDPrefix, ctx.CtxId.Bytes()), pk); err != nil {
+		return err
+	}
+	for _, key := range d.indexKeys(ctx) {
+		if err := batch.Put(key, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *kvIndexDB) deleteIndexes(batch ethdb.Batch, ctx *CrossTransactionIndexed) error {
+	for _, key := range d.indexKeys(ctx) {
+		if err := batch.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *kvIndexDB) uncache(ctx *CrossTransactionIndexed) {
+	if d.cache != nil {
+		d.cache.Remove(CtxIdIndex, ctx.CtxId)
+		d.cache.Remove(TxHashIndex, ctx.TxHash)
+	}
+}
+
+// indexedEq returns the field and the index prefix of the first q.Eq matcher in filter on
+// an indexed field, false if the filter can't be served by an index.
+func (d *kvIndexDB) indexedEq(filter []q.Matcher) (FieldName, []byte, bool) {
+	for _, m := range filter {
+		field, value, ok := eqValue(m)
+		if !ok {
+			continue
+		}
+		if v, ok := encodeIndexValue(field, value); ok {
+			return field, append([]byte{kvIndexPrefix[0], kvIndexes[field]}, v...), true
+		}
+	}
+	return "", nil, false
+}
+
+// eqMatcher and eqCmp are the types of q.Eq matcher and its comparator
+var (
+	eqMatcher = reflect.TypeOf(q.Eq("", nil))
+	eqCmp     = reflect.ValueOf(q.Eq("", nil)).FieldByName("FieldMatcher").Elem().Type()
+)
+
+// eqValue returns the field and value of a q.Eq matcher, storm doesn't export the value,
+// so it is read by reflection.
+func eqValue(m q.Matcher) (FieldName, interface{}, bool) {
+	v := reflect.ValueOf(m)
+	if v.Type() != eqMatcher {
+		return "", nil, false
+	}
+	cmp := v.FieldByName("FieldMatcher").Elem()
+	if cmp.Type() != eqCmp || cmp.IsNil() {
+		return "", nil, false
+	}
+	cmp = cmp.Elem()
+	if token.Token(cmp.FieldByName("token").Int()) != token.EQL {
+		return "", nil, false
+	}
+	value := cmp.FieldByName("value")
+	value = reflect.NewAt(value.Type(), unsafe.Pointer(value.UnsafeAddr())).Elem()
+	return v.FieldByName("Field").String(), value.Interface(), true
+}
+
+// countKeys counts the keys under prefix
+func (d *kvIndexDB) countKeys(prefix []byte) int {
+	it := d.db.NewIteratorWithPrefix(d.key(prefix))
+	defer it.Release()
+	var count int
+	for it.Next() {
+		count++
+	}
+	return count
+}
+
+// hasFrom checks whether there is a key under prefix from prefix + start
+func (d *kvIndexDB) hasFrom(prefix, start []byte) bool {
+	it := d.db.NewIteratorWithStart(append(common.CopyBytes(prefix), start...))
+	defer it.Release()
+	return it.Next() && bytes.HasPrefix(it.Key(), prefix)
+}
+
+// scan iterates the records (or the secondary index if byIndex) under prefix in order,
+// calls fn with the ctxs matched filter until fn returns false.
+func (d *kvIndexDB) scan(prefix []byte, byIndex bool, filter []q.Matcher, fn func(*CrossTransactionIndexed) bool) {
+	it := d.db.NewIteratorWithPrefix(d.key(prefix))
+	defer it.Release()
+	matcher := q.And(filter...)
+	for it.Next() {
+		var (
+			ctx *CrossTransactionIndexed
+			err error
+		)
+		if byIndex {
+			key := it.Key()
+			ctx, err = d.readPK(key[len(key)-8:])
+		} else {
+			ctx = new(CrossTransactionIndexed)
+			err = json.Unmarshal(it.Value(), ctx)
+		}
+		if err != nil {
+			continue
+		}
+		if len(filter) > 0 {
+			if ok, err := matcher.Match(ctx); err != nil || !ok {
+				continue
+			}
+		}
+		if !fn(ctx) {
+			return
+		}
+	}
+}
+
+// compareField compares the field of ctxs in the order of storm
+func compareField(a, b *CrossTransactionIndexed, field FieldName) int {
+	cmpBig := func(x, y *big.Int) int {
+		if x == nil || y == nil {
+			return 0
+		}
+		return x.Cmp(y)
+	}
+	cmpUint := func(x, y uint64) int {
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+	switch field {
+	case PK:
+		return cmpUint(a.PK, b.PK)
+	case CtxIdIndex:
+		return bytes.Compare(a.CtxId.Bytes(), b.CtxId.Bytes())
+	case TxHashIndex:
+		return bytes.Compare(a.TxHash.Bytes(), b.TxHash.Bytes())
+	case FromField:
+		return bytes.Compare(a.From.Bytes(), b.From.Bytes())
+	case ToField:
+		return bytes.Compare(a.To.Bytes(), b.To.Bytes())
+	case StatusField:
+		return cmpUint(uint64(a.Status), uint64(b.Status))
+	case BlockNumField:
+		return cmpUint(a.BlockNum, b.BlockNum)
+	case PriceIndex:
+		if a.Price == nil || b.Price == nil {
+			return 0
+		}
+		return a.Price.Cmp(b.Price)
+	case DestinationValue:
+		return cmpBig(a.DestinationValue, b.DestinationValue)
+	case DestinationId:
+		return cmpBig(a.DestinationId, b.DestinationId)
+	}
+	return 0
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"math/big"
+	"testing"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/ethdb/memorydb"
+
+	cc "gbchain-org/go-gbchain/cross/core"
+
+	"github.com/asdine/storm/v3/q"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKVIndexDB_Reopen(t *testing.T) {
+	var (
+		kv      = memorydb.New()
+		ctxList = generateCtx(4)
+	)
+	db := NewKVIndexDB(big.NewInt(1), kv, 10)
+	other := NewKVIndexDB(big.NewInt(2), kv, 10)
+	assert.NoError(t, db.Writes(ctxList[:2], false))
+	assert.NoError(t, other.Writes(ctxList, false))
+
+	db = NewKVIndexDB(big.NewInt(1), kv, 10)
+	assert.Equal(t, 2, db.Count())
+	assert.NoError(t, db.Writes(ctxList[2:], false))
+	assert.Equal(t, 4, db.Count())
+	list := db.Query(0, 0, nil, false)
+	for i, ctx := range list {
+		assert.Equal(t, ctxList[i].ID(), ctx.ID())
+	}
+
+	assert.NoError(t, other.Clean())
+	assert.Equal(t, 4, db.Count())
+}
+
+func TestKVIndexDB_Indexed(t *testing.T) {
+	var (
+		kv      = memorydb.New()
+		ctxList = generateCtx(10)
+		db      = NewKVIndexDB(big.NewInt(1), kv, 0)
+	)
+	defer kv.Close()
+	assert.EqualValues(t, 0, db.Height())
+	ctxList[9].BlockNum = 1<<40 + 1
+	assert.NoError(t, db.Writes(ctxList, false))
+	assert.EqualValues(t, uint64(1<<40+1), db.Height())
+
+	field, value, ok := eqValue(q.Eq(FromField, ctxList[3].Data.From))
+	assert.True(t, ok)
+	assert.Equal(t, FromField, field)
+	assert.Equal(t, ctxList[3].Data.From, value)
+	_, _, ok = eqValue(q.Gte(BlockNumField, uint64(1)))
+	assert.False(t, ok)
+	_, _, ok = db.indexedEq([]q.Matcher{q.Eq(DestinationId, big.NewInt(1))})
+	assert.False(t, ok)
+
+	// the records are not decoded if the filter is served by an index
+	assert.NoError(t, kv.Put(db.key(kvRecordPrefix, encodePK(4)), []byte("corrupted")))
+	assert.Equal(t, 1, db.Count(q.Eq(FromField, ctxList[3].Data.From)))
+	assert.Equal(t, 10, db.Count(q.Eq(StatusField, cc.CtxStatusPending)))
+	assert.Equal(t, 10, db.Count())
+	assert.Equal(t, 9, db.Count(q.Eq(StatusField, cc.CtxStatusPending), q.Gte(BlockNumField, uint64(0))))
+	list := db.Query(2, 2, nil, false, q.Eq(StatusField, cc.CtxStatusPending))
+	assert.Equal(t, 2, len(list))
+	assert.Equal(t, ctxList[2].ID(), list[0].ID())
+	assert.Equal(t, ctxList[4].ID(), list[1].ID())
+}
+
+func TestReindexKV(t *testing.T) {
+	var (
+		kv      = memorydb.New()
+		ctxList = generateCtx(4)
+		token   = common.HexToAddress("0x1111")
+	)
+	ctxList[1].Data.Tokens = []common.Address{token, {}}
+	assert.NoError(t, NewKVIndexDB(big.NewInt(1), kv, 0).Writes(ctxList, false))
+	assert.NoError(t, NewKVIndexDB(big.NewInt(300), kv, 0).Writes(ctxList[:2], false))
+	assert.Equal(t, []*big.Int{big.NewInt(1), big.NewInt(300)}, KVChains(kv))
+
+	assert.NoError(t, reindexKV(kv))
+	for _, chainID := range KVChains(kv) {
+		db := NewKVIndexDB(chainID, kv, 0)
+		assert.Equal(t, ctxList[1], db.One(TokenField, token))
+	}
+	assert.Equal(t, 4, NewKVIndexDB(big.NewInt(1), kv, 0).Count())
+}
+
+func TestMigrateCtxDB(t *testing.T) {
+	root := setupIndexDB(t)
+	defer root.Close()
+	from := NewIndexDB(big.NewInt(7), root, 0)
+	from.Clean()
+	ctxList := generateCtx(10)
+	assert.NoError(t, from.Writes(ctxList, false))
+
+	chains, err := StormChains(root)
+	assert.NoError(t, err)
+	assert.Contains(t, chains, big.NewInt(7))
+
+	to := NewKVIndexDB(big.NewInt(7), memorydb.New(), 0)
+	count, err := MigrateCtxDB(from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, count)
+	assert.Equal(t, from.Query(0, 0, nil, false), to.Query(0, 0, nil, false))
+	assert.Equal(t, from.Height(), to.Height())
+}
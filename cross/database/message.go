@@ -0,0 +1,47 @@
+package db
+
+import (
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/ethdb"
+	"gbchain-org/go-gbchain/rlp"
+
+	cc "gbchain-org/go-gbchain/cross/core"
+)
+
+// messagePrefix + ctxID -> rlp encoded cc.MessageResult
+var messagePrefix = []byte("message")
+
+// MessageStore keeps the confirmed execution results of message ctxs
+type MessageStore struct {
+	db ethdb.KeyValueStore
+}
+
+func NewMessageStore(db ethdb.KeyValueStore) *MessageStore {
+	return &MessageStore{db: db}
+}
+
+func messageKey(id common.Hash) []byte {
+	return append(append([]byte{}, messagePrefix...), id.Bytes()...)
+}
+
+// Put stores the result, the result of a reorged execution is overwritten
+func (s *MessageStore) Put(result *cc.MessageResult) error {
+	enc, err := rlp.EncodeToBytes(result)
+	if err != nil {
+		return err
+	}
+	return s.db.Put(messageKey(result.CTxId), enc)
+}
+
+// Get returns the result of message ctx, nil if the message is not executed yet
+func (s *MessageStore) Get(id common.Hash) (*cc.MessageResult, error) {
+	enc, err := s.db.Get(messageKey(id))
+	if err != nil || len(enc) == 0 {
+		return nil, nil
+	}
+	var result cc.MessageResult
+	if err := rlp.DecodeBytes(enc, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
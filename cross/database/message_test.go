@@ -0,0 +1,32 @@
+package db
+
+import (
+	"testing"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/cross/core"
+	"gbchain-org/go-gbchain/ethdb/memorydb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageStore(t *testing.T) {
+	db := memorydb.New()
+	defer db.Close()
+
+	store := NewMessageStore(db)
+	result := &core.MessageResult{
+		CTxId:   common.HexToHash("0x01"),
+		TxHash:  common.HexToHash("0x02"),
+		Target:  common.HexToAddress("0x03"),
+		Success: true,
+		Result:  []byte{4, 5},
+	}
+	got, err := store.Get(result.CTxId)
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	assert.NoError(t, store.Put(result))
+	got, err = NewMessageStore(db).Get(result.CTxId)
+	assert.NoError(t, err)
+	assert.Equal(t, result, got)
+}
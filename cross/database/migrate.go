@@ -0,0 +1,101 @@
+package db
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/ethdb"
+
+	"github.com/asdine/storm/v3"
+	bolt "go.etcd.io/bbolt"
+)
+
+// migrateBatchSize is the count of ctxs copied in one batch
+const migrateBatchSize = 1000
+
+// StormChains returns the chainIDs of indexDBs stored in the storm db
+func StormChains(root *storm.DB) ([]*big.Int, error) {
+	var chains []*big.Int
+	err := root.Bolt.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+			if id := strings.TrimPrefix(string(name), "chain"); id != string(name) {
+				if chainID, ok := new(big.Int).SetString(id, 10); ok {
+					chains = append(chains, chainID)
+				}
+			}
+			return nil
+		})
+	})
+	return chains, err
+}
+
+// MigrateCtxDB copies all the ctxs of from into to in the order of insertion,
+// ctxs existed in to are not replaced. It returns the count of ctxs copied.
+func MigrateCtxDB(from, to CtxDB) (int, error) {
+	var count int
+	for page := 1; ; page++ {
+		ctxs := from.Query(migrateBatchSize, page, nil, false)
+		if len(ctxs) == 0 {
+			return count, nil
+		}
+		if err := to.Writes(ctxs, false); err != nil {
+			return count, err
+		}
+		count += len(ctxs)
+	}
+}
+
+// reindexStorm rebuilds the indexes of all the indexDBs stored in the storm db
+func reindexStorm(root *storm.DB) error {
+	chains, err := StormChains(root)
+	if err != nil {
+		return err
+	}
+	for _, chainID := range chains {
+		err := root.From("chain" + chainID.String()).ReIndex(&CrossTransactionIndexed{})
+		if err != nil && err != storm.ErrNotFound { // the chain without ctxs
+			return err
+		}
+	}
+	return nil
+}
+
+// KVChains returns the chainIDs of kvIndexDBs stored in the key-value database
+func KVChains(db ethdb.KeyValueStore) []*big.Int {
+	var (
+		chains []*big.Int
+		prefix = []byte("ctx")
+		start  = prefix
+	)
+	for {
+		it := db.NewIteratorWithStart(start)
+		ok := it.Next() && bytes.HasPrefix(it.Key(), prefix) && len(it.Key()) > len(prefix)+8
+		var id []byte
+		if ok {
+			id = common.CopyBytes(it.Key()[len(prefix) : len(prefix)+8])
+		}
+		it.Release()
+		if !ok {
+			return chains
+		}
+		chains = append(chains, new(big.Int).SetBytes(id))
+		// seek to the next chain
+		next := new(big.Int).Add(new(big.Int).SetBytes(id), common.Big1)
+		if next.BitLen() > 64 {
+			return chains
+		}
+		start = append(common.CopyBytes(prefix), common.LeftPadBytes(next.Bytes(), 8)...)
+	}
+}
+
+// reindexKV rebuilds the secondary indexes of all the kvIndexDBs stored in the key-value database
+func reindexKV(db ethdb.KeyValueStore) error {
+	for _, chainID := range KVChains(db) {
+		if err := NewKVIndexDB(chainID, db, 0).Repair(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
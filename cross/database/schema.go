@@ -0,0 +1,101 @@
+package db
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"gbchain-org/go-gbchain/ethdb"
+	"gbchain-org/go-gbchain/log"
+
+	"github.com/asdine/storm/v3"
+)
+
+// SchemaVersion is the version of the layout of cross databases,
+// it must be increased with a migration appended when the layout changed.
+const SchemaVersion uint64 = 2
+
+var ErrUnknownSchema = errors.New("unknown schema version")
+
+var (
+	schemaBucket = "schema"
+	schemaKey    = "version"
+	kvSchemaKey  = []byte("_schemaVersion")
+)
+
+// stormMigrations[i] upgrades the storm database (ctxs indexed by indexDB) from version i to i+1
+var stormMigrations = []func(db *storm.DB) error{
+	// 0 -> 1: the layout before versioning is kept, only the version is recorded
+	func(*storm.DB) error { return nil },
+	// 1 -> 2: ctxs are indexed by tokens
+	reindexStorm,
+}
+
+// kvMigrations[i] upgrades the key-value database (queue, transaction logs and kvIndexDB) from version i to i+1
+var kvMigrations = []func(db ethdb.KeyValueStore) error{
+	// 0 -> 1: the layout before versioning is kept, only the version is recorded
+	func(ethdb.KeyValueStore) error { return nil },
+	// 1 -> 2: ctxs are indexed by tokens
+	reindexKV,
+}
+
+// upgradeStorm migrates the storm database to the latest version of migrations.
+// A new database is marked as the latest version directly.
+func upgradeStorm(db *storm.DB, migrations []func(db *storm.DB) error) error {
+	var version uint64
+	switch err := db.Get(schemaBucket, schemaKey, &version); err {
+	case nil:
+	case storm.ErrNotFound:
+		chains, err := StormChains(db)
+		if err != nil {
+			return ErrCtxDbFailure{"read chains failed", err}
+		}
+		if len(chains) == 0 {
+			version = uint64(len(migrations))
+		}
+	default:
+		return ErrCtxDbFailure{"read schema version failed", err}
+	}
+	for ; version < uint64(len(migrations)); version++ {
+		log.Info("Upgrade cross database", "from", version, "to", version+1)
+		if err := migrations[version](db); err != nil {
+			return ErrCtxDbFailure{fmt.Sprintf("upgrade schema to %d failed", version+1), err}
+		}
+		if err := db.Set(schemaBucket, schemaKey, version+1); err != nil {
+			return ErrCtxDbFailure{"write schema version failed", err}
+		}
+	}
+	if version > uint64(len(migrations)) {
+		return fmt.Errorf("%w %d, supported %d", ErrUnknownSchema, version, len(migrations))
+	}
+	return db.Set(schemaBucket, schemaKey, version)
+}
+
+// upgradeKV migrates the key-value database to the latest version of migrations.
+// A new database is marked as the latest version directly.
+func upgradeKV(db ethdb.KeyValueStore, migrations []func(db ethdb.KeyValueStore) error) error {
+	var version uint64
+	if enc, err := db.Get(kvSchemaKey); err == nil && len(enc) == 8 {
+		version = binary.BigEndian.Uint64(enc)
+	} else {
+		it := db.NewIterator()
+		empty := !it.Next()
+		it.Release()
+		if empty {
+			version = uint64(len(migrations))
+		}
+	}
+	for ; version < uint64(len(migrations)); version++ {
+		log.Info("Upgrade cross database", "from", version, "to", version+1)
+		if err := migrations[version](db); err != nil {
+			return ErrCtxDbFailure{fmt.Sprintf("upgrade schema to %d failed", version+1), err}
+		}
+		if err := db.Put(kvSchemaKey, encodePK(version+1)); err != nil {
+			return ErrCtxDbFailure{"write schema version failed", err}
+		}
+	}
+	if version > uint64(len(migrations)) {
+		return fmt.Errorf("%w %d, supported %d", ErrUnknownSchema, version, len(migrations))
+	}
+	return db.Put(kvSchemaKey, encodePK(version))
+}
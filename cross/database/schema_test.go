@@ -0,0 +1,83 @@
+package db
+
+import (
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gbchain-org/go-gbchain/ethdb"
+	"gbchain-org/go-gbchain/ethdb/memorydb"
+
+	"github.com/asdine/storm/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaVersion(t *testing.T) {
+	assert.EqualValues(t, SchemaVersion, len(stormMigrations))
+	assert.EqualValues(t, SchemaVersion, len(kvMigrations))
+}
+
+func TestUpgradeStorm(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cross-schema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	db, err := storm.Open(filepath.Join(dir, "ctx.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var upgraded []int
+	migrations := []func(*storm.DB) error{
+		func(*storm.DB) error { upgraded = append(upgraded, 1); return nil },
+		func(*storm.DB) error { upgraded = append(upgraded, 2); return nil },
+	}
+
+	// the legacy database without version record
+	assert.NoError(t, NewIndexDB(big.NewInt(1), db, 0).Write(generateCtx(1)[0]))
+	assert.NoError(t, upgradeStorm(db, migrations[:1]))
+	assert.Equal(t, []int{1}, upgraded)
+
+	assert.NoError(t, upgradeStorm(db, migrations))
+	assert.Equal(t, []int{1, 2}, upgraded)
+	var version uint64
+	assert.NoError(t, db.Get(schemaBucket, schemaKey, &version))
+	assert.EqualValues(t, 2, version)
+
+	// the database is newer than supported
+	err = upgradeStorm(db, migrations[:1])
+	assert.True(t, errors.Is(err, ErrUnknownSchema))
+}
+
+func TestUpgradeKV(t *testing.T) {
+	var upgraded int
+	migrations := []func(ethdb.KeyValueStore) error{
+		func(ethdb.KeyValueStore) error { upgraded++; return nil },
+	}
+
+	// the new database is the latest version
+	db := memorydb.New()
+	assert.NoError(t, upgradeKV(db, migrations))
+	assert.Equal(t, 0, upgraded)
+	enc, err := db.Get(kvSchemaKey)
+	assert.NoError(t, err)
+	assert.Equal(t, encodePK(1), enc)
+
+	// the legacy database without version record
+	db = memorydb.New()
+	assert.NoError(t, db.Put(readPos, encodePK(1)))
+	assert.NoError(t, upgradeKV(db, migrations))
+	assert.Equal(t, 1, upgraded)
+	assert.NoError(t, upgradeKV(db, migrations))
+	assert.Equal(t, 1, upgraded)
+
+	// the database is newer than supported
+	assert.NoError(t, db.Put(kvSchemaKey, encodePK(2)))
+	err = upgradeKV(db, migrations)
+	assert.True(t, errors.Is(err, ErrUnknownSchema))
+}
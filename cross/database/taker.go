@@ -0,0 +1,138 @@
+package db
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/ethdb"
+	"gbchain-org/go-gbchain/rlp"
+)
+
+// takerPrefix + chainID + remoteID + kind + ... -> rlp encoded records of auto taker
+var takerPrefix = []byte("taker")
+
+const (
+	takerOrderKind      = byte('o') // + orderID -> TakerOrder
+	takerSubmissionKind = byte('s') // + ctxID -> TakerSubmission
+	takerNextIDKind     = byte('n') // -> next order ID
+)
+
+// TakerOrder is the standing order of a taker account, MaxPrice is PriceNum / PriceDenom
+type TakerOrder struct {
+	ID               uint64
+	Account          common.Address
+	Token            common.Address
+	DestinationToken common.Address
+	PriceNum         *big.Int
+	PriceDenom       *big.Int
+	Budget           *big.Int
+	Spent            *big.Int
+}
+
+// TakerSubmission is a ctx being taken by an order
+type TakerSubmission struct {
+	CtxID common.Hash
+	Order uint64
+	Value *big.Int
+	Hash  common.Hash // the taker transaction
+	Time  uint64      // unix time taken
+}
+
+// TakerStore keeps the orders of auto taker and the ctxs being taken by them for each chain pair,
+// so that they survive the restart of node.
+type TakerStore struct {
+	db ethdb.KeyValueStore
+}
+
+func NewTakerStore(db ethdb.KeyValueStore) *TakerStore {
+	return &TakerStore{db: db}
+}
+
+func takerKindPrefix(chainID, remoteID *big.Int, kind byte) []byte {
+	key := append(append([]byte{}, takerPrefix...), common.LeftPadBytes(chainID.Bytes(), 8)...)
+	key = append(key, common.LeftPadBytes(remoteID.Bytes(), 8)...)
+	return append(key, kind)
+}
+
+func takerOrderKey(chainID, remoteID *big.Int, id uint64) []byte {
+	var enc [8]byte
+	binary.BigEndian.PutUint64(enc[:], id)
+	return append(takerKindPrefix(chainID, remoteID, takerOrderKind), enc[:]...)
+}
+
+func takerSubmissionKey(chainID, remoteID *big.Int, ctxID common.Hash) []byte {
+	return append(takerKindPrefix(chainID, remoteID, takerSubmissionKind), ctxID.Bytes()...)
+}
+
+func (s *TakerStore) put(key []byte, val interface{}) error {
+	enc, err := rlp.EncodeToBytes(val)
+	if err != nil {
+		return err
+	}
+	return s.db.Put(key, enc)
+}
+
+// PutOrder stores the order, the order of the same ID is overwritten
+func (s *TakerStore) PutOrder(chainID, remoteID *big.Int, order *TakerOrder) error {
+	return s.put(takerOrderKey(chainID, remoteID, order.ID), order)
+}
+
+func (s *TakerStore) DeleteOrder(chainID, remoteID *big.Int, id uint64) error {
+	return s.db.Delete(takerOrderKey(chainID, remoteID, id))
+}
+
+// Orders returns the orders of chain pair in the order of IDs
+func (s *TakerStore) Orders(chainID, remoteID *big.Int) ([]*TakerOrder, error) {
+	var orders []*TakerOrder
+	it := s.db.NewIteratorWithPrefix(takerKindPrefix(chainID, remoteID, takerOrderKind))
+	defer it.Release()
+	for it.Next() {
+		var order TakerOrder
+		if err := rlp.DecodeBytes(it.Value(), &order); err != nil {
+			return nil, err
+		}
+		orders = append(orders, &order)
+	}
+	return orders, it.Error()
+}
+
+// PutSubmission stores the submission, the submission of the same ctx is overwritten
+func (s *TakerStore) PutSubmission(chainID, remoteID *big.Int, sub *TakerSubmission) error {
+	return s.put(takerSubmissionKey(chainID, remoteID, sub.CtxID), sub)
+}
+
+func (s *TakerStore) DeleteSubmission(chainID, remoteID *big.Int, ctxID common.Hash) error {
+	return s.db.Delete(takerSubmissionKey(chainID, remoteID, ctxID))
+}
+
+// Submissions returns the ctxs being taken of chain pair
+func (s *TakerStore) Submissions(chainID, remoteID *big.Int) ([]*TakerSubmission, error) {
+	var subs []*TakerSubmission
+	it := s.db.NewIteratorWithPrefix(takerKindPrefix(chainID, remoteID, takerSubmissionKind))
+	defer it.Release()
+	for it.Next() {
+		var sub TakerSubmission
+		if err := rlp.DecodeBytes(it.Value(), &sub); err != nil {
+			return nil, err
+		}
+		subs = append(subs, &sub)
+	}
+	return subs, it.Error()
+}
+
+// SetNextID stores the ID of the next order, so that the IDs of canceled orders are not reused
+func (s *TakerStore) SetNextID(chainID, remoteID *big.Int, id uint64) error {
+	var enc [8]byte
+	binary.BigEndian.PutUint64(enc[:], id)
+	return s.db.Put(takerKindPrefix(chainID, remoteID, takerNextIDKind), enc[:])
+}
+
+// NextID returns the ID of the next order, 0 if it is never stored
+func (s *TakerStore) NextID(chainID, remoteID *big.Int) uint64 {
+	enc, err := s.db.Get(takerKindPrefix(chainID, remoteID, takerNextIDKind))
+	if err != nil || len(enc) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(enc)
+}
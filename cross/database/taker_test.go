@@ -0,0 +1,51 @@
+package db
+
+import (
+	"math/big"
+	"testing"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/ethdb/memorydb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTakerStore(t *testing.T) {
+	db := memorydb.New()
+	defer db.Close()
+
+	var (
+		store    = NewTakerStore(db)
+		chainID  = big.NewInt(1)
+		remoteID = big.NewInt(2)
+	)
+	orders := []*TakerOrder{
+		{ID: 2, Account: common.HexToAddress("0x01"), PriceNum: big.NewInt(3), PriceDenom: big.NewInt(2), Budget: big.NewInt(100), Spent: big.NewInt(10)},
+		{ID: 1, Account: common.HexToAddress("0x02"), PriceNum: big.NewInt(1), PriceDenom: big.NewInt(1), Budget: big.NewInt(50), Spent: big.NewInt(0)},
+	}
+	for _, order := range orders {
+		assert.NoError(t, store.PutOrder(chainID, remoteID, order))
+	}
+	sub := &TakerSubmission{CtxID: common.HexToHash("0x03"), Order: 2, Value: big.NewInt(10), Hash: common.HexToHash("0x04"), Time: 5}
+	assert.NoError(t, store.PutSubmission(chainID, remoteID, sub))
+	assert.NoError(t, store.SetNextID(chainID, remoteID, 3))
+
+	// the orders are listed by IDs, and the ones of other pairs are not included
+	got, err := store.Orders(chainID, remoteID)
+	assert.NoError(t, err)
+	assert.Equal(t, []*TakerOrder{orders[1], orders[0]}, got)
+	got, err = store.Orders(remoteID, chainID)
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+	subs, err := store.Submissions(chainID, remoteID)
+	assert.NoError(t, err)
+	assert.Equal(t, []*TakerSubmission{sub}, subs)
+	assert.EqualValues(t, 3, store.NextID(chainID, remoteID))
+	assert.EqualValues(t, 0, store.NextID(remoteID, chainID))
+
+	assert.NoError(t, store.DeleteOrder(chainID, remoteID, 1))
+	assert.NoError(t, store.DeleteSubmission(chainID, remoteID, sub.CtxID))
+	got, _ = store.Orders(chainID, remoteID)
+	assert.Equal(t, []*TakerOrder{orders[0]}, got)
+	subs, _ = store.Submissions(chainID, remoteID)
+	assert.Empty(t, subs)
+}
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/ethdb"
+)
+
+// takerTxPrefix + ctxID -> hash of the taker transaction in destination chain
+var takerTxPrefix = []byte("ctxTaker")
+
+// TakerTxStore keeps the transactions taking ctxs, so that the taker of a ctx is found without
+// scanning the logs of destination chain
+type TakerTxStore struct {
+	db ethdb.KeyValueStore
+}
+
+func NewTakerTxStore(db ethdb.KeyValueStore) *TakerTxStore {
+	return &TakerTxStore{db: db}
+}
+
+func takerTxKey(id common.Hash) []byte {
+	return append(append([]byte{}, takerTxPrefix...), id.Bytes()...)
+}
+
+// Put stores the taker transaction of ctx, the reorged one is overwritten
+func (s *TakerTxStore) Put(id, hash common.Hash) error {
+	return s.db.Put(takerTxKey(id), hash.Bytes())
+}
+
+// Delete removes the taker transaction of ctx if it is hash, the taker stored by another
+// transaction is kept
+func (s *TakerTxStore) Delete(id, hash common.Hash) error {
+	if stored, ok := s.Get(id); !ok || stored != hash {
+		return nil
+	}
+	return s.db.Delete(takerTxKey(id))
+}
+
+// Get returns the taker transaction of ctx, false if the ctx is not taken
+func (s *TakerTxStore) Get(id common.Hash) (common.Hash, bool) {
+	enc, err := s.db.Get(takerTxKey(id))
+	if err != nil || len(enc) != common.HashLength {
+		return common.Hash{}, false
+	}
+	return common.BytesToHash(enc), true
+}
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"testing"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/ethdb/memorydb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTakerTxStore(t *testing.T) {
+	db := memorydb.New()
+	defer db.Close()
+
+	var (
+		store   = NewTakerTxStore(db)
+		id      = common.HexToHash("0x01")
+		taker   = common.HexToHash("0x02")
+		reorged = common.HexToHash("0x03")
+	)
+	_, ok := store.Get(id)
+	assert.False(t, ok)
+
+	assert.NoError(t, store.Put(id, reorged))
+	assert.NoError(t, store.Put(id, taker))
+	got, ok := NewTakerTxStore(db).Get(id)
+	assert.True(t, ok)
+	assert.Equal(t, taker, got)
+
+	// the reorg of an overwritten taker keeps the current one
+	assert.NoError(t, store.Delete(id, reorged))
+	_, ok = store.Get(id)
+	assert.True(t, ok)
+
+	assert.NoError(t, store.Delete(id, taker))
+	_, ok = store.Get(id)
+	assert.False(t, ok)
+}
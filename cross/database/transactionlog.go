@@ -1,6 +1,7 @@
 package db
 
 import (
+	"bytes"
 	"math/big"
 	"sync"
 
@@ -20,6 +21,7 @@ type TransactionLogs struct {
 	diskDB   ethdb.KeyValueStore
 	trieDB   *trie.Database
 	finished *trie.Trie
+	archive  *CtxArchive // finished ctxs moved out of the live index, nil if no archive
 
 	lock sync.RWMutex
 }
@@ -39,6 +41,13 @@ func NewTransactionLogs(db ethdb.KeyValueStore) (*TransactionLogs, error) {
 	return &TransactionLogs{diskDB: db, trieDB: database, finished: finished}, nil
 }
 
+// SetArchive sets the archive of finished ctxs, the archived ctxs are read as the finished ones
+func (l *TransactionLogs) SetArchive(archive *CtxArchive) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.archive = archive
+}
+
 func (l *TransactionLogs) Get(chainID *big.Int) *TransactionLog {
 	return &TransactionLog{
 		TransactionLogs: l,
@@ -76,6 +85,10 @@ func (l *TransactionLog) GetFinish(hash common.Hash) (*core.CrossTransactionWith
 	if err != nil {
 		return nil, false
 	}
+	if len(enc) == 0 && l.archive != nil {
+		ctx, err := l.archive.Get(l.chainID, hash)
+		return ctx, err == nil
+	}
 	var ctx core.CrossTransactionWithSignatures
 	if err := rlp.DecodeBytes(enc, &ctx); err != nil {
 		return nil, false
@@ -88,9 +101,38 @@ func (l *TransactionLog) IsFinish(hash common.Hash) bool {
 	defer l.lock.RUnlock()
 
 	b, err := l.finished.TryGet(getKey(l.chainID, hash))
+	if err == nil && len(b) == 0 && l.archive != nil {
+		return l.archive.Has(l.chainID, hash)
+	}
 	return err == nil && len(b) > 0
 }
 
+// Iterate calls fn with the finished ctxs in the log until fn returns false, the
+// archived ctxs are not included.
+func (l *TransactionLog) Iterate(fn func(ctx *core.CrossTransactionWithSignatures) bool) error {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	prefix := l.chainID.Bytes()
+	it := trie.NewIterator(l.finished.NodeIterator(prefix))
+	for it.Next() {
+		if !bytes.HasPrefix(it.Key, prefix) {
+			break
+		}
+		if len(it.Key) != len(prefix)+common.HashLength { // key of other chain sharing the prefix
+			continue
+		}
+		var ctx core.CrossTransactionWithSignatures
+		if err := rlp.DecodeBytes(it.Value, &ctx); err != nil {
+			return err
+		}
+		if !fn(&ctx) {
+			return nil
+		}
+	}
+	return it.Err
+}
+
 func (l *TransactionLog) Commit() (common.Hash, error) {
 	l.lock.Lock()
 	defer l.lock.Unlock()
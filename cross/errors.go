@@ -16,5 +16,6 @@ var (
 	ErrReorgCtx        = fmt.Errorf("[%w]: ctx is on sidechain", ErrVerifyCtx)
 	ErrInternal        = fmt.Errorf("[%w]: internal error", ErrVerifyCtx)
 	ErrRepetitionCtx   = fmt.Errorf("[%w]: repetition cross transaction", ErrVerifyCtx) // 合约重复接单
+	ErrInvalidProofCtx = fmt.Errorf("[%w]: verify receipt proof failed", ErrVerifyCtx)
 
 )
@@ -18,7 +18,15 @@ type ProtocolChain interface {
 type ServiceContext struct {
 	Config        *Config
 	ProtocolChain ProtocolChain
+	Contract      common.Address
 	Subscriber    trigger.Subscriber
 	Retriever     trigger.ChainRetriever
 	Executor      trigger.Executor
 }
+
+// ServicePair is a pair of chains bridged by the cross service, a chain could
+// appear in several pairs but must share the same ServiceContext.
+type ServicePair struct {
+	Main *ServiceContext
+	Sub  *ServiceContext
+}
@@ -0,0 +1,81 @@
+package cross
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+
+	"gbchain-org/go-gbchain/accounts"
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/crypto"
+)
+
+// AnchorSigner signs ctxs and transactions by the anchor account, which is unlocked in
+// the keystore of node or held by an external signer like clef.
+type AnchorSigner struct {
+	account  accounts.Account
+	manager  *accounts.Manager
+	external accounts.Wallet
+}
+
+// NewAnchorSigner creates the signer of anchor, the account is found in manager if external is nil
+func NewAnchorSigner(manager *accounts.Manager, anchor common.Address, external accounts.Wallet) *AnchorSigner {
+	return &AnchorSigner{
+		account:  accounts.Account{Address: anchor},
+		manager:  manager,
+		external: external,
+	}
+}
+
+func (s *AnchorSigner) wallet() (accounts.Wallet, error) {
+	if s.external != nil {
+		return s.external, nil
+	}
+	return s.manager.Find(s.account)
+}
+
+func (s *AnchorSigner) SignHash(hash []byte) ([]byte, error) {
+	wallet, err := s.wallet()
+	if err != nil {
+		return nil, err
+	}
+	return wallet.SignHash(s.account, hash)
+}
+
+// SignData signs the signing data of ctx, the external signer checks the ctx by the data
+func (s *AnchorSigner) SignData(data []byte) ([]byte, error) {
+	wallet, err := s.wallet()
+	if err != nil {
+		return nil, err
+	}
+	return wallet.SignData(s.account, accounts.MimetypeCrossCtx, data)
+}
+
+func (s *AnchorSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	wallet, err := s.wallet()
+	if err != nil {
+		return nil, err
+	}
+	return wallet.SignTx(s.account, tx, chainID)
+}
+
+// KeySigner signs ctxs and transactions by a private key in memory, it is used by tests and tools
+type KeySigner struct {
+	key *ecdsa.PrivateKey
+}
+
+func NewKeySigner(key *ecdsa.PrivateKey) *KeySigner {
+	return &KeySigner{key: key}
+}
+
+func (s *KeySigner) SignHash(hash []byte) ([]byte, error) {
+	return crypto.Sign(hash, s.key)
+}
+
+func (s *KeySigner) SignData(data []byte) ([]byte, error) {
+	return crypto.Sign(crypto.Keccak256(data), s.key)
+}
+
+func (s *KeySigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.NewEIP155Signer(chainID), s.key)
+}
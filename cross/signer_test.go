@@ -0,0 +1,162 @@
+package cross
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"gbchain-org/go-gbchain/accounts"
+	"gbchain-org/go-gbchain/accounts/abi"
+	"gbchain-org/go-gbchain/accounts/external"
+	"gbchain-org/go-gbchain/accounts/keystore"
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/common/hexutil"
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/crypto"
+	"gbchain-org/go-gbchain/internal/ethapi"
+	"gbchain-org/go-gbchain/params"
+	"gbchain-org/go-gbchain/rpc"
+	"gbchain-org/go-gbchain/signer/core"
+	"gbchain-org/go-gbchain/signer/rules"
+	"gbchain-org/go-gbchain/signer/storage"
+
+	cc "gbchain-org/go-gbchain/cross/core"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testPassword = "anchor password"
+
+// anchorRules approves the cross calls and ctxs only, the others go to the manual UI which rejects all
+const anchorRules = `
+function ApproveCrossTx(r) {
+	if (r.method == "makerFinish") { return "Approve" }
+	return "Reject"
+}
+function ApproveSignData(r) {
+	if (r.content_type == "application/x-cross-ctx") { return "Approve" }
+	return "Reject"
+}`
+
+// rejectUI is the manual UI of signer, it rejects all requests and answers the password
+type rejectUI struct{}
+
+func (rejectUI) ApproveTx(*core.SignTxRequest) (core.SignTxResponse, error) {
+	return core.SignTxResponse{Approved: false}, nil
+}
+func (rejectUI) ApproveSignData(*core.SignDataRequest) (core.SignDataResponse, error) {
+	return core.SignDataResponse{Approved: false}, nil
+}
+func (rejectUI) ApproveListing(*core.ListRequest) (core.ListResponse, error) {
+	return core.ListResponse{}, nil
+}
+func (rejectUI) ApproveNewAccount(*core.NewAccountRequest) (core.NewAccountResponse, error) {
+	return core.NewAccountResponse{Approved: false}, nil
+}
+func (rejectUI) ShowError(string)                          {}
+func (rejectUI) ShowInfo(string)                           {}
+func (rejectUI) OnApprovedTx(ethapi.SignTransactionResult) {}
+func (rejectUI) OnSignerStartup(core.StartupInfo)          {}
+func (rejectUI) RegisterUIServer(*core.UIServerAPI)        {}
+func (rejectUI) OnInputRequired(core.UserInputRequest) (core.UserInputResponse, error) {
+	return core.UserInputResponse{Text: testPassword}, nil
+}
+
+type noValidator struct{}
+
+func (noValidator) ValidateTransaction(*string, *core.SendTxArgs) (*core.ValidationMessages, error) {
+	return &core.ValidationMessages{}, nil
+}
+
+// newTestSigner starts a clef-compatible signer holding the key, which is ruled by anchorRules
+func newTestSigner(t *testing.T, key string, chainID int64) (url string, stop func()) {
+	dir, err := ioutil.TempDir("", "cross-signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	privateKey, _ := crypto.HexToECDSA(key)
+	if _, err := ks.ImportECDSA(privateKey, testPassword); err != nil {
+		t.Fatal(err)
+	}
+	ui, err := rules.NewRuleEvaluator(rejectUI{}, &storage.NoStorage{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ui.Init(anchorRules)
+	api := core.NewSignerAPI(accounts.NewManager(&accounts.Config{}, ks), chainID, true, ui, noValidator{}, false, &storage.NoStorage{})
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", api); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(server)
+	return ts.URL, func() {
+		ts.Close()
+		server.Stop()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestAnchorSigner_External(t *testing.T) {
+	var (
+		key     = "b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291"
+		anchor  = common.HexToAddress("0x71562b71999873DB5b286dF957af199Ec94617F7")
+		chainID = big.NewInt(1337)
+	)
+	url, stop := newTestSigner(t, key, chainID.Int64())
+	defer stop()
+
+	ext, err := external.NewExternalSigner(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := NewAnchorSigner(nil, anchor, ext)
+
+	// ctxs are signed by the signing data
+	ctxSigner := cc.MakeCtxSigner(chainID)
+	ctx := cc.NewCrossTransaction(big.NewInt(1e18), big.NewInt(2e18), big.NewInt(22),
+		common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03"),
+		common.HexToAddress("0x04"), common.Address{}, []byte("input"))
+	signed, err := cc.SignCtxData(ctx, ctxSigner, signer.SignData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, err := cc.CtxSender(ctxSigner, signed)
+	assert.NoError(t, err)
+	assert.Equal(t, anchor, from)
+
+	// the same signature as signed by the local key
+	privateKey, _ := crypto.HexToECDSA(key)
+	local, err := cc.SignCtx(ctx, ctxSigner, NewKeySigner(privateKey).SignHash)
+	assert.NoError(t, err)
+	assert.Equal(t, local.Data, signed.Data)
+
+	// the calls of cross contract are whitelisted by rules
+	data, _ := hexutil.Decode(params.CrossDemoAbi)
+	crossABI, err := abi.JSON(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	finish, err := cc.NewReceptTransaction(common.HexToHash("0x01"), common.HexToHash("0x02"), anchor,
+		common.HexToAddress("0x05"), big.NewInt(22), chainID).ConstructData(crossABI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contract := common.HexToAddress("0xc055")
+	tx, err := signer.SignTx(types.NewTransaction(7, contract, big.NewInt(0), 250000, big.NewInt(1e9), finish), chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender, err := types.Sender(types.NewEIP155Signer(chainID), tx)
+	assert.NoError(t, err)
+	assert.Equal(t, anchor, sender)
+	assert.EqualValues(t, 7, tx.Nonce())
+
+	// the others are rejected
+	_, err = signer.SignTx(types.NewTransaction(8, contract, big.NewInt(1), 21000, big.NewInt(1e9), nil), chainID)
+	assert.Error(t, err)
+}
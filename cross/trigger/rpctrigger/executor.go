@@ -0,0 +1,403 @@
+package rpctrigger
+
+import (
+	"bytes"
+	"math/big"
+	"sync"
+	"time"
+
+	gbchian "gbchain-org/go-gbchain"
+	"gbchain-org/go-gbchain/accounts/abi"
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/common/hexutil"
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/ethdb"
+	"gbchain-org/go-gbchain/log"
+	"gbchain-org/go-gbchain/params"
+	"gbchain-org/go-gbchain/rlp"
+
+	cc "gbchain-org/go-gbchain/cross/core"
+	"gbchain-org/go-gbchain/cross/trigger"
+)
+
+const (
+	maxFinishGasLimit = 250000
+	maxTakerGasLimit  = 500000
+	resendInterval    = 5 * time.Minute // interval of resending the finish transactions lost by the remote chain
+)
+
+// pendingPrefix + ctxID -> pendingFinish, the finish and cancel transactions are kept in db until
+// the remote chain rejects them in estimation, so they are sent again if lost or after restart
+var pendingPrefix = []byte("_rpcPendingFinish")
+
+func pendingKey(id common.Hash) []byte {
+	return append(append([]byte{}, pendingPrefix...), id.Bytes()...)
+}
+
+// pendingFinish is a makerFinish or makerCancel transaction not known to be executed yet
+type pendingFinish struct {
+	Rtx    *cc.ReceptTransaction
+	Refund bool
+	Tx     common.Hash // the transaction sent last time, empty if it is not sent
+}
+
+// Executor submits makerFinish and makerCancel transactions to the remote chain, transactions
+// are signed locally by signer and sent by eth_sendRawTransaction.
+type Executor struct {
+	client *Client
+	anchor common.Address
+	signer trigger.Signer
+	db     ethdb.KeyValueStore // persists the pending finish transactions, they are lost after restart if nil
+
+	contract    common.Address
+	contractABI abi.ABI
+
+	submitCh  chan []*cc.ReceptTransaction
+	cancelCh  chan []*cc.ReceptTransaction
+	messageCh chan []*cc.CrossTransactionWithSignatures
+	takerLock sync.Mutex // serializes the taker transactions, the nonces of takers are read from the remote chain
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+	log       log.Logger
+}
+
+func NewExecutor(client *Client, anchor common.Address, contract common.Address, signer trigger.Signer, db ethdb.KeyValueStore) (*Executor, error) {
+	logger := log.New("module", "rpcExecutor", "chainID", client.chainID)
+	data, err := hexutil.Decode(params.CrossDemoAbi)
+	if err != nil {
+		logger.Error("Parse crossABI", "err", err)
+		return nil, err
+	}
+	abi, err := abi.JSON(bytes.NewReader(data))
+	if err != nil {
+		logger.Error("Parse crossABI", "err", err)
+		return nil, err
+	}
+	return &Executor{
+		client:      client,
+		anchor:      anchor,
+		signer:      signer,
+		db:          db,
+		contract:    contract,
+		contractABI: abi,
+		submitCh:    make(chan []*cc.ReceptTransaction, 10),
+		cancelCh:    make(chan []*cc.ReceptTransaction, 10),
+		messageCh:   make(chan []*cc.CrossTransactionWithSignatures, 10),
+		stopCh:      make(chan struct{}),
+		log:         logger,
+	}, nil
+}
+
+func (exe *Executor) Start() {
+	exe.wg.Add(1)
+	go exe.loop()
+}
+
+func (exe *Executor) Stop() {
+	close(exe.stopCh)
+	exe.wg.Wait()
+}
+
+func (exe *Executor) loop() {
+	defer exe.wg.Done()
+	resend := time.NewTicker(resendInterval)
+	defer resend.Stop()
+
+	exe.resend() // the pending transactions before restart
+	for {
+		select {
+		case rtxs := <-exe.submitCh:
+			exe.submit(rtxs, false)
+		case rtxs := <-exe.cancelCh:
+			exe.submit(rtxs, true)
+		case cwss := <-exe.messageCh:
+			exe.execute(cwss)
+		case <-resend.C:
+			exe.resend()
+		case <-exe.stopCh:
+			return
+		}
+	}
+}
+
+func (exe *Executor) SignHash(hash []byte) ([]byte, error) {
+	return exe.signer.SignHash(hash)
+}
+
+func (exe *Executor) SignData(data []byte) ([]byte, error) {
+	return exe.signer.SignData(data)
+}
+
+func (exe *Executor) SubmitTransaction(rtxs []*cc.ReceptTransaction) {
+	select {
+	case exe.submitCh <- rtxs:
+	case <-exe.stopCh:
+		exe.log.Warn("executor is stopped, discard finish transactions", "count", len(rtxs))
+	}
+}
+
+func (exe *Executor) SubmitCancel(rtxs []*cc.ReceptTransaction) {
+	select {
+	case exe.cancelCh <- rtxs:
+	case <-exe.stopCh:
+		exe.log.Warn("executor is stopped, discard cancel transactions", "count", len(rtxs))
+	}
+}
+
+func (exe *Executor) SubmitMessage(cwss []*cc.CrossTransactionWithSignatures) {
+	select {
+	case exe.messageCh <- cwss:
+	case <-exe.stopCh:
+		exe.log.Warn("executor is stopped, discard message transactions", "count", len(cwss))
+	}
+}
+
+// submit sends makerFinish transactions of rtxs, or makerCancel transactions if refund is true
+func (exe *Executor) submit(rtxs []*cc.ReceptTransaction, refund bool) {
+	pendings := make([]*pendingFinish, len(rtxs))
+	for i, rtx := range rtxs {
+		pendings[i] = &pendingFinish{Rtx: rtx, Refund: refund}
+		exe.storePending(pendings[i])
+	}
+	exe.send(pendings)
+}
+
+// resend sends the pending transactions again if the last sent ones are lost by the remote chain
+func (exe *Executor) resend() {
+	if exe.db == nil {
+		return
+	}
+	var lost []*pendingFinish
+	it := exe.db.NewIteratorWithPrefix(pendingPrefix)
+	for it.Next() {
+		p := new(pendingFinish)
+		if err := rlp.DecodeBytes(it.Value(), p); err != nil {
+			exe.log.Warn("decode pending finish failed", "key", hexutil.Encode(it.Key()), "err", err)
+			continue
+		}
+		if p.Tx != (common.Hash{}) {
+			ctx, cancel := exe.client.context()
+			_, _, err := exe.client.TransactionByHash(ctx, p.Tx)
+			cancel()
+			if err == nil {
+				continue // pending or executed, it is removed once the estimation fails
+			}
+		}
+		lost = append(lost, p)
+	}
+	it.Release()
+	if len(lost) > 0 {
+		exe.log.Info("resend pending finish transactions", "count", len(lost))
+		exe.send(lost)
+	}
+}
+
+// send signs and sends the pending transactions, the ones rejected in estimation are finished already
+func (exe *Executor) send(pendings []*pendingFinish) {
+	ctx, cancel := exe.client.context()
+	defer cancel()
+
+	nonce, err := exe.client.PendingNonceAt(ctx, exe.anchor)
+	if err != nil {
+		exe.log.Warn("get remote nonce failed", "error", err)
+		return
+	}
+	gasPrice, err := exe.client.SuggestGasPrice(ctx)
+	if err != nil {
+		exe.log.Warn("get remote gas price failed", "error", err)
+		return
+	}
+
+	for _, p := range pendings {
+		tx, err := exe.lockout(p.Rtx, nonce, gasPrice, p.Refund)
+		if err != nil {
+			exe.log.Warn("create finish transaction failed", "id", p.Rtx.CTxId, "err", err)
+			continue
+		}
+		if tx == nil {
+			exe.deletePending(p)
+			continue
+		}
+		if err := exe.client.SendTransaction(ctx, tx); err != nil {
+			exe.log.Warn("send finish transaction failed", "id", p.Rtx.CTxId, "err", err)
+			continue
+		}
+		p.Tx = tx.Hash()
+		exe.storePending(p)
+		nonce++
+	}
+}
+
+func (exe *Executor) storePending(p *pendingFinish) {
+	if exe.db == nil {
+		return
+	}
+	enc, err := rlp.EncodeToBytes(p)
+	if err != nil {
+		exe.log.Warn("encode pending finish failed", "id", p.Rtx.CTxId, "err", err)
+		return
+	}
+	if err := exe.db.Put(pendingKey(p.Rtx.CTxId), enc); err != nil {
+		exe.log.Warn("store pending finish failed", "id", p.Rtx.CTxId, "err", err)
+	}
+}
+
+func (exe *Executor) deletePending(p *pendingFinish) {
+	if exe.db == nil {
+		return
+	}
+	if err := exe.db.Delete(pendingKey(p.Rtx.CTxId)); err != nil {
+		exe.log.Warn("delete pending finish failed", "id", p.Rtx.CTxId, "err", err)
+	}
+}
+
+func (exe *Executor) lockout(rtx *cc.ReceptTransaction, nonce uint64, gasPrice *big.Int, refund bool) (*types.Transaction, error) {
+	if rtx.DestinationId.Cmp(exe.client.chainID) != 0 {
+		exe.log.Warn("executing transaction is not matching this chain",
+			"destinationID", rtx.DestinationId, "chainID", exe.client.chainID)
+		return nil, nil
+	}
+	construct := rtx.ConstructData
+	if refund {
+		construct = rtx.ConstructCancelData
+	}
+	data, err := construct(exe.contractABI)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := exe.client.context()
+	defer cancel()
+	if _, err := exe.client.EstimateGas(ctx, gbchian.CallMsg{
+		From:     exe.anchor,
+		To:       &exe.contract,
+		Gas:      maxFinishGasLimit,
+		GasPrice: gasPrice,
+		Data:     data,
+	}); err != nil {
+		exe.log.Debug("already finish the cross Transaction", "id", rtx.CTxId, "err", err)
+		return nil, nil
+	}
+
+	tx := types.NewTransaction(nonce, exe.contract, big.NewInt(0), maxFinishGasLimit, gasPrice, data)
+	return exe.signer.SignTx(tx, exe.client.chainID)
+}
+
+// execute sends takerMessage transactions of cwss, the gas limit covers the message call and the taking
+func (exe *Executor) execute(cwss []*cc.CrossTransactionWithSignatures) {
+	ctx, cancel := exe.client.context()
+	defer cancel()
+
+	nonce, err := exe.client.PendingNonceAt(ctx, exe.anchor)
+	if err != nil {
+		exe.log.Warn("get remote nonce failed", "error", err)
+		return
+	}
+	gasPrice, err := exe.client.SuggestGasPrice(ctx)
+	if err != nil {
+		exe.log.Warn("get remote gas price failed", "error", err)
+		return
+	}
+
+	for _, cws := range cwss {
+		if cws.DestinationId().Cmp(exe.client.chainID) != 0 {
+			continue
+		}
+		msg, err := cc.ParseMessage(cws.Data.Input)
+		if err != nil {
+			exe.log.Warn("parse message failed", "id", cws.ID(), "err", err)
+			continue
+		}
+		data, err := cws.ConstructMessageData(exe.contractABI)
+		if err != nil {
+			exe.log.Warn("create message transaction failed", "id", cws.ID(), "err", err)
+			continue
+		}
+		gasLimit := msg.CallGas() + maxFinishGasLimit
+		if _, err := exe.client.EstimateGas(ctx, gbchian.CallMsg{
+			From:     exe.anchor,
+			To:       &exe.contract,
+			Gas:      gasLimit,
+			GasPrice: gasPrice,
+			Data:     data,
+		}); err != nil {
+			exe.log.Debug("message is executed already", "id", cws.ID(), "err", err)
+			continue
+		}
+		tx, err := exe.signer.SignTx(types.NewTransaction(nonce, exe.contract, big.NewInt(0), gasLimit, gasPrice, data), exe.client.chainID)
+		if err != nil {
+			exe.log.Warn("sign message transaction failed", "id", cws.ID(), "err", err)
+			continue
+		}
+		if err := exe.client.SendTransaction(ctx, tx); err != nil {
+			exe.log.Warn("send message transaction failed", "id", cws.ID(), "err", err)
+			continue
+		}
+		nonce++
+	}
+}
+
+// SubmitTaker sends the taker transactions of cwss by taker to the remote chain
+func (exe *Executor) SubmitTaker(taker common.Address, signer trigger.Signer, cwss []*cc.CrossTransactionWithSignatures) ([]common.Hash, error) {
+	ctx, cancel := exe.client.context()
+	defer cancel()
+
+	exe.takerLock.Lock()
+	defer exe.takerLock.Unlock()
+	nonce, err := exe.client.PendingNonceAt(ctx, taker)
+	if err != nil {
+		return nil, err
+	}
+	gasPrice, err := exe.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]common.Hash, len(cwss))
+	for i, cws := range cwss {
+		if cws.DestinationId().Cmp(exe.client.chainID) != 0 {
+			continue
+		}
+		data, err := cws.ConstructTakerData(exe.contractABI)
+		if err != nil {
+			exe.log.Warn("create taker transaction failed", "id", cws.ID(), "err", err)
+			continue
+		}
+		value := cws.TakerValue()
+		if _, err := exe.client.EstimateGas(ctx, gbchian.CallMsg{
+			From:     taker,
+			To:       &exe.contract,
+			Gas:      maxTakerGasLimit,
+			GasPrice: gasPrice,
+			Value:    value,
+			Data:     data,
+		}); err != nil {
+			exe.log.Debug("ctx could not be taken", "id", cws.ID(), "taker", taker, "err", err)
+			continue
+		}
+		tx, err := signer.SignTx(types.NewTransaction(nonce, exe.contract, value, maxTakerGasLimit, gasPrice, data), exe.client.chainID)
+		if err != nil {
+			exe.log.Warn("sign taker transaction failed", "id", cws.ID(), "taker", taker, "err", err)
+			continue
+		}
+		if err := exe.client.SendTransaction(ctx, tx); err != nil {
+			exe.log.Warn("send taker transaction failed", "id", cws.ID(), "taker", taker, "err", err)
+			continue
+		}
+		hashes[i] = tx.Hash()
+		nonce++
+	}
+	return hashes, nil
+}
+
+// TakerReceipt returns the receipt of the taker transaction of hash, nil if it is not mined
+func (exe *Executor) TakerReceipt(hash common.Hash) (*types.Receipt, error) {
+	ctx, cancel := exe.client.context()
+	defer cancel()
+
+	receipt, err := exe.client.TransactionReceipt(ctx, hash)
+	if err == gbchian.NotFound {
+		return nil, nil
+	}
+	return receipt, err
+}
@@ -0,0 +1,83 @@
+package rpctrigger
+
+import (
+	"math/big"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/params"
+
+	cc "gbchain-org/go-gbchain/cross/core"
+)
+
+// crossTopics are the contract events handled by the rpc subscriber
+var crossTopics = []common.Hash{
+	params.MakerTopic,
+	params.TakerTopic,
+	params.MakerFinishTopic,
+	params.TakerCancelTopic,
+	params.MakerCancelTopic,
+	params.MessageTopic,
+	params.MakerTokenTopic,
+	params.AddAnchorsTopic,
+	params.RemoveAnchorsTopic,
+	params.UpdateAnchorTopic,
+}
+
+// parseMaker decodes MakerTx(txId, from, to, remoteChainId, value, destValue, data) log
+func parseMaker(v *types.Log) *cc.CrossTransaction {
+	if len(v.Topics) < 3 || len(v.Data) < common.HashLength*6 {
+		return nil
+	}
+	var from, to common.Address
+	copy(from[:], v.Topics[2][common.HashLength-common.AddressLength:])
+	copy(to[:], v.Data[common.HashLength-common.AddressLength:common.HashLength])
+	count := common.BytesToHash(v.Data[common.HashLength*5 : common.HashLength*6]).Big().Uint64()
+	if uint64(len(v.Data)) < common.HashLength*6+count {
+		return nil
+	}
+	return cc.NewCrossTransaction(
+		common.BytesToHash(v.Data[common.HashLength*2:common.HashLength*3]).Big(),
+		common.BytesToHash(v.Data[common.HashLength*3:common.HashLength*4]).Big(),
+		common.BytesToHash(v.Data[common.HashLength:common.HashLength*2]).Big(),
+		v.Topics[1],
+		v.TxHash,
+		v.BlockHash,
+		from,
+		to,
+		v.Data[common.HashLength*6:common.HashLength*6+count])
+}
+
+// parseTaker decodes TakerTx(txId, to, remoteChainId, from, value, destValue) log
+func parseTaker(v *types.Log, chainID *big.Int) *cc.ReceptTransaction {
+	if len(v.Topics) < 3 || len(v.Data) < common.HashLength*4 {
+		return nil
+	}
+	var to common.Address
+	copy(to[:], v.Topics[2][common.HashLength-common.AddressLength:])
+	from := common.BytesToAddress(v.Data[common.HashLength*2-common.AddressLength : common.HashLength*2])
+	return cc.NewReceptTransaction(v.Topics[1], v.TxHash, from, to,
+		common.BytesToHash(v.Data[:common.HashLength]).Big(), chainID)
+}
+
+// parseTakerCancel decodes TakerCancel(txId, from, remoteChainId) log, the maker refunds itself
+func parseTakerCancel(v *types.Log, chainID *big.Int) *cc.ReceptTransaction {
+	if len(v.Topics) < 3 || len(v.Data) < common.HashLength {
+		return nil
+	}
+	var from common.Address
+	copy(from[:], v.Topics[2][common.HashLength-common.AddressLength:])
+	return cc.NewReceptTransaction(v.Topics[1], v.TxHash, from, from,
+		common.BytesToHash(v.Data[:common.HashLength]).Big(), chainID)
+}
+
+// parseAnchorUpdate decodes AddAnchors/RemoveAnchors/SetAnchorStatus(remoteChainId) log
+func parseAnchorUpdate(v *types.Log) *cc.RemoteChainInfo {
+	if len(v.Data) < common.HashLength {
+		return nil
+	}
+	return &cc.RemoteChainInfo{
+		RemoteChainId: common.BytesToHash(v.Data[:common.HashLength]).Big().Uint64(),
+		BlockNumber:   v.BlockNumber,
+	}
+}
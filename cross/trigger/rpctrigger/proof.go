@@ -0,0 +1,68 @@
+package rpctrigger
+
+import (
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/params"
+
+	"gbchain-org/go-gbchain/cross"
+	cc "gbchain-org/go-gbchain/cross/core"
+)
+
+// GetReceiptProof creates the receipt proof of the makerTx log of ctx,
+// receipts of the whole block are fetched to rebuild the receipts trie.
+func (r *Retriever) GetReceiptProof(ctx *cc.CrossTransaction) (*cc.ReceiptProof, error) {
+	c, cancel := r.client.context()
+	defer cancel()
+	block, err := r.client.BlockByHash(c, ctx.BlockHash())
+	if err != nil {
+		r.logger.Warn("get block of ctx failed", "ctxID", ctx.ID(), "error", err)
+		return nil, cross.ErrReorgCtx
+	}
+	var (
+		receipts          types.Receipts
+		txIndex, logIndex = -1, -1
+	)
+	for i, tx := range block.Transactions() {
+		receipt, err := r.client.TransactionReceipt(c, tx.Hash())
+		if err != nil {
+			r.logger.Warn("get receipt failed", "txHash", tx.Hash(), "error", err)
+			return nil, cross.ErrInternal
+		}
+		receipts = append(receipts, receipt)
+		if tx.Hash() != ctx.Data.TxHash {
+			continue
+		}
+		for j, l := range receipt.Logs {
+			if l.Address == r.contract && len(l.Topics) > 1 && l.Topics[0] == params.MakerTopic && l.Topics[1] == ctx.ID() {
+				txIndex, logIndex = i, j
+			}
+		}
+	}
+	if txIndex < 0 {
+		return nil, cc.ErrProofMissingLog
+	}
+	return cc.NewReceiptProof(block.Header(), receipts, uint(txIndex), uint(logIndex))
+}
+
+// VerifyReceiptProof checks the proof of ctx, the header must be in the canonical chain of remote
+func (r *Retriever) VerifyReceiptProof(ctx *cc.CrossTransaction, proof *cc.ReceiptProof) error {
+	if proof == nil || proof.Header == nil {
+		return cc.ErrInvalidProof
+	}
+	c, cancel := r.client.context()
+	defer cancel()
+	header, err := r.client.HeaderByNumber(c, proof.Header.Number)
+	if err != nil {
+		r.logger.Warn("get canonical header failed", "number", proof.Header.Number, "error", err)
+		return cross.ErrInternal
+	}
+	if header.Hash() != proof.Header.Hash() {
+		r.logger.Warn("receipt proof header is not canonical", "ctxID", ctx.ID(), "number", proof.Header.Number, "hash", proof.Header.Hash())
+		return cc.ErrProofHeaderMismatch
+	}
+	if err := proof.Verify(ctx, r.contract); err != nil {
+		r.logger.Warn("verify receipt proof failed", "ctxID", ctx.ID(), "error", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package rpctrigger
+
+import (
+	"math/big"
+	"sync"
+
+	gbchian "gbchain-org/go-gbchain"
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/log"
+	"gbchain-org/go-gbchain/params"
+
+	"gbchain-org/go-gbchain/cross"
+	cc "gbchain-org/go-gbchain/cross/core"
+	"gbchain-org/go-gbchain/cross/trigger"
+	"gbchain-org/go-gbchain/cross/trigger/simpletrigger/retriever"
+)
+
+const minRequireSignature = 2
+
+// Retriever implements trigger.ChainRetriever by querying the remote chain,
+// anchors are read from the cross contract by eth_call.
+type Retriever struct {
+	client   *Client
+	contract common.Address
+	depth    uint64
+
+	config           *cross.Config
+	anchors          map[uint64]*retriever.AnchorSet // chainID => anchorSet
+	requireSignature int
+	mu               sync.RWMutex
+
+	logger log.Logger
+}
+
+func NewRetriever(client *Client, contract common.Address, config *cross.Config, depth uint64) *Retriever {
+	return &Retriever{
+		client:           client,
+		contract:         contract,
+		depth:            depth,
+		config:           config,
+		anchors:          make(map[uint64]*retriever.AnchorSet),
+		requireSignature: minRequireSignature,
+		logger:           log.New("X-module", "rpcRetriever", "chainID", client.chainID),
+	}
+}
+
+func (r *Retriever) CanAcceptTxs() bool {
+	ctx, cancel := r.client.context()
+	defer cancel()
+	progress, err := r.client.SyncProgress(ctx)
+	return err == nil && progress == nil
+}
+
+func (r *Retriever) ConfirmedDepth() uint64 {
+	return r.depth
+}
+
+func (r *Retriever) CurrentBlockNumber() uint64 {
+	ctx, cancel := r.client.context()
+	defer cancel()
+	header, err := r.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		r.logger.Warn("get current header failed", "error", err)
+		return 0
+	}
+	return header.Number.Uint64()
+}
+
+func (r *Retriever) GetTransactionTimeOnChain(tx trigger.Transaction) uint64 {
+	ctx, cancel := r.client.context()
+	defer cancel()
+	if header, err := r.client.HeaderByHash(ctx, tx.BlockHash()); err == nil {
+		return header.Time
+	}
+	return 0
+}
+
+func (r *Retriever) GetTransactionNumberOnChain(tx trigger.Transaction) uint64 {
+	ctx, cancel := r.client.context()
+	defer cancel()
+	if header, err := r.client.HeaderByHash(ctx, tx.BlockHash()); err == nil {
+		return header.Number.Uint64()
+	}
+	//TODO return current for invisible block?
+	return r.CurrentBlockNumber()
+}
+
+func (r *Retriever) GetConfirmedTransactionNumberOnChain(tx trigger.Transaction) uint64 {
+	ctx, cancel := r.client.context()
+	defer cancel()
+	if header, err := r.client.HeaderByHash(ctx, tx.BlockHash()); err == nil {
+		return header.Number.Uint64() + r.depth
+	}
+	//TODO return current for invisible block?
+	return r.CurrentBlockNumber()
+}
+
+func (r *Retriever) RequireSignatures() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.requireSignature
+}
+
+func (r *Retriever) ExpireNumber() int {
+	return r.config.ExpireNumber()
+}
+
+func (r *Retriever) VerifyExpire(ctx *cc.CrossTransaction) error {
+	if r.ExpireNumber() >= 0 && r.CurrentBlockNumber()-r.GetTransactionNumberOnChain(ctx) > uint64(r.ExpireNumber()) {
+		r.logger.Debug("ctx is already expired", "ctxID", ctx.ID().String())
+		return cross.ErrExpiredCtx
+	}
+	return nil
+}
+
+func (r *Retriever) VerifySigner(ctx *cc.CrossTransaction, signChain, validChain *big.Int) (common.Address, error) {
+	r.logger.Debug("verify ctx signer", "ctx", ctx.ID(), "signChain", signChain, "validChain", validChain)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	anchorSet, ok := r.anchors[validChain.Uint64()]
+	if !ok {
+		anchors, signedCount, err := r.queryAnchors(validChain.Uint64())
+		if err != nil {
+			r.logger.Warn("query remote anchors failed", "error", err)
+			return common.Address{}, cross.ErrInternal
+		}
+		if len(anchors) == 0 {
+			r.logger.Warn("empty anchors in remote contract", "validChain", validChain)
+			return common.Address{}, cross.ErrInvalidSignCtx
+		}
+		r.config.Anchors = anchors
+		r.requireSignature = signedCount
+		anchorSet = retriever.NewAnchorSet(anchors)
+		r.anchors[validChain.Uint64()] = anchorSet
+	}
+	ctxSigner, err := r.config.CtxSigner(signChain)
+	if err != nil {
+		return common.Address{}, err
+	}
+	signer, ok := anchorSet.IsAnchorSignedCtx(ctx, ctxSigner)
+	if !ok {
+		r.logger.Warn("invalid signature", "anchors", anchorSet.String(), "ctxID", ctx.ID().String(), "signer", signer.String())
+		return signer, cross.ErrInvalidSignCtx
+	}
+	return signer, nil
+}
+
+// VerifyContract calls the cross contract to verify ctx
+// (must exist makerTx in source-chain, do not took by others in destination-chain)
+func (r *Retriever) VerifyContract(cws trigger.Transaction) error {
+	paddedCtxId := common.LeftPadBytes(cws.ID().Bytes(), 32) //CtxId
+	switch {
+	case r.client.chainID.Cmp(cws.ChainId()) == 0:
+		res, err := r.call(params.GetMakerTxFn, paddedCtxId, common.LeftPadBytes(cws.DestinationId().Bytes(), 32))
+		if err != nil {
+			r.logger.Warn("call getMakerTx failed", "error", err)
+			return cross.ErrInternal
+		}
+		if new(big.Int).SetBytes(res).Sign() == 0 { // error if makerTx is not existed in source-chain
+			return cross.ErrRepetitionCtx
+		}
+
+	case r.client.chainID.Cmp(cws.DestinationId()) == 0:
+		res, err := r.call(params.GetTakerTxFn, paddedCtxId, cws.From().Bytes(), common.LeftPadBytes(cws.ChainId().Bytes(), 32))
+		if err != nil {
+			r.logger.Warn("call getTakerTx failed", "error", err)
+			return cross.ErrInternal
+		}
+		if new(big.Int).SetBytes(res).Sign() != 0 { // error if takerTx is already taken in destination-chain
+			return cross.ErrRepetitionCtx
+		}
+	}
+	return nil
+}
+
+func (r *Retriever) UpdateAnchors(info *cc.RemoteChainInfo) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	anchors, signedCount, err := r.queryAnchors(info.RemoteChainId)
+	if err != nil {
+		r.logger.Warn("query remote anchors failed", "error", err)
+		return cross.ErrInternal
+	}
+	if anchors != nil {
+		r.config.Anchors = anchors
+		r.requireSignature = signedCount
+		r.anchors[info.RemoteChainId] = retriever.NewAnchorSet(anchors)
+	}
+	return nil
+}
+
+func (r *Retriever) queryAnchors(remoteChainID uint64) ([]common.Address, int, error) {
+	res, err := r.call(params.GetAnchorFn, common.LeftPadBytes(new(big.Int).SetUint64(remoteChainID).Bytes(), 32))
+	if err != nil {
+		return nil, 0, err
+	}
+	anchors, signedCount := retriever.ParseAnchors(res)
+	return anchors, signedCount, nil
+}
+
+// call invokes the cross contract function at the latest block
+func (r *Retriever) call(function []byte, inputs ...[]byte) ([]byte, error) {
+	data := append([]byte{}, function...)
+	for _, input := range inputs {
+		data = append(data, input...)
+	}
+	ctx, cancel := r.client.context()
+	defer cancel()
+	return r.client.CallContract(ctx, gbchian.CallMsg{To: &r.contract, Data: data}, nil)
+}
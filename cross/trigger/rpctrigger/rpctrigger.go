@@ -0,0 +1,133 @@
+// Package rpctrigger implements the cross triggers over RPC, so that an anchor
+// could bridge a chain which is run by a separate node.
+package rpctrigger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/cross"
+	"gbchain-org/go-gbchain/ethclient"
+	"gbchain-org/go-gbchain/rpc"
+
+	"gbchain-org/go-gbchain/cross/trigger/simpletrigger"
+)
+
+const defaultRequestTimeout = 10 * time.Second
+
+var DefaultConfirmDepth = simpletrigger.DefaultConfirmDepth
+
+var errNoGenesis = errors.New("genesis block is not found")
+
+// ConfirmDepth returns the confirmation depth of policy, the logs of remote chains are
+// confirmed by depth only, since the headers are not followed by subscriber.
+func ConfirmDepth(policy *cross.ConfirmPolicy) (uint64, error) {
+	switch {
+	case policy == nil:
+		return uint64(DefaultConfirmDepth), nil
+	case policy.Type != cross.ConfirmDepth && policy.Type != "":
+		return 0, fmt.Errorf("confirmation policy %q is not supported by remote chain %d", policy.Type, policy.ChainID)
+	case policy.Depth == 0:
+		return uint64(DefaultConfirmDepth), nil
+	}
+	return policy.Depth, nil
+}
+
+// Client wraps the ethclient and raw rpc client of a remote chain
+type Client struct {
+	*ethclient.Client
+	rpc *rpc.Client
+
+	chainID *big.Int
+	genesis common.Hash
+}
+
+// Dial connects a remote chain, websocket or ipc endpoint is required for log subscription
+func Dial(rawurl string) (*Client, error) {
+	c, err := rpc.Dial(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	client, err := NewClient(c)
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// NewClient creates a client of remote chain, chainID and genesis are fetched once
+func NewClient(c *rpc.Client) (*Client, error) {
+	client := &Client{Client: ethclient.NewClient(c), rpc: c}
+
+	ctx, cancel := client.context()
+	defer cancel()
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	genesis, err := client.HeaderByNumber(ctx, common.Big0)
+	if err != nil {
+		return nil, err
+	}
+	if genesis == nil {
+		return nil, errNoGenesis
+	}
+	client.chainID = chainID
+	client.genesis = genesis.Hash()
+	return client, nil
+}
+
+func (c *Client) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), defaultRequestTimeout)
+}
+
+// RPC returns the raw rpc client
+func (c *Client) RPC() *rpc.Client {
+	return c.rpc
+}
+
+// ProtocolChain implements cross.ProtocolChain for a remote chain
+type ProtocolChain struct {
+	client *Client
+
+	apis []rpc.API
+	mu   sync.Mutex
+}
+
+func NewProtocolChain(client *Client) *ProtocolChain {
+	return &ProtocolChain{client: client}
+}
+
+func (pc *ProtocolChain) ChainID() *big.Int {
+	return new(big.Int).Set(pc.client.chainID)
+}
+
+func (pc *ProtocolChain) GenesisHash() common.Hash {
+	return pc.client.genesis
+}
+
+// RegisterAPIs keeps the apis of remote chain, the remote node couldn't serve them,
+// they are served by the anchor node with the chain id suffixed to their namespaces,
+// e.g. cross2048_ctxQuery, since the namespaces are used by the local chain already.
+func (pc *ProtocolChain) RegisterAPIs(apis []rpc.API) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	for _, api := range apis {
+		api.Namespace = fmt.Sprintf("%s%d", api.Namespace, pc.client.chainID)
+		pc.apis = append(pc.apis, api)
+	}
+}
+
+// APIs returns the apis of remote chain served by the anchor node
+func (pc *ProtocolChain) APIs() []rpc.API {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	return pc.apis
+}
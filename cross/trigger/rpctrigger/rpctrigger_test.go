@@ -0,0 +1,559 @@
+package rpctrigger
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/common/hexutil"
+	"gbchain-org/go-gbchain/core/rawdb"
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/crypto"
+	"gbchain-org/go-gbchain/params"
+	"gbchain-org/go-gbchain/rlp"
+	"gbchain-org/go-gbchain/rpc"
+
+	"gbchain-org/go-gbchain/cross"
+	cc "gbchain-org/go-gbchain/cross/core"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testChainID  = big.NewInt(1024)
+	testRemoteID = big.NewInt(2048)
+	testContract = common.HexToAddress("0xAa00000000000000000000000000000000000000")
+)
+
+// fakeBackend is an in-memory stand-in of the remote chain's eth rpc service
+type fakeBackend struct {
+	headers []*types.Header
+	logs    map[common.Hash][]types.Log
+
+	anchors   []common.Address
+	nonce     uint64
+	sent      []*types.Transaction
+	finished  map[common.Hash]bool // ctxID => already finished or taken
+	notifiers map[rpc.ID]*rpc.Notifier
+
+	mu sync.Mutex
+}
+
+func newFakeBackend() *fakeBackend {
+	b := &fakeBackend{
+		logs:      make(map[common.Hash][]types.Log),
+		finished:  make(map[common.Hash]bool),
+		notifiers: make(map[rpc.ID]*rpc.Notifier),
+	}
+	b.headers = append(b.headers, &types.Header{Number: common.Big0, Difficulty: common.Big1})
+	return b
+}
+
+func (b *fakeBackend) client(t *testing.T) *Client {
+	srv := rpc.NewServer()
+	if err := srv.RegisterName("eth", b); err != nil {
+		t.Fatal(err)
+	}
+	client, err := NewClient(rpc.DialInProc(srv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// mine appends a block on top of the parent number, the later blocks are replaced
+func (b *fakeBackend) mine(parent uint64, fork byte, logs ...types.Log) *types.Header {
+	b.mu.Lock()
+	header := &types.Header{
+		ParentHash: b.headers[parent].Hash(),
+		Number:     new(big.Int).SetUint64(parent + 1),
+		Difficulty: common.Big1,
+		Extra:      []byte{fork},
+	}
+	b.headers = append(b.headers[:parent+1], header)
+	for i := range logs {
+		logs[i].BlockNumber = header.Number.Uint64()
+		logs[i].BlockHash = header.Hash()
+	}
+	b.logs[header.Hash()] = logs
+	notifiers := make(map[rpc.ID]*rpc.Notifier, len(b.notifiers))
+	for id, n := range b.notifiers {
+		notifiers[id] = n
+	}
+	b.mu.Unlock()
+
+	for id, n := range notifiers {
+		n.Notify(id, header)
+	}
+	return header
+}
+
+func (b *fakeBackend) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(testChainID)
+}
+
+func (b *fakeBackend) Syncing() (interface{}, error) {
+	return false, nil
+}
+
+func (b *fakeBackend) GetBlockByNumber(number rpc.BlockNumber, full bool) (*types.Header, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if number < 0 {
+		return b.headers[len(b.headers)-1], nil
+	}
+	if int(number) >= len(b.headers) {
+		return nil, nil
+	}
+	return b.headers[number], nil
+}
+
+func (b *fakeBackend) GetBlockByHash(hash common.Hash, full bool) (*types.Header, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, header := range b.headers {
+		if header.Hash() == hash {
+			return header, nil
+		}
+	}
+	return nil, nil
+}
+
+func (b *fakeBackend) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	sub := notifier.CreateSubscription()
+	b.mu.Lock()
+	b.notifiers[sub.ID] = notifier
+	b.mu.Unlock()
+	return sub, nil
+}
+
+type fakeFilter struct {
+	BlockHash *common.Hash     `json:"blockHash"`
+	Addresses []common.Address `json:"address"`
+}
+
+func (b *fakeBackend) GetLogs(filter fakeFilter) ([]types.Log, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if filter.BlockHash == nil {
+		return nil, errors.New("block hash is required")
+	}
+	logs := make([]types.Log, 0)
+	for _, l := range b.logs[*filter.BlockHash] {
+		for _, addr := range filter.Addresses {
+			if l.Address == addr {
+				logs = append(logs, l)
+			}
+		}
+	}
+	return logs, nil
+}
+
+type fakeCallArgs struct {
+	From common.Address  `json:"from"`
+	To   *common.Address `json:"to"`
+	Data hexutil.Bytes   `json:"data"`
+}
+
+func (b *fakeBackend) Call(args fakeCallArgs, number rpc.BlockNumber) (hexutil.Bytes, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	fn := []byte(args.Data[:4])
+	switch {
+	case bytes.Equal(fn, params.GetAnchorFn):
+		res := make([]byte, common.HashLength*(3+len(b.anchors)))
+		res[common.HashLength*2-1] = byte(len(b.anchors)) // require all anchors signed
+		res[common.HashLength*3-1] = byte(len(b.anchors))
+		for i, anchor := range b.anchors {
+			copy(res[common.HashLength*(4+i)-common.AddressLength:], anchor.Bytes())
+		}
+		return res, nil
+	case bytes.Equal(fn, params.GetTakerTxFn):
+		taken := b.finished[common.BytesToHash(args.Data[4:4+common.HashLength])]
+		if taken {
+			return common.LeftPadBytes([]byte{1}, common.HashLength), nil
+		}
+		return make([]byte, common.HashLength), nil
+	}
+	return nil, errors.New("unknown function")
+}
+
+func (b *fakeBackend) EstimateGas(args fakeCallArgs) (hexutil.Uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.finished[common.BytesToHash(args.Data[4:4+common.HashLength])] {
+		return 0, errors.New("execution reverted")
+	}
+	return maxFinishGasLimit, nil
+}
+
+func (b *fakeBackend) GetTransactionCount(address common.Address, number rpc.BlockNumber) hexutil.Uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return hexutil.Uint64(b.nonce)
+}
+
+func (b *fakeBackend) GasPrice() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(params.GWei))
+}
+
+func (b *fakeBackend) SendRawTransaction(encoded hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encoded, tx); err != nil {
+		return common.Hash{}, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nonce++
+	b.sent = append(b.sent, tx)
+	return tx.Hash(), nil
+}
+
+func (b *fakeBackend) GetTransactionByHash(hash common.Hash) *types.Transaction {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, tx := range b.sent {
+		if tx.Hash() == hash {
+			return tx
+		}
+	}
+	return nil
+}
+
+func makerLog(ctxID common.Hash, from, to common.Address) types.Log {
+	data := make([]byte, common.HashLength*6)
+	copy(data[common.HashLength-common.AddressLength:], to.Bytes())
+	copy(data[common.HashLength:common.HashLength*2], common.BigToHash(testRemoteID).Bytes())
+	copy(data[common.HashLength*2:common.HashLength*3], common.BigToHash(big.NewInt(100)).Bytes())
+	copy(data[common.HashLength*3:common.HashLength*4], common.BigToHash(big.NewInt(200)).Bytes())
+	return types.Log{
+		Address: testContract,
+		Topics:  []common.Hash{params.MakerTopic, ctxID, from.Hash()},
+		Data:    data,
+		TxHash:  ctxID,
+	}
+}
+
+func takerLog(ctxID common.Hash, from, to common.Address) types.Log {
+	data := make([]byte, common.HashLength*4)
+	copy(data[:common.HashLength], common.BigToHash(testRemoteID).Bytes())
+	copy(data[common.HashLength*2-common.AddressLength:], from.Bytes())
+	return types.Log{
+		Address: testContract,
+		Topics:  []common.Hash{params.TakerTopic, ctxID, to.Hash()},
+		Data:    data,
+		TxHash:  ctxID,
+	}
+}
+
+func takerCancelLog(ctxID common.Hash, from common.Address) types.Log {
+	return types.Log{
+		Address: testContract,
+		Topics:  []common.Hash{params.TakerCancelTopic, ctxID, from.Hash()},
+		Data:    common.BigToHash(testRemoteID).Bytes(),
+		TxHash:  ctxID,
+	}
+}
+
+func makerCancelLog(ctxID common.Hash, from common.Address) types.Log {
+	return types.Log{
+		Address: testContract,
+		Topics:  []common.Hash{params.MakerCancelTopic, ctxID, from.Hash()},
+		TxHash:  ctxID,
+	}
+}
+
+func waitBlockEvent(t *testing.T, ch <-chan cc.CrossBlockEvent) cc.CrossBlockEvent {
+	select {
+	case ev := <-ch:
+		return ev
+	case <-time.After(time.Second):
+		t.Fatal("wait block event timeout")
+	}
+	return cc.CrossBlockEvent{}
+}
+
+func TestSubscriber(t *testing.T) {
+	var (
+		backend = newFakeBackend()
+		client  = backend.client(t)
+		from    = common.HexToAddress("0x01")
+		to      = common.HexToAddress("0x02")
+		events  = make(chan cc.CrossBlockEvent, 10)
+	)
+	s, err := NewSubscriber(client, testContract, 2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+	s.SubscribeBlockEvent(events)
+
+	maker, taker := common.HexToHash("0xa1"), common.HexToHash("0xb1")
+	backend.mine(0, 0, makerLog(maker, from, to))
+	backend.mine(1, 0, takerLog(taker, from, to))
+
+	ev := waitBlockEvent(t, events)
+	assert.Equal(t, uint64(2), ev.Number.Uint64())
+	assert.Equal(t, 1, len(ev.NewTaker.Takers))
+	assert.Equal(t, taker, ev.NewTaker.Takers[0].CTxId)
+	assert.Equal(t, testRemoteID, ev.NewTaker.Takers[0].DestinationId)
+
+	// maker is confirmed at block 1+depth
+	backend.mine(2, 0)
+	ev = waitBlockEvent(t, events)
+	assert.Equal(t, uint64(3), ev.Number.Uint64())
+	assert.Equal(t, 1, len(ev.ConfirmedMaker.Txs))
+	ctx := ev.ConfirmedMaker.Txs[0]
+	assert.Equal(t, maker, ctx.ID())
+	assert.Equal(t, testRemoteID, ctx.DestinationId())
+	assert.Equal(t, big.NewInt(100), ctx.Data.Value)
+	assert.Equal(t, to, ctx.Data.To)
+
+	// reorg from block 2, the unconfirmed taker is rolled back
+	backend.mine(1, 1)
+	ev = waitBlockEvent(t, events)
+	assert.Equal(t, uint64(2), ev.Number.Uint64())
+	assert.Equal(t, 1, len(ev.ReorgTaker.Takers))
+	assert.Equal(t, taker, ev.ReorgTaker.Takers[0].CTxId)
+}
+
+func TestSubscriber_Resume(t *testing.T) {
+	var (
+		backend = newFakeBackend()
+		client  = backend.client(t)
+		db      = rawdb.NewMemoryDatabase()
+		from    = common.HexToAddress("0x01")
+		to      = common.HexToAddress("0x02")
+		events  = make(chan cc.CrossBlockEvent, 10)
+	)
+	maker := common.HexToHash("0xa1")
+	backend.mine(0, 0)
+	backend.mine(1, 0, makerLog(maker, from, to))
+	backend.mine(2, 0)
+
+	// the subscriber is stopped before block 2 is confirmed
+	enc := make([]byte, 8)
+	binary.BigEndian.PutUint64(enc, 2)
+	assert.NoError(t, db.Put(cursorKey, enc))
+	s, err := NewSubscriber(client, testContract, 2, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SubscribeBlockEvent(events)
+
+	backend.mine(3, 0)
+	ev := waitBlockEvent(t, events)
+	assert.Equal(t, uint64(4), ev.Number.Uint64())
+	assert.Equal(t, 1, len(ev.ConfirmedMaker.Txs))
+	assert.Equal(t, maker, ev.ConfirmedMaker.Txs[0].ID())
+	s.Stop()
+
+	// blocks 3 and 4 are not confirmed yet
+	enc, err = db.Get(cursorKey)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), binary.BigEndian.Uint64(enc))
+}
+
+func TestProtocolChain_APIs(t *testing.T) {
+	pc := NewProtocolChain(newFakeBackend().client(t))
+	pc.RegisterAPIs([]rpc.API{{Namespace: "cross", Public: true}})
+	assert.Equal(t, 1, len(pc.APIs()))
+	assert.Equal(t, "cross1024", pc.APIs()[0].Namespace)
+}
+
+func TestSubscriber_Cancel(t *testing.T) {
+	var (
+		backend = newFakeBackend()
+		client  = backend.client(t)
+		from    = common.HexToAddress("0x01")
+		events  = make(chan cc.CrossBlockEvent, 10)
+	)
+	s, err := NewSubscriber(client, testContract, 2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+	s.SubscribeBlockEvent(events)
+
+	cancel, refund := common.HexToHash("0xa1"), common.HexToHash("0xa2")
+	backend.mine(0, 0, takerCancelLog(cancel, from))
+	backend.mine(1, 0, makerCancelLog(refund, from))
+
+	ev := waitBlockEvent(t, events)
+	assert.Equal(t, 1, len(ev.NewCancel.Cancels))
+	assert.Equal(t, cancel, ev.NewCancel.Cancels[0].CTxId)
+	assert.Equal(t, from, ev.NewCancel.Cancels[0].From)
+	assert.Equal(t, from, ev.NewCancel.Cancels[0].To)
+	assert.Equal(t, testRemoteID, ev.NewCancel.Cancels[0].DestinationId)
+
+	// cancel is confirmed at block 1+depth, refund at block 2+depth
+	backend.mine(2, 0)
+	ev = waitBlockEvent(t, events)
+	assert.Equal(t, 1, len(ev.ConfirmedCancel.Cancels))
+	assert.Equal(t, cancel, ev.ConfirmedCancel.Cancels[0].CTxId)
+
+	backend.mine(3, 0)
+	ev = waitBlockEvent(t, events)
+	assert.Equal(t, 1, len(ev.MakerCancel.Cancels))
+	assert.Equal(t, refund, ev.MakerCancel.Cancels[0].ID)
+	assert.Equal(t, cc.CtxStatusCancelled, ev.MakerCancel.Cancels[0].Status)
+}
+
+func TestRetriever_VerifySigner(t *testing.T) {
+	var (
+		backend = newFakeBackend()
+		client  = backend.client(t)
+		keys    = make([]*ecdsa.PrivateKey, 2)
+	)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		backend.anchors = append(backend.anchors, crypto.PubkeyToAddress(keys[i].PublicKey))
+	}
+	r := NewRetriever(client, testContract, &cross.Config{}, uint64(DefaultConfirmDepth))
+	assert.True(t, r.CanAcceptTxs())
+
+	ctx := cc.NewCrossTransaction(big.NewInt(1), big.NewInt(2), testChainID, common.HexToHash("0xa1"),
+		common.Hash{}, common.Hash{}, common.Address{}, common.Address{}, nil)
+	signer := cc.NewEIP155CtxSigner(testRemoteID)
+	signed, err := cc.SignCtx(ctx, signer, func(hash []byte) ([]byte, error) { return crypto.Sign(hash, keys[0]) })
+	assert.NoError(t, err)
+
+	addr, err := r.VerifySigner(signed, testRemoteID, testRemoteID)
+	assert.NoError(t, err)
+	assert.Equal(t, backend.anchors[0], addr)
+	assert.Equal(t, 2, r.RequireSignatures())
+
+	other, _ := crypto.GenerateKey()
+	signed, err = cc.SignCtx(ctx, signer, func(hash []byte) ([]byte, error) { return crypto.Sign(hash, other) })
+	assert.NoError(t, err)
+	_, err = r.VerifySigner(signed, testRemoteID, testRemoteID)
+	assert.Equal(t, cross.ErrInvalidSignCtx, err)
+
+	// ctx is made on remote chain and taken on this chain
+	assert.NoError(t, r.VerifyContract(signed))
+	backend.finished[ctx.ID()] = true
+	assert.Equal(t, cross.ErrRepetitionCtx, r.VerifyContract(signed))
+}
+
+func TestRetriever_VerifyExpire(t *testing.T) {
+	var (
+		backend = newFakeBackend()
+		client  = backend.client(t)
+	)
+	made := backend.mine(0, 0)
+	ctx := cc.NewCrossTransaction(big.NewInt(1), big.NewInt(2), testChainID, common.HexToHash("0xa1"),
+		common.Hash{}, made.Hash(), common.Address{}, common.Address{}, nil)
+
+	never := NewRetriever(client, testContract, &cross.Config{}, uint64(DefaultConfirmDepth))
+	r := NewRetriever(client, testContract, &cross.Config{Expire: 2}, uint64(DefaultConfirmDepth))
+	assert.Equal(t, -1, never.ExpireNumber())
+	assert.Equal(t, 2, r.ExpireNumber())
+
+	backend.mine(1, 0)
+	backend.mine(2, 0)
+	assert.NoError(t, r.VerifyExpire(ctx))
+
+	// the ctx made at block 1 expires at block 4
+	backend.mine(3, 0)
+	assert.Equal(t, cross.ErrExpiredCtx, r.VerifyExpire(ctx))
+	assert.NoError(t, never.VerifyExpire(ctx))
+}
+
+func TestExecutor_SubmitTransaction(t *testing.T) {
+	var (
+		backend = newFakeBackend()
+		client  = backend.client(t)
+		key, _  = crypto.GenerateKey()
+		anchor  = crypto.PubkeyToAddress(key.PublicKey)
+	)
+	exe, err := NewExecutor(client, anchor, testContract, cross.NewKeySigner(key), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exe.Start()
+	defer exe.Stop()
+
+	finished := common.HexToHash("0xa2")
+	backend.finished[finished] = true
+	exe.SubmitTransaction([]*cc.ReceptTransaction{
+		cc.NewReceptTransaction(common.HexToHash("0xa1"), common.Hash{}, common.Address{}, common.Address{}, testChainID, testRemoteID),
+		cc.NewReceptTransaction(finished, common.Hash{}, common.Address{}, common.Address{}, testChainID, testRemoteID),
+		cc.NewReceptTransaction(common.HexToHash("0xa3"), common.Hash{}, common.Address{}, common.Address{}, testRemoteID, testChainID),
+		cc.NewReceptTransaction(common.HexToHash("0xa4"), common.Hash{}, common.Address{}, common.Address{}, testChainID, testRemoteID),
+	})
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		backend.mu.Lock()
+		sent := len(backend.sent)
+		backend.mu.Unlock()
+		if sent >= 2 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+	assert.Equal(t, 2, len(backend.sent))
+	signer := types.NewEIP155Signer(testChainID)
+	for i, tx := range backend.sent {
+		assert.Equal(t, uint64(i), tx.Nonce())
+		assert.Equal(t, testContract, *tx.To())
+		sender, err := types.Sender(signer, tx)
+		assert.NoError(t, err)
+		assert.Equal(t, anchor, sender)
+	}
+}
+
+func TestExecutor_Resend(t *testing.T) {
+	var (
+		backend = newFakeBackend()
+		client  = backend.client(t)
+		db      = rawdb.NewMemoryDatabase()
+		key, _  = crypto.GenerateKey()
+		anchor  = crypto.PubkeyToAddress(key.PublicKey)
+	)
+	exe, err := NewExecutor(client, anchor, testContract, cross.NewKeySigner(key), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := common.HexToHash("0xa1")
+	exe.submit([]*cc.ReceptTransaction{
+		cc.NewReceptTransaction(id, common.Hash{}, common.Address{}, common.Address{}, testChainID, testRemoteID),
+	}, false)
+	assert.Equal(t, 1, len(backend.sent))
+	has, _ := db.Has(pendingKey(id))
+	assert.True(t, has)
+
+	// the sent transaction is known by the remote chain
+	exe.resend()
+	assert.Equal(t, 1, len(backend.sent))
+
+	// the transaction is lost, it is sent again
+	backend.mu.Lock()
+	backend.sent = nil
+	backend.mu.Unlock()
+	exe.resend()
+	assert.Equal(t, 1, len(backend.sent))
+
+	// the ctx is finished, the pending transaction is removed
+	backend.mu.Lock()
+	backend.sent, backend.finished[id] = nil, true
+	backend.mu.Unlock()
+	exe.resend()
+	assert.Equal(t, 0, len(backend.sent))
+	has, _ = db.Has(pendingKey(id))
+	assert.False(t, has)
+}
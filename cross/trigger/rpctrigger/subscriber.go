@@ -0,0 +1,373 @@
+package rpctrigger
+
+import (
+	"context"
+	"encoding/binary"
+	"math/big"
+	"sync"
+
+	gbchian "gbchain-org/go-gbchain"
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/ethdb"
+	"gbchain-org/go-gbchain/event"
+	"gbchain-org/go-gbchain/log"
+	"gbchain-org/go-gbchain/params"
+
+	cc "gbchain-org/go-gbchain/cross/core"
+)
+
+const (
+	headChanSize   = 16
+	maxCatchBlocks = 1024 // max blocks handled at once when subscriber is behind the remote head
+)
+
+// cursorKey is the first block not confirmed in db, the blocks from it are handled again after restart
+var cursorKey = []byte("_rpcSubscriberCursor")
+
+// blockLogs is the unconfirmed cross contract logs of a remote block
+type blockLogs struct {
+	number uint64
+	hash   common.Hash
+	logs   []types.Log
+}
+
+// Subscriber subscribes new heads of remote chain and filters cross contract logs by block,
+// logs are confirmed after depth blocks, or rolled back if the block becomes a side fork.
+type Subscriber struct {
+	client   *Client
+	contract common.Address
+	depth    uint64
+	db       ethdb.KeyValueStore // persists the cursor, the subscriber starts from the remote head after restart if nil
+
+	blocks []*blockLogs // unconfirmed blocks in ascending order
+	next   uint64       // next block number to handle
+	latest uint64       // number of the latest remote head
+
+	headCh  chan *types.Header
+	headSub gbchian.Subscription
+
+	blockEventFeed event.Feed
+	scope          event.SubscriptionScope
+	stop           chan struct{}
+	wg             sync.WaitGroup
+
+	log log.Logger
+}
+
+// NewSubscriber creates the subscriber of contract logs in the remote chain, it resumes from
+// the cursor in db, the blocks not confirmed before restart are handled again.
+func NewSubscriber(client *Client, contract common.Address, depth uint64, db ethdb.KeyValueStore) (*Subscriber, error) {
+	s := &Subscriber{
+		client:   client,
+		contract: contract,
+		depth:    depth,
+		db:       db,
+		headCh:   make(chan *types.Header, headChanSize),
+		stop:     make(chan struct{}),
+		log:      log.New("X-module", "rpcSubscriber", "chainID", client.chainID),
+	}
+	if db != nil {
+		if enc, err := db.Get(cursorKey); err == nil && len(enc) == 8 {
+			s.next = binary.BigEndian.Uint64(enc)
+		}
+	}
+	sub, err := client.SubscribeNewHead(context.Background(), s.headCh)
+	if err != nil {
+		return nil, err
+	}
+	s.headSub = sub
+
+	s.wg.Add(1)
+	go s.loop()
+
+	return s, nil
+}
+
+func (s *Subscriber) loop() {
+	defer s.wg.Done()
+
+	// ready is selected if the subscriber is behind the remote head, the blocks are handled
+	// in batches between the new heads
+	ready := make(chan struct{})
+	close(ready)
+	for {
+		var catchUp <-chan struct{}
+		if s.next != 0 && s.next <= s.latest {
+			catchUp = ready
+		}
+		select {
+		case head := <-s.headCh:
+			if err := s.handleHead(head); err != nil {
+				s.log.Warn("handle remote head failed", "number", head.Number, "error", err)
+			}
+
+		case <-catchUp:
+			if err := s.catchUp(); err != nil {
+				s.log.Warn("catch up remote head failed", "from", s.next, "head", s.latest, "error", err)
+			}
+
+		case err := <-s.headSub.Err():
+			if err != nil {
+				s.log.Error("remote head subscription failed", "error", err)
+			}
+			return
+
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+func (s *Subscriber) Stop() {
+	s.headSub.Unsubscribe()
+	s.scope.Close()
+	close(s.stop)
+	s.wg.Wait()
+}
+
+func (s *Subscriber) SubscribeBlockEvent(ch chan<- cc.CrossBlockEvent) event.Subscription {
+	return s.scope.Track(s.blockEventFeed.Subscribe(ch))
+}
+
+func (s *Subscriber) handleHead(head *types.Header) error {
+	if err := s.rollback(); err != nil {
+		return err
+	}
+	s.latest = head.Number.Uint64()
+	if s.next == 0 {
+		s.next = s.latest // start from the newest head without cursor
+	}
+	return s.catchUp()
+}
+
+// catchUp handles at most maxCatchBlocks blocks towards the latest head, and persists the cursor
+func (s *Subscriber) catchUp() error {
+	defer s.saveCursor()
+	for n := 0; n < maxCatchBlocks && s.next <= s.latest; n++ {
+		if err := s.handleBlock(s.next); err != nil {
+			s.latest = s.next - 1 // retry on the next head
+			return err
+		}
+	}
+	return nil
+}
+
+// saveCursor persists the first block not confirmed, which is handled first after restart
+func (s *Subscriber) saveCursor() {
+	if s.db == nil || s.next == 0 {
+		return
+	}
+	cursor := s.next
+	if len(s.blocks) > 0 {
+		cursor = s.blocks[0].number
+	}
+	enc := make([]byte, 8)
+	binary.BigEndian.PutUint64(enc, cursor)
+	if err := s.db.Put(cursorKey, enc); err != nil {
+		s.log.Warn("Failed to persist subscriber cursor", "number", cursor, "error", err)
+	}
+}
+
+// rollback drops unconfirmed blocks which are not in the canonical chain any more
+func (s *Subscriber) rollback() error {
+	for i, b := range s.blocks {
+		ctx, cancel := s.client.context()
+		header, err := s.client.HeaderByNumber(ctx, new(big.Int).SetUint64(b.number))
+		cancel()
+		if err != nil && err != gbchian.NotFound {
+			return err
+		}
+		if header != nil && header.Hash() == b.hash {
+			continue
+		}
+		s.log.Info("⑂ remote block became a side fork", "number", b.number, "hash", b.hash)
+		dropped := s.blocks[i:]
+		s.blocks, s.next = s.blocks[:i], b.number
+		s.sendReorg(dropped)
+		return nil
+	}
+	return nil
+}
+
+func (s *Subscriber) sendReorg(dropped []*blockLogs) {
+	reorgEvent := cc.CrossBlockEvent{Number: new(big.Int).SetUint64(dropped[0].number)}
+	for _, b := range dropped {
+		for i := range b.logs {
+			v := &b.logs[i]
+			switch v.Topics[0] {
+			case params.TakerTopic: // reorg executing -> waiting
+				if rtx := parseTaker(v, s.client.chainID); rtx != nil {
+					reorgEvent.ReorgTaker.Takers = append(reorgEvent.ReorgTaker.Takers, rtx)
+				}
+
+			case params.MakerFinishTopic: // reorg finishing -> executed
+				if len(v.Topics) >= 3 {
+					reorgEvent.ReorgFinish.Finishes = append(reorgEvent.ReorgFinish.Finishes, &cc.CrossTransactionModifier{
+						ID:     v.Topics[1],
+						Status: cc.CtxStatusExecuted,
+						Type:   cc.Reorg,
+					})
+				}
+
+			case params.TakerCancelTopic: // reorg cancelling -> waiting
+				if rtx := parseTakerCancel(v, s.client.chainID); rtx != nil {
+					reorgEvent.ReorgCancel.Cancels = append(reorgEvent.ReorgCancel.Cancels, rtx)
+				}
+			}
+		}
+	}
+	if !reorgEvent.IsEmpty() {
+		s.blockEventFeed.Send(reorgEvent)
+	}
+}
+
+func (s *Subscriber) handleBlock(number uint64) error {
+	ctx, cancel := s.client.context()
+	defer cancel()
+
+	header, err := s.client.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return err
+	}
+	hash := header.Hash()
+	logs, err := s.client.FilterLogs(ctx, gbchian.FilterQuery{
+		BlockHash: &hash,
+		Addresses: []common.Address{s.contract},
+		Topics:    [][]common.Hash{crossTopics},
+	})
+	if err != nil {
+		return err
+	}
+
+	var unconfirmed []types.Log
+	current := cc.CrossBlockEvent{Number: new(big.Int).SetUint64(number)}
+	for i := range logs {
+		v := &logs[i]
+		if v.Address != s.contract || len(v.Topics) == 0 {
+			continue
+		}
+		switch v.Topics[0] {
+		case params.MakerTopic:
+			unconfirmed = append(unconfirmed, *v)
+
+		case params.TakerTopic:
+			if rtx := parseTaker(v, s.client.chainID); rtx != nil {
+				current.NewTaker.Takers = append(current.NewTaker.Takers, rtx)
+				unconfirmed = append(unconfirmed, *v)
+			}
+
+		case params.MakerFinishTopic:
+			if len(v.Topics) >= 3 {
+				current.NewFinish.Finishes = append(current.NewFinish.Finishes, &cc.CrossTransactionModifier{
+					ID:            v.Topics[1],
+					AtBlockNumber: v.BlockNumber,
+					Status:        cc.CtxStatusFinishing,
+				})
+				unconfirmed = append(unconfirmed, *v)
+			}
+
+		case params.TakerCancelTopic:
+			if rtx := parseTakerCancel(v, s.client.chainID); rtx != nil {
+				current.NewCancel.Cancels = append(current.NewCancel.Cancels, rtx)
+				unconfirmed = append(unconfirmed, *v)
+			}
+
+		case params.MakerCancelTopic, params.MessageTopic, params.MakerTokenTopic:
+			if len(v.Topics) >= 3 {
+				unconfirmed = append(unconfirmed, *v)
+			}
+
+		case params.AddAnchorsTopic, params.RemoveAnchorsTopic, params.UpdateAnchorTopic:
+			if info := parseAnchorUpdate(v); info != nil {
+				current.NewAnchor.ChainInfo = append(current.NewAnchor.ChainInfo, info)
+			}
+		}
+	}
+
+	s.shift(number, &current)
+	s.blocks = append(s.blocks, &blockLogs{number: number, hash: hash, logs: unconfirmed})
+	s.next = number + 1
+
+	if !current.IsEmpty() {
+		s.blockEventFeed.Send(current)
+	}
+	return nil
+}
+
+// shift confirms the blocks which exceed the depth, confirmed logs are combined
+// into current event if they are confirmed at the current height.
+func (s *Subscriber) shift(height uint64, current *cc.CrossBlockEvent) {
+	for len(s.blocks) > 0 && s.blocks[0].number+s.depth <= height {
+		b := s.blocks[0]
+		s.blocks = s.blocks[1:]
+
+		var (
+			ctxs            []*cc.CrossTransaction
+			rtxs            []*cc.ReceptTransaction
+			finishModifiers []*cc.CrossTransactionModifier
+			cancels         []*cc.ReceptTransaction
+			cancelModifiers []*cc.CrossTransactionModifier
+			results         []*cc.MessageResult
+			tokens          []*cc.MakerToken
+		)
+		for i := range b.logs {
+			v := &b.logs[i]
+			switch v.Topics[0] {
+			case params.MakerTopic:
+				if ctx := parseMaker(v); ctx != nil {
+					ctxs = append(ctxs, ctx)
+				}
+			case params.TakerTopic:
+				if rtx := parseTaker(v, s.client.chainID); rtx != nil {
+					rtxs = append(rtxs, rtx)
+				}
+			case params.MakerFinishTopic:
+				finishModifiers = append(finishModifiers, &cc.CrossTransactionModifier{
+					ID:            v.Topics[1],
+					AtBlockNumber: v.BlockNumber + s.depth,
+					Status:        cc.CtxStatusFinished,
+				})
+			case params.TakerCancelTopic:
+				if rtx := parseTakerCancel(v, s.client.chainID); rtx != nil {
+					cancels = append(cancels, rtx)
+				}
+			case params.MakerCancelTopic:
+				cancelModifiers = append(cancelModifiers, &cc.CrossTransactionModifier{
+					ID:            v.Topics[1],
+					AtBlockNumber: v.BlockNumber + s.depth,
+					Status:        cc.CtxStatusCancelled,
+				})
+			case params.MessageTopic:
+				if result := cc.NewMessageResult(v); result != nil {
+					results = append(results, result)
+				}
+			case params.MakerTokenTopic:
+				if token := cc.NewMakerToken(v); token != nil {
+					tokens = append(tokens, token)
+				}
+			}
+		}
+		cc.WithMakerTokens(ctxs, tokens)
+		cc.WithMessageResults(rtxs, results)
+
+		confirmNumber := b.number + s.depth
+		if current.Number.Uint64() == confirmNumber {
+			current.ConfirmedMaker.Txs = append(current.ConfirmedMaker.Txs, ctxs...)
+			current.ConfirmedTaker.Txs = append(current.ConfirmedTaker.Txs, rtxs...)
+			current.ConfirmedFinish.Finishes = append(current.ConfirmedFinish.Finishes, finishModifiers...)
+			current.ConfirmedCancel.Cancels = append(current.ConfirmedCancel.Cancels, cancels...)
+			current.MakerCancel.Cancels = append(current.MakerCancel.Cancels, cancelModifiers...)
+
+		} else if len(ctxs)|len(rtxs)|len(finishModifiers)|len(cancels)|len(cancelModifiers) > 0 {
+			s.blockEventFeed.Send(cc.CrossBlockEvent{
+				Number:          new(big.Int).SetUint64(confirmNumber),
+				ConfirmedMaker:  cc.ConfirmedMakerEvent{Txs: ctxs},
+				ConfirmedTaker:  cc.ConfirmedTakerEvent{Txs: rtxs},
+				ConfirmedFinish: cc.ConfirmedFinishEvent{Finishes: finishModifiers},
+				ConfirmedCancel: cc.ConfirmedCancelEvent{Cancels: cancels},
+				MakerCancel:     cc.MakerCancelEvent{Cancels: cancelModifiers},
+			})
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package simpletrigger
+
+import (
+	"fmt"
+	"time"
+
+	"gbchain-org/go-gbchain/consensus/dpos"
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/cross"
+	"gbchain-org/go-gbchain/log"
+	"gbchain-org/go-gbchain/params"
+)
+
+// Confirmer decides which blocks are confirmed, the cross events of a block are promoted
+// to the confirmed ones when it is confirmed.
+type Confirmer interface {
+	// Confirmed returns the number of the highest block confirmed when the block of number is the head
+	Confirmed(number uint64) uint64
+}
+
+// HeaderReader retrieves the canonical headers of confirmer
+type HeaderReader interface {
+	GetHeaderByNumber(number uint64) *types.Header
+}
+
+// NewConfirmer creates the confirmer of policy, the blocks are confirmed by DefaultConfirmDepth
+// blocks if policy is nil.
+func NewConfirmer(policy *cross.ConfirmPolicy, chain HeaderReader, config *params.ChainConfig) (Confirmer, error) {
+	if policy == nil {
+		return NewDepthConfirmer(uint64(DefaultConfirmDepth)), nil
+	}
+	switch policy.Type {
+	case cross.ConfirmDepth, "":
+		if policy.Depth == 0 {
+			return NewDepthConfirmer(uint64(DefaultConfirmDepth)), nil
+		}
+		return NewDepthConfirmer(policy.Depth), nil
+
+	case cross.ConfirmFinality:
+		switch {
+		case config.Istanbul != nil, config.Raft:
+			return finalConfirmer{}, nil // blocks are final once they are inserted
+		case config.DPoS != nil:
+			return &dposConfirmer{chain: chain}, nil
+		}
+		return nil, fmt.Errorf("chain %d has no finality: %v", policy.ChainID, config)
+
+	case cross.ConfirmTime:
+		if policy.Period <= 0 {
+			return nil, fmt.Errorf("invalid confirmation period of chain %d: %v", policy.ChainID, policy.Period)
+		}
+		return &timeConfirmer{chain: chain, period: uint64((policy.Period + time.Second - 1) / time.Second)}, nil
+	}
+	return nil, fmt.Errorf("unknown confirmation policy of chain %d: %q", policy.ChainID, policy.Type)
+}
+
+type depthConfirmer uint64
+
+// NewDepthConfirmer returns the confirmer of which blocks are confirmed by depth blocks on top of them
+func NewDepthConfirmer(depth uint64) Confirmer {
+	return depthConfirmer(depth)
+}
+
+func (c depthConfirmer) Confirmed(number uint64) uint64 {
+	if number < uint64(c) {
+		return 0
+	}
+	return number - uint64(c)
+}
+
+// finalConfirmer confirms the blocks of engines with instant finality, like Istanbul
+// committed by seals and Raft.
+type finalConfirmer struct{}
+
+func (finalConfirmer) Confirmed(number uint64) uint64 {
+	return number
+}
+
+// dposConfirmer confirms the blocks confirmed by enough DPoS signers, which is recorded
+// in the header of head.
+type dposConfirmer struct {
+	chain HeaderReader
+}
+
+func (c *dposConfirmer) Confirmed(number uint64) uint64 {
+	header := c.chain.GetHeaderByNumber(number)
+	if header == nil {
+		return 0
+	}
+	confirmed, err := dpos.ConfirmedNumber(header)
+	if err != nil {
+		log.Warn("Failed to decode dpos confirmed number", "number", number, "error", err)
+		return 0
+	}
+	return confirmed
+}
+
+// timeConfirmer confirms the blocks sealed period before head.
+type timeConfirmer struct {
+	chain  HeaderReader
+	period uint64 // seconds
+}
+
+func (c *timeConfirmer) Confirmed(number uint64) uint64 {
+	head := c.chain.GetHeaderByNumber(number)
+	if head == nil {
+		return 0
+	}
+	for ; number > 0; number-- {
+		header := c.chain.GetHeaderByNumber(number)
+		if header == nil {
+			return 0
+		}
+		if header.Time+c.period <= head.Time {
+			return number
+		}
+	}
+	return 0
+}
@@ -0,0 +1,71 @@
+package simpletrigger
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"gbchain-org/go-gbchain/core/types"
+	"gbchain-org/go-gbchain/cross"
+	"gbchain-org/go-gbchain/params"
+)
+
+// testHeaders is a chain of headers sealed every 5 seconds
+type testHeaders []*types.Header
+
+func newTestHeaders(n int) testHeaders {
+	headers := make(testHeaders, n)
+	for i := range headers {
+		headers[i] = &types.Header{Number: big.NewInt(int64(i)), Time: uint64(i) * 5}
+	}
+	return headers
+}
+
+func (h testHeaders) GetHeaderByNumber(number uint64) *types.Header {
+	if number >= uint64(len(h)) {
+		return nil
+	}
+	return h[number]
+}
+
+func TestConfirmer(t *testing.T) {
+	headers := newTestHeaders(20)
+	tests := []struct {
+		policy *cross.ConfirmPolicy
+		config *params.ChainConfig
+		head   uint64
+		want   uint64
+	}{
+		{nil, params.TestChainConfig, 19, 19 - uint64(DefaultConfirmDepth)},
+		{nil, params.TestChainConfig, 3, 0},
+		{&cross.ConfirmPolicy{Type: cross.ConfirmDepth}, params.TestChainConfig, 19, 19 - uint64(DefaultConfirmDepth)},
+		{&cross.ConfirmPolicy{Type: cross.ConfirmDepth, Depth: 3}, params.TestChainConfig, 10, 7},
+		{&cross.ConfirmPolicy{Type: cross.ConfirmFinality}, &params.ChainConfig{Istanbul: &params.IstanbulConfig{}}, 10, 10},
+		{&cross.ConfirmPolicy{Type: cross.ConfirmFinality}, &params.ChainConfig{Raft: true}, 10, 10},
+		{&cross.ConfirmPolicy{Type: cross.ConfirmTime, Period: 12 * time.Second}, params.TestChainConfig, 10, 7},
+		{&cross.ConfirmPolicy{Type: cross.ConfirmTime, Period: 15 * time.Second}, params.TestChainConfig, 10, 7},
+		{&cross.ConfirmPolicy{Type: cross.ConfirmTime, Period: time.Minute}, params.TestChainConfig, 10, 0},
+	}
+	for i, tt := range tests {
+		confirmer, err := NewConfirmer(tt.policy, headers, tt.config)
+		if err != nil {
+			t.Fatalf("test %d: can't create confirmer: %v", i, err)
+		}
+		if have := confirmer.Confirmed(tt.head); have != tt.want {
+			t.Errorf("test %d: confirmed mismatch: have %d, want %d", i, have, tt.want)
+		}
+	}
+}
+
+func TestConfirmerInvalid(t *testing.T) {
+	policies := []*cross.ConfirmPolicy{
+		{Type: cross.ConfirmFinality}, // pow chain
+		{Type: cross.ConfirmTime},
+		{Type: "unknown"},
+	}
+	for i, policy := range policies {
+		if _, err := NewConfirmer(policy, newTestHeaders(1), params.TestChainConfig); err == nil {
+			t.Errorf("test %d: invalid policy %q is accepted", i, policy.Type)
+		}
+	}
+}
@@ -5,10 +5,10 @@ import (
 	"context"
 	"math/big"
 	"math/rand"
+	"sort"
 	"sync"
 	"time"
 
-	"gbchain-org/go-gbchain/accounts"
 	"gbchain-org/go-gbchain/accounts/abi"
 	"gbchain-org/go-gbchain/common"
 	"gbchain-org/go-gbchain/common/hexutil"
@@ -21,14 +21,37 @@ import (
 
 	cc "gbchain-org/go-gbchain/cross/core"
 	"gbchain-org/go-gbchain/cross/metric"
+	"gbchain-org/go-gbchain/cross/trigger"
 	"gbchain-org/go-gbchain/cross/trigger/simpletrigger"
 )
 
 const (
 	maxFinishGasLimit     = 250000
+	maxTakerGasLimit      = 500000
 	maxFinishTransactions = 256
 )
 
+// BumpConfig is the schedule to replace the transactions stuck in txpool
+type BumpConfig struct {
+	Interval time.Duration // replace a transaction if it is not confirmed in the interval
+	Percent  uint64        // percentage of gas price increased, at least the price bump of txpool
+}
+
+var DefaultBumpConfig = BumpConfig{
+	Interval: 2 * time.Minute,
+	Percent:  core.DefaultTxPoolConfig.PriceBump,
+}
+
+func (c BumpConfig) sanitize() BumpConfig {
+	if c.Interval <= 0 {
+		c.Interval = DefaultBumpConfig.Interval
+	}
+	if c.Percent < core.DefaultTxPoolConfig.PriceBump {
+		c.Percent = core.DefaultTxPoolConfig.PriceBump
+	}
+	return c
+}
+
 type TranParam struct {
 	gasLimit uint64
 	gasPrice *big.Int
@@ -44,6 +67,7 @@ type queueDB interface {
 
 type SimpleExecutor struct {
 	anchor    common.Address
+	signer    trigger.Signer
 	gasHelper *GasHelper
 	future    queueDB
 
@@ -54,14 +78,21 @@ type SimpleExecutor struct {
 	contract    common.Address
 	contractABI abi.ABI
 
-	submitCh chan []*cc.ReceptTransaction
-	stopCh   chan struct{}
-	wg       sync.WaitGroup
-	log      log.Logger
+	bump      BumpConfig
+	tracker   *nonceTracker
+	lock      sync.RWMutex // protects tracker
+	takerLock sync.Mutex   // serializes the taker transactions, the nonces of takers are read from txpool
+
+	submitCh  chan []*cc.ReceptTransaction
+	cancelCh  chan []*cc.ReceptTransaction
+	messageCh chan []*cc.CrossTransactionWithSignatures
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+	log       log.Logger
 }
 
-func NewSimpleExecutor(chain simpletrigger.GBChain, anchor common.Address, contract common.Address, qdb queueDB) (
-	*SimpleExecutor, error) {
+func NewSimpleExecutor(chain simpletrigger.GBChain, anchor common.Address, signer trigger.Signer, contract common.Address,
+	qdb queueDB, bump BumpConfig) (*SimpleExecutor, error) {
 	logger := log.New("module", "executor", "chainID", chain.ChainConfig().ChainID)
 	data, err := hexutil.Decode(params.CrossDemoAbi)
 	if err != nil {
@@ -80,16 +111,29 @@ func NewSimpleExecutor(chain simpletrigger.GBChain, anchor common.Address, contr
 		future:      qdb,
 		gpo:         chain.GasOracle(),
 		anchor:      anchor,
+		signer:      signer,
 		gasHelper:   NewGasHelper(chain.BlockChain(), chain),
 		contract:    contract,
 		contractABI: abi,
+		bump:        bump.sanitize(),
+		tracker:     newNonceTracker(0),
 		submitCh:    make(chan []*cc.ReceptTransaction, 10),
+		cancelCh:    make(chan []*cc.ReceptTransaction, 10),
+		messageCh:   make(chan []*cc.CrossTransactionWithSignatures, 10),
 		stopCh:      make(chan struct{}),
 		log:         logger,
 	}, nil
 }
 
 func (exe *SimpleExecutor) Start() {
+	// rebuild the nonce from chain state, transactions left in txpool are adopted on promotion
+	if state, err := exe.chain.BlockChain().State(); err == nil {
+		exe.lock.Lock()
+		exe.tracker = newNonceTracker(state.GetNonce(exe.anchor))
+		exe.lock.Unlock()
+	} else {
+		exe.log.Warn("get state nonce failed", "error", err)
+	}
 	exe.wg.Add(1)
 	go exe.loop()
 }
@@ -101,7 +145,17 @@ func (exe *SimpleExecutor) loop() {
 	for {
 		select {
 		case rtxs := <-exe.submitCh:
-			if txs := exe.getTxForLockOut(rtxs); len(txs) > 0 {
+			if txs := exe.getTxForLockOut(rtxs, false); len(txs) > 0 {
+				exe.pm.AddLocals(txs)
+			}
+
+		case rtxs := <-exe.cancelCh:
+			if txs := exe.getTxForLockOut(rtxs, true); len(txs) > 0 {
+				exe.pm.AddLocals(txs)
+			}
+
+		case cwss := <-exe.messageCh:
+			if txs := exe.getTxForMessage(cwss); len(txs) > 0 {
 				exe.pm.AddLocals(txs)
 			}
 
@@ -141,29 +195,36 @@ func (exe *SimpleExecutor) Stop() {
 }
 
 func newSignedTransaction(nonce uint64, to common.Address, gasLimit uint64, gasPrice *big.Int,
-	data []byte, networkId uint64, signHash cc.SignHash) (*types.Transaction, error) {
+	data []byte, networkId uint64, signer trigger.Signer) (*types.Transaction, error) {
 	tx := types.NewTransaction(nonce, to, big.NewInt(0), gasLimit, gasPrice, data)
-	signer := types.NewEIP155Signer(big.NewInt(int64(networkId)))
-	txHash := signer.Hash(tx)
-	signature, err := signHash(txHash.Bytes())
-	if err != nil {
-		return nil, err
+	return signer.SignTx(tx, new(big.Int).SetUint64(networkId))
+}
+
+// resignTransaction signs tx again with the new nonce and gas price
+func resignTransaction(tx *types.Transaction, nonce uint64, gasPrice *big.Int, networkId uint64,
+	signer trigger.Signer) (*types.Transaction, error) {
+	if tx.To() == nil {
+		tx = types.NewContractCreation(nonce, tx.Value(), tx.Gas(), gasPrice, tx.Data())
+	} else {
+		tx = types.NewTransaction(nonce, *tx.To(), tx.Value(), tx.Gas(), gasPrice, tx.Data())
 	}
-	signedTx, err := tx.WithSignature(signer, signature)
+	return signer.SignTx(tx, new(big.Int).SetUint64(networkId))
+}
+
+func (exe *SimpleExecutor) SignHash(hash []byte) ([]byte, error) {
+	sig, err := exe.signer.SignHash(hash)
 	if err != nil {
-		return nil, err
+		exe.log.Error("sign hash failed", "address", exe.anchor, "error", err)
 	}
-	return signedTx, nil
+	return sig, err
 }
 
-func (exe *SimpleExecutor) SignHash(hash []byte) ([]byte, error) {
-	account := accounts.Account{Address: exe.anchor}
-	wallet, err := exe.chain.AccountManager().Find(account)
+func (exe *SimpleExecutor) SignData(data []byte) ([]byte, error) {
+	sig, err := exe.signer.SignData(data)
 	if err != nil {
-		exe.log.Error("account not found ", "address", exe.anchor)
-		return nil, err
+		exe.log.Error("sign data failed", "address", exe.anchor, "error", err)
 	}
-	return wallet.SignHash(account, hash)
+	return sig, err
 }
 
 func (exe *SimpleExecutor) SubmitTransaction(rtxs []*cc.ReceptTransaction) {
@@ -189,80 +250,166 @@ func (exe *SimpleExecutor) SubmitTransaction(rtxs []*cc.ReceptTransaction) {
 	}
 }
 
+// SubmitCancel submits makerCancel transactions to refund makers, they are not queued
+// into the future like finish transactions since cancellations are rare.
+func (exe *SimpleExecutor) SubmitCancel(rtxs []*cc.ReceptTransaction) {
+	select {
+	case exe.cancelCh <- rtxs:
+	case <-exe.stopCh:
+		exe.log.Warn("executor is stopped, discard cancel transactions", "count", len(rtxs))
+	}
+}
+
+// SubmitMessage submits takerMessage transactions to execute the messages, they are
+// not queued into the future since the anchors race to execute them.
+func (exe *SimpleExecutor) SubmitMessage(cwss []*cc.CrossTransactionWithSignatures) {
+	select {
+	case exe.messageCh <- cwss:
+	case <-exe.stopCh:
+		exe.log.Warn("executor is stopped, discard message transactions", "count", len(cwss))
+	}
+}
+
+// PromoteTransaction resubmits the transactions stuck in txpool with higher gas prices, repairs the nonce gaps
+// and submits the transactions waiting in the future queue if txpool is idle.
 func (exe *SimpleExecutor) PromoteTransaction() {
-	pending, err := exe.pm.Pending()
+	pooled, err := exe.pooledTxs()
 	if err != nil {
 		exe.log.Warn("promoteTransaction failed", "error", err)
 		return
 	}
-	var (
-		newTxs   types.Transactions
-		promotes types.Transactions
-	)
-
-	txs, ok := pending[exe.anchor]
-
-	var stateNonce uint64
 	state, err := exe.chain.BlockChain().State()
 	if err != nil {
 		exe.log.Warn("get state nonce failed", "error", err)
+		return
 	}
-	stateNonce = state.GetNonce(exe.anchor)
-
-	if ok {
-		var count uint64
-		var nonceBegin uint64
-		for _, v := range txs {
-			if stateNonce > 0 && v.Nonce() < stateNonce { // tx is already packed in state, will be discard in txPool
-				continue
-			}
-			if count < core.DefaultTxPoolConfig.AccountSlots {
-				if count == 0 {
-					nonceBegin = v.Nonce()
-				}
-				if ok, _ := exe.checkTransaction(exe.anchor, *v.To(), v.Gas(), v.GasPrice(), v.Data()); !ok {
-					exe.log.Debug("already finish the cross Transaction", "tx", v.Hash())
-					// continue TODO: 虽然会失败，但是如果不promote此交易，交易池将会阻塞
-				}
-				gasPrice := new(big.Int).Div(new(big.Int).Mul(
-					v.GasPrice(), big.NewInt(100+int64(core.DefaultTxPoolConfig.PriceBump))), big.NewInt(100))
-
-				if gasPrice.Cmp(MaxGasPrice) > 0 {
-					exe.log.Info("overflow max gas price, set to max", "tx", v.Hash().String())
-					gasPrice = new(big.Int).Sub(MaxGasPrice, big.NewInt(rand.Int63n(1e9))) //随机调低价格以改变hash替换原交易
-				}
-
-				tx, err := newSignedTransaction(nonceBegin+count, *v.To(), v.Gas(), gasPrice, v.Data(), exe.pm.NetworkId(), exe.SignHash)
-				if err != nil {
-					exe.log.Warn("promoteTransaction resign failed", "error", err)
-					continue
-				}
 
-				newTxs = append(newTxs, tx)
-				count++
-			} else {
-				break
-			}
+	now := time.Now()
+	exe.lock.Lock()
+	var (
+		gaps      = exe.tracker.gaps
+		resubmits = exe.tracker.reschedule(state.GetNonce(exe.anchor), pooled, now, exe.bump.Interval)
+		newTxs    types.Transactions
+		bumps     int
+	)
+	for _, r := range resubmits {
+		gasPrice := r.tx.GasPrice()
+		if r.bump {
+			gasPrice = bumpGasPrice(gasPrice, exe.bump.Percent)
+			bumps++
+		}
+		tx, err := exe.resign(r.tx, r.nonce, gasPrice, pooled[r.nonce])
+		if err != nil {
+			exe.log.Warn("promoteTransaction resign failed", "nonce", r.nonce, "error", err)
+			continue
+		}
+		s := &sentTx{tx: tx, ctxID: r.ctxID, refund: r.refund, time: r.time, bumps: r.bumps}
+		if r.bump {
+			s.time = now
+			s.bumps++
 		}
+		exe.tracker.add(s)
+		newTxs = append(newTxs, tx)
+	}
+	if exe.tracker.gaps > gaps {
+		exe.log.Warn("Repair nonce gaps", "gaps", exe.tracker.gaps-gaps, "nonce", exe.tracker.nonce)
+	}
+	pending := len(exe.tracker.sent)
+	exe.lock.Unlock()
+
+	if len(newTxs) > 0 {
 		exe.pm.AddLocals(newTxs)
 	}
 
-	if !ok || txs.Len() < maxFinishTransactions {
-		promotes = exe.promoteIdleTxs(maxFinishTransactions-txs.Len(), exe.pm.GetNonce(exe.anchor))
+	var promotes types.Transactions
+	if pending < maxFinishTransactions {
+		promotes = exe.promoteIdleTxs(maxFinishTransactions - pending)
 		if promotes.Len() > 0 {
 			exe.pm.AddLocals(promotes)
 		}
 	}
 
-	exe.log.Info("Promote Transactions", "bumpPrice", len(newTxs), "promoteFuture", len(promotes), "futures", exe.future.Size())
+	exe.log.Info("Promote Transactions", "resubmit", len(newTxs), "bumpPrice", bumps,
+		"promoteFuture", len(promotes), "futures", exe.future.Size())
+}
+
+// resign signs tx again with the nonce, the gas price is increased to replace the pooled one with the same nonce
+func (exe *SimpleExecutor) resign(tx *types.Transaction, nonce uint64, gasPrice *big.Int, pooled *types.Transaction) (*types.Transaction, error) {
+	if pooled != nil && pooled.Hash() != tx.Hash() {
+		if min := bumpGasPrice(pooled.GasPrice(), core.DefaultTxPoolConfig.PriceBump); gasPrice.Cmp(min) < 0 {
+			gasPrice = min
+		}
+	}
+	return resignTransaction(tx, nonce, gasPrice, exe.pm.NetworkId(), exe.signer)
 }
 
-func (exe *SimpleExecutor) getTxForLockOut(rwss []*cc.ReceptTransaction) []*types.Transaction {
-	nonce := exe.pm.GetNonce(exe.anchor)
+// bumpGasPrice increases gas price by percent, it is limited by MaxGasPrice
+func bumpGasPrice(gasPrice *big.Int, percent uint64) *big.Int {
+	price := new(big.Int).Div(new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(100+percent)), big.NewInt(100))
+	if price.Cmp(MaxGasPrice) > 0 {
+		log.Info("overflow max gas price, set to max", "price", price)
+		price = new(big.Int).Sub(MaxGasPrice, big.NewInt(rand.Int63n(1e9))) //随机调低价格以改变hash替换原交易
+	}
+	return price
+}
+
+// pooledTxs returns the pending transactions of anchor in txpool by nonce
+func (exe *SimpleExecutor) pooledTxs() (map[uint64]*types.Transaction, error) {
+	pending, err := exe.pm.Pending()
+	if err != nil {
+		return nil, err
+	}
+	pooled := make(map[uint64]*types.Transaction, len(pending[exe.anchor]))
+	for _, tx := range pending[exe.anchor] {
+		pooled[tx.Nonce()] = tx
+	}
+	return pooled, nil
+}
+
+// Status returns the transactions submitted by executor
+func (exe *SimpleExecutor) Status() (*trigger.ExecutorStatus, error) {
+	state, err := exe.chain.BlockChain().State()
+	if err != nil {
+		return nil, err
+	}
+	exe.lock.RLock()
+	defer exe.lock.RUnlock()
+
+	status := &trigger.ExecutorStatus{
+		Anchor:     exe.anchor,
+		Nonce:      hexutil.Uint64(exe.tracker.nonce),
+		StateNonce: hexutil.Uint64(state.GetNonce(exe.anchor)),
+		Gaps:       hexutil.Uint64(exe.tracker.gaps),
+		Future:     hexutil.Uint64(exe.future.Size()),
+		Pending:    make([]*trigger.ExecutingTransaction, 0, len(exe.tracker.sent)),
+	}
+	for nonce, s := range exe.tracker.sent {
+		status.Pending = append(status.Pending, &trigger.ExecutingTransaction{
+			Nonce:    hexutil.Uint64(nonce),
+			Hash:     s.tx.Hash(),
+			CtxID:    s.ctxID,
+			Refund:   s.refund,
+			GasPrice: (*hexutil.Big)(s.tx.GasPrice()),
+			Bumps:    s.bumps,
+		})
+	}
+	sort.Slice(status.Pending, func(i, j int) bool { return status.Pending[i].Nonce < status.Pending[j].Nonce })
+	return status, nil
+}
+
+func (exe *SimpleExecutor) getTxForLockOut(rwss []*cc.ReceptTransaction, refund bool) []*types.Transaction {
+	pooled, err := exe.pooledTxs()
+	if err != nil {
+		exe.log.Warn("get txPool pending failed", "error", err)
+	}
+	exe.lock.Lock()
+	defer exe.lock.Unlock()
+	nonce := exe.tracker.next(exe.pm.GetNonce(exe.anchor))
 
 	var txs []*types.Transaction
 	for _, rws := range rwss {
-		if tx := exe.lockout(rws, nonce); tx != nil {
+		if tx := exe.lockout(rws, nonce, refund, pooled[nonce]); tx != nil {
+			exe.tracker.add(&sentTx{tx: tx, ctxID: rws.CTxId, refund: refund, time: time.Now()})
 			txs = append(txs, tx)
 			nonce++
 		}
@@ -271,13 +418,15 @@ func (exe *SimpleExecutor) getTxForLockOut(rwss []*cc.ReceptTransaction) []*type
 	return txs
 }
 
-func (exe *SimpleExecutor) lockout(rws *cc.ReceptTransaction, nonce uint64) *types.Transaction {
+// lockout creates the makerFinish transaction of rws, or the makerCancel transaction if refund is true,
+// the gas price is increased to replace the stale transaction left in txpool with the same nonce.
+func (exe *SimpleExecutor) lockout(rws *cc.ReceptTransaction, nonce uint64, refund bool, pooled *types.Transaction) *types.Transaction {
 	if rws.DestinationId.Uint64() != exe.pm.NetworkId() {
 		exe.log.Warn("executing transaction is not matching this chain",
 			"destinationID", rws.DestinationId, "chainID", exe.pm.NetworkId())
 		return nil
 	}
-	param, err := exe.createTransaction(rws)
+	param, err := exe.createTransaction(rws, refund)
 	if err != nil {
 		exe.log.Warn("getTxForLockOut CreateTransaction", "id", rws.CTxId, "err", err)
 		return nil
@@ -287,7 +436,12 @@ func (exe *SimpleExecutor) lockout(rws *cc.ReceptTransaction, nonce uint64) *typ
 		return nil
 	}
 
-	tx, err := newSignedTransaction(nonce, exe.contract, param.gasLimit, param.gasPrice, param.data, exe.pm.NetworkId(), exe.SignHash)
+	if pooled != nil {
+		if min := bumpGasPrice(pooled.GasPrice(), core.DefaultTxPoolConfig.PriceBump); param.gasPrice.Cmp(min) < 0 {
+			param.gasPrice = min
+		}
+	}
+	tx, err := newSignedTransaction(nonce, exe.contract, param.gasLimit, param.gasPrice, param.data, exe.pm.NetworkId(), exe.signer)
 	if err != nil {
 		exe.log.Warn("GetTxForLockOut newSignedTransaction", "id", rws.CTxId, "err", err)
 		return nil
@@ -295,7 +449,141 @@ func (exe *SimpleExecutor) lockout(rws *cc.ReceptTransaction, nonce uint64) *typ
 	return tx
 }
 
-func (exe *SimpleExecutor) createTransaction(rws *cc.ReceptTransaction) (*TranParam, error) {
+func (exe *SimpleExecutor) getTxForMessage(cwss []*cc.CrossTransactionWithSignatures) []*types.Transaction {
+	pooled, err := exe.pooledTxs()
+	if err != nil {
+		exe.log.Warn("get txPool pending failed", "error", err)
+	}
+	exe.lock.Lock()
+	defer exe.lock.Unlock()
+	nonce := exe.tracker.next(exe.pm.GetNonce(exe.anchor))
+
+	var txs []*types.Transaction
+	for _, cws := range cwss {
+		if tx := exe.execute(cws, nonce, pooled[nonce]); tx != nil {
+			exe.tracker.add(&sentTx{tx: tx, ctxID: cws.ID(), time: time.Now()})
+			txs = append(txs, tx)
+			nonce++
+		}
+	}
+	return txs
+}
+
+// execute creates the takerMessage transaction of cws, the gas limit covers the message call and the taking
+func (exe *SimpleExecutor) execute(cws *cc.CrossTransactionWithSignatures, nonce uint64, pooled *types.Transaction) *types.Transaction {
+	if cws.DestinationId().Uint64() != exe.pm.NetworkId() {
+		exe.log.Warn("executing message is not matching this chain",
+			"destinationID", cws.DestinationId(), "chainID", exe.pm.NetworkId())
+		return nil
+	}
+	msg, err := cc.ParseMessage(cws.Data.Input)
+	if err != nil {
+		exe.log.Warn("parse message failed", "id", cws.ID(), "err", err)
+		return nil
+	}
+	data, err := cws.ConstructMessageData(exe.contractABI)
+	if err != nil {
+		exe.log.Error("ConstructMessageData", "id", cws.ID(), "err", err)
+		return nil
+	}
+	gasPrice, err := exe.gpo.SuggestPrice(context.Background())
+	if err != nil {
+		exe.log.Warn("suggest gas price failed", "id", cws.ID(), "err", err)
+		return nil
+	}
+	if gasPrice.Cmp(eth.DefaultConfig.Miner.GasPrice) < 0 {
+		gasPrice.Set(eth.DefaultConfig.Miner.GasPrice)
+	}
+	if pooled != nil {
+		if min := bumpGasPrice(pooled.GasPrice(), core.DefaultTxPoolConfig.PriceBump); gasPrice.Cmp(min) < 0 {
+			gasPrice = min
+		}
+	}
+	gasLimit := msg.CallGas() + maxFinishGasLimit
+	if ok, _ := exe.checkTransaction(exe.anchor, exe.contract, gasLimit, gasPrice, data); !ok {
+		exe.log.Debug("message is executed already", "id", cws.ID())
+		return nil
+	}
+	tx, err := newSignedTransaction(nonce, exe.contract, gasLimit, gasPrice, data, exe.pm.NetworkId(), exe.signer)
+	if err != nil {
+		exe.log.Warn("execute message newSignedTransaction", "id", cws.ID(), "err", err)
+		return nil
+	}
+	return tx
+}
+
+// SubmitTaker sends the taker transactions of cwss by taker, they are not tracked like the transactions
+// of anchor, the ctxs are taken again by the auto taker if the transactions are dropped.
+func (exe *SimpleExecutor) SubmitTaker(taker common.Address, signer trigger.Signer, cwss []*cc.CrossTransactionWithSignatures) ([]common.Hash, error) {
+	gasPrice, err := exe.gpo.SuggestPrice(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	if gasPrice.Cmp(eth.DefaultConfig.Miner.GasPrice) < 0 {
+		gasPrice.Set(eth.DefaultConfig.Miner.GasPrice)
+	}
+	exe.takerLock.Lock()
+	defer exe.takerLock.Unlock()
+	nonce := exe.pm.GetNonce(taker)
+
+	var (
+		hashes = make([]common.Hash, len(cwss))
+		txs    types.Transactions
+	)
+	for i, cws := range cwss {
+		if cws.DestinationId().Uint64() != exe.pm.NetworkId() {
+			exe.log.Warn("taking ctx is not matching this chain", "destinationID", cws.DestinationId(), "chainID", exe.pm.NetworkId())
+			continue
+		}
+		data, err := cws.ConstructTakerData(exe.contractABI)
+		if err != nil {
+			exe.log.Error("ConstructTakerData", "id", cws.ID(), "err", err)
+			continue
+		}
+		value := cws.TakerValue()
+		if ok, err := exe.gasHelper.checkExec(context.Background(), CallArgs{
+			From:     taker,
+			To:       &exe.contract,
+			Data:     data,
+			GasPrice: hexutil.Big(*gasPrice),
+			Gas:      hexutil.Uint64(maxTakerGasLimit),
+			Value:    hexutil.Big(*value),
+		}); !ok {
+			exe.log.Debug("ctx could not be taken", "id", cws.ID(), "taker", taker, "err", err)
+			continue
+		}
+		tx, err := signer.SignTx(types.NewTransaction(nonce, exe.contract, value, maxTakerGasLimit, gasPrice, data),
+			new(big.Int).SetUint64(exe.pm.NetworkId()))
+		if err != nil {
+			exe.log.Warn("sign taker transaction failed", "id", cws.ID(), "taker", taker, "err", err)
+			continue
+		}
+		hashes[i] = tx.Hash()
+		txs = append(txs, tx)
+		nonce++
+	}
+	if len(txs) > 0 {
+		exe.pm.AddLocals(txs)
+	}
+	return hashes, nil
+}
+
+// TakerReceipt returns the receipt of the taker transaction of hash, nil if it is not mined
+func (exe *SimpleExecutor) TakerReceipt(hash common.Hash) (*types.Receipt, error) {
+	bc := exe.chain.BlockChain()
+	tx, blockHash, _ := bc.GetTransactionByTxHash(hash)
+	if tx == nil {
+		return nil, nil
+	}
+	for _, receipt := range bc.GetReceiptsByHash(blockHash) {
+		if receipt.TxHash == hash {
+			return receipt, nil
+		}
+	}
+	return nil, nil
+}
+
+func (exe *SimpleExecutor) createTransaction(rws *cc.ReceptTransaction, refund bool) (*TranParam, error) {
 	gasPrice, err := exe.gpo.SuggestPrice(context.Background())
 	if err != nil {
 		return nil, err
@@ -305,7 +593,11 @@ func (exe *SimpleExecutor) createTransaction(rws *cc.ReceptTransaction) (*TranPa
 			"suggest", gasPrice, "minerPrice", eth.DefaultConfig.Miner.GasPrice)
 		gasPrice.Set(eth.DefaultConfig.Miner.GasPrice)
 	}
-	data, err := rws.ConstructData(exe.contractABI)
+	construct := rws.ConstructData
+	if refund {
+		construct = rws.ConstructCancelData
+	}
+	data, err := construct(exe.contractABI)
 	if err != nil {
 		exe.log.Error("ConstructData", "err", err)
 		return nil, err
@@ -334,7 +626,15 @@ func (exe *SimpleExecutor) checkTransaction(address, contract common.Address, ga
 	return exe.gasHelper.checkExec(context.Background(), callArgs)
 }
 
-func (exe *SimpleExecutor) promoteIdleTxs(idles int, nonce uint64) types.Transactions {
+func (exe *SimpleExecutor) promoteIdleTxs(idles int) types.Transactions {
+	pooled, err := exe.pooledTxs()
+	if err != nil {
+		exe.log.Warn("get txPool pending failed", "error", err)
+	}
+	exe.lock.Lock()
+	defer exe.lock.Unlock()
+	nonce := exe.tracker.next(exe.pm.GetNonce(exe.anchor))
+
 	exe.log.Debug("promote idle txs", "idle", idles, "nonce", nonce)
 	var promotes types.Transactions
 	for ; idles > 0; idles-- {
@@ -352,7 +652,8 @@ func (exe *SimpleExecutor) promoteIdleTxs(idles int, nonce uint64) types.Transac
 			exe.log.Warn("promote decode failed", "error", err)
 			continue
 		}
-		if tx := exe.lockout(&rtx, nonce); tx != nil {
+		if tx := exe.lockout(&rtx, nonce, false, pooled[nonce]); tx != nil {
+			exe.tracker.add(&sentTx{tx: tx, ctxID: rtx.CTxId, time: time.Now()})
 			promotes = append(promotes, tx)
 			nonce++
 		}
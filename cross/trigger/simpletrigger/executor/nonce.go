@@ -0,0 +1,104 @@
+package executor
+
+import (
+	"sort"
+	"time"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/core/types"
+)
+
+// sentTx is a transaction submitted by executor but not confirmed yet
+type sentTx struct {
+	tx     *types.Transaction
+	ctxID  common.Hash // zero if the tx is found in txpool after restart
+	refund bool
+	time   time.Time // the last time tx is submitted
+	bumps  int
+}
+
+// resubmit is a sent transaction which should be signed again with the nonce
+type resubmit struct {
+	*sentTx
+	nonce uint64
+	bump  bool // increase the gas price
+}
+
+// nonceTracker tracks the nonces of transactions submitted by executor
+type nonceTracker struct {
+	nonce uint64             // the nonce of next transaction
+	sent  map[uint64]*sentTx // nonce -> unconfirmed transaction
+	gaps  uint64             // count of nonce gaps repaired
+}
+
+func newNonceTracker(nonce uint64) *nonceTracker {
+	return &nonceTracker{nonce: nonce, sent: make(map[uint64]*sentTx)}
+}
+
+// next returns the nonce of a new transaction, poolNonce is the pending nonce of txpool
+func (t *nonceTracker) next(poolNonce uint64) uint64 {
+	if t.nonce < poolNonce {
+		t.nonce = poolNonce
+	}
+	return t.nonce
+}
+
+func (t *nonceTracker) add(s *sentTx) {
+	nonce := s.tx.Nonce()
+	t.sent[nonce] = s
+	if nonce >= t.nonce {
+		t.nonce = nonce + 1
+	}
+}
+
+// reschedule forgets the transactions confirmed before stateNonce and moves the later transactions
+// down to fill the nonce gaps. It returns the transactions should be resubmitted, which are moved,
+// dropped from txpool or waited longer than interval since last submitted.
+func (t *nonceTracker) reschedule(stateNonce uint64, pooled map[uint64]*types.Transaction,
+	now time.Time, interval time.Duration) []resubmit {
+	for nonce := range t.sent {
+		if nonce < stateNonce {
+			delete(t.sent, nonce)
+		}
+	}
+	// adopt transactions found in txpool, they are submitted before restart
+	for nonce, tx := range pooled {
+		if nonce >= stateNonce && t.sent[nonce] == nil {
+			t.sent[nonce] = &sentTx{tx: tx, time: now}
+		}
+	}
+	if t.nonce < stateNonce {
+		t.nonce = stateNonce
+	}
+
+	nonces := make([]uint64, 0, len(t.sent))
+	for nonce := range t.sent {
+		nonces = append(nonces, nonce)
+	}
+	sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+
+	var (
+		resubmits []resubmit
+		sent      = make(map[uint64]*sentTx, len(t.sent))
+		next      = stateNonce
+		expect    = stateNonce
+	)
+	for _, nonce := range nonces {
+		s := t.sent[nonce]
+		if nonce != expect {
+			t.gaps++
+		}
+		expect = nonce + 1
+		pool, inPool := pooled[next]
+		switch {
+		case nonce != next, !inPool, pool.Hash() != s.tx.Hash():
+			resubmits = append(resubmits, resubmit{sentTx: s, nonce: next, bump: now.Sub(s.time) >= interval})
+		case now.Sub(s.time) >= interval:
+			resubmits = append(resubmits, resubmit{sentTx: s, nonce: next, bump: true})
+		}
+		sent[next] = s
+		next++
+	}
+	t.sent, t.nonce = sent, next
+	return resubmits
+}
@@ -0,0 +1,77 @@
+package executor
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/core/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTx(nonce uint64) *types.Transaction {
+	return types.NewTransaction(nonce, common.Address{}, big.NewInt(0), maxFinishGasLimit, big.NewInt(1), nil)
+}
+
+func TestNonceTracker_Reschedule(t *testing.T) {
+	var (
+		now     = time.Now()
+		tracker = newNonceTracker(5)
+		pooled  = make(map[uint64]*types.Transaction)
+	)
+	assert.EqualValues(t, 5, tracker.next(3))
+	assert.EqualValues(t, 6, tracker.next(6))
+
+	for nonce := uint64(6); nonce < 10; nonce++ {
+		tx := newTestTx(nonce)
+		tracker.add(&sentTx{tx: tx, time: now})
+		pooled[nonce] = tx
+	}
+	assert.EqualValues(t, 10, tracker.nonce)
+
+	// nothing changed
+	assert.Empty(t, tracker.reschedule(6, pooled, now, time.Minute))
+	assert.EqualValues(t, 10, tracker.nonce)
+
+	// 6 is confirmed, 8 is dropped
+	delete(pooled, 6)
+	delete(pooled, 8)
+	resubmits := tracker.reschedule(7, pooled, now, time.Minute)
+	assert.Equal(t, 1, len(resubmits))
+	assert.EqualValues(t, 8, resubmits[0].nonce)
+	assert.False(t, resubmits[0].bump)
+	assert.Equal(t, 3, len(tracker.sent))
+	assert.EqualValues(t, 0, tracker.gaps)
+
+	// bump the stuck transactions
+	resubmits = tracker.reschedule(7, pooled, now.Add(time.Minute), time.Minute)
+	assert.Equal(t, 3, len(resubmits))
+	for _, r := range resubmits {
+		assert.True(t, r.bump)
+	}
+
+	// nonce 7 is lost, 8 and 9 are moved down
+	delete(tracker.sent, 7)
+	delete(pooled, 7)
+	resubmits = tracker.reschedule(7, pooled, now, time.Minute)
+	assert.Equal(t, 2, len(resubmits))
+	assert.EqualValues(t, 7, resubmits[0].nonce)
+	assert.EqualValues(t, 8, resubmits[0].tx.Nonce())
+	assert.EqualValues(t, 8, resubmits[1].nonce)
+	assert.EqualValues(t, 1, tracker.gaps)
+	assert.EqualValues(t, 9, tracker.nonce)
+
+	// transactions in txpool are adopted after restart
+	tracker = newNonceTracker(7)
+	resubmits = tracker.reschedule(7, pooled, now, time.Minute)
+	assert.Equal(t, 1, len(resubmits)) // 9 is moved to 7
+	assert.EqualValues(t, 7, resubmits[0].nonce)
+	assert.EqualValues(t, 8, tracker.nonce)
+}
+
+func TestBumpGasPrice(t *testing.T) {
+	assert.Equal(t, big.NewInt(110), bumpGasPrice(big.NewInt(100), 10))
+	assert.True(t, bumpGasPrice(MaxGasPrice, 10).Cmp(MaxGasPrice) <= 0)
+}
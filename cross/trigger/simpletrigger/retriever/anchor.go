@@ -2,13 +2,18 @@ package retriever
 
 import (
 	"bytes"
+	"fmt"
 	"math/big"
 
 	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/consensus"
+	"gbchain-org/go-gbchain/consensus/ethash"
 	"gbchain-org/go-gbchain/core"
+	"gbchain-org/go-gbchain/core/rawdb"
 	"gbchain-org/go-gbchain/core/state"
 	"gbchain-org/go-gbchain/core/types"
 	"gbchain-org/go-gbchain/core/vm"
+	"gbchain-org/go-gbchain/ethdb"
 	"gbchain-org/go-gbchain/log"
 	"gbchain-org/go-gbchain/params"
 
@@ -55,6 +60,11 @@ func QueryAnchor(config *params.ChainConfig, bc core.ChainContext, statedb *stat
 	if err != nil {
 		log.Info("QueryAnchor apply getAnchor transaction failed", "err", err)
 	}
+	return ParseAnchors(res)
+}
+
+// ParseAnchors decodes the result of contract getAnchors call into anchors and signConfirmCount
+func ParseAnchors(res []byte) ([]common.Address, int) {
 	var anchors []common.Address
 	if len(res) > 64 {
 		signConfirmCount := new(big.Int).SetBytes(res[common.HashLength : common.HashLength*2]).Uint64()
@@ -71,3 +81,46 @@ func QueryAnchor(config *params.ChainConfig, bc core.ChainContext, statedb *stat
 	}
 	return nil, minRequireSignature
 }
+
+// dbChain is the chain context of the blocks stored in db, the blocks are not verified
+// so the engine only reads the coinbase of headers.
+type dbChain struct {
+	db ethdb.Database
+}
+
+func (c dbChain) Engine() consensus.Engine {
+	return ethash.NewFaker()
+}
+
+func (c dbChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	return rawdb.ReadHeader(c.db, hash, number)
+}
+
+// ReadAnchors returns the anchors of remoteChainId and the count of signatures required,
+// which are queried in the state of the head block stored in db. It is used to verify
+// ctxs when the chain is not running.
+func ReadAnchors(db ethdb.Database, contract common.Address, remoteChainId uint64) ([]common.Address, int, error) {
+	genesis := rawdb.ReadCanonicalHash(db, 0)
+	config := rawdb.ReadChainConfig(db, genesis)
+	if config == nil {
+		return nil, 0, fmt.Errorf("chain config of genesis %x not found", genesis)
+	}
+	hash := rawdb.ReadHeadBlockHash(db)
+	number := rawdb.ReadHeaderNumber(db, hash)
+	if number == nil {
+		return nil, 0, fmt.Errorf("head block %x not found", hash)
+	}
+	header := rawdb.ReadHeader(db, hash, *number)
+	if header == nil {
+		return nil, 0, fmt.Errorf("head header %x not found", hash)
+	}
+	statedb, err := state.New(header.Root, state.NewDatabase(db))
+	if err != nil {
+		return nil, 0, fmt.Errorf("state of head %d: %v", *number, err)
+	}
+	anchors, signedCount := QueryAnchor(config, dbChain{db}, statedb, header, contract, remoteChainId)
+	if len(anchors) == 0 {
+		return nil, 0, fmt.Errorf("no anchors of chain %d in contract %s at block %d", remoteChainId, contract.String(), *number)
+	}
+	return anchors, signedCount, nil
+}
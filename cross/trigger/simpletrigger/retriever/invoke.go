@@ -19,11 +19,12 @@ import (
 
 // ChainInvoke invoke blockchain interfaces to get block and transaction states
 type ChainInvoke struct {
-	bc simpletrigger.BlockChain
+	bc        simpletrigger.BlockChain
+	confirmer simpletrigger.Confirmer
 }
 
-func NewChainInvoke(chain simpletrigger.BlockChain) *ChainInvoke {
-	return &ChainInvoke{bc: chain}
+func NewChainInvoke(chain simpletrigger.BlockChain, confirmer simpletrigger.Confirmer) *ChainInvoke {
+	return &ChainInvoke{bc: chain, confirmer: confirmer}
 }
 
 func (c ChainInvoke) CurrentBlockNumber() uint64 {
@@ -43,12 +44,19 @@ func (c ChainInvoke) GetTransactionNumberOnChain(tx trigger.Transaction) uint64
 
 func (c ChainInvoke) GetConfirmedTransactionNumberOnChain(tx trigger.Transaction) uint64 {
 	if num := c.bc.GetBlockNumber(tx.BlockHash()); num != nil {
-		return *num + uint64(simpletrigger.DefaultConfirmDepth)
+		return *num + c.ConfirmedDepth()
 	}
 	//TODO return current for invisible block?
 	return c.bc.CurrentBlock().NumberU64()
 }
 
+// ConfirmedDepth returns the blocks on top of the last confirmed block, it varies with
+// the head if the blocks are not confirmed by a fixed depth.
+func (c ChainInvoke) ConfirmedDepth() uint64 {
+	current := c.CurrentBlockNumber()
+	return current - c.confirmer.Confirmed(current)
+}
+
 func (c ChainInvoke) GetTransactionTimeOnChain(tx trigger.Transaction) uint64 {
 	if header := c.bc.GetHeaderByHash(tx.BlockHash()); header != nil {
 		return header.Time
@@ -0,0 +1,45 @@
+package retriever
+
+import (
+	"gbchain-org/go-gbchain/params"
+
+	"gbchain-org/go-gbchain/cross"
+	cc "gbchain-org/go-gbchain/cross/core"
+)
+
+// GetReceiptProof creates the receipt proof of the makerTx log of ctx in local chain
+func (v *CreditValidator) GetReceiptProof(ctx *cc.CrossTransaction) (*cc.ReceiptProof, error) {
+	header := v.chain.GetHeaderByHash(ctx.BlockHash())
+	if header == nil {
+		return nil, cross.ErrReorgCtx
+	}
+	receipts := v.chain.GetReceiptsByHash(ctx.BlockHash())
+	for i, receipt := range receipts {
+		if receipt.TxHash != ctx.Data.TxHash {
+			continue
+		}
+		for j, l := range receipt.Logs {
+			if l.Address == v.contract && len(l.Topics) > 1 && l.Topics[0] == params.MakerTopic && l.Topics[1] == ctx.ID() {
+				return cc.NewReceiptProof(header, receipts, uint(i), uint(j))
+			}
+		}
+	}
+	return nil, cc.ErrProofMissingLog
+}
+
+// VerifyReceiptProof checks the proof of ctx, the header must be in the canonical chain
+func (v *CreditValidator) VerifyReceiptProof(ctx *cc.CrossTransaction, proof *cc.ReceiptProof) error {
+	if proof == nil || proof.Header == nil {
+		return cc.ErrInvalidProof
+	}
+	hash := proof.Header.Hash()
+	if v.chain.GetCanonicalHash(proof.Header.Number.Uint64()) != hash {
+		v.logger.Warn("receipt proof header is not canonical", "ctxID", ctx.ID(), "number", proof.Header.Number, "hash", hash)
+		return cc.ErrProofHeaderMismatch
+	}
+	if err := proof.Verify(ctx, v.contract); err != nil {
+		v.logger.Warn("verify receipt proof failed", "ctxID", ctx.ID(), "error", err)
+		return err
+	}
+	return nil
+}
@@ -15,11 +15,16 @@ type SimpleRetriever struct {
 	pm simpletrigger.ProtocolManager
 }
 
+// NewSimpleRetriever creates the retriever of chain, the blocks are confirmed by DefaultConfirmDepth
+// blocks if confirmer is nil.
 func NewSimpleRetriever(bc simpletrigger.BlockChain, pm simpletrigger.ProtocolManager, contract common.Address,
-	config *cross.Config, chainConfig *params.ChainConfig) trigger.ChainRetriever {
+	config *cross.Config, chainConfig *params.ChainConfig, confirmer simpletrigger.Confirmer) trigger.ChainRetriever {
+	if confirmer == nil {
+		confirmer = simpletrigger.NewDepthConfirmer(uint64(simpletrigger.DefaultConfirmDepth))
+	}
 	r := new(SimpleRetriever)
 	r.pm = pm
-	r.ChainInvoke = NewChainInvoke(bc)
+	r.ChainInvoke = NewChainInvoke(bc, confirmer)
 	r.CreditValidator = NewCreditleValidator(contract, bc, config, chainConfig)
 	r.CreditValidator.SimpleRetriever = r
 	return r
@@ -28,7 +33,3 @@ func NewSimpleRetriever(bc simpletrigger.BlockChain, pm simpletrigger.ProtocolMa
 func (s *SimpleRetriever) CanAcceptTxs() bool {
 	return s.pm.CanAcceptTxs()
 }
-
-func (s *SimpleRetriever) ConfirmedDepth() uint64 {
-	return uint64(simpletrigger.DefaultConfirmDepth)
-}
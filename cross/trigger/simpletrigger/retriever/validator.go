@@ -15,10 +15,7 @@ import (
 	"gbchain-org/go-gbchain/cross/trigger/simpletrigger"
 )
 
-const (
-	minRequireSignature = 2
-	expireNumber        = -1 //pending rtx expired after block num (-1 if never expired)
-)
+const minRequireSignature = 2
 
 //
 type CreditValidator struct {
@@ -64,7 +61,7 @@ func (v *CreditValidator) RequireSignatures() int {
 }
 
 func (v *CreditValidator) ExpireNumber() int {
-	return expireNumber
+	return v.config.ExpireNumber()
 }
 
 func (v *CreditValidator) VerifyExpire(ctx *cc.CrossTransaction) error {
@@ -107,7 +104,11 @@ func (v *CreditValidator) VerifySigner(ctx *cc.CrossTransaction, signChain, vali
 		anchorSet = NewAnchorSet(v.config.Anchors)
 		v.anchors[validChain.Uint64()] = anchorSet
 	}
-	signer, ok := anchorSet.IsAnchorSignedCtx(ctx, cc.NewEIP155CtxSigner(signChain))
+	ctxSigner, err := v.config.CtxSigner(signChain)
+	if err != nil {
+		return common.Address{}, err
+	}
+	signer, ok := anchorSet.IsAnchorSignedCtx(ctx, ctxSigner)
 	if !ok {
 		v.logger.Warn("invalid signature", "anchors", anchorSet.String(), "ctxID", ctx.ID().String(), "signer", signer.String())
 		return signer, cross.ErrInvalidSignCtx
@@ -29,6 +29,8 @@ type BlockChain interface {
 	core.ChainContext
 	GetBlockNumber(hash common.Hash) *uint64
 	GetHeaderByHash(hash common.Hash) *types.Header
+	GetCanonicalHash(number uint64) common.Hash
+	GetReceiptsByHash(hash common.Hash) types.Receipts
 	CurrentBlock() *types.Block
 	StateAt(root common.Hash) (*state.StateDB, error)
 }
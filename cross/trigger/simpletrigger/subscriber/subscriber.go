@@ -1,11 +1,14 @@
 package subscriber
 
 import (
+	"encoding/binary"
+	"errors"
 	"math/big"
 	"sync"
 	"time"
 
 	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/ethdb"
 	"gbchain-org/go-gbchain/event"
 	"gbchain-org/go-gbchain/log"
 	"gbchain-org/go-gbchain/params"
@@ -15,10 +18,23 @@ import (
 	"gbchain-org/go-gbchain/cross/trigger/simpletrigger"
 )
 
+// cursorKey is the number of the last handled block in db, which is a key of the queue db shared with executor
+var cursorKey = []byte("_subscriberCursor")
+
+var (
+	errScanning = errors.New("block logs are being replayed")
+	errStopped  = errors.New("subscriber is stopped")
+)
+
 type CreditSubscriber struct {
 	unconfirmedBlockLogs
 	contract common.Address
-	journal  *unconfirmedJournal
+	db       ethdb.KeyValueStore // persists the cursor, the logs are not replayed after restart if nil
+
+	scanLock   sync.Mutex  // orders the blocks of chain and the replayed blocks
+	cursor     uint64      // number of the last handled block
+	cursorHash common.Hash // hash of the last handled block
+	scanning   bool        // the new blocks of chain are handled by the replay
 
 	blockEventFeed event.Feed
 	scope          event.SubscriptionScope
@@ -31,70 +47,153 @@ type CreditSubscriber struct {
 	reorgHook    func(number *big.Int, deletedLogs, rebirthLogs [][]*types.Log)
 }
 
-func NewSimpleSubscriber(contract common.Address, chain chainRetriever, journalPath string) *CreditSubscriber {
+// NewSimpleSubscriber creates the subscriber of contract logs in chain, the logs are confirmed by
+// DefaultConfirmDepth blocks if confirmer is nil.
+func NewSimpleSubscriber(contract common.Address, chain chainRetriever, confirmer simpletrigger.Confirmer, db ethdb.KeyValueStore) *CreditSubscriber {
+	if confirmer == nil {
+		confirmer = simpletrigger.NewDepthConfirmer(uint64(simpletrigger.DefaultConfirmDepth))
+	}
 	s := &CreditSubscriber{
 		contract: contract,
+		db:       db,
 		unconfirmedBlockLogs: unconfirmedBlockLogs{
-			chain: chain,
-			depth: uint(simpletrigger.DefaultConfirmDepth),
+			chain:     chain,
+			confirmer: confirmer,
 		},
 		stop: make(chan struct{}),
 	}
+	if db != nil {
+		if enc, err := db.Get(cursorKey); err == nil && len(enc) == 8 {
+			s.cursor = binary.BigEndian.Uint64(enc)
+		}
+	}
+
+	s.chain.SetCrossSubscriber(s)
+	return s
+}
+
+func (s *CreditSubscriber) Stop() {
+	s.scope.Close()
+	close(s.stop)
+	s.wg.Wait()
+}
 
-	if journalPath != "" {
-		s.journal = newJournal(journalPath)
-		if err := s.journal.load(s.add); err != nil {
-			log.Warn("failed to load unconfirmed journal", "error", err)
+// Resume replays the blocks after the cursor persisted before restart in background.
+// The blocks not confirmed at that time are replayed too, so their logs are confirmed.
+// If no cursor is persisted, e.g. upgraded from the unconfirmed journal, the blocks not
+// confirmed by the head are replayed, which were kept by the journal before.
+func (s *CreditSubscriber) Resume() error {
+	s.scanLock.Lock()
+	cursor := s.cursor
+	s.scanLock.Unlock()
+	if cursor == 0 {
+		head := s.chain.CurrentHeader()
+		if head == nil || head.Number.Uint64() == 0 {
+			return nil // the ctxs made before are synchronised from the other anchors
 		}
-		if err := s.journal.rotate(s.blocks); err != nil {
-			log.Warn("Failed to rotate unconfirmed journal", "err", err)
+		return s.Rescan(s.confirmer.Confirmed(head.Number.Uint64()) + 1)
+	}
+	return s.Rescan(cursor + 1)
+}
+
+// Rescan replays the block logs from number to the current head in background, the block
+// events are reported again. The unconfirmed blocks are always replayed, since the set of
+// unconfirmed blocks is rebuilt by the replay.
+func (s *CreditSubscriber) Rescan(from uint64) error {
+	s.scanLock.Lock()
+	defer s.scanLock.Unlock()
+
+	select {
+	case <-s.stop:
+		return errStopped
+	default:
+	}
+	if s.scanning {
+		return errScanning
+	}
+	if s.cursor > 0 {
+		if unconfirmed := s.confirmer.Confirmed(s.cursor) + 1; unconfirmed < from {
+			from = unconfirmed
 		}
 	}
+	if from == 0 {
+		from = 1
+	}
+	s.lock.Lock()
+	s.blocks = nil
+	s.lock.Unlock()
 
-	s.chain.SetCrossSubscriber(s)
+	s.scanning = true
 	s.wg.Add(1)
-	go s.loop()
-
-	return s
+	go s.scan(from)
+	return nil
 }
 
-func (s *CreditSubscriber) loop() {
+// scan replays the block logs from number until the head.
+func (s *CreditSubscriber) scan(from uint64) {
 	defer s.wg.Done()
 
-	journal := time.NewTicker(time.Minute * 30)
-	defer journal.Stop()
-
-	for {
+	start := time.Now()
+	log.Info("Replaying cross block logs", "chainID", s.chain.GetChainConfig().ChainID, "from", from)
+	for number := from; ; number++ {
 		select {
-		case <-journal.C:
-			if s.journal == nil {
-				break
-			}
-			s.lock.Lock()
-			if err := s.journal.rotate(s.blocks); err != nil {
-				log.Warn("Failed to rotate unconfirmed journal", "err", err)
-			}
-			s.lock.Unlock()
-
 		case <-s.stop:
 			return
+		default:
+		}
+		if !s.scanBlock(number) {
+			log.Info("Replayed cross block logs", "chainID", s.chain.GetChainConfig().ChainID,
+				"from", from, "to", number-1, "elapsed", common.PrettyDuration(time.Since(start)))
+			return
 		}
 	}
 }
 
-func (s *CreditSubscriber) Stop() {
-	s.scope.Close()
-	close(s.stop)
-	s.wg.Wait()
+// scanBlock replays the block logs of number from receipts, false is returned if the
+// block is beyond the head, the new blocks are handled by chain since then.
+func (s *CreditSubscriber) scanBlock(number uint64) bool {
+	s.scanLock.Lock()
+	defer s.scanLock.Unlock()
 
-	if s.journal != nil {
-		if err := s.journal.rotate(s.blocks); err != nil {
-			log.Warn("Failed to rotate unconfirmed journal", "error", err)
-		}
+	header := s.chain.GetHeaderByNumber(number)
+	if header == nil {
+		s.scanning = false
+		return false
+	}
+	var logs []*types.Log
+	for _, receipt := range s.chain.GetReceiptsByHash(header.Hash()) {
+		logs = append(logs, receipt.Logs...)
+	}
+	s.handleLogs(number, header.Hash(), logs)
+	return true
+}
+
+// setCursor records the last handled block, the number is persisted to resume after restart.
+func (s *CreditSubscriber) setCursor(number uint64, hash common.Hash) {
+	s.cursor, s.cursorHash = number, hash
+	if s.db == nil {
+		return
+	}
+	enc := make([]byte, 8)
+	binary.BigEndian.PutUint64(enc, number)
+	if err := s.db.Put(cursorKey, enc); err != nil {
+		log.Warn("Failed to persist subscriber cursor", "number", number, "error", err)
 	}
 }
 
 func (s *CreditSubscriber) StoreCrossContractLog(blockNumber uint64, hash common.Hash, logs []*types.Log) {
+	s.scanLock.Lock()
+	defer s.scanLock.Unlock()
+
+	// the block is handled by the replay, or it is replayed already
+	if s.scanning || (blockNumber == s.cursor && hash == s.cursorHash) {
+		return
+	}
+	s.handleLogs(blockNumber, hash, logs)
+}
+
+// handleLogs reports the new logs of block, and the logs confirmed by it.
+func (s *CreditSubscriber) handleLogs(blockNumber uint64, hash common.Hash, logs []*types.Log) {
 	var unconfirmedLogs []*types.Log
 	currentEvent := cc.CrossBlockEvent{Number: new(big.Int).SetUint64(blockNumber)}
 	if s.newLogHook != nil {
@@ -104,6 +203,7 @@ func (s *CreditSubscriber) StoreCrossContractLog(blockNumber uint64, hash common
 		var takers []*cc.ReceptTransaction
 		var finishes []*cc.CrossTransactionModifier
 		var updates []*cc.RemoteChainInfo
+		var cancels []*cc.ReceptTransaction
 		for _, v := range logs {
 			if s.contract == v.Address && len(v.Topics) > 0 {
 				switch v.Topics[0] {
@@ -138,6 +238,17 @@ func (s *CreditSubscriber) StoreCrossContractLog(blockNumber uint64, hash common
 						unconfirmedLogs = append(unconfirmedLogs, v)
 					}
 
+				case params.TakerCancelTopic:
+					if len(v.Topics) >= 3 && len(v.Data) >= common.HashLength {
+						cancels = append(cancels, newCancelRecept(v, s.chain.GetChainConfig().ChainID))
+						unconfirmedLogs = append(unconfirmedLogs, v)
+					}
+
+				case params.MakerCancelTopic, params.MessageTopic, params.MakerTokenTopic:
+					if len(v.Topics) >= 3 {
+						unconfirmedLogs = append(unconfirmedLogs, v)
+					}
+
 				case params.AddAnchorsTopic, params.RemoveAnchorsTopic, params.UpdateAnchorTopic:
 					updates = append(updates,
 						&cc.RemoteChainInfo{
@@ -151,15 +262,20 @@ func (s *CreditSubscriber) StoreCrossContractLog(blockNumber uint64, hash common
 		currentEvent.NewTaker.Takers = append(currentEvent.NewTaker.Takers, takers...)
 		currentEvent.NewFinish.Finishes = append(currentEvent.NewFinish.Finishes, finishes...)
 		currentEvent.NewAnchor.ChainInfo = append(currentEvent.NewAnchor.ChainInfo, updates...)
+		currentEvent.NewCancel.Cancels = append(currentEvent.NewCancel.Cancels, cancels...)
 	}
 
 	s.insert(blockNumber, hash, unconfirmedLogs, &currentEvent)
+	s.setCursor(blockNumber, hash)
 	if !currentEvent.IsEmpty() {
 		s.crossBlockSend(currentEvent)
 	}
 }
 
 func (s *CreditSubscriber) NotifyBlockReorg(number *big.Int, deletedLogs [][]*types.Log, rebirthLogs [][]*types.Log) {
+	s.scanLock.Lock()
+	defer s.scanLock.Unlock()
+
 	var reorgEvent cc.CrossBlockEvent
 	if s.reorgHook != nil {
 		s.reorgHook(number, deletedLogs, rebirthLogs)
@@ -186,6 +302,12 @@ func (s *CreditSubscriber) NotifyBlockReorg(number *big.Int, deletedLogs [][]*ty
 							Type:   cc.Reorg,
 						})
 					}
+
+				case params.TakerCancelTopic: // reorg cancelling -> waiting
+					if len(l.Topics) >= 3 && len(l.Data) >= common.HashLength {
+						reorgEvent.ReorgCancel.Cancels = append(reorgEvent.ReorgCancel.Cancels,
+							newCancelRecept(l, s.chain.GetChainConfig().ChainID))
+					}
 				}
 			}
 		}
@@ -195,14 +317,23 @@ func (s *CreditSubscriber) NotifyBlockReorg(number *big.Int, deletedLogs [][]*ty
 		s.crossBlockSend(reorgEvent)
 	}
 
-	// restore rebirthLogs to change CrossStore status and insert into unconfirmedLogs
+	// restore rebirthLogs to change CrossStore status and insert into unconfirmedLogs,
+	// the blocks of new chain are handled by the replay if it is running
 	for _, rebirthLog := range rebirthLogs { // reverse logs(lowerNum -> higherNum)
-		if len(rebirthLog) > 0 {
-			s.StoreCrossContractLog(rebirthLog[0].BlockNumber, rebirthLog[0].BlockHash, rebirthLog)
+		if len(rebirthLog) > 0 && !s.scanning {
+			s.handleLogs(rebirthLog[0].BlockNumber, rebirthLog[0].BlockHash, rebirthLog)
 		}
 	}
 }
 
+// newCancelRecept decodes TakerCancel(txId, from, remoteChainId) log, the maker refunds itself
+func newCancelRecept(l *types.Log, chainID *big.Int) *cc.ReceptTransaction {
+	var from common.Address
+	copy(from[:], l.Topics[2][common.HashLength-common.AddressLength:])
+	return cc.NewReceptTransaction(l.Topics[1], l.TxHash, from, from,
+		common.BytesToHash(l.Data[:common.HashLength]).Big(), chainID)
+}
+
 func (s *CreditSubscriber) crossBlockSend(ev cc.CrossBlockEvent) int {
 	return s.blockEventFeed.Send(ev)
 }
@@ -11,7 +11,10 @@ import (
 	"gbchain-org/go-gbchain/core/types"
 	"gbchain-org/go-gbchain/core/vm"
 	cc "gbchain-org/go-gbchain/cross/core"
+	"gbchain-org/go-gbchain/cross/trigger"
+	"gbchain-org/go-gbchain/cross/trigger/simpletrigger"
 	"gbchain-org/go-gbchain/crypto"
+	"gbchain-org/go-gbchain/ethdb/memorydb"
 	"gbchain-org/go-gbchain/params"
 
 	"github.com/stretchr/testify/assert"
@@ -41,8 +44,8 @@ func TestSimpleSubscriber_NotifyBlockReorg(t *testing.T) {
 	blockchain, _ := core.NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil)
 	defer blockchain.Stop()
 
-	subscriber := NewSimpleSubscriber(common.Address{}, blockchain, "")
-	subscriber.depth = 4
+	subscriber := NewSimpleSubscriber(common.Address{}, blockchain, nil, nil)
+	subscriber.confirmer = simpletrigger.NewDepthConfirmer(4)
 
 	chain, _ := core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), db, 4, func(i int, gen *core.BlockGen) {
 		if i == 1 {
@@ -133,3 +136,105 @@ func TestSimpleSubscriber_NotifyBlockReorg(t *testing.T) {
 	assert.Equal(t, 0, len(shifts[3]))
 	assert.Equal(t, 1, len(shifts[4]))
 }
+
+// detachedChain is the blockchain which doesn't report blocks to the subscriber.
+type detachedChain struct {
+	*core.BlockChain
+}
+
+func (c detachedChain) SetCrossSubscriber(s trigger.Subscriber) {}
+
+func TestSimpleSubscriber_Resume(t *testing.T) {
+	var (
+		key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		db     = rawdb.NewMemoryDatabase()
+
+		// this code generates a log
+		code  = common.Hex2Bytes("60606040525b7f24ec1d3ff24c2f6ff210738839dbc339cd45a5294d85c79361016243157aae7b60405180905060405180910390a15b600a8060416000396000f360606040526008565b00")
+		gspec = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  core.GenesisAlloc{addr: {Balance: big.NewInt(10000000000000)}},
+		}
+		genesis = gspec.MustCommit(db)
+		signer  = types.NewEIP155Signer(gspec.Config.ChainID)
+	)
+
+	blockchain, _ := core.NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil)
+	defer blockchain.Stop()
+
+	chain, _ := core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), db, 8, func(i int, gen *core.BlockGen) {
+		if i == 1 || i == 2 {
+			tx, err := types.SignTx(types.NewContractCreation(gen.TxNonce(addr), new(big.Int), 1000000, new(big.Int), code), signer, key)
+			if err != nil {
+				t.Fatalf("failed to create tx: %v", err)
+			}
+			gen.AddTx(tx)
+		}
+	})
+	if _, err := blockchain.InsertChain(chain); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	keep := func(number uint64, hash common.Hash, logs []*types.Log, unconfirmedLogs *[]*types.Log, current *cc.CrossBlockEvent) {
+		*unconfirmedLogs = append(*unconfirmedLogs, logs...)
+	}
+
+	// the subscriber is stopped after the first 4 blocks
+	cursordb := memorydb.New()
+	subscriber := NewSimpleSubscriber(common.Address{}, detachedChain{blockchain}, nil, cursordb)
+	subscriber.confirmer = simpletrigger.NewDepthConfirmer(4)
+	subscriber.newLogHook = keep
+	for _, block := range chain[:4] {
+		var logs []*types.Log
+		for _, receipt := range blockchain.GetReceiptsByHash(block.Hash()) {
+			logs = append(logs, receipt.Logs...)
+		}
+		subscriber.StoreCrossContractLog(block.NumberU64(), block.Hash(), logs)
+	}
+	subscriber.Stop()
+
+	// the restarted subscriber replays the unconfirmed blocks and the blocks after cursor
+	subscriber = NewSimpleSubscriber(common.Address{}, detachedChain{blockchain}, nil, cursordb)
+	assert.Equal(t, uint64(4), subscriber.cursor)
+	subscriber.confirmer = simpletrigger.NewDepthConfirmer(4)
+	subscriber.newLogHook = keep
+
+	var replayed []uint64
+	shifts := make(map[uint64][]*types.Log)
+	subscriber.shiftLogHook = func(number uint64, hash common.Hash, confirmedLogs []*types.Log) {
+		replayed = append(replayed, number)
+		shifts[number] = append(shifts[number], confirmedLogs...)
+	}
+	if err := subscriber.Resume(); err != nil {
+		t.Fatalf("failed to resume: %v", err)
+	}
+	subscriber.wg.Wait()
+
+	assert.Equal(t, []uint64{1, 2, 3, 4}, replayed)
+	assert.Equal(t, 1, len(shifts[2]))
+	assert.Equal(t, 1, len(shifts[3]))
+	assert.Equal(t, uint64(8), subscriber.cursor)
+	assert.False(t, subscriber.scanning)
+
+	// the forced replay starts from the block given
+	replayed = nil
+	assert.NoError(t, subscriber.Rescan(2))
+	subscriber.wg.Wait()
+	assert.Equal(t, []uint64{2, 3, 4}, replayed)
+	subscriber.Stop()
+	assert.Equal(t, errStopped, subscriber.Rescan(1))
+
+	// the subscriber without cursor, e.g. upgraded from the unconfirmed journal, replays the
+	// blocks not confirmed by the head
+	subscriber = NewSimpleSubscriber(common.Address{}, detachedChain{blockchain}, nil, memorydb.New())
+	subscriber.confirmer = simpletrigger.NewDepthConfirmer(4)
+	var handled []uint64
+	subscriber.newLogHook = func(number uint64, hash common.Hash, logs []*types.Log, unconfirmedLogs *[]*types.Log, current *cc.CrossBlockEvent) {
+		handled = append(handled, number)
+	}
+	assert.NoError(t, subscriber.Resume())
+	subscriber.wg.Wait()
+	assert.Equal(t, []uint64{5, 6, 7, 8}, handled)
+	assert.Equal(t, uint64(8), subscriber.cursor)
+	subscriber.Stop()
+}
@@ -12,11 +12,14 @@ import (
 
 	cc "gbchain-org/go-gbchain/cross/core"
 	"gbchain-org/go-gbchain/cross/trigger"
+	"gbchain-org/go-gbchain/cross/trigger/simpletrigger"
 )
 
 type chainRetriever interface {
+	CurrentHeader() *types.Header
 	GetHeaderByNumber(number uint64) *types.Header
 	GetTransactionByTxHash(hash common.Hash) (*types.Transaction, common.Hash, uint64)
+	GetReceiptsByHash(hash common.Hash) types.Receipts
 	GetChainConfig() *params.ChainConfig
 	SetCrossSubscriber(s trigger.Subscriber)
 }
@@ -30,10 +33,10 @@ type unconfirmedBlockLog struct {
 }
 
 type unconfirmedBlockLogs struct {
-	chain  chainRetriever // Blockchain to verify canonical status through
-	depth  uint           // Depth after which to discard previous blocks
-	blocks *ring.Ring     // Block infos to allow canonical chain cross checks
-	lock   sync.RWMutex   // Protects the fields from concurrent access
+	chain     chainRetriever          // Blockchain to verify canonical status through
+	confirmer simpletrigger.Confirmer // Decides the blocks confirmed, which are discarded from the set
+	blocks    *ring.Ring              // Block infos to allow canonical chain cross checks
+	lock      sync.RWMutex            // Protects the fields from concurrent access
 }
 
 func (s *CreditSubscriber) add(index uint64, hash common.Hash, blockLogs []*types.Log) {
@@ -48,8 +51,6 @@ func (s *CreditSubscriber) add(index uint64, hash common.Hash, blockLogs []*type
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	s.journalLog(blockLogs)
-
 	if s.blocks == nil {
 		s.blocks = item
 	} else {
@@ -57,17 +58,6 @@ func (s *CreditSubscriber) add(index uint64, hash common.Hash, blockLogs []*type
 	}
 }
 
-func (s *CreditSubscriber) journalLog(blockLogs []*types.Log) {
-	if s.journal == nil {
-		return
-	}
-	for _, blog := range blockLogs {
-		if err := s.journal.insert(blog); err != nil {
-			log.Warn("Failed to journal unconfirmed logs", "err", err)
-		}
-	}
-}
-
 // Insert adds a new block to the set of trigger ones.
 func (s *CreditSubscriber) insert(index uint64, hash common.Hash, blockLogs []*types.Log, currentEvent *cc.CrossBlockEvent) {
 	// If a new block was mined locally, shift out any old enough blocks
@@ -77,18 +67,19 @@ func (s *CreditSubscriber) insert(index uint64, hash common.Hash, blockLogs []*t
 	s.add(index, hash, blockLogs)
 }
 
-// Shift drops all trigger blocks from the set which exceed the trigger sets depth
-// allowance, checking them against the canonical chain for inclusion or staleness
-// report.
+// Shift drops all trigger blocks from the set which are confirmed at height, checking
+// them against the canonical chain for inclusion or staleness report.
 func (s *CreditSubscriber) shift(height uint64, currentEvent *cc.CrossBlockEvent) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
+	confirmed := s.confirmer.Confirmed(height)
+
 loop:
 	for s.blocks != nil {
 		// Retrieve the next trigger block and abort if too fresh
 		next := s.blocks.Value.(*unconfirmedBlockLog)
-		// Block seems to exceed depth allowance, check for canonical status
+		// Block seems to be confirmed, check for canonical status
 		header := s.chain.GetHeaderByNumber(next.index)
 		switch {
 		case header == nil:
@@ -97,7 +88,7 @@ loop:
 		case header.Hash() != next.hash:
 			log.Info("⑂ block became a side fork", "number", next.index, "hash", next.hash)
 
-		case next.index+uint64(s.depth) > height: // not confirmed yet
+		case next.index > confirmed: // not confirmed yet
 			break loop
 
 		default:
@@ -108,6 +99,10 @@ loop:
 				var ctxs []*cc.CrossTransaction
 				var rtxs []*cc.ReceptTransaction
 				var finishModifiers []*cc.CrossTransactionModifier
+				var cancels []*cc.ReceptTransaction
+				var cancelModifiers []*cc.CrossTransactionModifier
+				var results []*cc.MessageResult
+				var tokens []*cc.MakerToken
 				for _, v := range next.logs {
 					tx, blockHash, blockNumber := s.chain.GetTransactionByTxHash(v.TxHash)
 					if tx != nil && blockHash == v.BlockHash && blockNumber == v.BlockNumber &&
@@ -144,14 +139,36 @@ loop:
 						case params.MakerFinishTopic == v.Topics[0]:
 							finishModifiers = append(finishModifiers, &cc.CrossTransactionModifier{
 								ID:            v.Topics[1],
-								AtBlockNumber: v.BlockNumber + uint64(s.depth),
+								AtBlockNumber: height,
 								Status:        cc.CtxStatusFinished,
 							})
+
+						case params.TakerCancelTopic == v.Topics[0] && len(v.Data) >= common.HashLength:
+							cancels = append(cancels, newCancelRecept(v, s.chain.GetChainConfig().ChainID))
+
+						case params.MakerCancelTopic == v.Topics[0]:
+							cancelModifiers = append(cancelModifiers, &cc.CrossTransactionModifier{
+								ID:            v.Topics[1],
+								AtBlockNumber: height,
+								Status:        cc.CtxStatusCancelled,
+							})
+
+						case params.MessageTopic == v.Topics[0]:
+							if result := cc.NewMessageResult(v); result != nil {
+								results = append(results, result)
+							}
+
+						case params.MakerTokenTopic == v.Topics[0]:
+							if token := cc.NewMakerToken(v); token != nil {
+								tokens = append(tokens, token)
+							}
 						}
 					}
 				}
+				cc.WithMakerTokens(ctxs, tokens)
+				cc.WithMessageResults(rtxs, results)
 
-				confirmNumber := header.Number.Uint64() + uint64(s.depth) // make a confirmed number
+				confirmNumber := height // the logs are confirmed at height
 
 				// add confirmed logs into current block event
 				if currentEvent != nil && currentEvent.Number.Uint64() == confirmNumber {
@@ -159,13 +176,17 @@ loop:
 					currentEvent.ConfirmedMaker.Txs = append(currentEvent.ConfirmedMaker.Txs, ctxs...)
 					currentEvent.ConfirmedTaker.Txs = append(currentEvent.ConfirmedTaker.Txs, rtxs...)
 					currentEvent.ConfirmedFinish.Finishes = append(currentEvent.ConfirmedFinish.Finishes, finishModifiers...)
+					currentEvent.ConfirmedCancel.Cancels = append(currentEvent.ConfirmedCancel.Cancels, cancels...)
+					currentEvent.MakerCancel.Cancels = append(currentEvent.MakerCancel.Cancels, cancelModifiers...)
 
-				} else if len(ctxs)|len(rtxs)|len(finishModifiers) > 0 {
+				} else if len(ctxs)|len(rtxs)|len(finishModifiers)|len(cancels)|len(cancelModifiers) > 0 {
 					s.crossBlockSend(cc.CrossBlockEvent{
 						Number:          new(big.Int).SetUint64(confirmNumber),
 						ConfirmedMaker:  cc.ConfirmedMakerEvent{Txs: ctxs},
 						ConfirmedTaker:  cc.ConfirmedTakerEvent{Txs: rtxs},
 						ConfirmedFinish: cc.ConfirmedFinishEvent{Finishes: finishModifiers},
+						ConfirmedCancel: cc.ConfirmedCancelEvent{Cancels: cancels},
+						MakerCancel:     cc.MakerCancelEvent{Cancels: cancelModifiers},
 					})
 				}
 			}
@@ -25,12 +25,22 @@ func newNoopChainRetriever() *noopChainRetriever {
 func (r *noopChainRetriever) insert(header *types.Header) {
 	r.chain[header.Number.Uint64()] = header
 }
-func (r *noopChainRetriever) SetCrossSubscriber(s trigger.Subscriber)       {}
+func (r *noopChainRetriever) SetCrossSubscriber(s trigger.Subscriber) {}
+func (r *noopChainRetriever) CurrentHeader() *types.Header {
+	var head *types.Header
+	for _, header := range r.chain {
+		if head == nil || header.Number.Cmp(head.Number) > 0 {
+			head = header
+		}
+	}
+	return head
+}
 func (r *noopChainRetriever) GetHeaderByNumber(number uint64) *types.Header { return r.chain[number] }
 func (r *noopChainRetriever) GetTransactionByTxHash(hash common.Hash) (*types.Transaction, common.Hash, uint64) {
 	return nil, common.Hash{}, 0
 }
-func (r *noopChainRetriever) GetChainConfig() *params.ChainConfig { return nil }
+func (r *noopChainRetriever) GetReceiptsByHash(hash common.Hash) types.Receipts { return nil }
+func (r *noopChainRetriever) GetChainConfig() *params.ChainConfig               { return nil }
 
 // Tests that inserting blocks into the unconfirmed set accumulates them until
 // the desired depth is reached, after which they begin to be dropped.
@@ -38,7 +48,7 @@ func TestUnconfirmedInsertBounds(t *testing.T) {
 	simpletrigger.DefaultConfirmDepth = 12
 	limit := simpletrigger.DefaultConfirmDepth
 
-	pool := NewSimpleSubscriber(common.Address{}, newNoopChainRetriever(), "")
+	pool := NewSimpleSubscriber(common.Address{}, newNoopChainRetriever(), nil, nil)
 	for depth := uint64(0); depth < 2*uint64(limit); depth++ {
 		// Insert multiple blocks for the same level just to stress it
 		for i := 0; i < int(depth); i++ {
@@ -62,7 +72,7 @@ func TestUnconfirmedShifts(t *testing.T) {
 	limit, start := uint(12), uint64(25)
 
 	chain := newNoopChainRetriever()
-	pool := NewSimpleSubscriber(common.Address{}, chain, "")
+	pool := NewSimpleSubscriber(common.Address{}, chain, nil, nil)
 	for depth := start; depth < start+uint64(limit); depth++ {
 		header := types.Header{
 			ParentHash: [32]byte{byte(depth)},
@@ -4,6 +4,8 @@ import (
 	"math/big"
 
 	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/common/hexutil"
+	"gbchain-org/go-gbchain/core/types"
 	"gbchain-org/go-gbchain/cross/core"
 	"gbchain-org/go-gbchain/event"
 )
@@ -14,14 +16,70 @@ type Subscriber interface {
 	Stop()
 }
 
+// Signer signs ctxs and transactions by the anchor account
+type Signer interface {
+	SignHash(hash []byte) ([]byte, error) // not supported by external signers
+	SignData(data []byte) ([]byte, error) // signs keccak256(data), data is the signing data of ctx
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
 // Executor execute transactions on blockchain
 type Executor interface {
 	SignHash([]byte) ([]byte, error)
+	SignData([]byte) ([]byte, error) // signs ctx by the signing data, which could be checked by external signers
 	SubmitTransaction([]*core.ReceptTransaction)
+	SubmitCancel([]*core.ReceptTransaction) // refund makers whose ctxs are cancelled in remote chain
 	Start()
 	Stop()
 }
 
+// ExecutorStatus is the state of transactions submitted by executor
+type ExecutorStatus struct {
+	Anchor     common.Address          `json:"anchor"`
+	Nonce      hexutil.Uint64          `json:"nonce"`      // nonce of the next transaction
+	StateNonce hexutil.Uint64          `json:"stateNonce"` // nonce of anchor in the latest state
+	Gaps       hexutil.Uint64          `json:"gaps"`       // count of nonce gaps repaired
+	Future     hexutil.Uint64          `json:"future"`     // count of receipts waiting in the future queue
+	Pending    []*ExecutingTransaction `json:"pending"`    // submitted transactions not confirmed
+}
+
+// ExecutingTransaction is a transaction submitted by executor
+type ExecutingTransaction struct {
+	Nonce    hexutil.Uint64 `json:"nonce"`
+	Hash     common.Hash    `json:"hash"`
+	CtxID    common.Hash    `json:"ctxId"`
+	Refund   bool           `json:"refund"`
+	GasPrice *hexutil.Big   `json:"gasPrice"`
+	Bumps    int            `json:"bumps"` // times of gas price bumped
+}
+
+// ExecutorStatusReader is implemented by executors tracking their submitted transactions
+type ExecutorStatusReader interface {
+	Status() (*ExecutorStatus, error)
+}
+
+// MessageExecutor is implemented by executors which execute message ctxs by takerMessage
+type MessageExecutor interface {
+	SubmitMessage([]*core.CrossTransactionWithSignatures)
+}
+
+// TakerExecutor is implemented by executors which take remote ctxs by the taker accounts of node
+type TakerExecutor interface {
+	// SubmitTaker sends the taker transactions of cwss signed by signer of taker, which pays the destination
+	// values. The hashes of sent transactions are returned in the order of cwss, zero if a ctx is skipped.
+	SubmitTaker(taker common.Address, signer Signer, cwss []*core.CrossTransactionWithSignatures) ([]common.Hash, error)
+	// TakerReceipt returns the receipt of the taker transaction of hash, nil if it is not mined
+	TakerReceipt(hash common.Hash) (*types.Receipt, error)
+}
+
+// Rescanner is implemented by subscribers which replay the block logs of local chain from receipts
+type Rescanner interface {
+	// Resume replays the blocks not handled before restart
+	Resume() error
+	// Rescan replays the blocks from number to the current head, the block events are reported again
+	Rescan(from uint64) error
+}
+
 // Validator validate cross transaction on blockchain, check tx signer on contract
 type Validator interface {
 	VerifyExpire(ctx *core.CrossTransaction) error
@@ -51,3 +109,12 @@ type ChainRetriever interface {
 	GetTransactionNumberOnChain(Transaction) uint64
 	GetConfirmedTransactionNumberOnChain(Transaction) uint64
 }
+
+// ProofRetriever provides receipt proofs of maker logs, it is required when
+// ctxs are validated by receipt proofs besides anchor signatures
+type ProofRetriever interface {
+	// GetReceiptProof creates the receipt proof of a local confirmed maker transaction
+	GetReceiptProof(ctx *core.CrossTransaction) (*core.ReceiptProof, error)
+	// VerifyReceiptProof checks the proof against a canonical header of this chain
+	VerifyReceiptProof(ctx *core.CrossTransaction, proof *core.ReceiptProof) error
+}
@@ -0,0 +1,221 @@
+// Package bls implements BLS signatures over the bn256 curve, signatures are
+// points of G1 and could be aggregated into a single signature, which is
+// verified by one pairing check (compatible with the bn256Pairing precompile).
+//
+// Public keys carry the key in both G1 and G2, the G1 key is aggregated cheaply
+// by contracts while the G2 key is used in the pairing check. Aggregating keys
+// of unknown parties is vulnerable to rogue key attacks, so the keys must be
+// registered by a trusted party (e.g. the cross contract owner).
+package bls
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+
+	"gbchain-org/go-gbchain/common/math"
+	"gbchain-org/go-gbchain/crypto"
+	"gbchain-org/go-gbchain/crypto/bn256"
+)
+
+const (
+	SecretKeyLength = 32
+	PublicKeyLength = 64 + 128 // G1 || G2
+	SignatureLength = 64       // G1
+)
+
+var (
+	// P is the prime of the base field of bn256
+	P, _ = new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208583", 10)
+	// Order is the number of elements in both G1 and G2 of bn256
+	Order, _ = new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+
+	sqrtExp = new(big.Int).Rsh(new(big.Int).Add(P, big.NewInt(1)), 2) // (P+1)/4, P = 3 mod 4
+	curveB  = big.NewInt(3)
+
+	g1Gen = new(bn256.G1).ScalarBaseMult(big.NewInt(1))
+	g2Gen = new(bn256.G2).ScalarBaseMult(big.NewInt(1))
+)
+
+var (
+	ErrInvalidSecretKey = errors.New("invalid bls secret key")
+	ErrInvalidPublicKey = errors.New("invalid bls public key")
+	ErrInvalidSignature = errors.New("invalid bls signature")
+	ErrEmptyAggregation = errors.New("nothing to aggregate")
+)
+
+type SecretKey struct {
+	x *big.Int
+}
+
+type PublicKey struct {
+	g1 *bn256.G1
+	g2 *bn256.G2
+}
+
+type Signature struct {
+	p *bn256.G1
+}
+
+// GenerateKey generates a secret key from rand, crypto/rand.Reader is used if rand is nil
+func GenerateKey(r io.Reader) (*SecretKey, error) {
+	if r == nil {
+		r = rand.Reader
+	}
+	for {
+		x, err := rand.Int(r, Order)
+		if err != nil {
+			return nil, err
+		}
+		if x.Sign() > 0 {
+			return &SecretKey{x: x}, nil
+		}
+	}
+}
+
+func SecretKeyFromBytes(b []byte) (*SecretKey, error) {
+	x := new(big.Int).SetBytes(b)
+	if len(b) != SecretKeyLength || x.Sign() == 0 || x.Cmp(Order) >= 0 {
+		return nil, ErrInvalidSecretKey
+	}
+	return &SecretKey{x: x}, nil
+}
+
+func (k *SecretKey) Marshal() []byte {
+	return math.PaddedBigBytes(k.x, SecretKeyLength)
+}
+
+func (k *SecretKey) PublicKey() *PublicKey {
+	return &PublicKey{
+		g1: new(bn256.G1).ScalarBaseMult(k.x),
+		g2: new(bn256.G2).ScalarBaseMult(k.x),
+	}
+}
+
+// Sign signs the hash of a message, the signature is H(hash)^x in G1
+func (k *SecretKey) Sign(hash []byte) *Signature {
+	return &Signature{p: new(bn256.G1).ScalarMult(HashToG1(hash), k.x)}
+}
+
+// SignHash is the signing function in the form of core.SignHash
+func (k *SecretKey) SignHash(hash []byte) ([]byte, error) {
+	return k.Sign(hash).Marshal(), nil
+}
+
+// PublicKeyFromBytes decodes G1 || G2 public key, both keys must be of the same secret
+func PublicKeyFromBytes(b []byte) (*PublicKey, error) {
+	if len(b) != PublicKeyLength {
+		return nil, ErrInvalidPublicKey
+	}
+	pk := &PublicKey{g1: new(bn256.G1), g2: new(bn256.G2)}
+	if _, err := pk.g1.Unmarshal(b[:64]); err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+	if _, err := pk.g2.Unmarshal(b[64:]); err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+	// e(pk1, g2) == e(g1, pk2)
+	if !bn256.PairingCheck([]*bn256.G1{pk.g1, new(bn256.G1).Neg(g1Gen)}, []*bn256.G2{g2Gen, pk.g2}) {
+		return nil, ErrInvalidPublicKey
+	}
+	return pk, nil
+}
+
+func (pk *PublicKey) Marshal() []byte {
+	return append(pk.g1.Marshal(), pk.g2.Marshal()...)
+}
+
+// G1 returns the marshaled G1 key (x || y)
+func (pk *PublicKey) G1() []byte {
+	return pk.g1.Marshal()
+}
+
+// G2 returns the marshaled G2 key (x.i || x.r || y.i || y.r)
+func (pk *PublicKey) G2() []byte {
+	return pk.g2.Marshal()
+}
+
+func SignatureFromBytes(b []byte) (*Signature, error) {
+	if len(b) != SignatureLength {
+		return nil, ErrInvalidSignature
+	}
+	sig := &Signature{p: new(bn256.G1)}
+	if _, err := sig.p.Unmarshal(b); err != nil {
+		return nil, ErrInvalidSignature
+	}
+	return sig, nil
+}
+
+func (sig *Signature) Marshal() []byte {
+	return sig.p.Marshal()
+}
+
+// Verify checks e(sig, g2) == e(H(hash), pk)
+func Verify(pk *PublicKey, hash []byte, sig *Signature) bool {
+	return bn256.PairingCheck(
+		[]*bn256.G1{sig.p, new(bn256.G1).Neg(HashToG1(hash))},
+		[]*bn256.G2{g2Gen, pk.g2},
+	)
+}
+
+// AggregateSignatures sums up signatures of the same message
+func AggregateSignatures(sigs ...*Signature) (*Signature, error) {
+	if len(sigs) == 0 {
+		return nil, ErrEmptyAggregation
+	}
+	p := sigs[0].p
+	for _, sig := range sigs[1:] {
+		p = new(bn256.G1).Add(p, sig.p)
+	}
+	return &Signature{p: p}, nil
+}
+
+// AggregatePublicKeys sums up public keys, the aggregated signature is verified by the aggregated key
+func AggregatePublicKeys(pks ...*PublicKey) (*PublicKey, error) {
+	if len(pks) == 0 {
+		return nil, ErrEmptyAggregation
+	}
+	g1, g2 := pks[0].g1, pks[0].g2
+	for _, pk := range pks[1:] {
+		g1 = new(bn256.G1).Add(g1, pk.g1)
+		g2 = new(bn256.G2).Add(g2, pk.g2)
+	}
+	return &PublicKey{g1: g1, g2: g2}, nil
+}
+
+// HashToG1 maps hash to a point of G1 by try-and-increment:
+// x = hash mod P, increase x until x^3+3 is a quadratic residue, y = (x^3+3)^((P+1)/4).
+// The same mapping is implemented by contracts with the modexp precompile.
+func HashToG1(hash []byte) *bn256.G1 {
+	x := new(big.Int).SetBytes(hash)
+	x.Mod(x, P)
+	for {
+		y2 := new(big.Int).Exp(x, big.NewInt(3), P)
+		y2.Add(y2, curveB).Mod(y2, P)
+		y := new(big.Int).Exp(y2, sqrtExp, P)
+		if new(big.Int).Exp(y, big.NewInt(2), P).Cmp(y2) == 0 {
+			b := append(math.PaddedBigBytes(x, 32), math.PaddedBigBytes(y, 32)...)
+			p := new(bn256.G1)
+			if _, err := p.Unmarshal(b); err == nil {
+				return p
+			}
+		}
+		x.Add(x, big.NewInt(1)).Mod(x, P)
+	}
+}
+
+// VerifyAggregated checks the aggregated signature in the way of cross contract, which holds
+// the G1 keys only and receives the aggregated G2 key from caller. The G1 and G2 keys
+// are checked to be the same key and the signature is verified by one pairing check:
+//   e(sig + r*pk1, g2) == e(H(hash) + r*g1, pk2), r = keccak256(sig || pk1 || pk2 || hash) mod Order
+func VerifyAggregated(pk *PublicKey, hash []byte, sig *Signature) bool {
+	r := new(big.Int).SetBytes(crypto.Keccak256(sig.Marshal(), pk.G1(), pk.G2(), hash))
+	r.Mod(r, Order)
+	left := new(bn256.G1).Add(sig.p, new(bn256.G1).ScalarMult(pk.g1, r))
+	right := new(bn256.G1).Add(HashToG1(hash), new(bn256.G1).ScalarMult(g1Gen, r))
+	return bn256.PairingCheck(
+		[]*bn256.G1{left, new(bn256.G1).Neg(right)},
+		[]*bn256.G2{g2Gen, pk.g2},
+	)
+}
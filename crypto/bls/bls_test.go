@@ -0,0 +1,100 @@
+package bls
+
+import (
+	"bytes"
+	"testing"
+
+	"gbchain-org/go-gbchain/crypto"
+)
+
+func TestSignVerify(t *testing.T) {
+	key, err := GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := crypto.Keccak256([]byte("hello"))
+	sig := key.Sign(hash)
+	if !Verify(key.PublicKey(), hash, sig) {
+		t.Fatal("signature is not verified")
+	}
+	if Verify(key.PublicKey(), crypto.Keccak256([]byte("world")), sig) {
+		t.Fatal("signature of another message is verified")
+	}
+	other, _ := GenerateKey(nil)
+	if Verify(other.PublicKey(), hash, sig) {
+		t.Fatal("signature is verified by another key")
+	}
+
+	dec, err := SignatureFromBytes(sig.Marshal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Verify(key.PublicKey(), hash, dec) {
+		t.Fatal("decoded signature is not verified")
+	}
+}
+
+func TestKeyEncoding(t *testing.T) {
+	key, _ := GenerateKey(nil)
+	dec, err := SecretKeyFromBytes(key.Marshal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(dec.PublicKey().Marshal(), key.PublicKey().Marshal()) {
+		t.Fatal("secret key mismatch after decoding")
+	}
+	if _, err := SecretKeyFromBytes(make([]byte, SecretKeyLength)); err != ErrInvalidSecretKey {
+		t.Errorf("expected %v, got %v", ErrInvalidSecretKey, err)
+	}
+
+	pk, err := PublicKeyFromBytes(key.PublicKey().Marshal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(pk.Marshal(), key.PublicKey().Marshal()) {
+		t.Fatal("public key mismatch after decoding")
+	}
+
+	// G1 and G2 keys of different secrets
+	other, _ := GenerateKey(nil)
+	mixed := append(key.PublicKey().G1(), other.PublicKey().G2()...)
+	if _, err := PublicKeyFromBytes(mixed); err != ErrInvalidPublicKey {
+		t.Errorf("expected %v, got %v", ErrInvalidPublicKey, err)
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	hash := crypto.Keccak256([]byte("hello"))
+	var (
+		sigs []*Signature
+		pks  []*PublicKey
+	)
+	for i := 0; i < 3; i++ {
+		key, _ := GenerateKey(nil)
+		sigs = append(sigs, key.Sign(hash))
+		pks = append(pks, key.PublicKey())
+	}
+	sig, err := AggregateSignatures(sigs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, err := AggregatePublicKeys(pks...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Verify(pk, hash, sig) {
+		t.Fatal("aggregated signature is not verified")
+	}
+	if !VerifyAggregated(pk, hash, sig) {
+		t.Fatal("aggregated signature is not verified in the way of contract")
+	}
+
+	// missing one signer
+	part, _ := AggregatePublicKeys(pks[:2]...)
+	if VerifyAggregated(part, hash, sig) {
+		t.Fatal("aggregated signature is verified by part of keys")
+	}
+	if _, err := AggregateSignatures(); err != ErrEmptyAggregation {
+		t.Errorf("expected %v, got %v", ErrEmptyAggregation, err)
+	}
+}
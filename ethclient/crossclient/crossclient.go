@@ -0,0 +1,328 @@
+// Package crossclient provides a client for the cross RPC API.
+//
+// The public methods are served by the RPC of each chain of anchor node, they select the
+// chain pair by remoteID, which could be nil if the chain has only one pair. The admin
+// methods are served by the RPC of anchor node.
+package crossclient
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"gbchain-org/go-gbchain"
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/common/hexutil"
+	"gbchain-org/go-gbchain/cross/backend"
+	"gbchain-org/go-gbchain/cross/backend/synchronise"
+	cc "gbchain-org/go-gbchain/cross/core"
+	"gbchain-org/go-gbchain/cross/trigger"
+	"gbchain-org/go-gbchain/rlp"
+	"gbchain-org/go-gbchain/rpc"
+)
+
+// Client defines typed wrappers for the cross RPC API.
+type Client struct {
+	c *rpc.Client
+}
+
+// Dial connects a client to the given URL.
+func Dial(rawurl string) (*Client, error) {
+	return DialContext(context.Background(), rawurl)
+}
+
+func DialContext(ctx context.Context, rawurl string) (*Client, error) {
+	c, err := rpc.DialContext(ctx, rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(c), nil
+}
+
+// NewClient creates a client that uses the given RPC client.
+func NewClient(c *rpc.Client) *Client {
+	return &Client{c}
+}
+
+func (xc *Client) Close() {
+	xc.c.Close()
+}
+
+func toBig(n *big.Int) *hexutil.Big {
+	return (*hexutil.Big)(n)
+}
+
+// Ctx Access
+
+// CtxGet returns the ctx of id, it could be finished, made in the chain or waiting for takers.
+func (xc *Client) CtxGet(ctx context.Context, id common.Hash, remoteID *big.Int) (*backend.RPCCrossTransaction, error) {
+	var r *backend.RPCCrossTransaction
+	if err := xc.c.CallContext(ctx, &r, "cross_ctxGet", id, toBig(remoteID)); err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, gbchian.NotFound
+	}
+	return r, nil
+}
+
+// CtxQuery returns the ctx made by the maker transaction of hash.
+func (xc *Client) CtxQuery(ctx context.Context, hash common.Hash, remoteID *big.Int) (*backend.RPCCrossTransaction, error) {
+	var r *backend.RPCCrossTransaction
+	if err := xc.c.CallContext(ctx, &r, "cross_ctxQuery", hash, toBig(remoteID)); err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, gbchian.NotFound
+	}
+	return r, nil
+}
+
+// CtxGetByNumber returns the ids of ctxs made in the block range [begin, end], grouped by status.
+func (xc *Client) CtxGetByNumber(ctx context.Context, begin, end uint64, remoteID *big.Int) (map[cc.CtxStatus][]common.Hash, error) {
+	var r map[cc.CtxStatus][]common.Hash
+	err := xc.c.CallContext(ctx, &r, "cross_ctxGetByNumber", hexutil.Uint64(begin), hexutil.Uint64(end), toBig(remoteID))
+	return r, err
+}
+
+// CtxContentByPage returns the waiting ctxs made in the chain ("local") and the ones
+// could be taken in the chain ("remote"), in the order of prices.
+func (xc *Client) CtxContentByPage(ctx context.Context, localSize, localPage, remoteSize, remotePage int, remoteID *big.Int) (map[string]backend.RPCPageCrossTransactions, error) {
+	var r map[string]backend.RPCPageCrossTransactions
+	err := xc.c.CallContext(ctx, &r, "cross_ctxContentByPage", localSize, localPage, remoteSize, remotePage, toBig(remoteID))
+	return r, err
+}
+
+// CtxIllegalByPage returns the illegal ctxs made in the chain.
+func (xc *Client) CtxIllegalByPage(ctx context.Context, pageSize, startPage int, remoteID *big.Int) (*backend.RPCPageCrossTransactions, error) {
+	var r *backend.RPCPageCrossTransactions
+	err := xc.c.CallContext(ctx, &r, "cross_ctxIllegalByPage", pageSize, startPage, toBig(remoteID))
+	return r, err
+}
+
+// CtxQueryDestValue returns the waiting ctxs paying at least value in the chain, the ctxs
+// are filtered by the destination token if it is not nil, zero for native coin.
+func (xc *Client) CtxQueryDestValue(ctx context.Context, value *big.Int, pageSize, startPage int, remoteID *big.Int, token *common.Address) (*backend.RPCPageCrossTransactions, error) {
+	var r *backend.RPCPageCrossTransactions
+	err := xc.c.CallContext(ctx, &r, "cross_ctxQueryDestValue", toBig(value), pageSize, startPage, toBig(remoteID), token)
+	return r, err
+}
+
+// CtxOwner returns all the ctxs made by from in the chain.
+func (xc *Client) CtxOwner(ctx context.Context, from common.Address, remoteID *big.Int) (map[string]map[uint64][]*backend.RPCOwnerCrossTransaction, error) {
+	var r map[string]map[uint64][]*backend.RPCOwnerCrossTransaction
+	err := xc.c.CallContext(ctx, &r, "cross_ctxOwner", from, toBig(remoteID))
+	return r, err
+}
+
+// CtxOwnerByPage returns the ctxs made by from in the chain.
+func (xc *Client) CtxOwnerByPage(ctx context.Context, from common.Address, pageSize, startPage int, remoteID *big.Int) (*backend.RPCPageOwnerCrossTransactions, error) {
+	var r *backend.RPCPageOwnerCrossTransactions
+	err := xc.c.CallContext(ctx, &r, "cross_ctxOwnerByPage", from, pageSize, startPage, toBig(remoteID))
+	return r, err
+}
+
+// CtxTakerByPage returns the remote ctxs could only be taken by to.
+func (xc *Client) CtxTakerByPage(ctx context.Context, to common.Address, pageSize, startPage int, remoteID *big.Int) (*backend.RPCPageOwnerCrossTransactions, error) {
+	var r *backend.RPCPageOwnerCrossTransactions
+	err := xc.c.CallContext(ctx, &r, "cross_ctxTakerByPage", to, pageSize, startPage, toBig(remoteID))
+	return r, err
+}
+
+// CtxCancel returns the takerCancel call of an expired ctx made in the chain.
+func (xc *Client) CtxCancel(ctx context.Context, id common.Hash, remoteID *big.Int) (*backend.RPCCancelTransaction, error) {
+	var r *backend.RPCCancelTransaction
+	err := xc.c.CallContext(ctx, &r, "cross_ctxCancel", id, toBig(remoteID))
+	return r, err
+}
+
+// CtxMessage returns the message of ctx and the result of its execution.
+func (xc *Client) CtxMessage(ctx context.Context, id common.Hash, remoteID *big.Int) (*backend.RPCMessage, error) {
+	var r *backend.RPCMessage
+	err := xc.c.CallContext(ctx, &r, "cross_ctxMessage", id, toBig(remoteID))
+	return r, err
+}
+
+// CtxAggregatedSignature returns the aggregated BLS signature of ctx.
+func (xc *Client) CtxAggregatedSignature(ctx context.Context, id common.Hash, remoteID *big.Int) (*cc.AggregatedSignature, error) {
+	var r *cc.AggregatedSignature
+	err := xc.c.CallContext(ctx, &r, "cross_ctxAggregatedSignature", id, toBig(remoteID))
+	return r, err
+}
+
+// SubscribeCtxStatus subscribes to the ctxs made in or bridged to the chain, which are
+// stored or whose status are changed. The ctxs are filtered by the optional filter.
+func (xc *Client) SubscribeCtxStatus(ctx context.Context, filter *backend.CtxStatusFilter, ch chan<- *backend.RPCCrossTransaction) (gbchian.Subscription, error) {
+	return xc.c.Subscribe(ctx, "cross", ch, "ctxStatus", filter)
+}
+
+// Service State
+
+// PoolStats returns the numbers of pending and queued ctxs in the pool of chain pair.
+func (xc *Client) PoolStats(ctx context.Context, remoteID *big.Int) (pending, queue int, err error) {
+	var r map[string]int
+	if err := xc.c.CallContext(ctx, &r, "cross_poolStats", toBig(remoteID)); err != nil {
+		return 0, 0, err
+	}
+	return r["pending"], r["queue"], nil
+}
+
+// Monitor returns the ctxs signed by each anchor.
+func (xc *Client) Monitor(ctx context.Context, remoteID *big.Int) (*backend.MonitorInfo, error) {
+	var r *backend.MonitorInfo
+	err := xc.c.CallContext(ctx, &r, "cross_monitor", toBig(remoteID))
+	return r, err
+}
+
+// ExecutorStatus returns the transactions submitted by the executor of the chain.
+func (xc *Client) ExecutorStatus(ctx context.Context) (*trigger.ExecutorStatus, error) {
+	var r *trigger.ExecutorStatus
+	err := xc.c.CallContext(ctx, &r, "cross_executorStatus")
+	return r, err
+}
+
+// Admin
+
+// Anchors returns the anchors of each chain served by the node.
+func (xc *Client) Anchors(ctx context.Context) (map[uint64][]common.Address, error) {
+	var r map[uint64][]common.Address
+	err := xc.c.CallContext(ctx, &r, "cross_anchors")
+	return r, err
+}
+
+// Peers returns the connected anchor peers.
+func (xc *Client) Peers(ctx context.Context) ([]*backend.CrossPeerInfo, error) {
+	var r []*backend.CrossPeerInfo
+	err := xc.c.CallContext(ctx, &r, "cross_peers")
+	return r, err
+}
+
+// Bans returns the anchor peers banned for misbehaving and the time their bans expire.
+func (xc *Client) Bans(ctx context.Context) (map[string]time.Time, error) {
+	var r map[string]time.Time
+	err := xc.c.CallContext(ctx, &r, "cross_bans")
+	return r, err
+}
+
+// Evidence returns the evidences of misbehaving anchors signing ctxs of the chain.
+func (xc *Client) Evidence(ctx context.Context, chainID *big.Int) ([]*backend.RPCEvidence, error) {
+	var r []*backend.RPCEvidence
+	err := xc.c.CallContext(ctx, &r, "cross_evidence", toBig(chainID))
+	return r, err
+}
+
+// Height returns the store height of each chain pair, keyed by "local->remote".
+func (xc *Client) Height(ctx context.Context) (map[string]uint64, error) {
+	var r map[string]hexutil.Uint64
+	if err := xc.c.CallContext(ctx, &r, "cross_height"); err != nil {
+		return nil, err
+	}
+	heights := make(map[string]uint64, len(r))
+	for pair, height := range r {
+		heights[pair] = uint64(height)
+	}
+	return heights, nil
+}
+
+// Stats returns the numbers of ctxs in each status, keyed by chain.
+func (xc *Client) Stats(ctx context.Context) (map[uint64]map[cc.CtxStatus]int, error) {
+	var r map[uint64]map[cc.CtxStatus]int
+	err := xc.c.CallContext(ctx, &r, "cross_stats")
+	return r, err
+}
+
+// SyncPending synchronises the pending ctxs with the anchor peers, it reports whether any peer is connected.
+func (xc *Client) SyncPending(ctx context.Context) (bool, error) {
+	var r bool
+	err := xc.c.CallContext(ctx, &r, "cross_syncPending")
+	return r, err
+}
+
+// SyncStore synchronises the stores with the anchor peers.
+func (xc *Client) SyncStore(ctx context.Context) (bool, error) {
+	var r bool
+	err := xc.c.CallContext(ctx, &r, "cross_syncStore")
+	return r, err
+}
+
+// Repair repairs the indexes of stores.
+func (xc *Client) Repair(ctx context.Context) error {
+	return xc.c.CallContext(ctx, nil, "cross_repair")
+}
+
+// SetStoreDelay sets the blocks the ctxs of chain pair are stored after they are confirmed.
+func (xc *Client) SetStoreDelay(ctx context.Context, chainID *big.Int, number uint64, remoteID *big.Int) error {
+	return xc.c.CallContext(ctx, nil, "cross_setStoreDelay", toBig(chainID), hexutil.Uint64(number), toBig(remoteID))
+}
+
+// Remove removes the ctxs of chain pair made before the block number, it reports whether any ctx is removed.
+func (xc *Client) Remove(ctx context.Context, chainID *big.Int, number uint64, remoteID *big.Int) (bool, error) {
+	var r bool
+	err := xc.c.CallContext(ctx, &r, "cross_remove", toBig(chainID), hexutil.Uint64(number), toBig(remoteID))
+	return r, err
+}
+
+// Archive moves the finished ctxs of chain pair made before the block number into the archive,
+// it reports whether any ctx is archived.
+func (xc *Client) Archive(ctx context.Context, chainID *big.Int, number uint64, remoteID *big.Int) (bool, error) {
+	var r bool
+	err := xc.c.CallContext(ctx, &r, "cross_archive", toBig(chainID), hexutil.Uint64(number), toBig(remoteID))
+	return r, err
+}
+
+// PruneReport returns the space reclaimed by archiving finished ctxs.
+func (xc *Client) PruneReport(ctx context.Context) (*backend.RPCPruneReport, error) {
+	var r *backend.RPCPruneReport
+	err := xc.c.CallContext(ctx, &r, "cross_pruneReport")
+	return r, err
+}
+
+// Divergences returns the store ranges of chain pairs still different from peers after
+// synchronised by digests, which are keyed by the pairs like "1->2".
+func (xc *Client) Divergences(ctx context.Context) (map[string][]synchronise.Divergence, error) {
+	var r map[string][]synchronise.Divergence
+	err := xc.c.CallContext(ctx, &r, "cross_divergences")
+	return r, err
+}
+
+// Rescan replays the block logs of chain from the block fromBlock in background.
+func (xc *Client) Rescan(ctx context.Context, chainID *big.Int, fromBlock uint64) error {
+	return xc.c.CallContext(ctx, nil, "cross_rescan", toBig(chainID), hexutil.Uint64(fromBlock))
+}
+
+// ImportCtx imports a ctx signed by enough anchors into the store of its chain pair.
+func (xc *Client) ImportCtx(ctx context.Context, cws *cc.CrossTransactionWithSignatures) error {
+	data, err := rlp.EncodeToBytes(cws)
+	if err != nil {
+		return err
+	}
+	return xc.c.CallContext(ctx, nil, "cross_importCtx", hexutil.Bytes(data))
+}
+
+// Auto Taker
+
+// AddTakerOrder places a standing order taking the remote ctxs in chainID, the ID of order is returned.
+func (xc *Client) AddTakerOrder(ctx context.Context, chainID *big.Int, order backend.TakerOrderArgs, remoteID *big.Int) (uint64, error) {
+	var r hexutil.Uint64
+	err := xc.c.CallContext(ctx, &r, "cross_addTakerOrder", toBig(chainID), order, toBig(remoteID))
+	return uint64(r), err
+}
+
+// CancelTakerOrder removes the standing order.
+func (xc *Client) CancelTakerOrder(ctx context.Context, chainID *big.Int, id uint64, remoteID *big.Int) error {
+	return xc.c.CallContext(ctx, nil, "cross_cancelTakerOrder", toBig(chainID), hexutil.Uint64(id), toBig(remoteID))
+}
+
+// TakerOrders returns the standing orders in the order of placing.
+func (xc *Client) TakerOrders(ctx context.Context, chainID *big.Int, remoteID *big.Int) ([]*backend.RPCTakerOrder, error) {
+	var r []*backend.RPCTakerOrder
+	err := xc.c.CallContext(ctx, &r, "cross_takerOrders", toBig(chainID), toBig(remoteID))
+	return r, err
+}
+
+// TakerBook returns the cheapest remote waiting ctxs matched by the last round of auto taker.
+func (xc *Client) TakerBook(ctx context.Context, chainID *big.Int, limit int, remoteID *big.Int) ([]*backend.RPCCrossTransaction, error) {
+	var r []*backend.RPCCrossTransaction
+	err := xc.c.CallContext(ctx, &r, "cross_takerBook", toBig(chainID), limit, toBig(remoteID))
+	return r, err
+}
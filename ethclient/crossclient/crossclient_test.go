@@ -0,0 +1,292 @@
+package crossclient
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"gbchain-org/go-gbchain"
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/cross"
+	"gbchain-org/go-gbchain/cross/backend"
+	cc "gbchain-org/go-gbchain/cross/core"
+	"gbchain-org/go-gbchain/cross/trigger"
+	"gbchain-org/go-gbchain/crypto"
+	"gbchain-org/go-gbchain/event"
+	"gbchain-org/go-gbchain/node"
+	"gbchain-org/go-gbchain/rpc"
+)
+
+var (
+	testMainID  = big.NewInt(18)
+	testSubID   = big.NewInt(19)
+	testAnchors = []common.Address{common.HexToAddress("0x01"), common.HexToAddress("0x02")}
+)
+
+// testChain serves the public cross apis of a chain by its own rpc server
+type testChain struct {
+	chainID *big.Int
+	server  *rpc.Server
+}
+
+func (c *testChain) ChainID() *big.Int        { return c.chainID }
+func (c *testChain) GenesisHash() common.Hash { return common.BigToHash(c.chainID) }
+func (c *testChain) RegisterAPIs(apis []rpc.API) {
+	for _, api := range apis {
+		if err := c.server.RegisterName(api.Namespace, api.Service); err != nil {
+			panic(err)
+		}
+	}
+}
+
+type testSubscriber struct{ feed event.Feed }
+
+func (s *testSubscriber) SubscribeBlockEvent(ch chan<- cc.CrossBlockEvent) event.Subscription {
+	return s.feed.Subscribe(ch)
+}
+func (s *testSubscriber) Stop() {}
+
+// testRetriever accepts all ctxs signed by two anchors
+type testRetriever struct{}
+
+func (testRetriever) VerifyExpire(ctx *cc.CrossTransaction) error          { return nil }
+func (testRetriever) VerifyContract(cws trigger.Transaction) error         { return nil }
+func (testRetriever) UpdateAnchors(info *cc.RemoteChainInfo) error         { return nil }
+func (testRetriever) RequireSignatures() int                               { return 2 }
+func (testRetriever) ExpireNumber() int                                    { return -1 }
+func (testRetriever) CanAcceptTxs() bool                                   { return true }
+func (testRetriever) ConfirmedDepth() uint64                               { return 1 }
+func (testRetriever) CurrentBlockNumber() uint64                           { return 1 }
+func (testRetriever) GetTransactionTimeOnChain(trigger.Transaction) uint64 { return 0 }
+func (testRetriever) GetTransactionNumberOnChain(trigger.Transaction) uint64 {
+	return 0
+}
+func (testRetriever) GetConfirmedTransactionNumberOnChain(trigger.Transaction) uint64 {
+	return 0
+}
+func (testRetriever) VerifySigner(ctx *cc.CrossTransaction, signChain, storeChainID *big.Int) (common.Address, error) {
+	return common.Address{}, nil
+}
+
+type testExecutor struct{}
+
+func (testExecutor) SignHash([]byte) ([]byte, error)           { return nil, nil }
+func (testExecutor) SignData([]byte) ([]byte, error)           { return nil, nil }
+func (testExecutor) SubmitTransaction([]*cc.ReceptTransaction) {}
+func (testExecutor) SubmitCancel([]*cc.ReceptTransaction)      {}
+func (testExecutor) Start()                                    {}
+func (testExecutor) Stop()                                     {}
+
+func newTestServiceContext(chainID *big.Int) *cross.ServiceContext {
+	config := cross.DefaultConfig
+	config.Anchors = testAnchors
+	return &cross.ServiceContext{
+		Config:        &config,
+		ProtocolChain: &testChain{chainID: chainID, server: rpc.NewServer()},
+		Subscriber:    new(testSubscriber),
+		Retriever:     testRetriever{},
+		Executor:      testExecutor{},
+	}
+}
+
+// newTestBackend starts a node running the cross service of a chain pair, the clients of
+// the admin apis and public apis of main chain are returned.
+func newTestBackend(t *testing.T, datadir string) (*node.Node, *Client, *Client) {
+	main, sub := newTestServiceContext(testMainID), newTestServiceContext(testSubID)
+	n, err := node.New(&node.Config{DataDir: datadir})
+	if err != nil {
+		t.Fatalf("can't create test node: %v", err)
+	}
+	n.Register(func(ctx *node.ServiceContext) (node.Service, error) {
+		return backend.NewCrossService(ctx, []cross.ServicePair{{Main: main, Sub: sub}}, cross.DefaultConfig)
+	})
+	if err := n.Start(); err != nil {
+		t.Fatalf("can't start test node: %v", err)
+	}
+
+	admin, err := n.Attach()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := rpc.DialInProc(main.ProtocolChain.(*testChain).server)
+	return n, NewClient(admin), NewClient(chain)
+}
+
+// newTestCtx creates a ctx of main chain signed by n anchors
+func newTestCtx(t *testing.T, n int) *cc.CrossTransactionWithSignatures {
+	var (
+		signer = cc.MakeCtxSigner(testMainID)
+		tx     = cc.NewCrossTransaction(big.NewInt(1e18), big.NewInt(2e18), testSubID,
+			common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03"),
+			common.HexToAddress("0x04"), common.Address{}, nil)
+		cws *cc.CrossTransactionWithSignatures
+	)
+	for i := 0; i < n; i++ {
+		key, _ := crypto.GenerateKey()
+		signed, err := cc.SignCtxData(tx, signer, cross.NewKeySigner(key).SignData)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cws == nil {
+			cws = cc.NewCrossTransactionWithSignatures(signed, 1)
+		} else if err := cws.AddSignature(signed); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cws.SetStatus(cc.CtxStatusWaiting)
+	return cws
+}
+
+func TestAdmin(t *testing.T) {
+	datadir, err := ioutil.TempDir("", "crossclient")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(datadir)
+	n, admin, _ := newTestBackend(t, datadir)
+	defer n.Stop()
+	ctx := context.Background()
+
+	anchors, err := admin.Anchors(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anchors) != 2 || len(anchors[testMainID.Uint64()]) != len(testAnchors) {
+		t.Errorf("anchors mismatch: %v", anchors)
+	}
+	heights, err := admin.Height(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(heights) != 2 {
+		t.Errorf("heights mismatch: %v", heights)
+	}
+	peers, err := admin.Peers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(peers) != 0 {
+		t.Errorf("peers mismatch: have %d, want 0", len(peers))
+	}
+	if err := admin.ImportCtx(ctx, newTestCtx(t, 1)); err == nil {
+		t.Errorf("ctx without enough signatures is imported")
+	}
+	if _, err := admin.Evidence(ctx, big.NewInt(1)); err == nil {
+		t.Errorf("evidence of unregistered chain is returned")
+	}
+}
+
+func TestImportCtx(t *testing.T) {
+	datadir, err := ioutil.TempDir("", "crossclient")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(datadir)
+	n, admin, client := newTestBackend(t, datadir)
+	defer n.Stop()
+	ctx := context.Background()
+
+	ch := make(chan *backend.RPCCrossTransaction, 1)
+	sub, err := client.SubscribeCtxStatus(ctx, &backend.CtxStatusFilter{From: []common.Address{common.HexToAddress("0x04")}}, ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	cws := newTestCtx(t, 2)
+	if _, err := client.CtxGet(ctx, cws.ID(), nil); err != gbchian.NotFound {
+		t.Fatalf("ctx is found before imported: %v", err)
+	}
+	if err := admin.ImportCtx(ctx, cws); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case tx := <-ch:
+		if tx.CTxId != cws.ID() {
+			t.Errorf("ctx mismatch: have %s, want %s", tx.CTxId.String(), cws.ID().String())
+		}
+	case err := <-sub.Err():
+		t.Fatal(err)
+	case <-time.After(time.Second):
+		t.Fatal("ctx status is not notified")
+	}
+
+	tx, err := client.CtxGet(ctx, cws.ID(), testSubID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.CTxId != cws.ID() || tx.Value.ToInt().Cmp(cws.Data.Value) != 0 || len(tx.V) != 2 {
+		t.Errorf("ctx mismatch: %+v", tx)
+	}
+	if tx, err := client.CtxQuery(ctx, cws.Data.TxHash, nil); err != nil || tx.CTxId != cws.ID() {
+		t.Errorf("ctx of maker transaction mismatch: %v, %v", tx, err)
+	}
+
+	stats, err := admin.Stats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := stats[testMainID.Uint64()][cws.Status]; n != 1 {
+		t.Errorf("stats mismatch: have %d, want 1", n)
+	}
+	owned, err := client.CtxOwnerByPage(ctx, cws.Data.From, 10, 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txs := owned.Data[testSubID.Uint64()]; len(txs) != 1 || txs[0].CTxId != cws.ID() {
+		t.Errorf("owned ctxs mismatch: %v", owned.Data)
+	}
+	if _, _, err := client.PoolStats(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Monitor(ctx, big.NewInt(1)); err == nil {
+		t.Errorf("monitor of unregistered chain pair is returned")
+	}
+}
+
+func TestArchive(t *testing.T) {
+	datadir, err := ioutil.TempDir("", "crossclient")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(datadir)
+	n, admin, client := newTestBackend(t, datadir)
+	defer n.Stop()
+	ctx := context.Background()
+
+	cws := newTestCtx(t, 2)
+	cws.SetStatus(cc.CtxStatusFinished)
+	if err := admin.ImportCtx(ctx, cws); err != nil {
+		t.Fatal(err)
+	}
+	if archived, err := admin.Archive(ctx, testMainID, 10, nil); err != nil || !archived {
+		t.Fatalf("finished ctx is not archived: %v", err)
+	}
+
+	// the archived ctx is read transparently
+	tx, err := client.CtxGet(ctx, cws.ID(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.CTxId != cws.ID() || tx.Status != cc.CtxStatusFinished || len(tx.V) != 2 {
+		t.Errorf("archived ctx mismatch: %+v", tx)
+	}
+	stats, err := admin.Stats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := stats[testMainID.Uint64()][cc.CtxStatusFinished]; n != 0 {
+		t.Errorf("archived ctx is kept in store: %d", n)
+	}
+	report, err := admin.PruneReport(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Ctxs != 1 || report.Size == 0 || report.Archived == 0 {
+		t.Errorf("prune report mismatch: %+v", report)
+	}
+}
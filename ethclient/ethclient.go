@@ -542,6 +542,8 @@ func toCallArg(msg gbchian.CallMsg) interface{} {
 	return arg
 }
 
+// SendCrossTxMain imports a signed ctx made in the main chain of the default chain pair,
+// the ctxs of other pairs are imported by ImportCtx of crossclient.
 func (ec *Client) SendCrossTxMain(ctx context.Context, tx *cc.CrossTransactionWithSignatures) error {
 	data, err := rlp.EncodeToBytes(tx)
 	if err != nil {
@@ -550,6 +552,7 @@ func (ec *Client) SendCrossTxMain(ctx context.Context, tx *cc.CrossTransactionWi
 	return ec.c.CallContext(ctx, nil, "cross_importMainCtx", hexutil.Encode(data))
 }
 
+// SendCrossTxSub imports a signed ctx made in the sub chain of the default chain pair.
 func (ec *Client) SendCrossTxSub(ctx context.Context, tx *cc.CrossTransactionWithSignatures) error {
 	data, err := rlp.EncodeToBytes(tx)
 	if err != nil {
@@ -0,0 +1,476 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/common/hexutil"
+	"gbchain-org/go-gbchain/eth/filters"
+	"gbchain-org/go-gbchain/internal/ethapi"
+	"gbchain-org/go-gbchain/params"
+	"gbchain-org/go-gbchain/rpc"
+
+	cc "gbchain-org/go-gbchain/cross/core"
+	cdb "gbchain-org/go-gbchain/cross/database"
+
+	"github.com/asdine/storm/v3/q"
+)
+
+const (
+	defaultCtxPageSize = 50
+	maxCtxPageSize     = 1000
+	ctxCursorPrefix    = "ctx:"
+)
+
+var (
+	errNoCrossService = errors.New("cross chain service is not available")
+	errInvalidCursor  = errors.New("invalid cursor")
+)
+
+// CrossBackend is the cross chain service that cross queries operate on.
+type CrossBackend interface {
+	// ChainPairs returns the chain pairs bridged by the service
+	ChainPairs() []cc.ChainPair
+	// CtxStore returns the store of ctxs made in chainID
+	CtxStore(chainID *big.Int) (cdb.CtxDB, error)
+	// Contract returns the cross contract deployed in chainID
+	Contract(chainID *big.Int) (common.Address, error)
+	// Anchors returns the anchors of chainID
+	Anchors(chainID *big.Int) []common.Address
+	// TakerTx returns the transaction taking the ctx, false if it's not taken
+	TakerTx(ctxID common.Hash) (common.Hash, bool)
+}
+
+// CrossTransaction represents a cross transaction made in a chain and taken in its
+// destination chain, the transactions of its lifecycle are linked if the node
+// serves the chains.
+type CrossTransaction struct {
+	r   *Resolver
+	ctx *cc.CrossTransactionWithSignatures
+}
+
+func (c *CrossTransaction) Id(ctx context.Context) common.Hash {
+	return c.ctx.ID()
+}
+
+func (c *CrossTransaction) Status(ctx context.Context) string {
+	return c.ctx.Status.String()
+}
+
+func (c *CrossTransaction) ChainId(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*c.ctx.ChainId())
+}
+
+func (c *CrossTransaction) DestinationId(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*c.ctx.DestinationId())
+}
+
+func (c *CrossTransaction) From(ctx context.Context) common.Address {
+	return c.ctx.Data.From
+}
+
+func (c *CrossTransaction) To(ctx context.Context) common.Address {
+	return c.ctx.Data.To
+}
+
+func (c *CrossTransaction) Value(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*c.ctx.Data.Value)
+}
+
+func (c *CrossTransaction) DestinationValue(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*c.ctx.Data.DestinationValue)
+}
+
+func (c *CrossTransaction) Input(ctx context.Context) hexutil.Bytes {
+	return hexutil.Bytes(c.ctx.Data.Input)
+}
+
+func (c *CrossTransaction) BlockHash(ctx context.Context) common.Hash {
+	return c.ctx.Data.BlockHash
+}
+
+func (c *CrossTransaction) BlockNumber(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(c.ctx.BlockNum)
+}
+
+func (c *CrossTransaction) Signatures(ctx context.Context) []*Signature {
+	sigs := make([]*Signature, 0, c.ctx.SignaturesLength())
+	for _, tx := range c.ctx.Resolution() {
+		sigs = append(sigs, &Signature{tx: tx})
+	}
+	return sigs
+}
+
+// Maker is the transaction which makes the ctx in its chain.
+func (c *CrossTransaction) Maker(ctx context.Context) (*Transaction, error) {
+	backend := c.r.chainBackend(c.ctx.ChainId())
+	if backend == nil {
+		return nil, nil
+	}
+	tx := &Transaction{backend: backend, hash: c.ctx.Data.TxHash}
+	if t, err := tx.resolve(ctx); err != nil || t == nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// Taker is the transaction which takes the ctx in the destination chain.
+func (c *CrossTransaction) Taker(ctx context.Context) (*Transaction, error) {
+	if c.ctx.Status < cc.CtxStatusExecuting || c.ctx.Status > cc.CtxStatusFinished {
+		return nil, nil
+	}
+	backend := c.r.chainBackend(c.ctx.DestinationId())
+	if backend == nil {
+		return nil, nil
+	}
+	hash, ok := c.r.cross.TakerTx(c.ctx.ID())
+	if !ok {
+		return nil, nil
+	}
+	tx := &Transaction{backend: backend, hash: hash}
+	if t, err := tx.resolve(ctx); err != nil || t == nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// Finish is the transaction which finishes the ctx in its chain.
+func (c *CrossTransaction) Finish(ctx context.Context) (*Transaction, error) {
+	if c.ctx.Status < cc.CtxStatusFinishing || c.ctx.Status > cc.CtxStatusFinished {
+		return nil, nil
+	}
+	return c.r.findCtxTransaction(ctx, c.ctx.ChainId(), params.MakerFinishTopic, c.ctx.ID(), int64(c.ctx.BlockNum))
+}
+
+// Signature is an anchor signature of a cross transaction.
+type Signature struct {
+	tx *cc.CrossTransaction
+}
+
+func (s *Signature) V(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*s.tx.Data.V)
+}
+
+func (s *Signature) R(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*s.tx.Data.R)
+}
+
+func (s *Signature) S(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*s.tx.Data.S)
+}
+
+// Signer recovers the anchor of signature, it's null if the signature is not signed by ECDSA.
+func (s *Signature) Signer(ctx context.Context) *common.Address {
+	addr, err := cc.CtxSender(cc.MakeCtxSigner(s.tx.ChainId()), s.tx)
+	if err != nil {
+		return nil
+	}
+	return &addr
+}
+
+// Anchor is an anchor which signs the cross transactions of a chain.
+type Anchor struct {
+	address common.Address
+	chainID *big.Int
+}
+
+func (a *Anchor) Address(ctx context.Context) common.Address {
+	return a.address
+}
+
+func (a *Anchor) ChainId(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*a.chainID)
+}
+
+// ChainPair is a direction of cross transactions bridged by the cross service.
+type ChainPair struct {
+	r    *Resolver
+	pair cc.ChainPair
+}
+
+func (p *ChainPair) Local(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*p.pair.LocalID())
+}
+
+func (p *ChainPair) Remote(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*p.pair.RemoteID())
+}
+
+func (p *ChainPair) Contract(ctx context.Context) (common.Address, error) {
+	return p.r.cross.Contract(p.pair.LocalID())
+}
+
+func (p *ChainPair) Anchors(ctx context.Context) []*Anchor {
+	chainID := p.pair.LocalID()
+	addresses := p.r.cross.Anchors(chainID)
+	anchors := make([]*Anchor, len(addresses))
+	for i, addr := range addresses {
+		anchors[i] = &Anchor{address: addr, chainID: chainID}
+	}
+	return anchors
+}
+
+// CrossTransactionFilter selects cross transactions, all the fields are optional.
+type CrossTransactionFilter struct {
+	Status              *[]string
+	From                *common.Address
+	To                  *common.Address
+	DestinationId       *hexutil.Big
+	MinDestinationValue *hexutil.Big
+	MaxDestinationValue *hexutil.Big
+	FromBlock           *hexutil.Uint64
+	ToBlock             *hexutil.Uint64
+}
+
+func (f *CrossTransactionFilter) matchers() ([]q.Matcher, error) {
+	if f == nil {
+		return nil, nil
+	}
+	var matchers []q.Matcher
+	if f.Status != nil && len(*f.Status) > 0 {
+		var status []q.Matcher
+		for _, s := range *f.Status {
+			var st cc.CtxStatus
+			if err := st.UnmarshalText([]byte(s)); err != nil {
+				return nil, fmt.Errorf("invalid ctx status %q", s)
+			}
+			status = append(status, q.Eq(cdb.StatusField, st))
+		}
+		matchers = append(matchers, q.Or(status...))
+	}
+	if f.From != nil {
+		matchers = append(matchers, q.Eq(cdb.FromField, *f.From))
+	}
+	if f.To != nil {
+		matchers = append(matchers, q.Eq(cdb.ToField, *f.To))
+	}
+	if f.DestinationId != nil {
+		matchers = append(matchers, q.Eq(cdb.DestinationId, f.DestinationId.ToInt()))
+	}
+	if f.MinDestinationValue != nil {
+		matchers = append(matchers, q.Gte(cdb.DestinationValue, f.MinDestinationValue.ToInt()))
+	}
+	if f.MaxDestinationValue != nil {
+		matchers = append(matchers, q.Lte(cdb.DestinationValue, f.MaxDestinationValue.ToInt()))
+	}
+	if f.FromBlock != nil {
+		matchers = append(matchers, q.Gte(cdb.BlockNumField, uint64(*f.FromBlock)))
+	}
+	if f.ToBlock != nil {
+		matchers = append(matchers, q.Lte(cdb.BlockNumField, uint64(*f.ToBlock)))
+	}
+	return matchers, nil
+}
+
+// CrossTransactionConnection is a page of cross transactions.
+type CrossTransactionConnection struct {
+	total     int
+	edges     []*CrossTransactionEdge
+	hasNext   bool
+	endCursor *string
+}
+
+func (c *CrossTransactionConnection) TotalCount(ctx context.Context) int32 {
+	return int32(c.total)
+}
+
+func (c *CrossTransactionConnection) Edges(ctx context.Context) []*CrossTransactionEdge {
+	return c.edges
+}
+
+func (c *CrossTransactionConnection) PageInfo(ctx context.Context) *PageInfo {
+	return &PageInfo{hasNext: c.hasNext, endCursor: c.endCursor}
+}
+
+// CrossTransactionEdge is a cross transaction with its cursor in the connection.
+type CrossTransactionEdge struct {
+	cursor string
+	node   *CrossTransaction
+}
+
+func (e *CrossTransactionEdge) Cursor(ctx context.Context) string {
+	return e.cursor
+}
+
+func (e *CrossTransactionEdge) Node(ctx context.Context) *CrossTransaction {
+	return e.node
+}
+
+// PageInfo tells whether there are more items after the connection.
+type PageInfo struct {
+	hasNext   bool
+	endCursor *string
+}
+
+func (p *PageInfo) HasNextPage(ctx context.Context) bool {
+	return p.hasNext
+}
+
+func (p *PageInfo) EndCursor(ctx context.Context) *string {
+	return p.endCursor
+}
+
+// encodeCursor encodes the position of a ctx in the order of (BlockNum, CtxId) into an opaque cursor
+func encodeCursor(ctx *cc.CrossTransactionWithSignatures) string {
+	return base64.StdEncoding.EncodeToString([]byte(ctxCursorPrefix + strconv.FormatUint(ctx.BlockNum, 10) + ":" + ctx.ID().Hex()))
+}
+
+func decodeCursor(cursor string) (uint64, common.Hash, error) {
+	b, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(b), ctxCursorPrefix) {
+		return 0, common.Hash{}, errInvalidCursor
+	}
+	parts := strings.Split(strings.TrimPrefix(string(b), ctxCursorPrefix), ":")
+	if len(parts) != 2 {
+		return 0, common.Hash{}, errInvalidCursor
+	}
+	number, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, common.Hash{}, errInvalidCursor
+	}
+	id, err := hexutil.Decode(parts[1])
+	if err != nil || len(id) != common.HashLength {
+		return 0, common.Hash{}, errInvalidCursor
+	}
+	return number, common.BytesToHash(id), nil
+}
+
+// afterCursor matches the ctxs after the cursor in the order of (BlockNum, CtxId)
+func afterCursor(number uint64, id common.Hash) q.Matcher {
+	return q.Or(
+		q.Gt(cdb.BlockNumField, number),
+		q.And(q.Eq(cdb.BlockNumField, number), q.NewFieldMatcher(cdb.CtxIdIndex, ctxIDAfter(id))),
+	)
+}
+
+// ctxIDAfter matches the ctx IDs greater than it, storm can't compare hashes by q.Gt
+type ctxIDAfter common.Hash
+
+func (m ctxIDAfter) MatchField(v interface{}) (bool, error) {
+	id, ok := v.(common.Hash)
+	if !ok {
+		return false, nil
+	}
+	return bytes.Compare(id.Bytes(), m[:]) > 0, nil
+}
+
+// chainBackend returns the backend of chainID, it's nil if the chain is not served by this node.
+func (r *Resolver) chainBackend(chainID *big.Int) ethapi.Backend {
+	if chainID == nil {
+		return nil
+	}
+	return r.chains[chainID.Uint64()]
+}
+
+// findCtxTransaction finds the transaction emitting the ctx log of topic in the cross contract of chainID.
+func (r *Resolver) findCtxTransaction(ctx context.Context, chainID *big.Int, topic, ctxID common.Hash, begin int64) (*Transaction, error) {
+	backend := r.chainBackend(chainID)
+	if backend == nil {
+		return nil, nil
+	}
+	contract, err := r.cross.Contract(chainID)
+	if err != nil {
+		return nil, err
+	}
+	filter := filters.NewRangeFilter(filters.Backend(backend), begin, rpc.LatestBlockNumber.Int64(),
+		[]common.Address{contract}, [][]common.Hash{{topic}, {ctxID}})
+	logs, err := runFilter(ctx, backend, filter)
+	if err != nil || len(logs) == 0 {
+		return nil, err
+	}
+	// the ctx log of the latest transaction is the valid one
+	return logs[len(logs)-1].transaction, nil
+}
+
+func (r *Resolver) CrossTransaction(ctx context.Context, args struct {
+	ChainId hexutil.Big
+	Id      common.Hash
+}) (*CrossTransaction, error) {
+	if r.cross == nil {
+		return nil, errNoCrossService
+	}
+	store, err := r.cross.CtxStore(args.ChainId.ToInt())
+	if err != nil {
+		return nil, err
+	}
+	tx, _ := store.Read(args.Id)
+	if tx == nil {
+		return nil, nil
+	}
+	return &CrossTransaction{r: r, ctx: tx}, nil
+}
+
+// CrossTransactionsArgs are the arguments of crossTransactions query.
+type CrossTransactionsArgs struct {
+	ChainId hexutil.Big
+	Filter  *CrossTransactionFilter
+	First   *int32
+	After   *string
+}
+
+func (r *Resolver) CrossTransactions(ctx context.Context, args CrossTransactionsArgs) (*CrossTransactionConnection, error) {
+	if r.cross == nil {
+		return nil, errNoCrossService
+	}
+	store, err := r.cross.CtxStore(args.ChainId.ToInt())
+	if err != nil {
+		return nil, err
+	}
+	matchers, err := args.Filter.matchers()
+	if err != nil {
+		return nil, err
+	}
+	first := defaultCtxPageSize
+	if args.First != nil {
+		if *args.First <= 0 || *args.First > maxCtxPageSize {
+			return nil, fmt.Errorf("first should be in range [1, %d]", maxCtxPageSize)
+		}
+		first = int(*args.First)
+	}
+	conn := &CrossTransactionConnection{total: store.Count(matchers...)}
+	if args.After != nil {
+		number, id, err := decodeCursor(*args.After)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, afterCursor(number, id))
+	}
+
+	// query one more ctx to check whether there is next page
+	ctxs := store.Query(first+1, 1, []cdb.FieldName{cdb.BlockNumField, cdb.CtxIdIndex}, false, matchers...)
+	if len(ctxs) == 0 {
+		return conn, nil
+	}
+	if len(ctxs) > first {
+		conn.hasNext = true
+		ctxs = ctxs[:first]
+	}
+	for _, tx := range ctxs {
+		conn.edges = append(conn.edges, &CrossTransactionEdge{
+			cursor: encodeCursor(tx),
+			node:   &CrossTransaction{r: r, ctx: tx},
+		})
+	}
+	end := conn.edges[len(conn.edges)-1].cursor
+	conn.endCursor = &end
+	return conn, nil
+}
+
+func (r *Resolver) ChainPairs(ctx context.Context) ([]*ChainPair, error) {
+	if r.cross == nil {
+		return nil, errNoCrossService
+	}
+	pairs := r.cross.ChainPairs()
+	result := make([]*ChainPair, len(pairs))
+	for i, pair := range pairs {
+		result[i] = &ChainPair{r: r, pair: pair}
+	}
+	return result, nil
+}
@@ -0,0 +1,136 @@
+package graphql
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gbchain-org/go-gbchain/common"
+	"gbchain-org/go-gbchain/common/hexutil"
+	"gbchain-org/go-gbchain/crypto"
+
+	cc "gbchain-org/go-gbchain/cross/core"
+	cdb "gbchain-org/go-gbchain/cross/database"
+
+	"github.com/asdine/storm/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCrossBackend struct {
+	chainID *big.Int
+	store   cdb.CtxDB
+	takers  map[common.Hash]common.Hash
+}
+
+func (b *testCrossBackend) ChainPairs() []cc.ChainPair {
+	return []cc.ChainPair{cc.NewChainPair(b.chainID, big.NewInt(2))}
+}
+
+func (b *testCrossBackend) CtxStore(chainID *big.Int) (cdb.CtxDB, error) {
+	return b.store, nil
+}
+
+func (b *testCrossBackend) Contract(chainID *big.Int) (common.Address, error) {
+	return common.HexToAddress("0xc"), nil
+}
+
+func (b *testCrossBackend) Anchors(chainID *big.Int) []common.Address {
+	return []common.Address{common.HexToAddress("0xa")}
+}
+
+func (b *testCrossBackend) TakerTx(ctxID common.Hash) (common.Hash, bool) {
+	hash, ok := b.takers[ctxID]
+	return hash, ok
+}
+
+func TestCrossTransactions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "graphql-cross")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	db, err := storm.Open(filepath.Join(dir, "ctx.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var (
+		chainID = big.NewInt(1)
+		key, _  = crypto.GenerateKey()
+		signer  = cc.NewEIP155CtxSigner(chainID)
+		store   = cdb.NewIndexDB(chainID, db, 0)
+		r       = &Resolver{cross: &testCrossBackend{chainID: chainID, store: store}}
+	)
+	for i := 1; i <= 5; i++ {
+		ctx := cc.NewCrossTransaction(big.NewInt(1), big.NewInt(int64(i)), big.NewInt(2), common.BigToHash(big.NewInt(int64(i))),
+			common.Hash{}, common.Hash{}, common.Address{}, common.Address{}, nil)
+		signed, err := cc.SignCtx(ctx, signer, func(hash []byte) ([]byte, error) { return crypto.Sign(hash, key) })
+		assert.NoError(t, err)
+		// two ctxs in a block, they are paged in the order of IDs
+		cws := cc.NewCrossTransactionWithSignatures(signed, uint64(i+1)/2)
+		cws.SetStatus(cc.CtxStatusWaiting)
+		assert.NoError(t, store.Write(cws))
+	}
+
+	first := int32(2)
+	conn, err := r.CrossTransactions(context.Background(), CrossTransactionsArgs{ChainId: hexutil.Big(*chainID), First: &first})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(5), conn.TotalCount(nil))
+	assert.Equal(t, 2, len(conn.Edges(nil)))
+	assert.True(t, conn.PageInfo(nil).HasNextPage(nil))
+
+	// the next page with destination value >= 4
+	min := hexutil.Big(*big.NewInt(4))
+	after := conn.PageInfo(nil).EndCursor(nil)
+	conn, err = r.CrossTransactions(context.Background(), CrossTransactionsArgs{
+		ChainId: hexutil.Big(*chainID),
+		Filter:  &CrossTransactionFilter{MinDestinationValue: &min},
+		First:   &first,
+		After:   after,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), conn.TotalCount(nil))
+	assert.Equal(t, 2, len(conn.Edges(nil)))
+	assert.False(t, conn.PageInfo(nil).HasNextPage(nil))
+
+	// walk through the pages across the ctxs of the same block
+	var ids []common.Hash
+	one := int32(1)
+	for after = nil; ; after = conn.PageInfo(nil).EndCursor(nil) {
+		conn, err = r.CrossTransactions(context.Background(), CrossTransactionsArgs{ChainId: hexutil.Big(*chainID), First: &one, After: after})
+		assert.NoError(t, err)
+		for _, edge := range conn.Edges(nil) {
+			ids = append(ids, edge.Node(nil).Id(nil))
+		}
+		if !conn.PageInfo(nil).HasNextPage(nil) {
+			break
+		}
+	}
+	assert.Equal(t, 5, len(ids))
+	for i, id := range ids {
+		assert.Equal(t, common.BigToHash(big.NewInt(int64(i+1))), id)
+	}
+
+	invalid := "invalid"
+	_, err = r.CrossTransactions(context.Background(), CrossTransactionsArgs{ChainId: hexutil.Big(*chainID), After: &invalid})
+	assert.Equal(t, errInvalidCursor, err)
+
+	conn, err = r.CrossTransactions(context.Background(), CrossTransactionsArgs{ChainId: hexutil.Big(*chainID), Filter: &CrossTransactionFilter{MinDestinationValue: &min}})
+	assert.NoError(t, err)
+	edges := conn.Edges(nil)
+	assert.Equal(t, 2, len(edges))
+	ctx := edges[0].Node(nil)
+	assert.Equal(t, "waiting", ctx.Status(nil))
+	assert.Equal(t, hexutil.Big(*big.NewInt(4)), ctx.DestinationValue(nil))
+	sigs := ctx.Signatures(nil)
+	assert.Equal(t, 1, len(sigs))
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), *sigs[0].Signer(nil))
+
+	status := []string{"unknown"}
+	_, err = r.CrossTransactions(context.Background(), CrossTransactionsArgs{ChainId: hexutil.Big(*chainID), Filter: &CrossTransactionFilter{Status: &status}})
+	assert.Error(t, err)
+}
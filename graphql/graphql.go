@@ -849,6 +849,8 @@ func (p *Pending) EstimateGas(ctx context.Context, args struct {
 // Resolver is the top-level object in the GraphQL hierarchy.
 type Resolver struct {
 	backend ethapi.Backend
+	cross   CrossBackend              // cross chain service, nil if it's not running
+	chains  map[uint64]ethapi.Backend // backends of the chains bridged by the cross service
 }
 
 func (r *Resolver) Block(ctx context.Context, args struct {
@@ -6,7 +6,7 @@ import (
 
 func TestBuildSchema(t *testing.T) {
 	// Make sure the schema can be parsed and matched up to the object model.
-	if _, err := newHandler(nil); err != nil {
+	if _, err := newHandler(nil, nil); err != nil {
 		t.Errorf("Could not construct GraphQL handler: %v", err)
 	}
 }
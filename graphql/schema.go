@@ -275,6 +275,116 @@ const schema string = `
       estimateGas(data: CallData!): Long!
     }
 
+    # CrossTransaction is a cross chain transaction made in a chain and taken
+    # in its destination chain.
+    type CrossTransaction {
+        # Id is the ID of this cross transaction.
+        id: Bytes32!
+        # Status is the lifecycle status of this cross transaction, one of pending,
+        # waiting, illegal, executing, executed, finishing, finished, cancelling
+        # and cancelled.
+        status: String!
+        # ChainId is the chain this cross transaction is made in.
+        chainId: BigInt!
+        # DestinationId is the chain this cross transaction is taken in.
+        destinationId: BigInt!
+        # From is the maker of this cross transaction.
+        from: Address!
+        # To is the only taker allowed to take this cross transaction, it's zero
+        # address if anyone could take it.
+        to: Address!
+        # Value is the value, in wei, sold by the maker.
+        value: BigInt!
+        # DestinationValue is the value, in wei, paid by the taker in the
+        # destination chain.
+        destinationValue: BigInt!
+        # Input is the data supplied by the maker.
+        input: Bytes!
+        # BlockHash is the hash of the block this cross transaction is made in.
+        blockHash: Bytes32!
+        # BlockNumber is the number of the block this cross transaction is made in,
+        # or the block its status is changed in.
+        blockNumber: Long!
+        # Signatures are the anchor signatures of this cross transaction.
+        signatures: [Signature!]!
+        # Maker is the transaction making this cross transaction. This will be
+        # null if the chain is not served by this node.
+        maker: Transaction
+        # Taker is the transaction taking this cross transaction in the destination
+        # chain. This will be null if it's not taken yet, or the destination chain is
+        # not served by this node, or the taking is not seen by this node.
+        taker: Transaction
+        # Finish is the transaction finishing this cross transaction. This will be
+        # null if it's not finished yet or the chain is not served by this node.
+        finish: Transaction
+    }
+
+    # Signature is an anchor signature of a cross transaction.
+    type Signature {
+        v: BigInt!
+        r: BigInt!
+        s: BigInt!
+        # Signer is the anchor recovered from the signature. This will be null
+        # if the signature is aggregated by BLS.
+        signer: Address
+    }
+
+    # Anchor is an anchor signing the cross transactions of a chain.
+    type Anchor {
+        address: Address!
+        chainId: BigInt!
+    }
+
+    # ChainPair is a direction of cross transactions bridged by the node, cross
+    # transactions are made in the local chain and taken in the remote chain.
+    type ChainPair {
+        local: BigInt!
+        remote: BigInt!
+        # Contract is the cross contract of the local chain.
+        contract: Address!
+        # Anchors are the anchors of the local chain.
+        anchors: [Anchor!]!
+    }
+
+    # CrossTransactionFilter is a set of criteria for cross transactions, all the
+    # fields are optional.
+    input CrossTransactionFilter {
+        # Status matches any of the listed statuses.
+        status: [String!]
+        from: Address
+        to: Address
+        destinationId: BigInt
+        # MinDestinationValue and MaxDestinationValue limit the destination
+        # value range, inclusive.
+        minDestinationValue: BigInt
+        maxDestinationValue: BigInt
+        # FromBlock and ToBlock limit the block range, inclusive.
+        fromBlock: Long
+        toBlock: Long
+    }
+
+    # CrossTransactionConnection is a page of cross transactions ordered by
+    # block number.
+    type CrossTransactionConnection {
+        # TotalCount is the number of cross transactions matching the filter.
+        totalCount: Int!
+        edges: [CrossTransactionEdge!]!
+        pageInfo: PageInfo!
+    }
+
+    type CrossTransactionEdge {
+        # Cursor is an opaque position of the node, it's used by after to
+        # fetch the following cross transactions.
+        cursor: String!
+        node: CrossTransaction!
+    }
+
+    type PageInfo {
+        hasNextPage: Boolean!
+        # EndCursor is the cursor of the last edge, or null if the page is empty.
+        endCursor: String
+    }
+
     type Query {
         # Block fetches an Ethereum block by number or by hash. If neither is
         # supplied, the most recent known block is returned.
@@ -295,6 +405,15 @@ const schema string = `
         protocolVersion: Int!
         # Syncing returns information on the current synchronisation state.
         syncing: SyncState
+        # CrossTransaction returns a cross transaction made in chainId specified
+        # by its ID.
+        crossTransaction(chainId: BigInt!, id: Bytes32!): CrossTransaction
+        # CrossTransactions returns the cross transactions made in chainId
+        # matching the filter in the order of block numbers and IDs, at most first
+        # ones after the cursor are returned.
+        crossTransactions(chainId: BigInt!, filter: CrossTransactionFilter, first: Int, after: String): CrossTransactionConnection!
+        # ChainPairs returns the chain pairs bridged by the node.
+        chainPairs: [ChainPair!]!
     }
 
     type Mutation {
@@ -20,6 +20,8 @@ type Service struct {
 	vhosts   []string         // Recognised vhosts
 	timeouts rpc.HTTPTimeouts // Timeout settings for HTTP requests.
 	backend  ethapi.Backend   // The backend that queries will operate onn.
+	cross    CrossBackend     // The cross chain service that cross queries will operate on.
+	chains   []ethapi.Backend // The backends of chains bridged by the cross service.
 	handler  http.Handler     // The `http.Handler` used to answer queries.
 	listener net.Listener     // The listening socket.
 }
@@ -35,6 +37,13 @@ func New(backend ethapi.Backend, endpoint string, cors, vhosts []string, timeout
 	}, nil
 }
 
+// RegisterCross registers the cross chain service and the backends of its bridged chains
+// to resolve cross queries, it should be called before the service is started.
+func (s *Service) RegisterCross(cross CrossBackend, chains ...ethapi.Backend) {
+	s.cross = cross
+	s.chains = chains
+}
+
 // Protocols returns the list of protocols exported by this service.
 func (s *Service) Protocols() []p2p.Protocol { return nil }
 
@@ -45,7 +54,7 @@ func (s *Service) APIs() []rpc.API { return nil }
 // layer was also initialized to spawn any goroutines required by the service.
 func (s *Service) Start(server *p2p.Server) error {
 	var err error
-	s.handler, err = newHandler(s.backend)
+	s.handler, err = newHandler(s.backend, s.cross, s.chains...)
 	if err != nil {
 		return err
 	}
@@ -59,8 +68,14 @@ func (s *Service) Start(server *p2p.Server) error {
 
 // newHandler returns a new `http.Handler` that will answer GraphQL queries.
 // It additionally exports an interactive query browser on the / endpoint.
-func newHandler(backend ethapi.Backend) (http.Handler, error) {
-	q := Resolver{backend}
+func newHandler(backend ethapi.Backend, cross CrossBackend, chains ...ethapi.Backend) (http.Handler, error) {
+	q := Resolver{backend: backend, cross: cross, chains: make(map[uint64]ethapi.Backend)}
+	if backend != nil {
+		chains = append([]ethapi.Backend{backend}, chains...)
+	}
+	for _, chain := range chains {
+		q.chains[chain.ChainConfig().ChainID.Uint64()] = chain
+	}
 
 	s, err := graphql.ParseSchema(schema, &q)
 	if err != nil {
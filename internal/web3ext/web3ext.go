@@ -83,6 +83,16 @@ web3._extend({
 				call: 'cross_ctxTakerByPage',
 				params: 3,
 		}),
+		new web3._extend.Method({
+				name: 'ctxCancel',
+				call: 'cross_ctxCancel',
+				params: 1,
+		}),
+		new web3._extend.Method({
+				name: 'ctxMessage',
+				call: 'cross_ctxMessage',
+				params: 1,
+		}),
 		new web3._extend.Method({
 				name: 'getCtxStats',
 				call: 'cross_ctxStats',
@@ -98,6 +108,17 @@ web3._extend({
 			call: 'cross_setStoreDelay',
 			params: 2,
 		}),
+		new web3._extend.Method({
+			name: 'rescan',
+			call: 'cross_rescan',
+			params: 2,
+			inputFormatter: [web3._extend.utils.fromDecimal, web3._extend.utils.fromDecimal],
+		}),
+		new web3._extend.Method({
+			name: 'archive',
+			call: 'cross_archive',
+			params: 2,
+		}),
 		new web3._extend.Method({
 			name: 'remove',
 			call: 'cross_remove',
@@ -113,20 +134,66 @@ web3._extend({
 			call: 'cross_syncStore',
 			params: 0,
 		}),
+		new web3._extend.Method({
+			name: 'evidence',
+			call: 'cross_evidence',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal],
+		}),
+		new web3._extend.Method({
+			name: 'addTakerOrder',
+			call: 'cross_addTakerOrder',
+			params: 2,
+			inputFormatter: [web3._extend.utils.fromDecimal, null],
+		}),
+		new web3._extend.Method({
+			name: 'cancelTakerOrder',
+			call: 'cross_cancelTakerOrder',
+			params: 2,
+			inputFormatter: [web3._extend.utils.fromDecimal, web3._extend.utils.fromDecimal],
+		}),
+		new web3._extend.Method({
+			name: 'takerOrders',
+			call: 'cross_takerOrders',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal],
+		}),
+		new web3._extend.Method({
+			name: 'takerBook',
+			call: 'cross_takerBook',
+			params: 2,
+			inputFormatter: [web3._extend.utils.fromDecimal, null],
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
 			name: 'peers',
 			getter: 'cross_peers'
 		}),
+		new web3._extend.Property({
+			name: 'bans',
+			getter: 'cross_bans'
+		}),
 		new web3._extend.Property({
 			name: 'height',
 			getter: 'cross_height'
 		}),
+		new web3._extend.Property({
+			name: 'executorStatus',
+			getter: 'cross_executorStatus'
+		}),
 		new web3._extend.Property({
 			name: 'stats',
 			getter: 'cross_stats'
 		}),
+		new web3._extend.Property({
+			name: 'pruneReport',
+			getter: 'cross_pruneReport'
+		}),
+		new web3._extend.Property({
+			name: 'divergences',
+			getter: 'cross_divergences'
+		}),
 	    new web3._extend.Property({
 			name: 'anchors',
 			getter: 'cross_anchors'
@@ -47,6 +47,10 @@ var (
 		accounts.MimetypeTextPlain,
 		0x45,
 	}
+	ApplicationCrossCtx = SigFormat{
+		accounts.MimetypeCrossCtx,
+		0x03,
+	}
 )
 
 type ValidatorData struct {
@@ -243,6 +247,23 @@ func (api *SignerAPI) determineSignatureFormat(ctx context.Context, contentType
 		// Clique uses V on the form 0 or 1
 		useEthereumV = false
 		req = &SignDataRequest{ContentType: mediaType, Rawdata: cliqueRlp, Messages: messages, Hash: sighash}
+	case ApplicationCrossCtx.Mime:
+		// Cross chain transaction signed by anchors
+		stringData, ok := data.(string)
+		if !ok {
+			return nil, useEthereumV, fmt.Errorf("input for %v must be an hex-encoded string", ApplicationCrossCtx.Mime)
+		}
+		ctxData, err := hexutil.Decode(stringData)
+		if err != nil {
+			return nil, useEthereumV, err
+		}
+		messages, err := crossCtxMessages(ctxData)
+		if err != nil {
+			return nil, useEthereumV, err
+		}
+		// Ctx signatures use V on the form 0 or 1
+		useEthereumV = false
+		req = &SignDataRequest{ContentType: mediaType, Rawdata: ctxData, Messages: messages, Hash: crypto.Keccak256(ctxData)}
 	default: // also case TextPlain.Mime:
 		// Calculates an Ethereum ECDSA signature for:
 		// hash = keccak256("\x19${byteVersion}Ethereum Signed Message:\n${message length}${message}")
@@ -295,6 +316,58 @@ func cliqueHeaderHashAndRlp(header *types.Header) (hash, rlp []byte, err error)
 	return hash, rlp, err
 }
 
+// crossCtxSigningLength is the length of signing data of a cross chain transaction without input:
+// value(32) | ctxId(32) | txHash(32) | from(20) | blockHash(32) | destinationId(32) | destinationValue(32)
+const crossCtxSigningLength = 212
+
+// crossTokenCtxDomain leads the signing data of ERC20 cross chain transactions, which is
+// domain(32) | <cross chain transaction without input> | token(20) | destinationToken(20) | input
+var crossTokenCtxDomain = common.HexToHash("0x945e5c2ff84b11106e53ad234d1fd11aed93b2e1e33a9febbcbe257e0cf20b80")
+
+// crossCtxMessages decodes the signing data of a cross chain transaction to be shown to the user
+func crossCtxMessages(data []byte) ([]*NameValueType, error) {
+	var tokens []byte
+	if len(data) >= common.HashLength && common.BytesToHash(data[:common.HashLength]) == crossTokenCtxDomain {
+		if len(data) < common.HashLength+crossCtxSigningLength+common.AddressLength*2 {
+			return nil, fmt.Errorf("cross token transaction data too short, %d < %d", len(data), common.HashLength+crossCtxSigningLength+common.AddressLength*2)
+		}
+		data = data[common.HashLength:]
+		tokens = data[crossCtxSigningLength : crossCtxSigningLength+common.AddressLength*2]
+		data = append(append([]byte{}, data[:crossCtxSigningLength]...), data[crossCtxSigningLength+common.AddressLength*2:]...)
+	}
+	if len(data) < crossCtxSigningLength {
+		return nil, fmt.Errorf("cross transaction data too short, %d < %d", len(data), crossCtxSigningLength)
+	}
+	var (
+		value            = new(big.Int).SetBytes(data[:32])
+		ctxID            = common.BytesToHash(data[32:64])
+		txHash           = common.BytesToHash(data[64:96])
+		from             = common.BytesToAddress(data[96:116])
+		blockHash        = common.BytesToHash(data[116:148])
+		destinationID    = new(big.Int).SetBytes(data[148:180])
+		destinationValue = new(big.Int).SetBytes(data[180:212])
+		input            = data[212:]
+	)
+	messages := []*NameValueType{
+		{Name: "ctxId", Typ: "bytes32", Value: ctxID.Hex()},
+		{Name: "from", Typ: "address", Value: from.Hex()},
+		{Name: "value", Typ: "uint256", Value: value.String()},
+		{Name: "destinationId", Typ: "uint256", Value: destinationID.String()},
+		{Name: "destinationValue", Typ: "uint256", Value: destinationValue.String()},
+	}
+	if tokens != nil {
+		messages = append(messages,
+			&NameValueType{Name: "token", Typ: "address", Value: common.BytesToAddress(tokens[:common.AddressLength]).Hex()},
+			&NameValueType{Name: "destinationToken", Typ: "address", Value: common.BytesToAddress(tokens[common.AddressLength:]).Hex()},
+		)
+	}
+	return append(messages,
+		&NameValueType{Name: "txHash", Typ: "bytes32", Value: txHash.Hex()},
+		&NameValueType{Name: "blockHash", Typ: "bytes32", Value: blockHash.Hex()},
+		&NameValueType{Name: "input", Typ: "bytes", Value: hexutil.Encode(input)},
+	), nil
+}
+
 // SignTypedData signs EIP-712 conformant typed data
 // hash = keccak256("\x19${byteVersion}${domainSeparator}${hashStruct(message)}")
 func (api *SignerAPI) SignTypedData(ctx context.Context, addr common.MixedcaseAddress, typedData TypedData) (hexutil.Bytes, error) {
@@ -1,14 +1,19 @@
 package rules
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/robertkrimen/otto"
+	"gbchain-org/go-gbchain/accounts/abi"
+	"gbchain-org/go-gbchain/common/hexutil"
 	"gbchain-org/go-gbchain/internal/ethapi"
 	"gbchain-org/go-gbchain/log"
+	"gbchain-org/go-gbchain/params"
 	"gbchain-org/go-gbchain/signer/core"
 	"gbchain-org/go-gbchain/signer/rules/deps"
 	"gbchain-org/go-gbchain/signer/storage"
@@ -139,7 +144,65 @@ func (r *rulesetUI) checkApproval(jsfunc string, jsarg []byte, err error) (bool,
 	return false, fmt.Errorf("unknown response")
 }
 
+// crossTxRequest is the request passed to ApproveCrossTx, the transaction calls the method of cross contract
+type crossTxRequest struct {
+	*core.SignTxRequest
+	Method string `json:"method"`
+}
+
+var (
+	crossABI     abi.ABI
+	crossABIErr  error
+	crossABIOnce sync.Once
+)
+
+// crossMethod returns the name of cross contract method called by the transaction, empty if it's not a cross call
+func crossMethod(request *core.SignTxRequest) string {
+	if request == nil {
+		return ""
+	}
+	var (
+		args  = request.Transaction
+		input []byte
+	)
+	if args.Data != nil {
+		input = *args.Data
+	} else if args.Input != nil {
+		input = *args.Input
+	}
+	if args.To == nil || len(input) < 4 {
+		return ""
+	}
+	crossABIOnce.Do(func() {
+		var data []byte
+		if data, crossABIErr = hexutil.Decode(params.CrossDemoAbi); crossABIErr == nil {
+			crossABI, crossABIErr = abi.JSON(bytes.NewReader(data))
+		}
+	})
+	if crossABIErr != nil {
+		return ""
+	}
+	method, err := crossABI.MethodById(input[:4])
+	if err != nil {
+		return ""
+	}
+	return method.Name
+}
+
 func (r *rulesetUI) ApproveTx(request *core.SignTxRequest) (core.SignTxResponse, error) {
+	// Calls of cross contract are checked by ApproveCrossTx first, so that the rules could whitelist
+	// the transactions of anchors. They go on to ApproveTx if ApproveCrossTx is not defined.
+	if method := crossMethod(request); method != "" {
+		jsonreq, err := json.Marshal(crossTxRequest{request, method})
+		approved, err := r.checkApproval("ApproveCrossTx", jsonreq, err)
+		if err == nil {
+			if approved {
+				return core.SignTxResponse{Transaction: request.Transaction, Approved: true}, nil
+			}
+			return core.SignTxResponse{Approved: false}, nil
+		}
+		log.Info("Rule-based cross approval error, going to ApproveTx", "error", err)
+	}
 	jsonreq, err := json.Marshal(request)
 	approved, err := r.checkApproval("ApproveTx", jsonreq, err)
 	if err != nil {
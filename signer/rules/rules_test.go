@@ -1,16 +1,19 @@
 package rules
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
 	"strings"
 	"testing"
 
 	"gbchain-org/go-gbchain/accounts"
+	"gbchain-org/go-gbchain/accounts/abi"
 	"gbchain-org/go-gbchain/common"
 	"gbchain-org/go-gbchain/common/hexutil"
 	"gbchain-org/go-gbchain/core/types"
 	"gbchain-org/go-gbchain/internal/ethapi"
+	"gbchain-org/go-gbchain/params"
 	"gbchain-org/go-gbchain/signer/core"
 	"gbchain-org/go-gbchain/signer/storage"
 )
@@ -609,3 +612,70 @@ function ApproveSignData(r){
 		t.Fatalf("Expected approved")
 	}
 }
+
+func TestApproveCrossTx(t *testing.T) {
+	js := `
+	function ApproveCrossTx(r){
+		if(r.transaction.to.toLowerCase()=="0x000000000000000000000000000000000000c055" && r.method=="makerFinish"){ return "Approve"}
+		return "Reject"
+	}
+	function ApproveTx(r){
+		return "Approve"
+	}`
+	r, err := initRuleEngine(js)
+	if err != nil {
+		t.Fatalf("Couldn't create evaluator %v", err)
+	}
+	contract, _ := mixAddr("0x000000000000000000000000000000000000c055")
+	other, _ := mixAddr("0x000000000000000000000000000000000000dead")
+	from, _ := mixAddr("0x0000000000000000000000000000000000001337")
+	finish := hexutil.Bytes(crossCall(t, "makerFinish"))
+	cancel := hexutil.Bytes(crossCall(t, "makerCancel"))
+	transfer := hexutil.Bytes{0x01, 0x02, 0x03, 0x04}
+
+	for i, tt := range []struct {
+		to       *common.MixedcaseAddress
+		data     *hexutil.Bytes
+		approved bool
+	}{
+		{contract, &finish, true},
+		{contract, &cancel, false},
+		{other, &finish, false},
+		{other, &transfer, true}, // not a cross call, checked by ApproveTx
+		{contract, nil, true},
+	} {
+		resp, err := r.ApproveTx(&core.SignTxRequest{
+			Transaction: core.SendTxArgs{From: *from, To: tt.to, Data: tt.data},
+			Meta:        core.Metadata{Remote: "remoteip", Local: "localip", Scheme: "inproc"},
+		})
+		if err != nil {
+			t.Fatalf("test %d: unexpected error %v", i, err)
+		}
+		if resp.Approved != tt.approved {
+			t.Errorf("test %d: approved %v, want %v", i, resp.Approved, tt.approved)
+		}
+	}
+
+	// cross calls are checked by ApproveTx if ApproveCrossTx is not defined
+	r, err = initRuleEngine(`function ApproveTx(r){ return "Approve" }`)
+	if err != nil {
+		t.Fatalf("Couldn't create evaluator %v", err)
+	}
+	resp, err := r.ApproveTx(&core.SignTxRequest{Transaction: core.SendTxArgs{From: *from, To: contract, Data: &finish}})
+	if err != nil || !resp.Approved {
+		t.Errorf("Expected fallback to ApproveTx, approved %v err %v", resp.Approved, err)
+	}
+}
+
+// crossCall returns the selector of cross contract method followed by empty arguments
+func crossCall(t *testing.T, method string) []byte {
+	data, err := hexutil.Decode(params.CrossDemoAbi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crossABI, err := abi.JSON(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(crossABI.Methods[method].ID(), make([]byte, 64)...)
+}